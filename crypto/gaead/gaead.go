@@ -0,0 +1,108 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gaead provides authenticated encryption using AES-GCM and XChaCha20-Poly1305,
+// with versioned and self-describing ciphertext envelope for key rotation, and streaming encryption.
+//
+// The ciphertext envelope contains the version, algorithm and key id, so that the ciphertext can be
+// decrypted by the Keyring holding the key, after the primary key of the Keyring is rotated.
+package gaead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// Algorithm is the authenticated encryption algorithm.
+type Algorithm uint8
+
+const (
+	// AlgorithmAesGcm is AES-GCM with 96-bit random nonce, whose key is 16/24/32 bytes.
+	AlgorithmAesGcm Algorithm = 1
+
+	// AlgorithmXChaCha20Poly1305 is XChaCha20-Poly1305 with 192-bit random nonce, whose key is 32 bytes.
+	// It is recommended for large amounts of messages with the same key, as the nonce is long enough
+	// to be generated randomly without collision risk.
+	AlgorithmXChaCha20Poly1305 Algorithm = 2
+)
+
+// Key is the named key for encryption.
+type Key struct {
+	Id        string    // Id is the unique id of the key, which is stored in ciphertext envelope, up to 255 bytes.
+	Algorithm Algorithm // Algorithm is the encryption algorithm of the key.
+	Secret    []byte    // Secret is the key bytes.
+}
+
+// GenerateKey generates and returns a new random Key with `id` for `algorithm`.
+// It generates 32 bytes secret for all algorithms, that is AES-256 for AlgorithmAesGcm.
+func GenerateKey(id string, algorithm Algorithm) (*Key, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, gerror.WrapCode(gcode.CodeInternalError, err, `generate random key failed`)
+	}
+	key := &Key{
+		Id:        id,
+		Algorithm: algorithm,
+		Secret:    secret,
+	}
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// String returns the name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case AlgorithmAesGcm:
+		return "AES-GCM"
+	case AlgorithmXChaCha20Poly1305:
+		return "XChaCha20-Poly1305"
+	default:
+		return "Unknown"
+	}
+}
+
+// validate checks the key and returns error if it is invalid.
+func (k *Key) validate() error {
+	if k.Id == "" || len(k.Id) > 255 {
+		return gerror.NewCodef(gcode.CodeInvalidParameter, `invalid key id "%s", whose length should be 1-255`, k.Id)
+	}
+	_, err := k.newAEAD()
+	return err
+}
+
+// newAEAD creates and returns the AEAD of the key.
+func (k *Key) newAEAD() (cipher.AEAD, error) {
+	switch k.Algorithm {
+	case AlgorithmAesGcm:
+		block, err := aes.NewCipher(k.Secret)
+		if err != nil {
+			return nil, gerror.WrapCodef(gcode.CodeInvalidParameter, err, `invalid AES key for key id "%s"`, k.Id)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, gerror.WrapCode(gcode.CodeInternalError, err, `cipher.NewGCM failed`)
+		}
+		return aead, nil
+
+	case AlgorithmXChaCha20Poly1305:
+		aead, err := chacha20poly1305.NewX(k.Secret)
+		if err != nil {
+			return nil, gerror.WrapCodef(gcode.CodeInvalidParameter, err, `invalid XChaCha20-Poly1305 key for key id "%s"`, k.Id)
+		}
+		return aead, nil
+
+	default:
+		return nil, gerror.NewCodef(gcode.CodeNotSupported, `unsupported algorithm %d for key id "%s"`, k.Algorithm, k.Id)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gaead
+
+import (
+	"crypto/rand"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// The ciphertext envelope is in format:
+//
+//	| version(1) | mode(1) | algorithm(1) | key id length(1) | key id | nonce | sealed data |
+//
+// The header before the sealed data is authenticated as additional data.
+const (
+	envelopeVersion1  = 1 // envelopeVersion1 is the current version of the envelope format.
+	envelopeModeBlock = 0 // envelopeModeBlock is the mode for the whole message sealed once.
+	envelopeModeChunk = 1 // envelopeModeChunk is the mode for streaming, in which the message is sealed in chunks.
+)
+
+// envelopeHeader is the parsed header of ciphertext envelope.
+type envelopeHeader struct {
+	Version   uint8
+	Mode      uint8
+	Algorithm Algorithm
+	KeyId     string
+}
+
+// Encrypt encrypts `plainText` with the primary key, and returns the ciphertext envelope
+// with a random nonce. The optional `additionalData` is authenticated but not encrypted,
+// which must be the same for decryption.
+func (k *Keyring) Encrypt(plainText []byte, additionalData ...[]byte) ([]byte, error) {
+	key := k.Primary()
+	if key == nil {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, `no primary key for encryption`)
+	}
+	aead, err := key.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	header := encodeEnvelopeHeader(envelopeHeader{
+		Version:   envelopeVersion1,
+		Mode:      envelopeModeBlock,
+		Algorithm: key.Algorithm,
+		KeyId:     key.Id,
+	})
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, gerror.WrapCode(gcode.CodeInternalError, err, `generate nonce failed`)
+	}
+	cipherText := make([]byte, 0, len(header)+len(nonce)+len(plainText)+aead.Overhead())
+	cipherText = append(cipherText, header...)
+	cipherText = append(cipherText, nonce...)
+	return aead.Seal(cipherText, nonce, plainText, getAdditionalData(header, additionalData)), nil
+}
+
+// Decrypt decrypts the ciphertext envelope `cipherText` encrypted by Encrypt,
+// using the key specified by the envelope.
+func (k *Keyring) Decrypt(cipherText []byte, additionalData ...[]byte) ([]byte, error) {
+	header, headerSize, err := decodeEnvelopeHeader(cipherText)
+	if err != nil {
+		return nil, err
+	}
+	if header.Mode != envelopeModeBlock {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `ciphertext is streaming envelope, which should be decrypted by stream`)
+	}
+	key, err := k.getKey(header.KeyId)
+	if err != nil {
+		return nil, err
+	}
+	if key.Algorithm != header.Algorithm {
+		return nil, gerror.NewCodef(
+			gcode.CodeInvalidParameter, `algorithm mismatch for key id "%s": %s != %s`,
+			key.Id, header.Algorithm, key.Algorithm,
+		)
+	}
+	aead, err := key.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := aead.NonceSize()
+	if len(cipherText) < headerSize+nonceSize+aead.Overhead() {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `cipherText too short`)
+	}
+	var (
+		nonce  = cipherText[headerSize : headerSize+nonceSize]
+		sealed = cipherText[headerSize+nonceSize:]
+	)
+	plainText, err := aead.Open(nil, nonce, sealed, getAdditionalData(cipherText[:headerSize], additionalData))
+	if err != nil {
+		return nil, gerror.WrapCode(gcode.CodeInvalidParameter, err, `cipherText authentication failed`)
+	}
+	return plainText, nil
+}
+
+// Rotate re-encrypts `cipherText` with the primary key if it is encrypted by another key,
+// or else it returns `cipherText` itself.
+func (k *Keyring) Rotate(cipherText []byte, additionalData ...[]byte) ([]byte, error) {
+	keyId, err := KeyId(cipherText)
+	if err != nil {
+		return nil, err
+	}
+	if primary := k.Primary(); primary != nil && primary.Id == keyId {
+		return cipherText, nil
+	}
+	plainText, err := k.Decrypt(cipherText, additionalData...)
+	if err != nil {
+		return nil, err
+	}
+	return k.Encrypt(plainText, additionalData...)
+}
+
+// KeyId returns the key id from ciphertext envelope `cipherText` without decryption,
+// which is commonly used for checking whether the ciphertext needs rotation.
+func KeyId(cipherText []byte) (string, error) {
+	header, _, err := decodeEnvelopeHeader(cipherText)
+	if err != nil {
+		return "", err
+	}
+	return header.KeyId, nil
+}
+
+// encodeEnvelopeHeader encodes and returns the bytes of envelope header.
+func encodeEnvelopeHeader(header envelopeHeader) []byte {
+	buffer := make([]byte, 0, 4+len(header.KeyId))
+	buffer = append(buffer, header.Version, header.Mode, byte(header.Algorithm), byte(len(header.KeyId)))
+	return append(buffer, header.KeyId...)
+}
+
+// decodeEnvelopeHeader decodes the envelope header from `data`, and returns the header and its size.
+func decodeEnvelopeHeader(data []byte) (header envelopeHeader, size int, err error) {
+	if len(data) < 4 {
+		return header, 0, gerror.NewCode(gcode.CodeInvalidParameter, `cipherText too short`)
+	}
+	header = envelopeHeader{
+		Version:   data[0],
+		Mode:      data[1],
+		Algorithm: Algorithm(data[2]),
+	}
+	if header.Version != envelopeVersion1 {
+		return header, 0, gerror.NewCodef(gcode.CodeNotSupported, `unsupported envelope version %d`, header.Version)
+	}
+	size = 4 + int(data[3])
+	if len(data) < size {
+		return header, 0, gerror.NewCode(gcode.CodeInvalidParameter, `cipherText too short`)
+	}
+	header.KeyId = string(data[4:size])
+	return header, size, nil
+}
+
+// getAdditionalData returns the additional data for sealing, which is the envelope header
+// followed by the custom additional data.
+func getAdditionalData(header []byte, additionalData [][]byte) []byte {
+	if len(additionalData) == 0 || len(additionalData[0]) == 0 {
+		return header
+	}
+	data := make([]byte, 0, len(header)+len(additionalData[0]))
+	data = append(data, header...)
+	return append(data, additionalData[0]...)
+}
@@ -0,0 +1,104 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gaead
+
+import (
+	"sync"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// Keyring holds multiple keys for encryption and decryption, which is concurrent safe.
+// It encrypts using the primary key, and decrypts using the key specified by the ciphertext envelope.
+//
+// The key rotation is done by adding a new key and setting it as primary, in which case the
+// ciphertexts encrypted by old keys can still be decrypted, and be re-encrypted by Rotate.
+type Keyring struct {
+	mu      sync.RWMutex
+	keys    map[string]*Key // keys is the key id to key mapping.
+	primary *Key            // primary is the key for encryption.
+}
+
+// NewKeyring creates and returns a Keyring with `primary` key for encryption,
+// and `others` keys for decryption only.
+func NewKeyring(primary *Key, others ...*Key) (*Keyring, error) {
+	k := &Keyring{
+		keys: make(map[string]*Key),
+	}
+	for _, key := range append([]*Key{primary}, others...) {
+		if err := k.Add(key); err != nil {
+			return nil, err
+		}
+	}
+	k.primary = primary
+	return k, nil
+}
+
+// Add adds `key` into the Keyring for decryption.
+// It returns error if `key` is invalid, or there's another key with the same id.
+func (k *Keyring) Add(key *Key) error {
+	if key == nil {
+		return gerror.NewCode(gcode.CodeInvalidParameter, `key should not be nil`)
+	}
+	if err := key.validate(); err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[key.Id]; ok {
+		return gerror.NewCodef(gcode.CodeInvalidParameter, `key id "%s" already exists`, key.Id)
+	}
+	k.keys[key.Id] = key
+	return nil
+}
+
+// SetPrimary sets the key of `id` as primary key for encryption.
+func (k *Keyring) SetPrimary(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return gerror.NewCodef(gcode.CodeNotFound, `key id "%s" not found`, id)
+	}
+	k.primary = key
+	return nil
+}
+
+// Remove removes the key of `id`, after which the ciphertexts encrypted by it cannot be decrypted.
+// It returns error if the key is the primary key.
+func (k *Keyring) Remove(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.primary != nil && k.primary.Id == id {
+		return gerror.NewCodef(gcode.CodeInvalidOperation, `cannot remove primary key "%s"`, id)
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+// Primary returns the primary key.
+func (k *Keyring) Primary() *Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.primary
+}
+
+// Get returns the key of `id`, or nil if it does not exist.
+func (k *Keyring) Get(id string) *Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.keys[id]
+}
+
+// getKey returns the key of `id`, or error if it does not exist.
+func (k *Keyring) getKey(id string) (*Key, error) {
+	if key := k.Get(id); key != nil {
+		return key, nil
+	}
+	return nil, gerror.NewCodef(gcode.CodeNotFound, `key id "%s" not found`, id)
+}
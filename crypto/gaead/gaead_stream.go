@@ -0,0 +1,311 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gaead
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// The streaming ciphertext envelope is in format:
+//
+//	| envelope header | chunk size(4) | nonce prefix | sealed chunk | sealed chunk | ... |
+//
+// Each chunk is sealed with nonce of the nonce prefix, chunk counter(4) and last chunk flag(1),
+// so that the reordering, truncating and appending of chunks can be detected.
+// The header before the sealed chunks is authenticated as additional data of each chunk.
+const (
+	streamChunkSize    = 64 * 1024        // streamChunkSize is the plaintext size of each chunk.
+	streamMaxChunkSize = 16 * 1024 * 1024 // streamMaxChunkSize is the max chunk size accepted in decryption.
+	streamNonceSuffix  = 5                // streamNonceSuffix is the size of chunk counter and last chunk flag in nonce.
+)
+
+// encryptWriter is the writer for streaming encryption.
+type encryptWriter struct {
+	writer    io.Writer
+	aead      cipher.AEAD
+	data      []byte // data is the additional data of each chunk.
+	nonce     []byte // nonce is the nonce prefix followed by the chunk counter and last chunk flag.
+	counter   uint32 // counter is the counter of the current chunk.
+	buffer    []byte // buffer is the plaintext of the current chunk.
+	sealed    []byte // sealed is the reusable buffer of the sealed chunk.
+	closed    bool   // closed marks the writer closed.
+	lastError error  // lastError is the last error of writing, which fails the subsequent writing.
+}
+
+// decryptReader is the reader for streaming decryption.
+type decryptReader struct {
+	reader    io.Reader
+	aead      cipher.AEAD
+	data      []byte // data is the additional data of each chunk.
+	nonce     []byte // nonce is the nonce prefix followed by the chunk counter and last chunk flag.
+	counter   uint32 // counter is the counter of the current chunk.
+	chunk     []byte // chunk is the reusable buffer of the sealed chunk.
+	plain     []byte // plain is the unread plaintext of the current chunk.
+	done      bool   // done marks the last chunk read.
+	lastError error  // lastError is the last error of reading, which fails the subsequent reading.
+}
+
+// NewEncryptWriter returns a writer which encrypts the written data with the primary key in chunks,
+// and writes the streaming ciphertext envelope to `writer`. It writes the envelope header immediately.
+//
+// Note that the returned writer must be closed to write the last chunk,
+// and closing it does not close the underlying `writer`.
+func (k *Keyring) NewEncryptWriter(writer io.Writer, additionalData ...[]byte) (io.WriteCloser, error) {
+	key := k.Primary()
+	if key == nil {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, `no primary key for encryption`)
+	}
+	aead, err := key.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		noncePrefix = make([]byte, aead.NonceSize()-streamNonceSuffix)
+		chunkSize   = make([]byte, 4)
+		header      = encodeEnvelopeHeader(envelopeHeader{
+			Version:   envelopeVersion1,
+			Mode:      envelopeModeChunk,
+			Algorithm: key.Algorithm,
+			KeyId:     key.Id,
+		})
+	)
+	if _, err = rand.Read(noncePrefix); err != nil {
+		return nil, gerror.WrapCode(gcode.CodeInternalError, err, `generate nonce failed`)
+	}
+	binary.BigEndian.PutUint32(chunkSize, streamChunkSize)
+	header = append(header, chunkSize...)
+	header = append(header, noncePrefix...)
+	if _, err = writer.Write(header); err != nil {
+		return nil, gerror.Wrap(err, `write envelope header failed`)
+	}
+	return &encryptWriter{
+		writer: writer,
+		aead:   aead,
+		data:   getAdditionalData(header, additionalData),
+		nonce:  append(noncePrefix, make([]byte, streamNonceSuffix)...),
+		buffer: make([]byte, 0, streamChunkSize),
+	}, nil
+}
+
+// NewDecryptReader returns a reader which reads the streaming ciphertext envelope from `reader`,
+// and decrypts it using the key specified by the envelope. It reads the envelope header immediately.
+//
+// The reader returns error if any chunk fails authentication, or the stream is truncated.
+// Note that the data read before the error is authenticated, but might be partial.
+func (k *Keyring) NewDecryptReader(reader io.Reader, additionalData ...[]byte) (io.Reader, error) {
+	header := make([]byte, 4, 64)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, gerror.Wrap(err, `read envelope header failed`)
+	}
+	header = append(header, make([]byte, header[3])...)
+	if _, err := io.ReadFull(reader, header[4:]); err != nil {
+		return nil, gerror.Wrap(err, `read envelope header failed`)
+	}
+	envelope, _, err := decodeEnvelopeHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Mode != envelopeModeChunk {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `ciphertext is not streaming envelope`)
+	}
+	key, err := k.getKey(envelope.KeyId)
+	if err != nil {
+		return nil, err
+	}
+	if key.Algorithm != envelope.Algorithm {
+		return nil, gerror.NewCodef(
+			gcode.CodeInvalidParameter, `algorithm mismatch for key id "%s": %s != %s`,
+			key.Id, envelope.Algorithm, key.Algorithm,
+		)
+	}
+	aead, err := key.newAEAD()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		headerSize  = len(header)
+		prefixSize  = aead.NonceSize() - streamNonceSuffix
+		noncePrefix []byte
+	)
+	header = append(header, make([]byte, 4+prefixSize)...)
+	if _, err = io.ReadFull(reader, header[headerSize:]); err != nil {
+		return nil, gerror.Wrap(err, `read envelope header failed`)
+	}
+	chunkSize := binary.BigEndian.Uint32(header[headerSize : headerSize+4])
+	if chunkSize == 0 || chunkSize > streamMaxChunkSize {
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid chunk size %d`, chunkSize)
+	}
+	noncePrefix = append(noncePrefix, header[headerSize+4:]...)
+	return &decryptReader{
+		reader: reader,
+		aead:   aead,
+		data:   getAdditionalData(header, additionalData),
+		nonce:  append(noncePrefix, make([]byte, streamNonceSuffix)...),
+		chunk:  make([]byte, int(chunkSize)+aead.Overhead()),
+	}, nil
+}
+
+// EncryptStream encrypts all data from `src` with the primary key, and writes the streaming ciphertext
+// envelope to `dst`, which is commonly used for large files.
+func (k *Keyring) EncryptStream(dst io.Writer, src io.Reader, additionalData ...[]byte) error {
+	writer, err := k.NewEncryptWriter(dst, additionalData...)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(writer, src); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// DecryptStream decrypts the streaming ciphertext envelope from `src`, and writes the plaintext to `dst`.
+func (k *Keyring) DecryptStream(dst io.Writer, src io.Reader, additionalData ...[]byte) error {
+	reader, err := k.NewDecryptReader(src, additionalData...)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, reader)
+	return err
+}
+
+// Write implements interface io.Writer.
+func (w *encryptWriter) Write(p []byte) (n int, err error) {
+	if w.lastError != nil {
+		return 0, w.lastError
+	}
+	if w.closed {
+		return 0, gerror.NewCode(gcode.CodeInvalidOperation, `write to closed encrypt writer`)
+	}
+	for len(p) > 0 {
+		// The full chunk is sealed only if there's more data, as the last chunk is sealed in closing.
+		if len(w.buffer) == cap(w.buffer) {
+			if err = w.seal(false); err != nil {
+				w.lastError = err
+				return n, err
+			}
+		}
+		size := copy(w.buffer[len(w.buffer):cap(w.buffer)], p)
+		w.buffer = w.buffer[:len(w.buffer)+size]
+		p = p[size:]
+		n += size
+	}
+	return n, nil
+}
+
+// Close seals and writes the last chunk, which does not close the underlying writer.
+func (w *encryptWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	if w.lastError != nil {
+		return w.lastError
+	}
+	w.closed = true
+	return w.seal(true)
+}
+
+// seal seals the current chunk and writes it to the underlying writer.
+func (w *encryptWriter) seal(last bool) error {
+	// The nonce must not be reused, so the counter must not overflow.
+	if w.counter == math.MaxUint32 && !last {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `too many chunks in stream`)
+	}
+	setChunkNonce(w.nonce, w.counter, last)
+	w.sealed = w.aead.Seal(w.sealed[:0], w.nonce, w.buffer, w.data)
+	if _, err := w.writer.Write(w.sealed); err != nil {
+		return gerror.Wrap(err, `write sealed chunk failed`)
+	}
+	w.counter++
+	w.buffer = w.buffer[:0]
+	return nil
+}
+
+// Read implements interface io.Reader.
+func (r *decryptReader) Read(p []byte) (n int, err error) {
+	for len(r.plain) == 0 {
+		if r.lastError != nil {
+			return 0, r.lastError
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		if r.lastError = r.open(); r.lastError != nil && len(r.plain) == 0 {
+			return 0, r.lastError
+		}
+	}
+	n = copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// open reads and opens the next chunk.
+func (r *decryptReader) open() error {
+	size, err := io.ReadFull(r.reader, r.chunk)
+	switch err {
+	case nil:
+		// The full chunk might be the last chunk.
+		if plain, openErr := r.openChunk(r.chunk, false); openErr == nil {
+			r.plain = plain
+			return nil
+		}
+		plain, openErr := r.openChunk(r.chunk, true)
+		if openErr != nil {
+			return openErr
+		}
+		r.plain = plain
+		r.done = true
+		// There should be no data after the last chunk.
+		if n, _ := r.reader.Read(make([]byte, 1)); n > 0 {
+			return gerror.NewCode(gcode.CodeInvalidParameter, `unexpected data after the last chunk`)
+		}
+		return nil
+
+	case io.ErrUnexpectedEOF:
+		plain, openErr := r.openChunk(r.chunk[:size], true)
+		if openErr != nil {
+			return openErr
+		}
+		r.plain = plain
+		r.done = true
+		return nil
+
+	case io.EOF:
+		return gerror.NewCode(gcode.CodeInvalidParameter, `stream is truncated, the last chunk is missing`)
+
+	default:
+		return gerror.Wrap(err, `read sealed chunk failed`)
+	}
+}
+
+// openChunk opens the sealed chunk `chunk` with the current counter.
+func (r *decryptReader) openChunk(chunk []byte, last bool) ([]byte, error) {
+	setChunkNonce(r.nonce, r.counter, last)
+	plain, err := r.aead.Open(nil, r.nonce, chunk, r.data)
+	if err != nil {
+		return nil, gerror.WrapCodef(gcode.CodeInvalidParameter, err, `chunk %d authentication failed`, r.counter)
+	}
+	r.counter++
+	return plain, nil
+}
+
+// setChunkNonce sets the chunk counter and last chunk flag into the suffix of `nonce`.
+func setChunkNonce(nonce []byte, counter uint32, last bool) {
+	suffix := nonce[len(nonce)-streamNonceSuffix:]
+	binary.BigEndian.PutUint32(suffix, counter)
+	if last {
+		suffix[4] = 1
+	} else {
+		suffix[4] = 0
+	}
+}
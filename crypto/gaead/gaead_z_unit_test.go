@@ -0,0 +1,187 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gaead_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/ximplez-go/gf/crypto/gaead"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+var (
+	content        = []byte("GoFrame is a modular, powerful, high-performance and enterprise-class application development framework of Golang.")
+	additionalData = []byte("additional data")
+)
+
+func Test_Encrypt(t *testing.T) {
+	for _, algorithm := range []gaead.Algorithm{gaead.AlgorithmAesGcm, gaead.AlgorithmXChaCha20Poly1305} {
+		gtest.C(t, func(t *gtest.T) {
+			key, err := gaead.GenerateKey("v1", algorithm)
+			t.AssertNil(err)
+			keyring, err := gaead.NewKeyring(key)
+			t.AssertNil(err)
+
+			cipherText, err := keyring.Encrypt(content)
+			t.AssertNil(err)
+			plainText, err := keyring.Decrypt(cipherText)
+			t.AssertNil(err)
+			t.Assert(plainText, content)
+			keyId, err := gaead.KeyId(cipherText)
+			t.AssertNil(err)
+			t.Assert(keyId, "v1")
+
+			// Random nonce.
+			cipherText2, err := keyring.Encrypt(content)
+			t.AssertNil(err)
+			t.AssertNE(cipherText, cipherText2)
+
+			// Additional data.
+			cipherText, err = keyring.Encrypt(content, additionalData)
+			t.AssertNil(err)
+			plainText, err = keyring.Decrypt(cipherText, additionalData)
+			t.AssertNil(err)
+			t.Assert(plainText, content)
+			_, err = keyring.Decrypt(cipherText)
+			t.AssertNE(err, nil)
+
+			// Tampering.
+			tampered := bytes.Clone(cipherText)
+			tampered[len(tampered)-1] ^= 1
+			_, err = keyring.Decrypt(tampered, additionalData)
+			t.AssertNE(err, nil)
+			_, err = keyring.Decrypt(cipherText[:10], additionalData)
+			t.AssertNE(err, nil)
+		})
+	}
+}
+
+func Test_Keyring_Rotation(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		key1, err := gaead.GenerateKey("v1", gaead.AlgorithmAesGcm)
+		t.AssertNil(err)
+		key2, err := gaead.GenerateKey("v2", gaead.AlgorithmXChaCha20Poly1305)
+		t.AssertNil(err)
+		keyring, err := gaead.NewKeyring(key1)
+		t.AssertNil(err)
+		cipherText1, err := keyring.Encrypt(content)
+		t.AssertNil(err)
+
+		// Rotate the primary key.
+		t.AssertNil(keyring.Add(key2))
+		t.AssertNil(keyring.SetPrimary("v2"))
+		t.Assert(keyring.Primary().Id, "v2")
+		cipherText2, err := keyring.Encrypt(content)
+		t.AssertNil(err)
+		keyId, _ := gaead.KeyId(cipherText2)
+		t.Assert(keyId, "v2")
+		plainText, err := keyring.Decrypt(cipherText1)
+		t.AssertNil(err)
+		t.Assert(plainText, content)
+
+		// Re-encrypt with the primary key.
+		rotated, err := keyring.Rotate(cipherText1)
+		t.AssertNil(err)
+		keyId, _ = gaead.KeyId(rotated)
+		t.Assert(keyId, "v2")
+		unchanged, err := keyring.Rotate(cipherText2)
+		t.AssertNil(err)
+		t.Assert(unchanged, cipherText2)
+
+		// Remove the old key.
+		t.Assert(gerror.Code(keyring.Remove("v2")), gcode.CodeInvalidOperation)
+		t.AssertNil(keyring.Remove("v1"))
+		_, err = keyring.Decrypt(cipherText1)
+		t.Assert(gerror.Code(err), gcode.CodeNotFound)
+		plainText, err = keyring.Decrypt(rotated)
+		t.AssertNil(err)
+		t.Assert(plainText, content)
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		key, err := gaead.GenerateKey("v1", gaead.AlgorithmAesGcm)
+		t.AssertNil(err)
+		_, err = gaead.NewKeyring(key, key)
+		t.AssertNE(err, nil)
+		_, err = gaead.NewKeyring(&gaead.Key{Id: "v2", Algorithm: gaead.AlgorithmXChaCha20Poly1305, Secret: []byte("123")})
+		t.Assert(gerror.Code(err), gcode.CodeInvalidParameter)
+		_, err = gaead.NewKeyring(&gaead.Key{Id: "v3", Algorithm: 100, Secret: key.Secret})
+		t.Assert(gerror.Code(err), gcode.CodeNotSupported)
+		_, err = gaead.GenerateKey("", gaead.AlgorithmAesGcm)
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Stream(t *testing.T) {
+	var (
+		sizes = []int{0, 1, 64*1024 - 1, 64 * 1024, 64*1024 + 1, 3*64*1024 + 100}
+	)
+	for _, algorithm := range []gaead.Algorithm{gaead.AlgorithmAesGcm, gaead.AlgorithmXChaCha20Poly1305} {
+		gtest.C(t, func(t *gtest.T) {
+			key, err := gaead.GenerateKey("v1", algorithm)
+			t.AssertNil(err)
+			keyring, err := gaead.NewKeyring(key)
+			t.AssertNil(err)
+			for _, size := range sizes {
+				data := make([]byte, size)
+				_, err = rand.Read(data)
+				t.AssertNil(err)
+
+				var encrypted, decrypted bytes.Buffer
+				t.AssertNil(keyring.EncryptStream(&encrypted, bytes.NewReader(data), additionalData))
+				sealed := encrypted.Bytes()
+				t.AssertNil(keyring.DecryptStream(&decrypted, bytes.NewReader(sealed), additionalData))
+				t.Assert(decrypted.Len(), size)
+				t.Assert(bytes.Equal(decrypted.Bytes(), data), true)
+
+				// The block envelope decryption refuses streaming envelope.
+				_, err = keyring.Decrypt(sealed, additionalData)
+				t.AssertNE(err, nil)
+				// Wrong additional data.
+				t.AssertNE(keyring.DecryptStream(io.Discard, bytes.NewReader(sealed)), nil)
+				// Truncated.
+				t.AssertNE(keyring.DecryptStream(io.Discard, bytes.NewReader(sealed[:len(sealed)-1]), additionalData), nil)
+				// Appended.
+				t.AssertNE(keyring.DecryptStream(io.Discard, bytes.NewReader(append(bytes.Clone(sealed), 0)), additionalData), nil)
+			}
+		})
+	}
+}
+
+func Test_Stream_Truncated(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		key, err := gaead.GenerateKey("v1", gaead.AlgorithmAesGcm)
+		t.AssertNil(err)
+		keyring, err := gaead.NewKeyring(key)
+		t.AssertNil(err)
+
+		var (
+			data      = make([]byte, 2*64*1024+1)
+			encrypted bytes.Buffer
+		)
+		t.AssertNil(keyring.EncryptStream(&encrypted, bytes.NewReader(data)))
+		var (
+			sealed     = encrypted.Bytes()
+			chunkSize  = 64*1024 + 16
+			headerSize = len(sealed) - 2*chunkSize - (1 + 16)
+		)
+		// Dropping the last chunk at the chunk boundary is detected.
+		err = keyring.DecryptStream(io.Discard, bytes.NewReader(sealed[:headerSize+2*chunkSize]))
+		t.AssertNE(err, nil)
+		// Reordering chunks is detected.
+		reordered := bytes.Clone(sealed)
+		copy(reordered[headerSize:], sealed[headerSize+chunkSize:headerSize+2*chunkSize])
+		copy(reordered[headerSize+chunkSize:], sealed[headerSize:headerSize+chunkSize])
+		err = keyring.DecryptStream(io.Discard, bytes.NewReader(reordered))
+		t.AssertNE(err, nil)
+	})
+}
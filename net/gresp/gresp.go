@@ -0,0 +1,159 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gresp provides a small client for servers speaking RESP (REdis Serialization Protocol),
+// like Redis and its compatible key-value stores.
+package gresp
+
+import (
+	"context"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gpool"
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/net/gtcp"
+)
+
+// Client is a RESP client with connection pool.
+type Client struct {
+	config Config      // Client configuration.
+	pool   *gpool.Pool // Pool for reusable connections.
+}
+
+// Config is the configuration for Client.
+type Config struct {
+	Address     string        `json:"address"`     // Server address in "host:port" format.
+	User        string        `json:"user"`        // Username for AUTH, which is available since Redis 6.0.
+	Pass        string        `json:"pass"`        // Password for AUTH.
+	Db          int           `json:"db"`          // Database index for SELECT.
+	Timeout     time.Duration `json:"timeout"`     // Timeout for dialing and each command if the context has no deadline.
+	IdleTimeout time.Duration `json:"idleTimeout"` // Maximum idle duration for connections in the pool.
+}
+
+const (
+	defaultTimeout     = 10 * time.Second // Default timeout for dialing and commands.
+	defaultIdleTimeout = 60 * time.Second // Default idle timeout for pooled connections.
+)
+
+// New creates and returns a RESP client with given configuration.
+func New(config Config) *Client {
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = defaultIdleTimeout
+	}
+	c := &Client{
+		config: config,
+	}
+	c.pool = gpool.New(config.IdleTimeout, c.newConn, func(v interface{}) {
+		_ = v.(*gtcp.Conn).Close()
+	})
+	return c
+}
+
+// Do sends the command with its arguments to server and returns the reply.
+//
+// The arguments are sent in their string form except []byte, which is sent as it is.
+// The reply of bulk string is returned as string, integer as int64, array as []interface{},
+// and null reply as nil value. The error reply of server is returned as error.
+func (c *Client) Do(ctx context.Context, command string, args ...interface{}) (*gvar.Var, error) {
+	v, err := c.pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	conn := v.(*gtcp.Conn)
+	reply, err := c.doWithConn(ctx, conn, command, args...)
+	if err != nil {
+		// The connection might be broken, it is not reused.
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = c.pool.Put(conn)
+	if e, ok := reply.(Error); ok {
+		return nil, gerror.NewCodef(gcode.CodeOperationFailed, `%s: %s`, command, e)
+	}
+	return gvar.New(convertReply(reply)), nil
+}
+
+// Close closes the client and all its pooled connections.
+func (c *Client) Close(ctx context.Context) error {
+	c.pool.Close()
+	c.pool.Clear()
+	return nil
+}
+
+// newConn creates a connection and initializes it with authentication and database selection.
+func (c *Client) newConn() (interface{}, error) {
+	conn, err := gtcp.NewConn(c.config.Address, c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ctx      = context.Background()
+		commands = make([][]interface{}, 0)
+	)
+	if c.config.Pass != "" {
+		if c.config.User != "" {
+			commands = append(commands, []interface{}{"AUTH", c.config.User, c.config.Pass})
+		} else {
+			commands = append(commands, []interface{}{"AUTH", c.config.Pass})
+		}
+	}
+	if c.config.Db > 0 {
+		commands = append(commands, []interface{}{"SELECT", c.config.Db})
+	}
+	for _, command := range commands {
+		reply, err := c.doWithConn(ctx, conn, command[0].(string), command[1:]...)
+		if err == nil {
+			if e, ok := reply.(Error); ok {
+				err = gerror.NewCodef(gcode.CodeOperationFailed, `%s: %s`, command[0], e)
+			}
+		}
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// doWithConn sends command on given connection and reads its reply.
+func (c *Client) doWithConn(ctx context.Context, conn *gtcp.Conn, command string, args ...interface{}) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.config.Timeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.SetDeadline(time.Time{})
+	}()
+	if err := conn.Send(EncodeCommand(command, args...)); err != nil {
+		return nil, err
+	}
+	return ReadValue(conn)
+}
+
+// convertReply converts the RESP value to commonly used types.
+func convertReply(reply interface{}) interface{} {
+	switch v := reply.(type) {
+	case []byte:
+		return string(v)
+	case SimpleString:
+		return string(v)
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertReply(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
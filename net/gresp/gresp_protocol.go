@@ -0,0 +1,182 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gresp
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/net/gtcp"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+// Reader is the connection interface that RESP values are read from,
+// which is implemented by both gtcp.Conn and gtcp.PoolConn.
+type Reader interface {
+	// Recv receives and returns data of `length` from the connection.
+	Recv(length int, retry ...gtcp.Retry) ([]byte, error)
+
+	// RecvLine reads data from the connection until reads char '\n'.
+	RecvLine(retry ...gtcp.Retry) ([]byte, error)
+}
+
+// SimpleString is the RESP simple string type, like `+OK`.
+type SimpleString string
+
+// Error is the RESP error type, like `-ERR unknown command`.
+type Error string
+
+// Error implements the interface error.
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	typeSimpleString = '+'
+	typeError        = '-'
+	typeInteger      = ':'
+	typeBulkString   = '$'
+	typeArray        = '*'
+)
+
+// ReadValue reads and returns one RESP value from `reader`.
+//
+// The returned value is one of the types:
+// nil for null bulk string or null array,
+// SimpleString for simple string, Error for error, int64 for integer,
+// []byte for bulk string and []interface{} for array.
+func ReadValue(reader Reader) (interface{}, error) {
+	line, err := reader.RecvLine()
+	if err != nil {
+		return nil, err
+	}
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 {
+		return nil, gerror.NewCode(gcode.CodeInvalidRequest, `invalid RESP data: empty line`)
+	}
+	content := string(line[1:])
+	switch line[0] {
+	case typeSimpleString:
+		return SimpleString(content), nil
+
+	case typeError:
+		return Error(content), nil
+
+	case typeInteger:
+		n, err := strconv.ParseInt(content, 10, 64)
+		if err != nil {
+			return nil, gerror.WrapCodef(gcode.CodeInvalidRequest, err, `invalid RESP integer "%s"`, content)
+		}
+		return n, nil
+
+	case typeBulkString:
+		length, err := strconv.Atoi(content)
+		if err != nil {
+			return nil, gerror.WrapCodef(gcode.CodeInvalidRequest, err, `invalid RESP bulk length "%s"`, content)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		// The bulk data is ended with "\r\n".
+		data, err := reader.Recv(length + 2)
+		if err != nil {
+			return nil, err
+		}
+		return data[:length], nil
+
+	case typeArray:
+		length, err := strconv.Atoi(content)
+		if err != nil {
+			return nil, gerror.WrapCodef(gcode.CodeInvalidRequest, err, `invalid RESP array length "%s"`, content)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		array := make([]interface{}, length)
+		for i := 0; i < length; i++ {
+			if array[i], err = ReadValue(reader); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+
+	default:
+		return nil, gerror.NewCodef(gcode.CodeInvalidRequest, `invalid RESP type "%c"`, line[0])
+	}
+}
+
+// EncodeCommand encodes the command and its arguments as a RESP array of bulk strings,
+// which is the format that clients send commands to server.
+func EncodeCommand(command string, args ...interface{}) []byte {
+	var buffer = bytes.NewBuffer(nil)
+	buffer.WriteByte(typeArray)
+	buffer.WriteString(strconv.Itoa(len(args) + 1))
+	buffer.WriteString("\r\n")
+	writeBulk(buffer, []byte(command))
+	for _, arg := range args {
+		writeBulk(buffer, toBytes(arg))
+	}
+	return buffer.Bytes()
+}
+
+// EncodeValue encodes `value` in RESP format, which is usually used for server replies.
+//
+// The nil is encoded as null bulk string, SimpleString as simple string, error as error,
+// integers as integer, slice as array and any other types as bulk string.
+func EncodeValue(value interface{}) []byte {
+	var buffer = bytes.NewBuffer(nil)
+	writeValue(buffer, value)
+	return buffer.Bytes()
+}
+
+func writeValue(buffer *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteString("$-1\r\n")
+	case SimpleString:
+		buffer.WriteByte(typeSimpleString)
+		buffer.WriteString(string(v))
+		buffer.WriteString("\r\n")
+	case error:
+		buffer.WriteByte(typeError)
+		buffer.WriteString(v.Error())
+		buffer.WriteString("\r\n")
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		buffer.WriteByte(typeInteger)
+		buffer.WriteString(gconv.String(v))
+		buffer.WriteString("\r\n")
+	case []interface{}:
+		buffer.WriteByte(typeArray)
+		buffer.WriteString(strconv.Itoa(len(v)))
+		buffer.WriteString("\r\n")
+		for _, item := range v {
+			writeValue(buffer, item)
+		}
+	case []string:
+		writeValue(buffer, gconv.Interfaces(v))
+	default:
+		writeBulk(buffer, toBytes(v))
+	}
+}
+
+func writeBulk(buffer *bytes.Buffer, data []byte) {
+	buffer.WriteByte(typeBulkString)
+	buffer.WriteString(strconv.Itoa(len(data)))
+	buffer.WriteString("\r\n")
+	buffer.Write(data)
+	buffer.WriteString("\r\n")
+}
+
+// toBytes converts the argument to bytes in its string form.
+func toBytes(value interface{}) []byte {
+	if b, ok := value.([]byte); ok {
+		return b
+	}
+	return []byte(gconv.String(value))
+}
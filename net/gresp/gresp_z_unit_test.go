@@ -0,0 +1,155 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gresp_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/net/gresp"
+	"github.com/ximplez-go/gf/net/gtcp"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+var ctx = context.Background()
+
+// newTestServer starts a RESP server which supports only a few commands for testing.
+func newTestServer(pass string) *gtcp.Server {
+	s := gtcp.NewServer(gtcp.FreePortAddress, func(conn *gtcp.Conn) {
+		defer conn.Close()
+		var (
+			authed  = pass == ""
+			counter = gtype.NewInt64()
+		)
+		for {
+			value, err := gresp.ReadValue(conn)
+			if err != nil {
+				return
+			}
+			var (
+				args    = gconv.Strings(value)
+				command = strings.ToUpper(args[0])
+				reply   interface{}
+			)
+			switch {
+			case command == "AUTH":
+				if authed = args[len(args)-1] == pass; authed {
+					reply = gresp.SimpleString("OK")
+				} else {
+					reply = gresp.Error("WRONGPASS invalid password")
+				}
+			case !authed:
+				reply = gresp.Error("NOAUTH Authentication required")
+			case command == "PING":
+				reply = gresp.SimpleString("PONG")
+			case command == "ECHO":
+				reply = args[1]
+			case command == "INCR":
+				reply = counter.Add(1)
+			case command == "LIST":
+				reply = []interface{}{"a", int64(1), nil, []interface{}{"b"}}
+			case command == "NIL":
+				reply = nil
+			default:
+				reply = gresp.Error(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+			}
+			if err = conn.Send(gresp.EncodeValue(reply)); err != nil {
+				return
+			}
+		}
+	})
+	go s.Run()
+	time.Sleep(100 * time.Millisecond)
+	return s
+}
+
+func Test_Client_Do(t *testing.T) {
+	s := newTestServer("")
+	defer s.Close()
+	gtest.C(t, func(t *gtest.T) {
+		client := gresp.New(gresp.Config{
+			Address: s.GetListenedAddress(),
+		})
+		defer client.Close(ctx)
+
+		v, err := client.Do(ctx, "PING")
+		t.AssertNil(err)
+		t.Assert(v.String(), "PONG")
+
+		v, err = client.Do(ctx, "ECHO", "hello\r\nworld")
+		t.AssertNil(err)
+		t.Assert(v.String(), "hello\r\nworld")
+
+		v, err = client.Do(ctx, "ECHO", 100)
+		t.AssertNil(err)
+		t.Assert(v.Int(), 100)
+
+		v, err = client.Do(ctx, "INCR")
+		t.AssertNil(err)
+		t.Assert(v.Val(), int64(1))
+
+		v, err = client.Do(ctx, "LIST")
+		t.AssertNil(err)
+		t.Assert(v.Val(), []interface{}{"a", int64(1), nil, []interface{}{"b"}})
+
+		v, err = client.Do(ctx, "NIL")
+		t.AssertNil(err)
+		t.Assert(v.IsNil(), true)
+
+		_, err = client.Do(ctx, "UNKNOWN")
+		t.AssertNE(err, nil)
+		t.Assert(strings.Contains(err.Error(), "unknown command"), true)
+
+		// The connection is still usable after error reply.
+		v, err = client.Do(ctx, "PING")
+		t.AssertNil(err)
+		t.Assert(v.String(), "PONG")
+	})
+}
+
+func Test_Client_Auth(t *testing.T) {
+	s := newTestServer("123456")
+	defer s.Close()
+	gtest.C(t, func(t *gtest.T) {
+		client := gresp.New(gresp.Config{
+			Address: s.GetListenedAddress(),
+			Pass:    "123456",
+		})
+		defer client.Close(ctx)
+		v, err := client.Do(ctx, "PING")
+		t.AssertNil(err)
+		t.Assert(v.String(), "PONG")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		client := gresp.New(gresp.Config{
+			Address: s.GetListenedAddress(),
+			Pass:    "invalid",
+		})
+		defer client.Close(ctx)
+		_, err := client.Do(ctx, "PING")
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_EncodeCommand(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(
+			string(gresp.EncodeCommand("SET", "k", 1)),
+			"*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\n1\r\n",
+		)
+		t.Assert(string(gresp.EncodeValue(nil)), "$-1\r\n")
+		t.Assert(string(gresp.EncodeValue(gresp.SimpleString("OK"))), "+OK\r\n")
+		t.Assert(string(gresp.EncodeValue(gresp.Error("ERR"))), "-ERR\r\n")
+		t.Assert(string(gresp.EncodeValue(10)), ":10\r\n")
+		t.Assert(string(gresp.EncodeValue([]string{"a"})), "*1\r\n$1\r\na\r\n")
+	})
+}
@@ -9,6 +9,7 @@ package gtcp
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"io"
 	"net"
@@ -19,11 +20,13 @@ import (
 
 // Conn is the TCP connection object.
 type Conn struct {
-	net.Conn                     // Underlying TCP connection object.
-	reader         *bufio.Reader // Buffer reader for connection.
-	deadlineRecv   time.Time     // Timeout point for reading.
-	deadlineSend   time.Time     // Timeout point for writing.
-	bufferWaitRecv time.Duration // Interval duration for reading buffer.
+	net.Conn                       // Underlying TCP connection object.
+	reader         *bufio.Reader   // Buffer reader for connection.
+	deadlineRecv   time.Time       // Timeout point for reading.
+	deadlineSend   time.Time       // Timeout point for writing.
+	bufferWaitRecv time.Duration   // Interval duration for reading buffer.
+	ctx            context.Context // Context of the connection, which carries the connection span if instrumented.
+	instrumented   bool            // Whether the connection is accepted by server with instrumentation enabled.
 }
 
 const (
@@ -71,6 +74,16 @@ func NewConnByNetConn(conn net.Conn) *Conn {
 	}
 }
 
+// Ctx returns the context of the connection.
+// For connection accepted by server with instrumentation enabled, it carries the span of the connection,
+// or else it returns context.Background().
+func (c *Conn) Ctx() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 // Send writes data to remote address.
 func (c *Conn) Send(data []byte, retry ...Retry) error {
 	for {
@@ -7,11 +7,19 @@
 package gtcp
 
 import (
+	"context"
 	"encoding/binary"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/ximplez-go/gf/errors/gcode"
 	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/net/gtrace"
 )
 
 const (
@@ -20,6 +28,10 @@ const (
 	pkgHeaderSizeMax     // Max header size for simple package protocol.
 )
 
+const (
+	pkgCarrierHeaderSize = 2 // Header size of the trace context carrier in package.
+)
+
 // PkgOption is package option for simple protocol.
 type PkgOption struct {
 	// HeaderSize is used to mark the data length for next data receiving.
@@ -33,6 +45,11 @@ type PkgOption struct {
 
 	// Retry policy when operation fails.
 	Retry Retry
+
+	// Tracing enables the trace context propagation in the package, which should be enabled
+	// by both the sending and receiving peers, as it changes the package protocol.
+	// It also creates spans for the package sending and receiving.
+	Tracing bool
 }
 
 // SendPkg send data using simple package protocol.
@@ -42,12 +59,43 @@ type PkgOption struct {
 // Note that,
 // 1. The DataLength is the length of DataField, which does not contain the header size.
 // 2. The integer bytes of the package are encoded using BigEndian order.
+// 3. If PkgOption.Tracing is enabled, the DataField is CarrierLength(16bit)|Carrier(variant)|Data(variant),
+// in which the Carrier is the json encoded trace context of the connection.
 func (c *Conn) SendPkg(data []byte, option ...PkgOption) error {
+	return c.SendPkgCtx(c.Ctx(), data, option...)
+}
+
+// SendPkgCtx send data with context `ctx` using simple package protocol, see SendPkg.
+// If PkgOption.Tracing is enabled, the trace context of `ctx` is propagated to the remote peer.
+func (c *Conn) SendPkgCtx(ctx context.Context, data []byte, option ...PkgOption) (err error) {
 	pkgOption, err := getPkgOption(option...)
 	if err != nil {
 		return err
 	}
+	var carrier []byte
+	if c.instrumented || pkgOption.Tracing {
+		var span *gtrace.Span
+		ctx, span = gtrace.NewSpan(
+			ctx, tracingSpanNameSendPkg,
+			trace.WithSpanKind(trace.SpanKindProducer),
+			trace.WithAttributes(attribute.Int(tracingAttrKeyPackageDataSize, len(data))),
+		)
+		defer func() {
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}()
+		if pkgOption.Tracing {
+			if carrier, err = newPkgCarrier(ctx); err != nil {
+				return err
+			}
+		}
+	}
 	length := len(data)
+	if pkgOption.Tracing {
+		length += pkgCarrierHeaderSize + len(carrier)
+	}
 	if length > pkgOption.MaxDataSize {
 		return gerror.NewCodef(
 			gcode.CodeInvalidParameter,
@@ -56,9 +104,15 @@ func (c *Conn) SendPkg(data []byte, option ...PkgOption) error {
 		)
 	}
 	offset := pkgHeaderSizeMax - pkgOption.HeaderSize
-	buffer := make([]byte, pkgHeaderSizeMax+len(data))
+	buffer := make([]byte, pkgHeaderSizeMax+length)
 	binary.BigEndian.PutUint32(buffer[0:], uint32(length))
-	copy(buffer[pkgHeaderSizeMax:], data)
+	if pkgOption.Tracing {
+		binary.BigEndian.PutUint16(buffer[pkgHeaderSizeMax:], uint16(len(carrier)))
+		copy(buffer[pkgHeaderSizeMax+pkgCarrierHeaderSize:], carrier)
+		copy(buffer[pkgHeaderSizeMax+pkgCarrierHeaderSize+len(carrier):], data)
+	} else {
+		copy(buffer[pkgHeaderSizeMax:], data)
+	}
 	if pkgOption.Retry.Count > 0 {
 		return c.Send(buffer[offset:], pkgOption.Retry)
 	}
@@ -97,18 +151,42 @@ func (c *Conn) SendRecvPkgWithTimeout(data []byte, timeout time.Duration, option
 
 // RecvPkg receives data from connection using simple package protocol.
 func (c *Conn) RecvPkg(option ...PkgOption) (result []byte, err error) {
+	_, result, err = c.RecvPkgCtx(c.Ctx(), option...)
+	return
+}
+
+// RecvPkgCtx receives data with context `ctx` from connection using simple package protocol.
+// If PkgOption.Tracing is enabled, the returned context carries the trace context propagated
+// from the remote peer, or else it returns `ctx` or the context with the receiving span.
+func (c *Conn) RecvPkgCtx(ctx context.Context, option ...PkgOption) (newCtx context.Context, result []byte, err error) {
 	var (
-		buffer []byte
-		length int
+		buffer    []byte
+		length    int
+		startTime = time.Now()
 	)
 	pkgOption, err := getPkgOption(option...)
 	if err != nil {
-		return nil, err
+		return ctx, nil, err
+	}
+	if c.instrumented || pkgOption.Tracing {
+		defer func() {
+			var span *gtrace.Span
+			newCtx, span = gtrace.NewSpan(
+				newCtx, tracingSpanNameRecvPkg,
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithTimestamp(startTime),
+				trace.WithAttributes(attribute.Int(tracingAttrKeyPackageDataSize, len(result))),
+			)
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}()
 	}
 	// Header field.
 	buffer, err = c.Recv(pkgOption.HeaderSize, pkgOption.Retry)
 	if err != nil {
-		return nil, err
+		return ctx, nil, err
 	}
 	switch pkgOption.HeaderSize {
 	case 1:
@@ -124,14 +202,31 @@ func (c *Conn) RecvPkg(option ...PkgOption) (result []byte, err error) {
 	// It here validates the size of the package.
 	// It clears the buffer and returns error immediately if it validates failed.
 	if length < 0 || length > pkgOption.MaxDataSize {
-		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid package size %d`, length)
+		return ctx, nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid package size %d`, length)
 	}
 	// Empty package.
 	if length == 0 {
-		return nil, nil
+		return ctx, nil, nil
 	}
 	// Data field.
-	return c.Recv(length, pkgOption.Retry)
+	if result, err = c.Recv(length, pkgOption.Retry); err != nil || !pkgOption.Tracing {
+		return ctx, result, err
+	}
+	// Trace context carrier.
+	if len(result) < pkgCarrierHeaderSize {
+		return ctx, nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid package size %d for tracing`, length)
+	}
+	carrierLength := int(binary.BigEndian.Uint16(result))
+	if pkgCarrierHeaderSize+carrierLength > len(result) {
+		return ctx, nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid trace carrier size %d`, carrierLength)
+	}
+	if ctx, err = extractPkgCarrier(ctx, result[pkgCarrierHeaderSize:pkgCarrierHeaderSize+carrierLength]); err != nil {
+		return ctx, nil, err
+	}
+	if result = result[pkgCarrierHeaderSize+carrierLength:]; len(result) == 0 {
+		result = nil
+	}
+	return ctx, result, nil
 }
 
 // RecvPkgWithTimeout reads data from connection with timeout using simple package protocol.
@@ -185,3 +280,28 @@ func getPkgOption(option ...PkgOption) (*PkgOption, error) {
 	}
 	return &pkgOption, nil
 }
+
+// newPkgCarrier encodes and returns the trace context of `ctx` for package.
+func newPkgCarrier(ctx context.Context) ([]byte, error) {
+	gtrace.CheckSetDefaultTextMapPropagator()
+	carrier := gtrace.NewCarrier()
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	content, err := json.Marshal(carrier)
+	if err != nil {
+		return nil, gerror.Wrap(err, `marshal trace carrier failed`)
+	}
+	if len(content) > 0xFFFF {
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `trace carrier size %d exceeds max size %d`, len(content), 0xFFFF)
+	}
+	return content, nil
+}
+
+// extractPkgCarrier decodes the trace context `content` of package and returns the context carrying it.
+func extractPkgCarrier(ctx context.Context, content []byte) (context.Context, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return ctx, gerror.WrapCode(gcode.CodeInvalidParameter, err, `invalid trace carrier`)
+	}
+	gtrace.CheckSetDefaultTextMapPropagator()
+	return otel.GetTextMapPropagator().Extract(ctx, gtrace.NewCarrier(data)), nil
+}
@@ -30,11 +30,12 @@ const (
 
 // Server is a TCP server.
 type Server struct {
-	mu        sync.Mutex   // Used for Server.listen concurrent safety. -- The golang test with data race checks this.
-	listen    net.Listener // TCP address listener.
-	address   string       // Server listening address.
-	handler   func(*Conn)  // Connection handler.
-	tlsConfig *tls.Config  // TLS configuration.
+	mu           sync.Mutex   // Used for Server.listen concurrent safety. -- The golang test with data race checks this.
+	listen       net.Listener // TCP address listener.
+	address      string       // Server listening address.
+	handler      func(*Conn)  // Connection handler.
+	tlsConfig    *tls.Config  // TLS configuration.
+	instrumented bool         // Whether tracing and metrics instrumentation is enabled.
 }
 
 // Map for name to server, for singleton purpose.
@@ -114,6 +115,14 @@ func (s *Server) SetTLSConfig(tlsConfig *tls.Config) {
 	s.tlsConfig = tlsConfig
 }
 
+// EnableInstrumentation enables tracing and metrics instrumentation for the server, which should be called
+// before Run. It creates a span for each accepted connection, which is carried by Conn.Ctx, and records the
+// metrics of connections, received and sent bytes, errors and handler duration.
+// It also creates spans for the package sending and receiving of the accepted connections.
+func (s *Server) EnableInstrumentation() {
+	s.instrumented = true
+}
+
 // Close closes the listener and shutdowns the server.
 func (s *Server) Close() error {
 	s.mu.Lock()
@@ -161,7 +170,11 @@ func (s *Server) Run() (err error) {
 			err = gerror.Wrapf(err, `Listener.Accept failed`)
 			return err
 		} else if conn != nil {
-			go s.handler(NewConnByNetConn(conn))
+			if s.instrumented {
+				go s.handleConnWithInstrumentation(conn)
+			} else {
+				go s.handler(NewConnByNetConn(conn))
+			}
 		}
 	}
 }
@@ -0,0 +1,234 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ximplez-go/gf"
+	"github.com/ximplez-go/gf/net/gtrace"
+	"github.com/ximplez-go/gf/os/gmetric"
+)
+
+// localMetricManager manages the metrics of Server,
+// which are published only if instrumentation is enabled for the Server.
+type localMetricManager struct {
+	ServerConnections       gmetric.Counter
+	ServerActiveConnections gmetric.UpDownCounter
+	ServerReceivedBytes     gmetric.Counter
+	ServerSentBytes         gmetric.Counter
+	ServerErrors            gmetric.Counter
+	ServerHandlerDuration   gmetric.Histogram
+}
+
+const (
+	instrument                    = "github.com/ximplez-go/gf/net/gtcp.Server"
+	metricAttrKeyServerAddress    = "server.address"
+	metricAttrKeyErrorType        = "error.type"
+	metricErrorTypeRead           = "read"
+	metricErrorTypeWrite          = "write"
+	metricErrorTypePanic          = "panic"
+	tracingSpanNameServerConn     = "gtcp.Server.Conn"
+	tracingSpanNameSendPkg        = "gtcp.Conn.SendPkg"
+	tracingSpanNameRecvPkg        = "gtcp.Conn.RecvPkg"
+	tracingAttrKeyReceivedBytes   = "gtcp.received_bytes"
+	tracingAttrKeySentBytes       = "gtcp.sent_bytes"
+	tracingAttrKeyPackageDataSize = "gtcp.package.data_size"
+)
+
+// metricManager records the connections, traffic, errors and handler durations of instrumented servers.
+var metricManager = newMetricManager()
+
+// newMetricManager creates the connection and traffic metrics of gtcp servers.
+func newMetricManager() *localMetricManager {
+	meter := gmetric.GetGlobalProvider().Meter(gmetric.MeterOption{
+		Instrument:        instrument,
+		InstrumentVersion: gf.VERSION,
+	})
+	return &localMetricManager{
+		ServerConnections: meter.MustCounter(
+			"gtcp.server.connections",
+			gmetric.MetricOption{
+				Help: "Total number of accepted connections.",
+			},
+		),
+		ServerActiveConnections: meter.MustUpDownCounter(
+			"gtcp.server.active_connections",
+			gmetric.MetricOption{
+				Help: "Number of connections being handled.",
+			},
+		),
+		ServerReceivedBytes: meter.MustCounter(
+			"gtcp.server.received_bytes",
+			gmetric.MetricOption{
+				Help: "Total bytes received from accepted connections.",
+				Unit: "bytes",
+			},
+		),
+		ServerSentBytes: meter.MustCounter(
+			"gtcp.server.sent_bytes",
+			gmetric.MetricOption{
+				Help: "Total bytes sent to accepted connections.",
+				Unit: "bytes",
+			},
+		),
+		ServerErrors: meter.MustCounter(
+			"gtcp.server.errors",
+			gmetric.MetricOption{
+				Help: "Total number of reading, writing errors and handler panics of accepted connections.",
+			},
+		),
+		ServerHandlerDuration: meter.MustHistogram(
+			"gtcp.server.handler.duration",
+			gmetric.MetricOption{
+				Help: "Measures the duration of connection handler calls.",
+				Unit: "ms",
+				Buckets: []float64{
+					1, 5, 10, 25, 50, 75, 100, 250, 500, 750,
+					1000, 2500, 5000, 7500, 10000, 30000, 60000,
+				},
+			},
+		),
+	}
+}
+
+// newMetricOption creates and returns the metric operation option for server listening `address`.
+func (m *localMetricManager) newMetricOption(address string, attributes ...gmetric.Attribute) gmetric.Option {
+	return gmetric.Option{
+		Attributes: append(gmetric.Attributes{
+			gmetric.NewAttribute(metricAttrKeyServerAddress, address),
+		}, attributes...),
+	}
+}
+
+// handleError records the metrics for an error of type `errorType`.
+func (m *localMetricManager) handleError(ctx context.Context, address, errorType string) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	m.ServerErrors.Inc(ctx, m.newMetricOption(address, gmetric.NewAttribute(metricAttrKeyErrorType, errorType)))
+}
+
+// instrumentedNetConn wraps net.Conn for counting the received and sent bytes and errors.
+type instrumentedNetConn struct {
+	net.Conn
+	ctx           context.Context
+	address       string
+	receivedBytes atomic.Int64
+	sentBytes     atomic.Int64
+}
+
+// Read implements interface io.Reader.
+func (c *instrumentedNetConn) Read(b []byte) (n int, err error) {
+	n, err = c.Conn.Read(b)
+	if n > 0 {
+		c.receivedBytes.Add(int64(n))
+		if gmetric.IsEnabled() {
+			metricManager.ServerReceivedBytes.Add(c.ctx, float64(n), metricManager.newMetricOption(c.address))
+		}
+	}
+	if isInstrumentedError(err) {
+		metricManager.handleError(c.ctx, c.address, metricErrorTypeRead)
+	}
+	return
+}
+
+// Write implements interface io.Writer.
+func (c *instrumentedNetConn) Write(b []byte) (n int, err error) {
+	n, err = c.Conn.Write(b)
+	if n > 0 {
+		c.sentBytes.Add(int64(n))
+		if gmetric.IsEnabled() {
+			metricManager.ServerSentBytes.Add(c.ctx, float64(n), metricManager.newMetricOption(c.address))
+		}
+	}
+	if isInstrumentedError(err) {
+		metricManager.handleError(c.ctx, c.address, metricErrorTypeWrite)
+	}
+	return
+}
+
+// Unwrap returns the underlying net.Conn, eg: *net.TCPConn or *tls.Conn, which is for the handler
+// accessing features of the concrete connection type when the server is instrumented.
+func (c *instrumentedNetConn) Unwrap() net.Conn {
+	return c.Conn
+}
+
+// isInstrumentedError checks whether `err` should be recorded as error, which ignores the connection closing
+// and timeout errors, as they are commonly expected in connection reading.
+func isInstrumentedError(err error) bool {
+	return err != nil && err != io.EOF && !errors.Is(err, net.ErrClosed) && !isTimeout(err)
+}
+
+// handleConnWithInstrumentation handles the accepted connection `conn` with tracing and metrics.
+func (s *Server) handleConnWithInstrumentation(conn net.Conn) {
+	var (
+		startTime = time.Now()
+		address   = s.GetListenedAddress()
+		option    = metricManager.newMetricOption(address)
+	)
+	ctx, span := gtrace.NewSpan(
+		context.Background(),
+		tracingSpanNameServerConn,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.NetworkTransportTCP,
+			semconv.ServerAddress(address),
+			semconv.NetworkLocalAddress(conn.LocalAddr().String()),
+			semconv.NetworkPeerAddress(conn.RemoteAddr().String()),
+		),
+	)
+	instrumentedConn := &instrumentedNetConn{
+		Conn:    conn,
+		ctx:     ctx,
+		address: address,
+	}
+	if gmetric.IsEnabled() {
+		metricManager.ServerConnections.Inc(ctx, option)
+		metricManager.ServerActiveConnections.Inc(ctx, option)
+	}
+	defer func() {
+		if exception := recover(); exception != nil {
+			metricManager.handleError(ctx, address, metricErrorTypePanic)
+			span.SetStatus(codes.Error, fmt.Sprintf(`%+v`, exception))
+			s.endConnInstrumentation(ctx, span, instrumentedConn, startTime, option)
+			panic(exception)
+		}
+		s.endConnInstrumentation(ctx, span, instrumentedConn, startTime, option)
+	}()
+
+	c := NewConnByNetConn(instrumentedConn)
+	c.ctx = ctx
+	c.instrumented = true
+	s.handler(c)
+}
+
+// endConnInstrumentation records the metrics and ends the span after the connection handling.
+func (s *Server) endConnInstrumentation(
+	ctx context.Context, span *gtrace.Span, conn *instrumentedNetConn, startTime time.Time, option gmetric.Option,
+) {
+	if gmetric.IsEnabled() {
+		metricManager.ServerActiveConnections.Dec(ctx, option)
+		metricManager.ServerHandlerDuration.Record(float64(time.Since(startTime))/float64(time.Millisecond), option)
+	}
+	span.SetAttributes(
+		attribute.Int64(tracingAttrKeyReceivedBytes, conn.receivedBytes.Load()),
+		attribute.Int64(tracingAttrKeySentBytes, conn.sentBytes.Load()),
+	)
+	span.End()
+}
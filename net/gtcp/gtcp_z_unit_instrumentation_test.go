@@ -0,0 +1,132 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtcp_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ximplez-go/gf/net/gtcp"
+	"github.com/ximplez-go/gf/net/gtrace"
+	"github.com/ximplez-go/gf/os/gmetric"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+func Test_Server_Instrumentation(t *testing.T) {
+	var (
+		recorder       = tracetest.NewSpanRecorder()
+		tracerProvider = sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(recorder))
+		metricProvider = gmetric.NewPrometheusProvider()
+		traceIdChan    = make(chan string, 1)
+		unwrappedChan  = make(chan bool, 1)
+		pkgOption      = gtcp.PkgOption{Tracing: true}
+	)
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tracerProvider)
+	metricProvider.SetAsGlobal()
+
+	s := gtcp.NewServer(gtcp.FreePortAddress, func(conn *gtcp.Conn) {
+		defer conn.Close()
+		// The underlying connection is accessible by Unwrap when instrumented.
+		unwrapper, ok := conn.Conn.(interface{ Unwrap() net.Conn })
+		if ok {
+			_, ok = unwrapper.Unwrap().(*net.TCPConn)
+		}
+		unwrappedChan <- ok
+		for {
+			ctx, data, err := conn.RecvPkgCtx(conn.Ctx(), pkgOption)
+			if err != nil {
+				break
+			}
+			traceIdChan <- gtrace.GetTraceID(ctx)
+			if err = conn.SendPkgCtx(ctx, data, pkgOption); err != nil {
+				break
+			}
+		}
+	})
+	s.EnableInstrumentation()
+	go s.Run()
+	defer s.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	gtest.C(t, func(t *gtest.T) {
+		conn, err := gtcp.NewConn(s.GetListenedAddress())
+		t.AssertNil(err)
+
+		ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "client")
+		t.AssertNil(conn.SendPkgCtx(ctx, []byte("hello"), pkgOption))
+		replyCtx, result, err := conn.RecvPkgCtx(ctx, pkgOption)
+		t.AssertNil(err)
+		t.Assert(result, "hello")
+		span.End()
+		t.AssertNil(conn.Close())
+
+		// The trace context is propagated to the server and back to the client.
+		traceId := span.SpanContext().TraceID().String()
+		t.Assert(<-traceIdChan, traceId)
+		t.Assert(<-unwrappedChan, true)
+		t.Assert(trace.SpanContextFromContext(replyCtx).TraceID().String(), traceId)
+
+		time.Sleep(100 * time.Millisecond)
+		var spanCounts = make(map[string]int)
+		for _, s := range recorder.Ended() {
+			if s.SpanContext().TraceID().String() == traceId {
+				spanCounts[s.Name()]++
+			} else {
+				spanCounts[s.Name()+"@conn"]++
+			}
+		}
+		// Spans of the client and server sides.
+		t.Assert(spanCounts["gtcp.Conn.SendPkg"], 2)
+		t.Assert(spanCounts["gtcp.Conn.RecvPkg"], 2)
+		// Spans of the accepted connection and its last receiving which reads EOF.
+		t.Assert(spanCounts["gtcp.Server.Conn@conn"], 1)
+		t.Assert(spanCounts["gtcp.Conn.RecvPkg@conn"], 1)
+
+		var buffer = bytes.NewBuffer(nil)
+		t.AssertNil(metricProvider.Export(context.Background(), buffer))
+		var content = buffer.String()
+		t.Assert(gstr.Contains(content, "gtcp_server_connections{"), true)
+		t.Assert(gstr.Contains(content, "gtcp_server_active_connections{"), true)
+		t.Assert(gstr.Contains(content, "gtcp_server_received_bytes{"), true)
+		t.Assert(gstr.Contains(content, "gtcp_server_sent_bytes{"), true)
+		t.Assert(gstr.Contains(content, "gtcp_server_handler_duration_count{"), true)
+		t.Assert(gstr.Contains(content, "gtcp_server_errors{"), false)
+	})
+}
+
+func Test_Package_Tracing_InvalidCarrier(t *testing.T) {
+	s := gtcp.NewServer(gtcp.FreePortAddress, func(conn *gtcp.Conn) {
+		defer conn.Close()
+		_, _, err := conn.RecvPkgCtx(context.Background(), gtcp.PkgOption{Tracing: true})
+		if err != nil {
+			_ = conn.SendPkg([]byte(err.Error()))
+		}
+	})
+	go s.Run()
+	defer s.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	gtest.C(t, func(t *gtest.T) {
+		conn, err := gtcp.NewConn(s.GetListenedAddress())
+		t.AssertNil(err)
+		defer conn.Close()
+		// Package without trace carrier header.
+		result, err := conn.SendRecvPkg([]byte("a"))
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(string(result), "invalid package size"), true)
+	})
+}
@@ -0,0 +1,141 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtrace
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// ExporterConfig is the configuration for built-in span exporters.
+// All built-in exporters encode the spans in OTLP json format, that is, the json encoding of
+// ExportTraceServiceRequest of OpenTelemetry protocol.
+type ExporterConfig struct {
+	Type       string            `json:"type"`       // Exporter type, which is "stdout", "file" or "otlphttp".
+	Path       string            `json:"path"`       // File path for file exporter, eg: /var/log/trace/trace.json.
+	MaxSize    int64             `json:"maxSize"`    // Max size in bytes of file for file exporter, after which the file is rotated. It's 100MB in default.
+	MaxBackups int               `json:"maxBackups"` // Max backups of rotated files for file exporter. It's 10 in default.
+	Endpoint   string            `json:"endpoint"`   // OTLP/HTTP url for otlphttp exporter, eg: http://127.0.0.1:4318/v1/traces.
+	Headers    map[string]string `json:"headers"`    // Custom headers for otlphttp exporter.
+	Timeout    time.Duration     `json:"timeout"`    // Timeout of requests for otlphttp exporter. It's 10 seconds in default.
+}
+
+const (
+	ExporterTypeStdout   = "stdout"   // Exporter writing spans to stdout in json lines.
+	ExporterTypeFile     = "file"     // Exporter writing spans to rotating local files in json lines.
+	ExporterTypeOtlpHttp = "otlphttp" // Exporter posting spans to OTLP/HTTP collector in json.
+)
+
+const (
+	defaultExporterMaxSize    = 100 * 1024 * 1024
+	defaultExporterMaxBackups = 10
+	defaultExporterTimeout    = 10 * time.Second
+)
+
+// NewExporter creates and returns a built-in span exporter according to the type of `config`.
+func NewExporter(config ExporterConfig) (sdkTrace.SpanExporter, error) {
+	switch strings.ToLower(config.Type) {
+	case ExporterTypeStdout:
+		return newWriterExporter(nopWriteCloser{Writer: os.Stdout}), nil
+
+	case ExporterTypeFile:
+		if config.Path == "" {
+			return nil, gerror.NewCode(gcode.CodeInvalidConfiguration, `path is required for file exporter`)
+		}
+		if config.MaxSize <= 0 {
+			config.MaxSize = defaultExporterMaxSize
+		}
+		if config.MaxBackups <= 0 {
+			config.MaxBackups = defaultExporterMaxBackups
+		}
+		writer, err := newRotateFileWriter(config.Path, config.MaxSize, config.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		return newWriterExporter(writer), nil
+
+	case ExporterTypeOtlpHttp:
+		if config.Endpoint == "" {
+			return nil, gerror.NewCode(gcode.CodeInvalidConfiguration, `endpoint is required for otlphttp exporter`)
+		}
+		if config.Timeout <= 0 {
+			config.Timeout = defaultExporterTimeout
+		}
+		return newOtlpHttpExporter(config), nil
+
+	default:
+		return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `unsupported exporter type "%s"`, config.Type)
+	}
+}
+
+// writerExporter is the span exporter writing spans to writer in OTLP json lines,
+// each line is a batch of spans.
+type writerExporter struct {
+	mu      sync.Mutex
+	writer  io.WriteCloser
+	stopped bool
+}
+
+var (
+	// Check the implements for interface SpanExporter.
+	_ sdkTrace.SpanExporter = (*writerExporter)(nil)
+)
+
+// newWriterExporter creates and returns a writerExporter writing to `writer`.
+func newWriterExporter(writer io.WriteCloser) *writerExporter {
+	return &writerExporter{
+		writer: writer,
+	}
+}
+
+// ExportSpans implements interface SpanExporter.
+func (e *writerExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	content, err := marshalOtlpJson(spans)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return nil
+	}
+	_, err = e.writer.Write(append(content, '\n'))
+	return err
+}
+
+// Shutdown implements interface SpanExporter.
+func (e *writerExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return nil
+	}
+	e.stopped = true
+	return e.writer.Close()
+}
+
+// nopWriteCloser wraps io.Writer with no-op Close, which is used for stdout.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close implements interface io.Closer.
+func (nopWriteCloser) Close() error {
+	return nil
+}
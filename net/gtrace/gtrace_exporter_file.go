@@ -0,0 +1,106 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtrace
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gfile"
+)
+
+// rotateFileWriter is the file writer rotating the file by size, the rotated files are
+// renamed with numeric suffix, the larger number the older, like:
+// trace.json -> trace.json.1 -> trace.json.2 -> ... -> trace.json.{maxBackups}.
+// It is not concurrent safe, which is protected by writerExporter.
+type rotateFileWriter struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// newRotateFileWriter creates and returns a rotateFileWriter, which opens or creates the file of `path`.
+func newRotateFileWriter(path string, maxSize int64, maxBackups int) (*rotateFileWriter, error) {
+	w := &rotateFileWriter{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write implements interface io.Writer.
+// It rotates the file before writing if the file size would exceed the max size.
+func (w *rotateFileWriter) Write(p []byte) (n int, err error) {
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err = w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err = w.file.Write(p)
+	w.size += int64(n)
+	if err != nil {
+		err = gerror.Wrapf(err, `write trace file "%s" failed`, w.path)
+	}
+	return
+}
+
+// Close implements interface io.Closer.
+func (w *rotateFileWriter) Close() error {
+	return w.file.Close()
+}
+
+// open opens or creates the file for appending.
+func (w *rotateFileWriter) open() error {
+	if dir := gfile.Dir(w.path); !gfile.Exists(dir) {
+		if err := gfile.Mkdir(dir); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return gerror.Wrapf(err, `open trace file "%s" failed`, w.path)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return gerror.Wrapf(err, `stat trace file "%s" failed`, w.path)
+	}
+	w.file = file
+	w.size = stat.Size()
+	return nil
+}
+
+// rotate closes current file, shifts the backups and reopens a new file.
+func (w *rotateFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return gerror.Wrapf(err, `close trace file "%s" failed`, w.path)
+	}
+	_ = os.Remove(w.backupPath(w.maxBackups))
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if gfile.Exists(w.backupPath(i)) {
+			if err := os.Rename(w.backupPath(i), w.backupPath(i+1)); err != nil {
+				return gerror.Wrapf(err, `rotate trace file "%s" failed`, w.backupPath(i))
+			}
+		}
+	}
+	if err := os.Rename(w.path, w.backupPath(1)); err != nil {
+		return gerror.Wrapf(err, `rotate trace file "%s" failed`, w.path)
+	}
+	return w.open()
+}
+
+// backupPath returns the path of backup file with `index`.
+func (w *rotateFileWriter) backupPath(index int) string {
+	return fmt.Sprintf(`%s.%d`, w.path, index)
+}
@@ -0,0 +1,76 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtrace
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// otlpHttpExporter is the span exporter posting spans to OTLP/HTTP collector in json encoding.
+// The exporting is treated as failure if the response status code is not 2xx.
+type otlpHttpExporter struct {
+	config ExporterConfig
+	client *http.Client
+}
+
+var (
+	// Check the implements for interface SpanExporter.
+	_ sdkTrace.SpanExporter = (*otlpHttpExporter)(nil)
+)
+
+// newOtlpHttpExporter creates and returns an otlpHttpExporter with `config`.
+func newOtlpHttpExporter(config ExporterConfig) *otlpHttpExporter {
+	return &otlpHttpExporter{
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// ExportSpans implements interface SpanExporter.
+func (e *otlpHttpExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	content, err := marshalOtlpJson(spans)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint, bytes.NewReader(content))
+	if err != nil {
+		return gerror.WrapCodef(gcode.CodeInvalidConfiguration, err, `invalid otlphttp endpoint "%s"`, e.config.Endpoint)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range e.config.Headers {
+		request.Header.Set(key, value)
+	}
+	response, err := e.client.Do(request)
+	if err != nil {
+		return gerror.Wrapf(err, `export spans to "%s" failed`, e.config.Endpoint)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return gerror.Newf(`export spans to "%s" failed with status: %s`, e.config.Endpoint, response.Status)
+	}
+	return nil
+}
+
+// Shutdown implements interface SpanExporter.
+func (e *otlpHttpExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
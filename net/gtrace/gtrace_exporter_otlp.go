@@ -0,0 +1,268 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtrace
+
+import (
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/ximplez-go/gf/internal/json"
+)
+
+// The OTLP json encoding of spans, see:
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+// Note that the trace id and span id are hex encoded, and the 64-bit integers are encoded as strings.
+
+type otlpTracesData struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource      `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+	SchemaUrl  string            `json:"schemaUrl,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope     otlpScope   `json:"scope"`
+	Spans     []*otlpSpan `json:"spans"`
+	SchemaUrl string      `json:"schemaUrl,omitempty"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceId                string         `json:"traceId"`
+	SpanId                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	ParentSpanId           string         `json:"parentSpanId,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+	Name                   string         `json:"name"`
+	Kind                   int            `json:"kind"`
+	StartTimeUnixNano      string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano        string         `json:"endTimeUnixNano"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Events                 []otlpEvent    `json:"events,omitempty"`
+	DroppedEventsCount     int            `json:"droppedEventsCount,omitempty"`
+	Links                  []otlpLink     `json:"links,omitempty"`
+	DroppedLinksCount      int            `json:"droppedLinksCount,omitempty"`
+	Status                 otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano           string         `json:"timeUnixNano"`
+	Name                   string         `json:"name"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpLink struct {
+	TraceId                string         `json:"traceId"`
+	SpanId                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+const (
+	otlpStatusCodeOk    = 1
+	otlpStatusCodeError = 2
+)
+
+// marshalOtlpJson encodes `spans` to OTLP json, in which the spans are grouped by resource and
+// instrumentation scope.
+func marshalOtlpJson(spans []sdkTrace.ReadOnlySpan) ([]byte, error) {
+	var (
+		data              = &otlpTracesData{}
+		resourceSpansMap  = make(map[attribute.Distinct]*otlpResourceSpans)
+		scopeSpansMapping = make(map[*otlpResourceSpans]map[string]*otlpScopeSpans)
+	)
+	for _, span := range spans {
+		var (
+			resource    = span.Resource()
+			resourceKey attribute.Distinct
+		)
+		if resource != nil {
+			resourceKey = resource.Equivalent()
+		}
+		resourceSpans, ok := resourceSpansMap[resourceKey]
+		if !ok {
+			resourceSpans = &otlpResourceSpans{}
+			if resource != nil {
+				resourceSpans.Resource.Attributes = newOtlpKeyValues(resource.Attributes())
+				resourceSpans.SchemaUrl = resource.SchemaURL()
+			}
+			resourceSpansMap[resourceKey] = resourceSpans
+			scopeSpansMapping[resourceSpans] = make(map[string]*otlpScopeSpans)
+			data.ResourceSpans = append(data.ResourceSpans, resourceSpans)
+		}
+		var (
+			scope    = span.InstrumentationScope()
+			scopeKey = scope.Name + "@" + scope.Version + "@" + scope.SchemaURL
+		)
+		scopeSpans, ok := scopeSpansMapping[resourceSpans][scopeKey]
+		if !ok {
+			scopeSpans = &otlpScopeSpans{
+				Scope: otlpScope{
+					Name:    scope.Name,
+					Version: scope.Version,
+				},
+				SchemaUrl: scope.SchemaURL,
+			}
+			scopeSpansMapping[resourceSpans][scopeKey] = scopeSpans
+			resourceSpans.ScopeSpans = append(resourceSpans.ScopeSpans, scopeSpans)
+		}
+		scopeSpans.Spans = append(scopeSpans.Spans, newOtlpSpan(span))
+	}
+	return json.Marshal(data)
+}
+
+// newOtlpSpan converts `span` to otlpSpan.
+func newOtlpSpan(span sdkTrace.ReadOnlySpan) *otlpSpan {
+	var (
+		spanContext = span.SpanContext()
+		output      = &otlpSpan{
+			TraceId:                spanContext.TraceID().String(),
+			SpanId:                 spanContext.SpanID().String(),
+			TraceState:             spanContext.TraceState().String(),
+			Flags:                  uint32(spanContext.TraceFlags()),
+			Name:                   span.Name(),
+			Kind:                   int(span.SpanKind()),
+			StartTimeUnixNano:      newOtlpTime(span.StartTime()),
+			EndTimeUnixNano:        newOtlpTime(span.EndTime()),
+			Attributes:             newOtlpKeyValues(span.Attributes()),
+			DroppedAttributesCount: span.DroppedAttributes(),
+			DroppedEventsCount:     span.DroppedEvents(),
+			DroppedLinksCount:      span.DroppedLinks(),
+		}
+	)
+	if parent := span.Parent(); parent.SpanID().IsValid() {
+		output.ParentSpanId = parent.SpanID().String()
+	}
+	for _, event := range span.Events() {
+		output.Events = append(output.Events, otlpEvent{
+			TimeUnixNano:           newOtlpTime(event.Time),
+			Name:                   event.Name,
+			Attributes:             newOtlpKeyValues(event.Attributes),
+			DroppedAttributesCount: event.DroppedAttributeCount,
+		})
+	}
+	for _, link := range span.Links() {
+		output.Links = append(output.Links, otlpLink{
+			TraceId:                link.SpanContext.TraceID().String(),
+			SpanId:                 link.SpanContext.SpanID().String(),
+			TraceState:             link.SpanContext.TraceState().String(),
+			Attributes:             newOtlpKeyValues(link.Attributes),
+			DroppedAttributesCount: link.DroppedAttributeCount,
+		})
+	}
+	status := span.Status()
+	switch status.Code {
+	case codes.Ok:
+		output.Status.Code = otlpStatusCodeOk
+	case codes.Error:
+		output.Status.Code = otlpStatusCodeError
+		output.Status.Message = status.Description
+	}
+	return output
+}
+
+// newOtlpTime converts `t` to unix nano string.
+func newOtlpTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// newOtlpKeyValues converts `attrs` to otlpKeyValue slice.
+func newOtlpKeyValues(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	keyValues := make([]otlpKeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		keyValues = append(keyValues, otlpKeyValue{
+			Key:   string(attr.Key),
+			Value: newOtlpAnyValue(attr.Value),
+		})
+	}
+	return keyValues
+}
+
+// newOtlpAnyValue converts `value` to otlpAnyValue.
+func newOtlpAnyValue(value attribute.Value) otlpAnyValue {
+	switch value.Type() {
+	case attribute.BOOL:
+		v := value.AsBool()
+		return otlpAnyValue{BoolValue: &v}
+	case attribute.INT64:
+		v := strconv.FormatInt(value.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &v}
+	case attribute.FLOAT64:
+		v := value.AsFloat64()
+		return otlpAnyValue{DoubleValue: &v}
+	case attribute.BOOLSLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsBoolSlice() {
+			array.Values = append(array.Values, newOtlpAnyValue(attribute.BoolValue(v)))
+		}
+		return otlpAnyValue{ArrayValue: array}
+	case attribute.INT64SLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsInt64Slice() {
+			array.Values = append(array.Values, newOtlpAnyValue(attribute.Int64Value(v)))
+		}
+		return otlpAnyValue{ArrayValue: array}
+	case attribute.FLOAT64SLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsFloat64Slice() {
+			array.Values = append(array.Values, newOtlpAnyValue(attribute.Float64Value(v)))
+		}
+		return otlpAnyValue{ArrayValue: array}
+	case attribute.STRINGSLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsStringSlice() {
+			array.Values = append(array.Values, newOtlpAnyValue(attribute.StringValue(v)))
+		}
+		return otlpAnyValue{ArrayValue: array}
+	default:
+		v := value.Emit()
+		return otlpAnyValue{StringValue: &v}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtrace
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkResource "go.opentelemetry.io/otel/sdk/resource"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/net/gtrace/internal/provider"
+	"github.com/ximplez-go/gf/os/gfile"
+)
+
+// ProviderConfig is the configuration for TracerProvider created by NewProvider or SetProvider.
+type ProviderConfig struct {
+	ServiceName   string                  `json:"serviceName"`   // Service name of the resource, which is the binary name in default.
+	Attributes    map[string]string       `json:"attributes"`    // Custom attributes of the resource.
+	Sampler       string                  `json:"sampler"`       // Sampler name, which is "parentbased_always_on" in default. See SamplerXXX constants.
+	SamplerRatio  float64                 `json:"samplerRatio"`  // Sampling ratio in range [0, 1] for "traceidratio" and "parentbased_traceidratio" samplers.
+	BatchTimeout  time.Duration           `json:"batchTimeout"`  // Max delay for exporting buffered spans. It's 5 seconds in default.
+	BatchSize     int                     `json:"batchSize"`     // Max spans for each exporting. It's 512 in default.
+	QueueSize     int                     `json:"queueSize"`     // Max buffered spans waiting to be exported, after which the new spans are dropped. It's 2048 in default.
+	ExportTimeout time.Duration           `json:"exportTimeout"` // Timeout for each exporting. It's 30 seconds in default.
+	Exporters     []ExporterConfig        `json:"exporters"`     // Built-in exporters, which are created by NewExporter.
+	SpanExporters []sdkTrace.SpanExporter `json:"-"`             // Custom exporters, which are used along with Exporters.
+}
+
+const (
+	SamplerAlwaysOn                = "always_on"                // Samples every span.
+	SamplerAlwaysOff               = "always_off"               // Samples no span.
+	SamplerTraceIdRatio            = "traceidratio"             // Samples the ratio of traces by trace id.
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"    // Follows the parent sampling decision, and samples every root span.
+	SamplerParentBasedAlwaysOff    = "parentbased_always_off"   // Follows the parent sampling decision, and samples no root span.
+	SamplerParentBasedTraceIdRatio = "parentbased_traceidratio" // Follows the parent sampling decision, and samples the ratio of root spans.
+)
+
+const (
+	defaultProviderBatchTimeout  = 5 * time.Second
+	defaultProviderBatchSize     = 512
+	defaultProviderQueueSize     = 2048
+	defaultProviderExportTimeout = 30 * time.Second
+)
+
+// NewProvider creates and returns a TracerProvider with `config`, which samples spans with configured
+// sampler and exports sampled spans in batch with configured exporters.
+// Note that the returned TracerProvider should be shut down before process exits, or else the buffered
+// spans might be lost.
+func NewProvider(config ProviderConfig) (*sdkTrace.TracerProvider, error) {
+	sampler, err := newSampler(config.Sampler, config.SamplerRatio)
+	if err != nil {
+		return nil, err
+	}
+	exporters := make([]sdkTrace.SpanExporter, 0, len(config.Exporters)+len(config.SpanExporters))
+	for _, exporterConfig := range config.Exporters {
+		exporter, err := NewExporter(exporterConfig)
+		if err != nil {
+			// The created exporters are not used by any TracerProvider, which are shut down here
+			// in case of resource leaking, like the opened files.
+			shutdownExporters(exporters)
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+	}
+	exporters = append(exporters, config.SpanExporters...)
+
+	if config.BatchTimeout <= 0 {
+		config.BatchTimeout = defaultProviderBatchTimeout
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultProviderBatchSize
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultProviderQueueSize
+	}
+	if config.ExportTimeout <= 0 {
+		config.ExportTimeout = defaultProviderExportTimeout
+	}
+	options := []sdkTrace.TracerProviderOption{
+		sdkTrace.WithIDGenerator(provider.NewIDGenerator()),
+		sdkTrace.WithSampler(sampler),
+		sdkTrace.WithResource(newResource(config)),
+	}
+	for _, exporter := range exporters {
+		options = append(options, sdkTrace.WithBatcher(
+			exporter,
+			sdkTrace.WithBatchTimeout(config.BatchTimeout),
+			sdkTrace.WithMaxExportBatchSize(config.BatchSize),
+			sdkTrace.WithMaxQueueSize(config.QueueSize),
+			sdkTrace.WithExportTimeout(config.ExportTimeout),
+		))
+	}
+	return sdkTrace.NewTracerProvider(options...), nil
+}
+
+// SetProvider creates TracerProvider with `config` using NewProvider, and sets it as the global
+// TracerProvider, which is used by all tracing features of the framework.
+// It is the one-call setup for tracing, eg:
+//
+//	tp, err := gtrace.SetProvider(gtrace.ProviderConfig{
+//		ServiceName:  "order",
+//		Sampler:      gtrace.SamplerParentBasedTraceIdRatio,
+//		SamplerRatio: 0.1,
+//		Exporters: []gtrace.ExporterConfig{
+//			{Type: gtrace.ExporterTypeOtlpHttp, Endpoint: "http://127.0.0.1:4318/v1/traces"},
+//		},
+//	})
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer tp.Shutdown(ctx)
+func SetProvider(config ProviderConfig) (*sdkTrace.TracerProvider, error) {
+	tp, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tp)
+	CheckSetDefaultTextMapPropagator()
+	return tp, nil
+}
+
+// shutdownExporters shuts down given `exporters`, and logs the errors if any.
+func shutdownExporters(exporters []sdkTrace.SpanExporter) {
+	ctx := context.Background()
+	for _, exporter := range exporters {
+		if err := exporter.Shutdown(ctx); err != nil {
+			intlog.Errorf(ctx, `%+v`, err)
+		}
+	}
+}
+
+// newSampler creates and returns the sampler of given name and ratio.
+func newSampler(name string, ratio float64) (sdkTrace.Sampler, error) {
+	if ratio < 0 || ratio > 1 {
+		return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `invalid sampler ratio %v, it should be in range [0, 1]`, ratio)
+	}
+	switch strings.ToLower(name) {
+	case SamplerAlwaysOn:
+		return sdkTrace.AlwaysSample(), nil
+	case SamplerAlwaysOff:
+		return sdkTrace.NeverSample(), nil
+	case SamplerTraceIdRatio:
+		return sdkTrace.TraceIDRatioBased(ratio), nil
+	case "", SamplerParentBasedAlwaysOn:
+		return sdkTrace.ParentBased(sdkTrace.AlwaysSample()), nil
+	case SamplerParentBasedAlwaysOff:
+		return sdkTrace.ParentBased(sdkTrace.NeverSample()), nil
+	case SamplerParentBasedTraceIdRatio:
+		return sdkTrace.ParentBased(sdkTrace.TraceIDRatioBased(ratio)), nil
+	default:
+		return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `unsupported sampler "%s"`, name)
+	}
+}
+
+// newResource creates and returns the resource describing the service for the TracerProvider.
+func newResource(config ProviderConfig) *sdkResource.Resource {
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = gfile.Name(os.Args[0])
+	}
+	attrs := append(CommonLabels(), semconv.ServiceName(serviceName))
+	for key, value := range config.Attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	resource, err := sdkResource.Merge(sdkResource.Default(), sdkResource.NewSchemaless(attrs...))
+	if err != nil {
+		return sdkResource.NewSchemaless(attrs...)
+	}
+	return resource
+}
@@ -0,0 +1,246 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtrace_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/net/gtrace"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+func Test_Provider_OtlpHttp(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies [][]byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Token") != "token" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	gtest.C(t, func(t *gtest.T) {
+		ctx := context.Background()
+		tp, err := gtrace.NewProvider(gtrace.ProviderConfig{
+			ServiceName: "order",
+			Attributes:  map[string]string{"env": "test"},
+			Sampler:     gtrace.SamplerAlwaysOn,
+			Exporters: []gtrace.ExporterConfig{{
+				Type:     gtrace.ExporterTypeOtlpHttp,
+				Endpoint: server.URL + "/v1/traces",
+				Headers:  map[string]string{"X-Token": "token"},
+			}},
+		})
+		t.AssertNil(err)
+
+		tracer := tp.Tracer("test", trace.WithInstrumentationVersion("v1.0.0"))
+		ctx, parent := tracer.Start(ctx, "parent", trace.WithSpanKind(trace.SpanKindServer))
+		_, child := tracer.Start(ctx, "child")
+		child.SetAttributes(
+			attribute.String("key", "value"),
+			attribute.Int("count", 10),
+			attribute.StringSlice("tags", []string{"a", "b"}),
+		)
+		child.AddEvent("event", trace.WithAttributes(attribute.Bool("ok", true)))
+		child.SetStatus(codes.Error, "failed")
+		child.End()
+		parent.End()
+		t.AssertNil(tp.Shutdown(ctx))
+
+		mu.Lock()
+		defer mu.Unlock()
+		t.Assert(len(bodies), 1)
+		var data struct {
+			ResourceSpans []struct {
+				Resource struct {
+					Attributes []map[string]any
+				}
+				ScopeSpans []struct {
+					Scope map[string]any
+					Spans []map[string]any
+				}
+			}
+		}
+		t.AssertNil(json.Unmarshal(bodies[0], &data))
+		t.Assert(len(data.ResourceSpans), 1)
+		t.Assert(gstr.Contains(string(bodies[0]), `{"key":"service.name","value":{"stringValue":"order"}}`), true)
+		t.Assert(gstr.Contains(string(bodies[0]), `{"key":"env","value":{"stringValue":"test"}}`), true)
+		scopeSpans := data.ResourceSpans[0].ScopeSpans
+		t.Assert(len(scopeSpans), 1)
+		t.Assert(scopeSpans[0].Scope["name"], "test")
+		t.Assert(scopeSpans[0].Scope["version"], "v1.0.0")
+		t.Assert(len(scopeSpans[0].Spans), 2)
+
+		var (
+			childSpan  = scopeSpans[0].Spans[0]
+			parentSpan = scopeSpans[0].Spans[1]
+		)
+		t.Assert(childSpan["name"], "child")
+		t.Assert(parentSpan["name"], "parent")
+		t.Assert(parentSpan["kind"], 2)
+		t.Assert(childSpan["kind"], 1)
+		t.Assert(childSpan["traceId"], parentSpan["traceId"])
+		t.Assert(childSpan["parentSpanId"], parentSpan["spanId"])
+		t.Assert(len(childSpan["traceId"].(string)), 32)
+		t.Assert(childSpan["status"], map[string]any{"code": 2, "message": "failed"})
+		content := string(bodies[0])
+		t.Assert(gstr.Contains(content, `{"key":"count","value":{"intValue":"10"}}`), true)
+		t.Assert(gstr.Contains(content, `{"key":"tags","value":{"arrayValue":{"values":[{"stringValue":"a"},{"stringValue":"b"}]}}}`), true)
+		t.Assert(gstr.Contains(content, `"name":"event","attributes":[{"key":"ok","value":{"boolValue":true}}]`), true)
+	})
+}
+
+func Test_Provider_File(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx  = context.Background()
+			path = gfile.Temp(gtime.TimestampNanoStr(), "trace.json")
+		)
+		defer gfile.Remove(gfile.Dir(path))
+		tp, err := gtrace.NewProvider(gtrace.ProviderConfig{
+			Exporters: []gtrace.ExporterConfig{{
+				Type:       gtrace.ExporterTypeFile,
+				Path:       path,
+				MaxSize:    100,
+				MaxBackups: 2,
+			}},
+		})
+		t.AssertNil(err)
+
+		tracer := tp.Tracer("test")
+		for i := 0; i < 5; i++ {
+			_, span := tracer.Start(ctx, "span")
+			span.End()
+			t.AssertNil(tp.ForceFlush(ctx))
+		}
+		t.AssertNil(tp.Shutdown(ctx))
+
+		files, err := gfile.ScanDirFile(gfile.Dir(path), "trace.json*")
+		t.AssertNil(err)
+		t.Assert(len(files), 3)
+		t.Assert(gfile.Exists(path+".3"), false)
+		for _, file := range files {
+			lines := gstr.SplitAndTrim(gfile.GetContents(file), "\n")
+			t.Assert(len(lines), 1)
+			t.Assert(json.Valid([]byte(lines[0])), true)
+			t.Assert(gstr.Contains(lines[0], `"name":"span"`), true)
+		}
+	})
+}
+
+func Test_Provider_Sampler(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		traceId, _ = trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanId, _  = trace.SpanIDFromHex("00f067aa0ba902b7")
+		sampledCtx = trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceId,
+			SpanID:     spanId,
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		}))
+		isSampled = func(t *gtest.T, sampler string, ratio float64, ctx context.Context) bool {
+			tp, err := gtrace.NewProvider(gtrace.ProviderConfig{
+				Sampler:      sampler,
+				SamplerRatio: ratio,
+			})
+			t.AssertNil(err)
+			defer tp.Shutdown(ctx)
+			_, span := tp.Tracer("test").Start(ctx, "span")
+			defer span.End()
+			return span.SpanContext().IsSampled()
+		}
+	)
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(isSampled(t, "", 0, ctx), true)
+		t.Assert(isSampled(t, gtrace.SamplerAlwaysOn, 0, ctx), true)
+		t.Assert(isSampled(t, gtrace.SamplerAlwaysOff, 0, sampledCtx), false)
+		t.Assert(isSampled(t, gtrace.SamplerTraceIdRatio, 0, sampledCtx), false)
+		t.Assert(isSampled(t, gtrace.SamplerTraceIdRatio, 1, ctx), true)
+		t.Assert(isSampled(t, gtrace.SamplerParentBasedTraceIdRatio, 0, ctx), false)
+		t.Assert(isSampled(t, gtrace.SamplerParentBasedTraceIdRatio, 0, sampledCtx), true)
+		t.Assert(isSampled(t, gtrace.SamplerParentBasedAlwaysOff, 0, sampledCtx), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		_, err := gtrace.NewProvider(gtrace.ProviderConfig{Sampler: "unknown"})
+		t.AssertNE(err, nil)
+		_, err = gtrace.NewProvider(gtrace.ProviderConfig{Sampler: gtrace.SamplerTraceIdRatio, SamplerRatio: 2})
+		t.AssertNE(err, nil)
+		_, err = gtrace.NewProvider(gtrace.ProviderConfig{Exporters: []gtrace.ExporterConfig{{Type: "unknown"}}})
+		t.AssertNE(err, nil)
+		_, err = gtrace.NewProvider(gtrace.ProviderConfig{Exporters: []gtrace.ExporterConfig{{Type: gtrace.ExporterTypeFile}}})
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Provider_ExporterError(t *testing.T) {
+	if !gfile.Exists("/proc/self/fd") {
+		t.Skip("opened files are not observable on this platform")
+	}
+	// isOpened checks whether file of `path` is opened by current process.
+	isOpened := func(path string) bool {
+		fds, _ := gfile.ScanDirFile("/proc/self/fd", "*")
+		for _, fd := range fds {
+			if target, err := os.Readlink(fd); err == nil && target == path {
+				return true
+			}
+		}
+		return false
+	}
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp(gtime.TimestampNanoStr(), "trace.json")
+		defer gfile.Remove(gfile.Dir(path))
+		_, err := gtrace.NewProvider(gtrace.ProviderConfig{
+			Exporters: []gtrace.ExporterConfig{
+				{Type: gtrace.ExporterTypeFile, Path: path},
+				{Type: "unknown"},
+			},
+		})
+		t.AssertNE(err, nil)
+		t.Assert(gfile.Exists(path), true)
+		t.Assert(isOpened(path), false)
+	})
+}
+
+func Test_SetProvider(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		defer otel.SetTracerProvider(otel.GetTracerProvider())
+		t.Assert(gtrace.IsUsingDefaultProvider(), true)
+		tp, err := gtrace.SetProvider(gtrace.ProviderConfig{
+			Exporters: []gtrace.ExporterConfig{{Type: gtrace.ExporterTypeStdout}},
+		})
+		t.AssertNil(err)
+		defer tp.Shutdown(context.Background())
+		t.Assert(gtrace.IsUsingDefaultProvider(), false)
+
+		ctx, span := gtrace.NewSpan(context.Background(), "span")
+		span.End()
+		t.Assert(span.SpanContext().IsSampled(), true)
+		t.Assert(gtrace.GetTraceID(ctx), span.SpanContext().TraceID().String())
+	})
+}
@@ -7,6 +7,7 @@
 package gudp
 
 import (
+	"context"
 	"io"
 	"net"
 	"time"
@@ -17,6 +18,7 @@ import (
 // ServerConn holds the server side connection.
 type ServerConn struct {
 	*localConn
+	instrumentation *serverConnInstrumentation // Instrumentation of the connection, which is nil if not enabled.
 }
 
 // NewServerConn creates an udp connection that listens to `localAddress`.
@@ -28,13 +30,48 @@ func NewServerConn(listenedConn *net.UDPConn) *ServerConn {
 	}
 }
 
+// Ctx returns the context of the connection.
+// For connection of server with instrumentation enabled, it carries the span of the last received package,
+// or else it returns context.Background().
+func (c *ServerConn) Ctx() context.Context {
+	if c.instrumentation == nil {
+		return context.Background()
+	}
+	return c.instrumentation.Ctx()
+}
+
+// Recv receives and returns data from remote address, see localConn.Recv.
+func (c *ServerConn) Recv(buffer int, retry ...Retry) ([]byte, *net.UDPAddr, error) {
+	if c.instrumentation == nil {
+		return c.localConn.Recv(buffer, retry...)
+	}
+	c.instrumentation.endPackage()
+	data, remoteAddr, err := c.localConn.Recv(buffer, retry...)
+	c.instrumentation.startPackage(len(data), remoteAddr, err)
+	return data, remoteAddr, err
+}
+
+// Close closes the connection.
+func (c *ServerConn) Close() error {
+	if c.instrumentation != nil {
+		c.instrumentation.endPackage()
+	}
+	return c.localConn.Close()
+}
+
 // Send writes data to remote address.
 func (c *ServerConn) Send(data []byte, remoteAddr *net.UDPAddr, retry ...Retry) (err error) {
 	for {
 		_, err = c.WriteToUDP(data, remoteAddr)
 		if err == nil {
+			if c.instrumentation != nil {
+				c.instrumentation.handleSent(len(data))
+			}
 			return nil
 		}
+		if c.instrumentation != nil {
+			c.instrumentation.handleError(metricErrorTypeWrite, err)
+		}
 		// Connection closed.
 		if err == io.EOF {
 			return err
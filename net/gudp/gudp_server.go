@@ -41,6 +41,9 @@ type Server struct {
 
 	// Handler for UDP connection.
 	handler ServerHandler
+
+	// Whether tracing and metrics instrumentation is enabled.
+	instrumented bool
 }
 
 // ServerHandler handles all server connections.
@@ -89,6 +92,15 @@ func (s *Server) SetHandler(handler ServerHandler) {
 	s.handler = handler
 }
 
+// EnableInstrumentation enables tracing and metrics instrumentation for the server, which should be called
+// before Run. As there's no connection in UDP protocol, it creates a span for each received package, which
+// is carried by ServerConn.Ctx, and ends when next package receiving starts or the connection closes.
+// It also records the metrics of packages, received and sent bytes, errors and handler duration, in which
+// the handler duration is the duration between the package receiving and next package receiving.
+func (s *Server) EnableInstrumentation() {
+	s.instrumented = true
+}
+
 // Close closes the connection.
 // It will make server shutdowns immediately.
 func (s *Server) Close() (err error) {
@@ -119,10 +131,25 @@ func (s *Server) Run() error {
 		err = gerror.Wrapf(err, `net.ListenUDP failed for address "%s"`, s.address)
 		return err
 	}
+	conn := NewServerConn(listenedConn)
+	if s.instrumented {
+		address := gstr.Replace(
+			s.address, FreePortAddress, fmt.Sprintf(`:%d`, listenedConn.LocalAddr().(*net.UDPAddr).Port),
+		)
+		conn.instrumentation = newServerConnInstrumentation(address)
+	}
 	s.mu.Lock()
-	s.conn = NewServerConn(listenedConn)
+	s.conn = conn
 	s.mu.Unlock()
-	s.handler(s.conn)
+	if s.instrumented {
+		defer func() {
+			if exception := recover(); exception != nil {
+				conn.instrumentation.handlePanic(exception)
+				panic(exception)
+			}
+		}()
+	}
+	s.handler(conn)
 	return nil
 }
 
@@ -0,0 +1,219 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gudp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ximplez-go/gf"
+	"github.com/ximplez-go/gf/os/gmetric"
+)
+
+// localMetricManager manages the metrics of Server,
+// which are published only if instrumentation is enabled for the Server.
+type localMetricManager struct {
+	ServerPackages        gmetric.Counter
+	ServerReceivedBytes   gmetric.Counter
+	ServerSentBytes       gmetric.Counter
+	ServerErrors          gmetric.Counter
+	ServerHandlerDuration gmetric.Histogram
+}
+
+const (
+	instrument                 = "github.com/ximplez-go/gf/net/gudp.Server"
+	metricAttrKeyServerAddress = "server.address"
+	metricAttrKeyErrorType     = "error.type"
+	metricErrorTypeRead        = "read"
+	metricErrorTypeWrite       = "write"
+	metricErrorTypePanic       = "panic"
+	tracingSpanNameServerPkg   = "gudp.Server.Package"
+)
+
+// metricManager records the received packages, traffic, errors and handler durations of instrumented servers.
+var metricManager = newMetricManager()
+
+// newMetricManager creates the package and traffic metrics of gudp servers.
+func newMetricManager() *localMetricManager {
+	meter := gmetric.GetGlobalProvider().Meter(gmetric.MeterOption{
+		Instrument:        instrument,
+		InstrumentVersion: gf.VERSION,
+	})
+	return &localMetricManager{
+		ServerPackages: meter.MustCounter(
+			"gudp.server.packages",
+			gmetric.MetricOption{
+				Help: "Total number of received packages.",
+			},
+		),
+		ServerReceivedBytes: meter.MustCounter(
+			"gudp.server.received_bytes",
+			gmetric.MetricOption{
+				Help: "Total bytes of received packages.",
+				Unit: "bytes",
+			},
+		),
+		ServerSentBytes: meter.MustCounter(
+			"gudp.server.sent_bytes",
+			gmetric.MetricOption{
+				Help: "Total bytes of sent packages.",
+				Unit: "bytes",
+			},
+		),
+		ServerErrors: meter.MustCounter(
+			"gudp.server.errors",
+			gmetric.MetricOption{
+				Help: "Total number of reading, writing errors and handler panics.",
+			},
+		),
+		ServerHandlerDuration: meter.MustHistogram(
+			"gudp.server.handler.duration",
+			gmetric.MetricOption{
+				Help: "Measures the duration of handling each received package.",
+				Unit: "ms",
+				Buckets: []float64{
+					1, 5, 10, 25, 50, 75, 100, 250, 500, 750,
+					1000, 2500, 5000, 7500, 10000,
+				},
+			},
+		),
+	}
+}
+
+// serverConnInstrumentation holds the tracing and metrics state of ServerConn.
+// Note that it uses the otel api directly instead of package gtrace, which depends on this package.
+type serverConnInstrumentation struct {
+	mu        sync.Mutex
+	address   string          // Listened address of the server.
+	ctx       context.Context // Context carrying span of current package.
+	span      trace.Span      // Span of current package, which is nil if no package is being handled.
+	startTime time.Time       // Receiving time of current package.
+}
+
+// newServerConnInstrumentation creates and returns a serverConnInstrumentation for server listening `address`.
+func newServerConnInstrumentation(address string) *serverConnInstrumentation {
+	return &serverConnInstrumentation{
+		address: address,
+	}
+}
+
+// newMetricOption creates and returns the metric operation option of the server.
+func (i *serverConnInstrumentation) newMetricOption(attributes ...gmetric.Attribute) gmetric.Option {
+	return gmetric.Option{
+		Attributes: append(gmetric.Attributes{
+			gmetric.NewAttribute(metricAttrKeyServerAddress, i.address),
+		}, attributes...),
+	}
+}
+
+// Ctx returns the context carrying span of current package.
+func (i *serverConnInstrumentation) Ctx() context.Context {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.ctx == nil {
+		return context.Background()
+	}
+	return i.ctx
+}
+
+// startPackage starts the span of received package with `size` from `remoteAddr`.
+// It records the error if `err` is not nil.
+func (i *serverConnInstrumentation) startPackage(size int, remoteAddr *net.UDPAddr, err error) {
+	if err != nil {
+		i.handleError(metricErrorTypeRead, err)
+		return
+	}
+	attributes := []attribute.KeyValue{
+		semconv.NetworkTransportUDP,
+		semconv.ServerAddress(i.address),
+	}
+	if remoteAddr != nil {
+		attributes = append(attributes, semconv.NetworkPeerAddress(remoteAddr.String()))
+	}
+	ctx, span := otel.Tracer(instrument).Start(
+		context.Background(),
+		tracingSpanNameServerPkg,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attributes...),
+	)
+	i.mu.Lock()
+	i.ctx, i.span, i.startTime = ctx, span, time.Now()
+	i.mu.Unlock()
+	if gmetric.IsEnabled() {
+		metricManager.ServerPackages.Inc(ctx, i.newMetricOption())
+		metricManager.ServerReceivedBytes.Add(ctx, float64(size), i.newMetricOption())
+	}
+}
+
+// endPackage ends the span of current package and records its handling duration.
+func (i *serverConnInstrumentation) endPackage() {
+	i.mu.Lock()
+	span, startTime := i.span, i.startTime
+	i.ctx, i.span = nil, nil
+	i.mu.Unlock()
+	if span == nil {
+		return
+	}
+	if gmetric.IsEnabled() {
+		metricManager.ServerHandlerDuration.Record(float64(time.Since(startTime))/float64(time.Millisecond), i.newMetricOption())
+	}
+	span.End()
+}
+
+// handleSent records the sent bytes.
+func (i *serverConnInstrumentation) handleSent(size int) {
+	if gmetric.IsEnabled() {
+		metricManager.ServerSentBytes.Add(i.Ctx(), float64(size), i.newMetricOption())
+	}
+}
+
+// handleError records the error of type `errorType`, which ignores the connection closing and timeout errors.
+func (i *serverConnInstrumentation) handleError(errorType string, err error) {
+	if err == io.EOF || errors.Is(err, net.ErrClosed) {
+		return
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return
+	}
+	i.mu.Lock()
+	if i.span != nil {
+		i.span.RecordError(err)
+	}
+	i.mu.Unlock()
+	if gmetric.IsEnabled() {
+		metricManager.ServerErrors.Inc(i.Ctx(), i.newMetricOption(
+			gmetric.NewAttribute(metricAttrKeyErrorType, errorType),
+		))
+	}
+}
+
+// handlePanic records the panic of handler and ends the span of current package.
+func (i *serverConnInstrumentation) handlePanic(exception interface{}) {
+	i.mu.Lock()
+	if i.span != nil {
+		i.span.SetStatus(codes.Error, fmt.Sprintf(`%+v`, exception))
+	}
+	i.mu.Unlock()
+	if gmetric.IsEnabled() {
+		metricManager.ServerErrors.Inc(i.Ctx(), i.newMetricOption(
+			gmetric.NewAttribute(metricAttrKeyErrorType, metricErrorTypePanic),
+		))
+	}
+	i.endPackage()
+}
@@ -0,0 +1,94 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gudp_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ximplez-go/gf/net/gudp"
+	"github.com/ximplez-go/gf/os/gmetric"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+func Test_Server_Instrumentation(t *testing.T) {
+	var (
+		recorder       = tracetest.NewSpanRecorder()
+		tracerProvider = sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(recorder))
+		metricProvider = gmetric.NewPrometheusProvider()
+		spanIdChan     = make(chan trace.SpanID, 10)
+	)
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tracerProvider)
+	metricProvider.SetAsGlobal()
+
+	s := gudp.NewServer(gudp.FreePortAddress, func(conn *gudp.ServerConn) {
+		defer conn.Close()
+		for {
+			data, remote, err := conn.Recv(-1)
+			if err != nil {
+				break
+			}
+			spanIdChan <- trace.SpanContextFromContext(conn.Ctx()).SpanID()
+			if err = conn.Send(data, remote); err != nil {
+				break
+			}
+		}
+	})
+	s.EnableInstrumentation()
+	go s.Run()
+	defer s.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	gtest.C(t, func(t *gtest.T) {
+		conn, err := gudp.NewClientConn(s.GetListenedAddress())
+		t.AssertNil(err)
+		defer conn.Close()
+		for i := 0; i < 3; i++ {
+			result, err := conn.SendRecv([]byte("hello"), -1)
+			t.AssertNil(err)
+			t.Assert(result, "hello")
+		}
+		// Each received package has its own span.
+		var spanIds = make(map[trace.SpanID]struct{})
+		for i := 0; i < 3; i++ {
+			spanId := <-spanIdChan
+			t.Assert(spanId.IsValid(), true)
+			spanIds[spanId] = struct{}{}
+		}
+		t.Assert(len(spanIds), 3)
+
+		time.Sleep(100 * time.Millisecond)
+		var endedCount int
+		for _, span := range recorder.Ended() {
+			if span.Name() == "gudp.Server.Package" {
+				_, ok := spanIds[span.SpanContext().SpanID()]
+				t.Assert(ok, true)
+				endedCount++
+			}
+		}
+		// The package span ends when the handler starts next receiving.
+		t.Assert(endedCount, 3)
+
+		var buffer = bytes.NewBuffer(nil)
+		t.AssertNil(metricProvider.Export(context.Background(), buffer))
+		var content = buffer.String()
+		t.Assert(gstr.Contains(content, "gudp_server_packages{"), true)
+		t.Assert(gstr.Contains(content, "gudp_server_received_bytes{"), true)
+		t.Assert(gstr.Contains(content, "gudp_server_sent_bytes{"), true)
+		t.Assert(gstr.Contains(content, "gudp_server_handler_duration_count{"), true)
+		t.Assert(gstr.Contains(content, "gudp_server_errors{"), false)
+	})
+}
@@ -6,7 +6,9 @@
 
 // Package gcache provides kinds of cache management for process.
 //
-// It provides a concurrent-safe in-memory cache adapter for process in default.
+// It provides a concurrent-safe in-memory cache adapter for process in default,
+// a Redis adapter for cache sharing among processes, a gkvdb adapter for durable local cache,
+// and a two-level adapter composing them.
 package gcache
 
 import (
@@ -118,6 +120,30 @@ func GetOrSetFuncLock(ctx context.Context, key interface{}, f Func, duration tim
 	return defaultCache.GetOrSetFuncLock(ctx, key, f, duration)
 }
 
+// GetOrSetFuncSingle retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+//
+// Note that it differs from function `GetOrSetFunc` is that the function `f` is executed only once
+// for the same `key` concurrently, and other callers of the same `key` wait and share its result.
+func GetOrSetFuncSingle(ctx context.Context, key interface{}, f Func, duration time.Duration) (*gvar.Var, error) {
+	return defaultCache.GetOrSetFuncSingle(ctx, key, f, duration)
+}
+
+// GetOrSetFuncStale retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair turns stale after `softDuration` and expires after `duration`.
+//
+// The stale value is returned immediately, and exactly one background goroutine is started to
+// refresh the value using function `f`.
+func GetOrSetFuncStale(ctx context.Context, key interface{}, f Func, softDuration, duration time.Duration) (*gvar.Var, error) {
+	return defaultCache.GetOrSetFuncStale(ctx, key, f, softDuration, duration)
+}
+
 // Contains checks and returns true if `key` exists in the cache, or else returns false.
 func Contains(ctx context.Context, key interface{}) (bool, error) {
 	return defaultCache.Contains(ctx, key)
@@ -207,6 +233,16 @@ func MustGetOrSetFuncLock(ctx context.Context, key interface{}, f Func, duration
 	return defaultCache.MustGetOrSetFuncLock(ctx, key, f, duration)
 }
 
+// MustGetOrSetFuncSingle acts like GetOrSetFuncSingle, but it panics if any error occurs.
+func MustGetOrSetFuncSingle(ctx context.Context, key interface{}, f Func, duration time.Duration) *gvar.Var {
+	return defaultCache.MustGetOrSetFuncSingle(ctx, key, f, duration)
+}
+
+// MustGetOrSetFuncStale acts like GetOrSetFuncStale, but it panics if any error occurs.
+func MustGetOrSetFuncStale(ctx context.Context, key interface{}, f Func, softDuration, duration time.Duration) *gvar.Var {
+	return defaultCache.MustGetOrSetFuncStale(ctx, key, f, softDuration, duration)
+}
+
 // MustContains acts like Contains, but it panics if any error occurs.
 func MustContains(ctx context.Context, key interface{}) bool {
 	return defaultCache.MustContains(ctx, key)
@@ -0,0 +1,385 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"hash/fnv"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/internal/reflection"
+	"github.com/ximplez-go/gf/os/gkvdb"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+// AdapterKvdb is the gcache adapter implements using embedded key-value database gkvdb,
+// which keeps the cache durable in local file without external services.
+//
+// The keys are stored as strings with optional prefix, and the values of map, slice
+// and struct are serialized as JSON. The value retrieved is the serialized string,
+// which can be converted to expected type using functions of gvar.Var.
+type AdapterKvdb struct {
+	db     *gkvdb.DB                 // db is the database storing cache items.
+	prefix string                    // prefix is the prefix for all cache keys, which isolates keys of current cache.
+	locks  [kvdbLockCount]sync.Mutex // locks are striped locks of keys for the functions with Lock suffix.
+}
+
+const (
+	// kvdbLockCount is the count of striped locks for cache keys.
+	kvdbLockCount = 64
+)
+
+// NewAdapterKvdb creates and returns a new gkvdb cache adapter.
+//
+// The optional parameter `prefix` specifies the key prefix for all cache keys.
+// It is strongly recommended specifying a prefix if the database is shared
+// with other usages, as Keys/Values/Data/Size/Clear only operate the keys with the prefix.
+// It operates the whole database if no prefix given.
+func NewAdapterKvdb(db *gkvdb.DB, prefix ...string) *AdapterKvdb {
+	c := &AdapterKvdb{
+		db: db,
+	}
+	if len(prefix) > 0 {
+		c.prefix = prefix[0]
+	}
+	return c
+}
+
+// Set sets cache with `key`-`value` pair, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *AdapterKvdb) Set(ctx context.Context, key interface{}, value interface{}, duration time.Duration) error {
+	if value == nil || duration < 0 {
+		return c.db.Delete(c.kvdbKey(key))
+	}
+	content, err := c.serialize(value)
+	if err != nil {
+		return err
+	}
+	return c.db.Set(c.kvdbKey(key), content, duration)
+}
+
+// SetMap batch sets cache with key-value pairs by `data` map, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *AdapterKvdb) SetMap(ctx context.Context, data map[interface{}]interface{}, duration time.Duration) error {
+	for k, v := range data {
+		if err := c.Set(ctx, k, v, duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetIfNotExist sets cache with `key`-`value` pair which is expired after `duration`
+// if `key` does not exist in the cache. It returns true the `key` does not exist in the
+// cache, and it sets `value` successfully to the cache, or else it returns false.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *AdapterKvdb) SetIfNotExist(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (bool, error) {
+	// Execute the function and retrieve the result.
+	if f, ok := value.(Func); ok {
+		var err error
+		if value, err = f(ctx); err != nil {
+			return false, err
+		}
+	}
+	if value == nil || duration < 0 {
+		return false, c.db.Delete(c.kvdbKey(key))
+	}
+	content, err := c.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	return c.db.SetIfNotExist(c.kvdbKey(key), content, duration)
+}
+
+// SetIfNotExistFunc sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// The parameter `value` can be type of `func() interface{}`, but it does nothing if its
+// result is nil.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *AdapterKvdb) SetIfNotExistFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (ok bool, err error) {
+	isContained, err := c.Contains(ctx, key)
+	if err != nil || isContained {
+		return false, err
+	}
+	value, err := f(ctx)
+	if err != nil {
+		return false, err
+	}
+	return c.SetIfNotExist(ctx, key, value, duration)
+}
+
+// SetIfNotExistFuncLock sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+//
+// Note that the function `f` is executed within the lock of `key` in current adapter,
+// which does not serialize the callers of other processes sharing the database.
+func (c *AdapterKvdb) SetIfNotExistFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (ok bool, err error) {
+	unlock := c.lockKey(key)
+	defer unlock()
+	return c.SetIfNotExistFunc(ctx, key, f, duration)
+}
+
+// Get retrieves and returns the associated value of given `key`.
+// It returns nil if it does not exist or its value is nil.
+func (c *AdapterKvdb) Get(ctx context.Context, key interface{}) (*gvar.Var, error) {
+	content, err := c.db.Get(c.kvdbKey(key))
+	if err != nil || content == nil {
+		return nil, err
+	}
+	return gvar.New(string(content)), nil
+}
+
+// GetOrSet retrieves and returns the value of `key`, or sets `key`-`value` pair and
+// returns `value` if `key` does not exist in the cache. The key-value pair expires
+// after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *AdapterKvdb) GetOrSet(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (result *gvar.Var, err error) {
+	result, err = c.Get(ctx, key)
+	if err != nil || result != nil {
+		return
+	}
+	return c.doSetWithCheck(ctx, key, value, duration)
+}
+
+// GetOrSetFunc retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *AdapterKvdb) GetOrSetFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (result *gvar.Var, err error) {
+	result, err = c.Get(ctx, key)
+	if err != nil || result != nil {
+		return
+	}
+	value, err := f(ctx)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return c.doSetWithCheck(ctx, key, value, duration)
+}
+
+// GetOrSetFuncLock retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+//
+// Note that the function `f` is executed within the lock of `key` in current adapter,
+// which does not serialize the callers of other processes sharing the database.
+func (c *AdapterKvdb) GetOrSetFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (result *gvar.Var, err error) {
+	unlock := c.lockKey(key)
+	defer unlock()
+	return c.GetOrSetFunc(ctx, key, f, duration)
+}
+
+// Contains checks and returns true if `key` exists in the cache, or else returns false.
+func (c *AdapterKvdb) Contains(ctx context.Context, key interface{}) (bool, error) {
+	return c.db.Contains(c.kvdbKey(key))
+}
+
+// Size returns the number of items in the cache.
+func (c *AdapterKvdb) Size(ctx context.Context) (size int, err error) {
+	keys, err := c.db.Keys(c.prefix)
+	return len(keys), err
+}
+
+// Data returns a copy of all key-value pairs in the cache as map type.
+// Note that this function may lead lots of memory usage, you can implement this function
+// if necessary.
+func (c *AdapterKvdb) Data(ctx context.Context) (map[interface{}]interface{}, error) {
+	var data = make(map[interface{}]interface{})
+	err := c.db.Iterate(c.prefix, func(key string, value []byte) bool {
+		data[strings.TrimPrefix(key, c.prefix)] = string(value)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Keys returns all keys in the cache as slice.
+func (c *AdapterKvdb) Keys(ctx context.Context) ([]interface{}, error) {
+	keys, err := c.db.Keys(c.prefix)
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]interface{}, len(keys))
+	for i, key := range keys {
+		result[i] = strings.TrimPrefix(key, c.prefix)
+	}
+	return result, nil
+}
+
+// Values returns all values in the cache as slice.
+func (c *AdapterKvdb) Values(ctx context.Context) ([]interface{}, error) {
+	var values = make([]interface{}, 0)
+	err := c.db.Iterate(c.prefix, func(key string, value []byte) bool {
+		values = append(values, string(value))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Update updates the value of `key` without changing its expiration and returns the old value.
+// The returned value `exist` is false if the `key` does not exist in the cache.
+//
+// It deletes the `key` if given `value` is nil.
+// It does nothing if `key` does not exist in the cache.
+func (c *AdapterKvdb) Update(ctx context.Context, key interface{}, value interface{}) (oldValue *gvar.Var, exist bool, err error) {
+	if value == nil {
+		if oldValue, err = c.Get(ctx, key); err != nil || oldValue == nil {
+			return
+		}
+		return oldValue, true, c.db.Delete(c.kvdbKey(key))
+	}
+	content, err := c.serialize(value)
+	if err != nil {
+		return
+	}
+	oldContent, exist, err := c.db.Update(c.kvdbKey(key), content)
+	if err != nil || !exist {
+		return nil, false, err
+	}
+	return gvar.New(string(oldContent)), true, nil
+}
+
+// UpdateExpire updates the expiration of `key` and returns the old expiration duration value.
+//
+// It returns -1 and does nothing if the `key` does not exist in the cache.
+// It deletes the `key` if `duration` < 0.
+func (c *AdapterKvdb) UpdateExpire(ctx context.Context, key interface{}, duration time.Duration) (oldDuration time.Duration, err error) {
+	return c.db.UpdateExpire(c.kvdbKey(key), duration)
+}
+
+// GetExpire retrieves and returns the expiration of `key` in the cache.
+//
+// Note that,
+// It returns 0 if the `key` does not expire.
+// It returns -1 if the `key` does not exist in the cache.
+func (c *AdapterKvdb) GetExpire(ctx context.Context, key interface{}) (time.Duration, error) {
+	return c.db.GetExpire(c.kvdbKey(key))
+}
+
+// Remove deletes one or more keys from cache, and returns its value.
+// If multiple keys are given, it returns the value of the last deleted item.
+func (c *AdapterKvdb) Remove(ctx context.Context, keys ...interface{}) (lastValue *gvar.Var, err error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var kvdbKeys = make([]string, len(keys))
+	for i, key := range keys {
+		kvdbKeys[i] = c.kvdbKey(key)
+		if value, err := c.db.Get(kvdbKeys[i]); err != nil {
+			return nil, err
+		} else if value != nil {
+			lastValue = gvar.New(string(value))
+		}
+	}
+	return lastValue, c.db.Delete(kvdbKeys...)
+}
+
+// Clear clears all data of the cache.
+// Note that it clears the whole database if no prefix specified for the adapter.
+func (c *AdapterKvdb) Clear(ctx context.Context) error {
+	if c.prefix == "" {
+		return c.db.Clear()
+	}
+	keys, err := c.db.Keys(c.prefix)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	return c.db.Delete(keys...)
+}
+
+// Close does nothing, as the lifecycle of the database is managed by its creator.
+func (c *AdapterKvdb) Close(ctx context.Context) error {
+	return nil
+}
+
+// doSetWithCheck sets `key`-`value` pair if `key` does not exist in the cache, or else
+// it returns the existing value.
+func (c *AdapterKvdb) doSetWithCheck(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (*gvar.Var, error) {
+	if f, ok := value.(Func); ok {
+		var err error
+		if value, err = f(ctx); err != nil || value == nil {
+			return nil, err
+		}
+	}
+	if value == nil || duration < 0 {
+		return nil, c.db.Delete(c.kvdbKey(key))
+	}
+	ok, err := c.SetIfNotExist(ctx, key, value, duration)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// It was set by others concurrently.
+		if v, err := c.Get(ctx, key); err != nil || v != nil {
+			return v, err
+		}
+	}
+	return gvar.New(value), nil
+}
+
+// kvdbKey converts and returns the cache key to database key with prefix.
+func (c *AdapterKvdb) kvdbKey(key interface{}) string {
+	return c.prefix + gconv.String(key)
+}
+
+// lockKey locks `key` and returns the function unlocking it.
+func (c *AdapterKvdb) lockKey(key interface{}) (unlock func()) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(c.kvdbKey(key)))
+	mu := &c.locks[h.Sum32()%kvdbLockCount]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// serialize serializes map, slice and struct values as JSON, and the other values as strings,
+// as the database stores only bytes.
+func (c *AdapterKvdb) serialize(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	switch reflection.OriginTypeAndKind(value).OriginKind {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return json.Marshal(value)
+	default:
+		return []byte(gconv.String(value)), nil
+	}
+}
@@ -26,6 +26,7 @@ type AdapterMemory struct {
 	expireSets  *memoryExpireSets  // expireSets is the expiring timestamp to its key set mapping, which is used for quick indexing and deleting.
 	lru         *memoryLru         // lru is the LRU manager, which is enabled when attribute cap > 0.
 	eventList   *glist.List        // eventList is the asynchronous event list for internal data synchronization.
+	evicted     *gtype.Interface   // evicted is the evictHandler which is called when any item is evicted by LRU or expiration.
 	closed      *gtype.Bool        // closed controls the cache closed or not.
 }
 
@@ -60,6 +61,7 @@ func doNewAdapterMemory() *AdapterMemory {
 		expireTimes: newMemoryExpireTimes(),
 		expireSets:  newMemoryExpireSets(),
 		eventList:   glist.New(true),
+		evicted:     gtype.NewInterface(),
 		closed:      gtype.NewBool(),
 	}
 	// Here may be a "timer leak" if adapter is manually changed from adapter_memory adapter.
@@ -453,9 +455,13 @@ func (c *AdapterMemory) syncEventAndClearExpired(ctx context.Context) {
 		if expireSet = c.expireSets.Get(expireTime); expireSet != nil {
 			// Iterating the set to delete all keys in it.
 			expireSet.Iterator(func(key interface{}) bool {
+				item, ok := c.data.Get(key)
 				c.deleteExpiredKey(key)
 				// remove auto expired key for lru.
 				c.lru.Remove(key)
+				if handler := c.getEvictHandler(); ok && handler != nil {
+					handler(ctx, key, item.v, evictReasonExpire)
+				}
 				return true
 			})
 			// Deleting the set after all of its keys are deleted.
@@ -469,12 +475,35 @@ func (c *AdapterMemory) handleLruKey(ctx context.Context, keys ...interface{}) {
 		return
 	}
 	if evictedKeys := c.lru.SaveAndEvict(keys...); len(evictedKeys) > 0 {
-		_, _ = c.doRemove(ctx, evictedKeys...)
+		handler := c.getEvictHandler()
+		if handler == nil {
+			_, _ = c.doRemove(ctx, evictedKeys...)
+			return
+		}
+		for _, key := range evictedKeys {
+			if item, ok := c.data.Get(key); ok {
+				_, _ = c.doRemove(ctx, key)
+				handler(ctx, key, item.v, evictReasonLru)
+			}
+		}
 		return
 	}
 	return
 }
 
+// setEvictHandler sets the handler which is called when any item is evicted by LRU or expiration.
+func (c *AdapterMemory) setEvictHandler(handler evictHandler) {
+	c.evicted.Set(handler)
+}
+
+// getEvictHandler returns the handler which is called when any item is evicted, or nil if not set.
+func (c *AdapterMemory) getEvictHandler() evictHandler {
+	if handler, ok := c.evicted.Val().(evictHandler); ok && handler != nil {
+		return handler
+	}
+	return nil
+}
+
 // clearByKey deletes the key-value pair with given `key`.
 // The parameter `force` specifies whether doing this deleting forcibly.
 func (c *AdapterMemory) deleteExpiredKey(key interface{}) {
@@ -0,0 +1,536 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/internal/reflection"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+// RedisClient is the client interface that AdapterRedis executes commands with,
+// which is implemented by gresp.Client and can also be easily implemented by any other Redis client.
+type RedisClient interface {
+	// Do sends the command with its arguments to server and returns the reply.
+	// The null reply should be returned as nil value of *gvar.Var.
+	Do(ctx context.Context, command string, args ...interface{}) (*gvar.Var, error)
+}
+
+// AdapterRedis is the gcache adapter implements using Redis server.
+//
+// The keys are stored as strings with optional prefix, and the values of map, slice
+// and struct are serialized as JSON. The value retrieved is the serialized string,
+// which can be converted to expected type using functions of gvar.Var.
+type AdapterRedis struct {
+	client RedisClient // client is the Redis client executing commands.
+	prefix string      // prefix is the prefix for all cache keys, which isolates keys of current cache.
+}
+
+const (
+	// redisScanCount is the COUNT hint for each SCAN iteration.
+	redisScanCount = 1000
+)
+
+// NewAdapterRedis creates and returns a new Redis cache adapter.
+//
+// The optional parameter `prefix` specifies the key prefix for all cache keys.
+// It is strongly recommended specifying a prefix if the Redis database is shared
+// with other usages, as Keys/Values/Data/Size/Clear only operate the keys with the prefix.
+// It operates the whole database if no prefix given.
+func NewAdapterRedis(client RedisClient, prefix ...string) *AdapterRedis {
+	c := &AdapterRedis{
+		client: client,
+	}
+	if len(prefix) > 0 {
+		c.prefix = prefix[0]
+	}
+	return c
+}
+
+// Set sets cache with `key`-`value` pair, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *AdapterRedis) Set(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (err error) {
+	if value == nil || duration < 0 {
+		_, err = c.client.Do(ctx, "DEL", c.redisKey(key))
+		return
+	}
+	if value, err = c.serialize(value); err != nil {
+		return
+	}
+	if duration == 0 {
+		_, err = c.client.Do(ctx, "SET", c.redisKey(key), value)
+	} else {
+		_, err = c.client.Do(ctx, "SET", c.redisKey(key), value, "PX", c.durationMilli(duration))
+	}
+	return
+}
+
+// SetMap batch sets cache with key-value pairs by `data` map, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *AdapterRedis) SetMap(ctx context.Context, data map[interface{}]interface{}, duration time.Duration) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if duration < 0 {
+		var keys = make([]interface{}, 0, len(data))
+		for k := range data {
+			keys = append(keys, c.redisKey(k))
+		}
+		_, err := c.client.Do(ctx, "DEL", keys...)
+		return err
+	}
+	if duration == 0 {
+		var (
+			err  error
+			args = make([]interface{}, 0, len(data)*2)
+			dels = make([]interface{}, 0)
+		)
+		for k, v := range data {
+			if v == nil {
+				dels = append(dels, c.redisKey(k))
+				continue
+			}
+			if v, err = c.serialize(v); err != nil {
+				return err
+			}
+			args = append(args, c.redisKey(k), v)
+		}
+		if len(args) > 0 {
+			if _, err = c.client.Do(ctx, "MSET", args...); err != nil {
+				return err
+			}
+		}
+		if len(dels) > 0 {
+			_, err = c.client.Do(ctx, "DEL", dels...)
+		}
+		return err
+	}
+	for k, v := range data {
+		if err := c.Set(ctx, k, v, duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetIfNotExist sets cache with `key`-`value` pair which is expired after `duration`
+// if `key` does not exist in the cache. It returns true the `key` does not exist in the
+// cache, and it sets `value` successfully to the cache, or else it returns false.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *AdapterRedis) SetIfNotExist(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (bool, error) {
+	// Execute the function and retrieve the result.
+	if f, ok := value.(Func); ok {
+		var err error
+		if value, err = f(ctx); err != nil {
+			return false, err
+		}
+	}
+	if value == nil || duration < 0 {
+		_, err := c.client.Do(ctx, "DEL", c.redisKey(key))
+		return false, err
+	}
+	value, err := c.serialize(value)
+	if err != nil {
+		return false, err
+	}
+	var v *gvar.Var
+	if duration == 0 {
+		v, err = c.client.Do(ctx, "SET", c.redisKey(key), value, "NX")
+	} else {
+		v, err = c.client.Do(ctx, "SET", c.redisKey(key), value, "NX", "PX", c.durationMilli(duration))
+	}
+	if err != nil {
+		return false, err
+	}
+	// It replies null if the key already exists.
+	return !v.IsNil(), nil
+}
+
+// SetIfNotExistFunc sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// The parameter `value` can be type of `func() interface{}`, but it does nothing if its
+// result is nil.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *AdapterRedis) SetIfNotExistFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (ok bool, err error) {
+	isContained, err := c.Contains(ctx, key)
+	if err != nil || isContained {
+		return false, err
+	}
+	value, err := f(ctx)
+	if err != nil {
+		return false, err
+	}
+	return c.SetIfNotExist(ctx, key, value, duration)
+}
+
+// SetIfNotExistFuncLock sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+//
+// Note that the function `f` is not executed within any distributed lock, but the result is set
+// atomically only if `key` does not exist.
+func (c *AdapterRedis) SetIfNotExistFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (ok bool, err error) {
+	return c.SetIfNotExistFunc(ctx, key, f, duration)
+}
+
+// Get retrieves and returns the associated value of given `key`.
+// It returns nil if it does not exist or its value is nil.
+func (c *AdapterRedis) Get(ctx context.Context, key interface{}) (*gvar.Var, error) {
+	v, err := c.client.Do(ctx, "GET", c.redisKey(key))
+	if err != nil || v.IsNil() {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetOrSet retrieves and returns the value of `key`, or sets `key`-`value` pair and
+// returns `value` if `key` does not exist in the cache. The key-value pair expires
+// after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *AdapterRedis) GetOrSet(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (result *gvar.Var, err error) {
+	result, err = c.Get(ctx, key)
+	if err != nil || result != nil {
+		return
+	}
+	return c.doSetWithCheck(ctx, key, value, duration)
+}
+
+// GetOrSetFunc retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *AdapterRedis) GetOrSetFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (result *gvar.Var, err error) {
+	result, err = c.Get(ctx, key)
+	if err != nil || result != nil {
+		return
+	}
+	value, err := f(ctx)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return c.doSetWithCheck(ctx, key, value, duration)
+}
+
+// GetOrSetFuncLock retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+//
+// Note that the function `f` is not executed within any distributed lock, but the result is set
+// atomically only if `key` does not exist, or else the existing value is returned.
+func (c *AdapterRedis) GetOrSetFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (result *gvar.Var, err error) {
+	return c.GetOrSetFunc(ctx, key, f, duration)
+}
+
+// Contains checks and returns true if `key` exists in the cache, or else returns false.
+func (c *AdapterRedis) Contains(ctx context.Context, key interface{}) (bool, error) {
+	v, err := c.client.Do(ctx, "EXISTS", c.redisKey(key))
+	if err != nil {
+		return false, err
+	}
+	return v.Int64() > 0, nil
+}
+
+// Size returns the number of items in the cache.
+func (c *AdapterRedis) Size(ctx context.Context) (size int, err error) {
+	if c.prefix == "" {
+		v, err := c.client.Do(ctx, "DBSIZE")
+		if err != nil {
+			return 0, err
+		}
+		return v.Int(), nil
+	}
+	keys, err := c.scanKeys(ctx)
+	return len(keys), err
+}
+
+// Data returns a copy of all key-value pairs in the cache as map type.
+// Note that this function may lead lots of memory usage, you can implement this function
+// if necessary.
+func (c *AdapterRedis) Data(ctx context.Context) (map[interface{}]interface{}, error) {
+	keys, values, err := c.scanKeysAndValues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var data = make(map[interface{}]interface{}, len(keys))
+	for i, key := range keys {
+		data[key] = values[i]
+	}
+	return data, nil
+}
+
+// Keys returns all keys in the cache as slice.
+func (c *AdapterRedis) Keys(ctx context.Context) ([]interface{}, error) {
+	keys, err := c.scanKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]interface{}, len(keys))
+	for i, key := range keys {
+		result[i] = strings.TrimPrefix(key, c.prefix)
+	}
+	return result, nil
+}
+
+// Values returns all values in the cache as slice.
+func (c *AdapterRedis) Values(ctx context.Context) ([]interface{}, error) {
+	_, values, err := c.scanKeysAndValues(ctx)
+	return values, err
+}
+
+// Update updates the value of `key` without changing its expiration and returns the old value.
+// The returned value `exist` is false if the `key` does not exist in the cache.
+//
+// It deletes the `key` if given `value` is nil.
+// It does nothing if `key` does not exist in the cache.
+//
+// Note that it requires Redis server 6.2 or later, as it uses options KEEPTTL and GET of command SET.
+func (c *AdapterRedis) Update(ctx context.Context, key interface{}, value interface{}) (oldValue *gvar.Var, exist bool, err error) {
+	var redisKey = c.redisKey(key)
+	if value == nil {
+		if oldValue, err = c.Get(ctx, key); err != nil || oldValue == nil {
+			return
+		}
+		_, err = c.client.Do(ctx, "DEL", redisKey)
+		return oldValue, true, err
+	}
+	if value, err = c.serialize(value); err != nil {
+		return
+	}
+	oldValue, err = c.client.Do(ctx, "SET", redisKey, value, "XX", "KEEPTTL", "GET")
+	if err != nil || oldValue.IsNil() {
+		return nil, false, err
+	}
+	return oldValue, true, nil
+}
+
+// UpdateExpire updates the expiration of `key` and returns the old expiration duration value.
+//
+// It returns -1 and does nothing if the `key` does not exist in the cache.
+// It deletes the `key` if `duration` < 0.
+func (c *AdapterRedis) UpdateExpire(ctx context.Context, key interface{}, duration time.Duration) (oldDuration time.Duration, err error) {
+	if oldDuration, err = c.GetExpire(ctx, key); err != nil || oldDuration == -1 {
+		return
+	}
+	var redisKey = c.redisKey(key)
+	switch {
+	case duration < 0:
+		_, err = c.client.Do(ctx, "DEL", redisKey)
+	case duration == 0:
+		_, err = c.client.Do(ctx, "PERSIST", redisKey)
+	default:
+		_, err = c.client.Do(ctx, "PEXPIRE", redisKey, c.durationMilli(duration))
+	}
+	return
+}
+
+// GetExpire retrieves and returns the expiration of `key` in the cache.
+//
+// Note that,
+// It returns 0 if the `key` does not expire.
+// It returns -1 if the `key` does not exist in the cache.
+func (c *AdapterRedis) GetExpire(ctx context.Context, key interface{}) (time.Duration, error) {
+	v, err := c.client.Do(ctx, "PTTL", c.redisKey(key))
+	if err != nil {
+		return 0, err
+	}
+	switch pttl := v.Int64(); pttl {
+	case -1:
+		return 0, nil
+	case -2:
+		return -1, nil
+	default:
+		return time.Duration(pttl) * time.Millisecond, nil
+	}
+}
+
+// Remove deletes one or more keys from cache, and returns its value.
+// If multiple keys are given, it returns the value of the last deleted item.
+func (c *AdapterRedis) Remove(ctx context.Context, keys ...interface{}) (lastValue *gvar.Var, err error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var redisKeys = make([]interface{}, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = c.redisKey(key)
+	}
+	values, err := c.client.Do(ctx, "MGET", redisKeys...)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values.Interfaces() {
+		if value != nil {
+			lastValue = gvar.New(value)
+		}
+	}
+	_, err = c.client.Do(ctx, "DEL", redisKeys...)
+	return lastValue, err
+}
+
+// Clear clears all data of the cache.
+// Note that it flushes the whole database if no prefix specified for the adapter.
+func (c *AdapterRedis) Clear(ctx context.Context) error {
+	if c.prefix == "" {
+		_, err := c.client.Do(ctx, "FLUSHDB")
+		return err
+	}
+	keys, err := c.scanKeys(ctx)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	for start := 0; start < len(keys); start += redisScanCount {
+		end := start + redisScanCount
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if _, err = c.client.Do(ctx, "DEL", gconv.Interfaces(keys[start:end])...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close does nothing, as the lifecycle of the client is managed by its creator.
+func (c *AdapterRedis) Close(ctx context.Context) error {
+	return nil
+}
+
+// doSetWithCheck sets `key`-`value` pair if `key` does not exist in the cache, or else
+// it returns the existing value.
+func (c *AdapterRedis) doSetWithCheck(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (*gvar.Var, error) {
+	if f, ok := value.(Func); ok {
+		var err error
+		if value, err = f(ctx); err != nil || value == nil {
+			return nil, err
+		}
+	}
+	if value == nil || duration < 0 {
+		_, err := c.client.Do(ctx, "DEL", c.redisKey(key))
+		return nil, err
+	}
+	ok, err := c.SetIfNotExist(ctx, key, value, duration)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// It was set by others concurrently.
+		if v, err := c.Get(ctx, key); err != nil || v != nil {
+			return v, err
+		}
+	}
+	return gvar.New(value), nil
+}
+
+// scanKeys retrieves all redis keys of current cache using command SCAN.
+func (c *AdapterRedis) scanKeys(ctx context.Context) ([]string, error) {
+	var (
+		keys    = make([]string, 0)
+		cursor  = "0"
+		pattern = redisEscapePattern(c.prefix) + "*"
+	)
+	for {
+		v, err := c.client.Do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", redisScanCount)
+		if err != nil {
+			return nil, err
+		}
+		reply := v.Interfaces()
+		if len(reply) != 2 {
+			return keys, nil
+		}
+		keys = append(keys, gconv.Strings(reply[1])...)
+		if cursor = gconv.String(reply[0]); cursor == "0" {
+			return keys, nil
+		}
+	}
+}
+
+// scanKeysAndValues retrieves all keys and their values of current cache.
+// Note that the returned keys are without prefix.
+func (c *AdapterRedis) scanKeysAndValues(ctx context.Context) (keys []interface{}, values []interface{}, err error) {
+	redisKeys, err := c.scanKeys(ctx)
+	if err != nil || len(redisKeys) == 0 {
+		return
+	}
+	keys = make([]interface{}, 0, len(redisKeys))
+	values = make([]interface{}, 0, len(redisKeys))
+	for start := 0; start < len(redisKeys); start += redisScanCount {
+		end := start + redisScanCount
+		if end > len(redisKeys) {
+			end = len(redisKeys)
+		}
+		v, err := c.client.Do(ctx, "MGET", gconv.Interfaces(redisKeys[start:end])...)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, value := range v.Interfaces() {
+			// The key might be deleted during scanning.
+			if value == nil {
+				continue
+			}
+			keys = append(keys, strings.TrimPrefix(redisKeys[start+i], c.prefix))
+			values = append(values, value)
+		}
+	}
+	return
+}
+
+// redisKey converts and returns the cache key to redis key with prefix.
+func (c *AdapterRedis) redisKey(key interface{}) string {
+	return c.prefix + gconv.String(key)
+}
+
+// durationMilli returns the milliseconds of `duration`, which is at least 1.
+func (c *AdapterRedis) durationMilli(duration time.Duration) int64 {
+	if ms := duration.Milliseconds(); ms > 0 {
+		return ms
+	}
+	return 1
+}
+
+// serialize serializes map, slice and struct values as JSON, as Redis stores only strings.
+func (c *AdapterRedis) serialize(value interface{}) (interface{}, error) {
+	if _, ok := value.([]byte); ok {
+		return value, nil
+	}
+	switch reflection.OriginTypeAndKind(value).OriginKind {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return json.Marshal(value)
+	default:
+		return value, nil
+	}
+}
+
+// redisEscapePattern escapes the special characters of glob-style pattern in `s`.
+func redisEscapePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,374 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/util/gconv"
+	"github.com/ximplez-go/gf/util/guid"
+)
+
+// AdapterTwoLevel is the gcache adapter that composes a fast local Adapter in front of a
+// slower remote Adapter, which is usually memory adapter in front of Redis adapter.
+//
+// It reads through the local adapter, writes through to both adapters, and broadcasts the
+// invalidated keys to other instances using InvalidationBus, so that the local copies of the
+// keys on other instances are evicted.
+//
+// Note that the keys of local adapter are all converted to string, as the keys are
+// transferred among instances in string form.
+type AdapterTwoLevel struct {
+	local    Adapter         // local is the fast adapter for local copies.
+	remote   Adapter         // remote is the slower adapter as the source of truth.
+	bus      InvalidationBus // bus broadcasts invalidations among instances, which is optional.
+	localTTL time.Duration   // localTTL is the maximum TTL for local copies.
+	source   string          // source is the unique id of current instance.
+	closed   *gtype.Bool     // closed marks the adapter closed.
+}
+
+// AdapterTwoLevelOption is the option for AdapterTwoLevel.
+type AdapterTwoLevelOption struct {
+	// Bus broadcasts the invalidated keys to other instances.
+	// The local copies are not synchronized among instances if no bus configured.
+	Bus InvalidationBus
+
+	// LocalTTL is the maximum TTL for local copies, which bounds the staleness of local copies
+	// if any invalidation message is lost. It uses the TTL of remote item if it is 0.
+	LocalTTL time.Duration
+}
+
+// NewAdapterTwoLevel creates and returns a two-level adapter with `local` adapter in front of `remote` adapter.
+func NewAdapterTwoLevel(local, remote Adapter, option ...AdapterTwoLevelOption) *AdapterTwoLevel {
+	c := &AdapterTwoLevel{
+		local:  local,
+		remote: remote,
+		source: guid.S(),
+		closed: gtype.NewBool(),
+	}
+	if len(option) > 0 {
+		c.bus = option[0].Bus
+		c.localTTL = option[0].LocalTTL
+	}
+	if c.bus != nil {
+		c.bus.Subscribe(c.handleInvalidation)
+	}
+	return c
+}
+
+// Set sets cache with `key`-`value` pair, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *AdapterTwoLevel) Set(ctx context.Context, key interface{}, value interface{}, duration time.Duration) error {
+	if err := c.remote.Set(ctx, key, value, duration); err != nil {
+		return err
+	}
+	if err := c.local.Set(ctx, c.localKey(key), value, c.localDuration(duration)); err != nil {
+		return err
+	}
+	c.publish(ctx, key)
+	return nil
+}
+
+// SetMap batch sets cache with key-value pairs by `data` map, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *AdapterTwoLevel) SetMap(ctx context.Context, data map[interface{}]interface{}, duration time.Duration) error {
+	if err := c.remote.SetMap(ctx, data, duration); err != nil {
+		return err
+	}
+	var (
+		keys      = make([]interface{}, 0, len(data))
+		localData = make(map[interface{}]interface{}, len(data))
+	)
+	for k, v := range data {
+		keys = append(keys, k)
+		localData[c.localKey(k)] = v
+	}
+	if err := c.local.SetMap(ctx, localData, c.localDuration(duration)); err != nil {
+		return err
+	}
+	c.publish(ctx, keys...)
+	return nil
+}
+
+// SetIfNotExist sets cache with `key`-`value` pair which is expired after `duration`
+// if `key` does not exist in the cache. It returns true the `key` does not exist in the
+// cache, and it sets `value` successfully to the cache, or else it returns false.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *AdapterTwoLevel) SetIfNotExist(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (bool, error) {
+	ok, err := c.remote.SetIfNotExist(ctx, key, value, duration)
+	return ok, c.invalidate(ctx, ok, err, key)
+}
+
+// SetIfNotExistFunc sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *AdapterTwoLevel) SetIfNotExistFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (bool, error) {
+	ok, err := c.remote.SetIfNotExistFunc(ctx, key, f, duration)
+	return ok, c.invalidate(ctx, ok, err, key)
+}
+
+// SetIfNotExistFuncLock sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+//
+// Note that the locking behavior depends on the remote adapter.
+func (c *AdapterTwoLevel) SetIfNotExistFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (bool, error) {
+	ok, err := c.remote.SetIfNotExistFuncLock(ctx, key, f, duration)
+	return ok, c.invalidate(ctx, ok, err, key)
+}
+
+// Get retrieves and returns the associated value of given `key`.
+// It reads the local adapter first, and then reads the remote adapter and saves the value
+// to local adapter if it does not exist in local adapter.
+func (c *AdapterTwoLevel) Get(ctx context.Context, key interface{}) (*gvar.Var, error) {
+	v, err := c.local.Get(ctx, c.localKey(key))
+	if err != nil || v != nil {
+		return v, err
+	}
+	if v, err = c.remote.Get(ctx, key); err != nil || v == nil {
+		return v, err
+	}
+	return v, c.fillLocal(ctx, key, v)
+}
+
+// GetOrSet retrieves and returns the value of `key`, or sets `key`-`value` pair and
+// returns `value` if `key` does not exist in the cache. The key-value pair expires
+// after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *AdapterTwoLevel) GetOrSet(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (*gvar.Var, error) {
+	return c.doGetOrSet(ctx, key, func() (*gvar.Var, error) {
+		return c.remote.GetOrSet(ctx, key, value, duration)
+	})
+}
+
+// GetOrSetFunc retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *AdapterTwoLevel) GetOrSetFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (*gvar.Var, error) {
+	return c.doGetOrSet(ctx, key, func() (*gvar.Var, error) {
+		return c.remote.GetOrSetFunc(ctx, key, f, duration)
+	})
+}
+
+// GetOrSetFuncLock retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+//
+// Note that the locking behavior depends on the remote adapter.
+func (c *AdapterTwoLevel) GetOrSetFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (*gvar.Var, error) {
+	return c.doGetOrSet(ctx, key, func() (*gvar.Var, error) {
+		return c.remote.GetOrSetFuncLock(ctx, key, f, duration)
+	})
+}
+
+// Contains checks and returns true if `key` exists in the cache, or else returns false.
+func (c *AdapterTwoLevel) Contains(ctx context.Context, key interface{}) (bool, error) {
+	ok, err := c.local.Contains(ctx, c.localKey(key))
+	if err != nil || ok {
+		return ok, err
+	}
+	return c.remote.Contains(ctx, key)
+}
+
+// Size returns the number of items in the remote adapter.
+func (c *AdapterTwoLevel) Size(ctx context.Context) (size int, err error) {
+	return c.remote.Size(ctx)
+}
+
+// Data returns a copy of all key-value pairs in the remote adapter as map type.
+func (c *AdapterTwoLevel) Data(ctx context.Context) (data map[interface{}]interface{}, err error) {
+	return c.remote.Data(ctx)
+}
+
+// Keys returns all keys in the remote adapter as slice.
+func (c *AdapterTwoLevel) Keys(ctx context.Context) (keys []interface{}, err error) {
+	return c.remote.Keys(ctx)
+}
+
+// Values returns all values in the remote adapter as slice.
+func (c *AdapterTwoLevel) Values(ctx context.Context) (values []interface{}, err error) {
+	return c.remote.Values(ctx)
+}
+
+// Update updates the value of `key` without changing its expiration and returns the old value.
+// The returned value `exist` is false if the `key` does not exist in the cache.
+//
+// It deletes the `key` if given `value` is nil.
+// It does nothing if `key` does not exist in the cache.
+func (c *AdapterTwoLevel) Update(ctx context.Context, key interface{}, value interface{}) (oldValue *gvar.Var, exist bool, err error) {
+	oldValue, exist, err = c.remote.Update(ctx, key, value)
+	return oldValue, exist, c.invalidate(ctx, exist, err, key)
+}
+
+// UpdateExpire updates the expiration of `key` and returns the old expiration duration value.
+//
+// It returns -1 and does nothing if the `key` does not exist in the cache.
+// It deletes the `key` if `duration` < 0.
+func (c *AdapterTwoLevel) UpdateExpire(ctx context.Context, key interface{}, duration time.Duration) (oldDuration time.Duration, err error) {
+	oldDuration, err = c.remote.UpdateExpire(ctx, key, duration)
+	return oldDuration, c.invalidate(ctx, oldDuration != -1, err, key)
+}
+
+// GetExpire retrieves and returns the expiration of `key` in the remote adapter.
+//
+// Note that,
+// It returns 0 if the `key` does not expire.
+// It returns -1 if the `key` does not exist in the cache.
+func (c *AdapterTwoLevel) GetExpire(ctx context.Context, key interface{}) (time.Duration, error) {
+	return c.remote.GetExpire(ctx, key)
+}
+
+// Remove deletes one or more keys from cache, and returns its value.
+// If multiple keys are given, it returns the value of the last deleted item.
+func (c *AdapterTwoLevel) Remove(ctx context.Context, keys ...interface{}) (lastValue *gvar.Var, err error) {
+	if lastValue, err = c.remote.Remove(ctx, keys...); err != nil {
+		return
+	}
+	return lastValue, c.invalidate(ctx, true, nil, keys...)
+}
+
+// Clear clears all data of both adapters, and broadcasts other instances clearing their local data.
+func (c *AdapterTwoLevel) Clear(ctx context.Context) error {
+	if err := c.remote.Clear(ctx); err != nil {
+		return err
+	}
+	if err := c.local.Clear(ctx); err != nil {
+		return err
+	}
+	if c.bus != nil {
+		err := c.bus.Publish(ctx, InvalidationMessage{
+			Source: c.source,
+			Clear:  true,
+		})
+		if err != nil {
+			intlog.Errorf(ctx, `publish invalidation of clearing failed: %+v`, err)
+		}
+	}
+	return nil
+}
+
+// Close closes both the local and remote adapters.
+// Note that the InvalidationBus is not closed, which should be closed by its creator.
+func (c *AdapterTwoLevel) Close(ctx context.Context) error {
+	c.closed.Set(true)
+	if err := c.local.Close(ctx); err != nil {
+		return err
+	}
+	return c.remote.Close(ctx)
+}
+
+// doGetOrSet reads the value from local adapter, or else retrieves or sets the value using `remoteFunc`
+// and saves the value to local adapter.
+func (c *AdapterTwoLevel) doGetOrSet(ctx context.Context, key interface{}, remoteFunc func() (*gvar.Var, error)) (*gvar.Var, error) {
+	v, err := c.local.Get(ctx, c.localKey(key))
+	if err != nil || v != nil {
+		return v, err
+	}
+	if v, err = remoteFunc(); err != nil || v == nil {
+		return v, err
+	}
+	return v, c.fillLocal(ctx, key, v)
+}
+
+// fillLocal saves the value from remote adapter to local adapter, with the expiration of remote item.
+func (c *AdapterTwoLevel) fillLocal(ctx context.Context, key interface{}, value *gvar.Var) error {
+	expire, err := c.remote.GetExpire(ctx, key)
+	if err != nil {
+		return err
+	}
+	if expire < 0 {
+		// It was deleted or expired just now.
+		return nil
+	}
+	return c.local.Set(ctx, c.localKey(key), value.Val(), c.localDuration(expire))
+}
+
+// invalidate removes `keys` from local adapter and broadcasts the invalidation
+// if `changed` is true and `err` is nil.
+func (c *AdapterTwoLevel) invalidate(ctx context.Context, changed bool, err error, keys ...interface{}) error {
+	if err != nil || !changed {
+		return err
+	}
+	var localKeys = make([]interface{}, len(keys))
+	for i, key := range keys {
+		localKeys[i] = c.localKey(key)
+	}
+	if _, err = c.local.Remove(ctx, localKeys...); err != nil {
+		return err
+	}
+	c.publish(ctx, keys...)
+	return nil
+}
+
+// publish broadcasts the invalidated `keys` to other instances.
+// The invalidation is best-effort, it just logs the error if publishing fails.
+func (c *AdapterTwoLevel) publish(ctx context.Context, keys ...interface{}) {
+	if c.bus == nil || len(keys) == 0 {
+		return
+	}
+	err := c.bus.Publish(ctx, InvalidationMessage{
+		Source: c.source,
+		Keys:   gconv.Strings(keys),
+	})
+	if err != nil {
+		intlog.Errorf(ctx, `publish invalidation of keys %v failed: %+v`, keys, err)
+	}
+}
+
+// handleInvalidation handles the invalidation message from other instances.
+func (c *AdapterTwoLevel) handleInvalidation(ctx context.Context, message InvalidationMessage) {
+	if c.closed.Val() || message.Source == c.source {
+		return
+	}
+	var err error
+	if message.Clear {
+		err = c.local.Clear(ctx)
+	} else if len(message.Keys) > 0 {
+		_, err = c.local.Remove(ctx, gconv.Interfaces(message.Keys)...)
+	}
+	if err != nil {
+		intlog.Errorf(ctx, `handle invalidation message failed: %+v`, err)
+	}
+}
+
+// localKey converts `key` to the key of local adapter.
+func (c *AdapterTwoLevel) localKey(key interface{}) interface{} {
+	return gconv.String(key)
+}
+
+// localDuration returns the duration for local copies, which is bounded by localTTL.
+func (c *AdapterTwoLevel) localDuration(duration time.Duration) time.Duration {
+	if c.localTTL > 0 && duration >= 0 && (duration == 0 || duration > c.localTTL) {
+		return c.localTTL
+	}
+	return duration
+}
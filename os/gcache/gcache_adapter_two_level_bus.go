@@ -0,0 +1,68 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ximplez-go/gf/internal/json"
+)
+
+// InvalidationBus broadcasts the cache invalidations among instances for AdapterTwoLevel.
+//
+// Note that the implementer itself should guarantee the concurrent safety of these functions.
+type InvalidationBus interface {
+	// Publish broadcasts the invalidation message to other instances.
+	Publish(ctx context.Context, message InvalidationMessage) error
+
+	// Subscribe registers the handler which is called when invalidation message is received.
+	// Note that the message published by current instance might also be received by itself,
+	// which should be filtered by the handler using InvalidationMessage.Source.
+	Subscribe(handler InvalidationHandler)
+
+	// Close closes the bus and releases its resources.
+	Close(ctx context.Context) error
+}
+
+// InvalidationHandler is the handler for received invalidation message.
+type InvalidationHandler func(ctx context.Context, message InvalidationMessage)
+
+// InvalidationMessage is the message broadcast among instances for cache invalidation.
+type InvalidationMessage struct {
+	Source string   `json:"source"`          // Unique id of the instance publishing the message.
+	Keys   []string `json:"keys,omitempty"`  // Keys that are invalidated.
+	Clear  bool     `json:"clear,omitempty"` // Clear marks all keys are invalidated.
+}
+
+// invalidationHandlers manages the subscribed handlers for bus implements.
+type invalidationHandlers struct {
+	mu       sync.RWMutex
+	handlers []InvalidationHandler
+}
+
+// Subscribe registers the handler which is called when invalidation message is received.
+func (h *invalidationHandlers) Subscribe(handler InvalidationHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers = append(h.handlers, handler)
+}
+
+// dispatch decodes the received data and calls all handlers with the message.
+func (h *invalidationHandlers) dispatch(ctx context.Context, data []byte) error {
+	var message InvalidationMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	h.mu.RLock()
+	handlers := h.handlers
+	h.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, message)
+	}
+	return nil
+}
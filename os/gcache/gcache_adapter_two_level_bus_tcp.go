@@ -0,0 +1,262 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/internal/json"
+)
+
+// InvalidationBusTcp is the InvalidationBus implements using TCP,
+// which listens for messages from peers and sends messages to each of its peers
+// through long-lived connections.
+//
+// Messages are queued for each peer and sent by a background writer of the peer,
+// so that publishing never blocks on slow or unreachable peers.
+//
+// Note that it uses the standard library "net" directly instead of package gtcp,
+// as gtcp depends on gfile, which depends on gcache.
+type InvalidationBusTcp struct {
+	invalidationHandlers
+	listener  net.Listener          // listener receives connections from peers.
+	peers     []*tcpBusPeer         // peers are the other instances receiving messages.
+	mu        sync.Mutex            // mu guards accepted.
+	accepted  map[net.Conn]struct{} // accepted are the connections from peers, closed along with the bus.
+	closed    *gtype.Bool           // closed marks the bus closed.
+	closeChan chan struct{}         // closeChan notifies the writers to exit when the bus is closed.
+}
+
+// tcpBusPeer is a peer of InvalidationBusTcp, which has its own message queue and connection.
+type tcpBusPeer struct {
+	address string      // address of the peer.
+	queue   chan []byte // queue buffers the packets to be sent by the writer.
+	mu      sync.Mutex  // mu guards conn.
+	conn    net.Conn    // conn is the cached connection to the peer, which is nil if not connected.
+}
+
+const (
+	// tcpBusTimeout is the timeout for connecting and sending messages to peers.
+	tcpBusTimeout = 3 * time.Second
+	// tcpBusHeaderSize is the size of the header containing the message length.
+	tcpBusHeaderSize = 4
+	// tcpBusMaxMessageSize is the max size of a single message, which protects from malformed data.
+	tcpBusMaxMessageSize = 64 * 1024 * 1024
+	// tcpBusQueueSize is the max count of messages queued for each peer, exceeding messages are dropped.
+	tcpBusQueueSize = 1024
+)
+
+var (
+	// Check the implements for interface InvalidationBus.
+	_ InvalidationBus = (*InvalidationBusTcp)(nil)
+)
+
+// NewInvalidationBusTcp creates and returns an InvalidationBus using TCP,
+// which listens on `address` and sends messages to each of `peers`.
+func NewInvalidationBusTcp(address string, peers ...string) (*InvalidationBusTcp, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `net.Listen failed for address "%s"`, address)
+	}
+	bus := &InvalidationBusTcp{
+		listener:  listener,
+		peers:     make([]*tcpBusPeer, len(peers)),
+		accepted:  make(map[net.Conn]struct{}),
+		closed:    gtype.NewBool(),
+		closeChan: make(chan struct{}),
+	}
+	for i, address := range peers {
+		bus.peers[i] = &tcpBusPeer{
+			address: address,
+			queue:   make(chan []byte, tcpBusQueueSize),
+		}
+		go bus.write(bus.peers[i])
+	}
+	go bus.accept()
+	return bus, nil
+}
+
+// Publish queues the invalidation message for all peers, which is sent asynchronously.
+// It never blocks, and returns error if the message is dropped for any peer of which the queue is full.
+func (b *InvalidationBusTcp) Publish(ctx context.Context, message InvalidationMessage) error {
+	if b.closed.Val() {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `invalidation bus is closed`)
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	packet := make([]byte, tcpBusHeaderSize+len(data))
+	binary.BigEndian.PutUint32(packet, uint32(len(data)))
+	copy(packet[tcpBusHeaderSize:], data)
+	for _, peer := range b.peers {
+		select {
+		case peer.queue <- packet:
+		default:
+			err = gerror.NewCodef(
+				gcode.CodeOperationFailed,
+				`invalidation message dropped as the queue of peer "%s" is full`, peer.address,
+			)
+		}
+	}
+	return err
+}
+
+// GetListenedAddress retrieves and returns the address that current bus listens on.
+func (b *InvalidationBusTcp) GetListenedAddress() string {
+	return b.listener.Addr().String()
+}
+
+// Close closes the bus, including the listener and all connections.
+// The messages still in queues are discarded.
+func (b *InvalidationBusTcp) Close(ctx context.Context) error {
+	if !b.closed.Cas(false, true) {
+		return nil
+	}
+	close(b.closeChan)
+	err := b.listener.Close()
+	for _, peer := range b.peers {
+		peer.mu.Lock()
+		if peer.conn != nil {
+			_ = peer.conn.Close()
+			peer.conn = nil
+		}
+		peer.mu.Unlock()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.accepted {
+		_ = conn.Close()
+		delete(b.accepted, conn)
+	}
+	return err
+}
+
+// write loops sending the queued packets to `peer` until the bus is closed.
+func (b *InvalidationBusTcp) write(peer *tcpBusPeer) {
+	ctx := context.Background()
+	for {
+		select {
+		case <-b.closeChan:
+			return
+		case packet := <-peer.queue:
+			if err := b.send(peer, packet); err != nil && !b.closed.Val() {
+				intlog.Errorf(ctx, `%+v`, err)
+			}
+		}
+	}
+}
+
+// send sends packet to `peer`, which reuses the cached connection and reconnects once if it fails.
+func (b *InvalidationBusTcp) send(peer *tcpBusPeer, packet []byte) (err error) {
+	var conn net.Conn
+	for i := 0; i < 2; i++ {
+		if conn, err = b.getConn(peer); err != nil {
+			return err
+		}
+		if err = conn.SetWriteDeadline(time.Now().Add(tcpBusTimeout)); err == nil {
+			if _, err = conn.Write(packet); err == nil {
+				return nil
+			}
+		}
+		// The cached connection might be closed by the peer, it retries with a new one.
+		_ = conn.Close()
+		peer.mu.Lock()
+		if peer.conn == conn {
+			peer.conn = nil
+		}
+		peer.mu.Unlock()
+	}
+	return gerror.Wrapf(err, `send invalidation message to peer "%s" failed`, peer.address)
+}
+
+// getConn returns the cached connection to `peer`, or creates one if it is not connected.
+// It dials without holding the lock, as the connection is only created by the writer of the peer.
+func (b *InvalidationBusTcp) getConn(peer *tcpBusPeer) (net.Conn, error) {
+	peer.mu.Lock()
+	conn := peer.conn
+	peer.mu.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+	conn, err := net.DialTimeout("tcp", peer.address, tcpBusTimeout)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `connect to peer "%s" failed`, peer.address)
+	}
+	peer.mu.Lock()
+	defer peer.mu.Unlock()
+	// It checks the closed status under the lock, so that the connection is not leaked by Close.
+	if b.closed.Val() {
+		_ = conn.Close()
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, `invalidation bus is closed`)
+	}
+	peer.conn = conn
+	return conn, nil
+}
+
+// accept loops accepting connections from peers until the bus is closed.
+func (b *InvalidationBusTcp) accept() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			if b.closed.Val() || gerror.Is(err, net.ErrClosed) {
+				return
+			}
+			intlog.Errorf(context.Background(), `accept invalidation bus connection failed: %+v`, err)
+			continue
+		}
+		b.mu.Lock()
+		if b.closed.Val() {
+			b.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		b.accepted[conn] = struct{}{}
+		b.mu.Unlock()
+		go b.handleConn(conn)
+	}
+}
+
+// handleConn receives and dispatches messages from a peer connection.
+func (b *InvalidationBusTcp) handleConn(conn net.Conn) {
+	var (
+		ctx    = context.Background()
+		header = make([]byte, tcpBusHeaderSize)
+	)
+	defer func() {
+		b.mu.Lock()
+		delete(b.accepted, conn)
+		b.mu.Unlock()
+		_ = conn.Close()
+	}()
+	for !b.closed.Val() {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		length := binary.BigEndian.Uint32(header)
+		if length > tcpBusMaxMessageSize {
+			intlog.Errorf(ctx, `invalid invalidation message length: %d`, length)
+			return
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(conn, data); err != nil {
+			return
+		}
+		if err := b.dispatch(ctx, data); err != nil {
+			intlog.Errorf(ctx, `dispatch invalidation message failed: %+v`, err)
+		}
+	}
+}
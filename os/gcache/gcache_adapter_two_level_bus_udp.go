@@ -0,0 +1,136 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"net"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/net/gudp"
+)
+
+// InvalidationBusUdp is the InvalidationBus implements using UDP,
+// which broadcasts messages using multicast group or to specified peers.
+type InvalidationBusUdp struct {
+	invalidationHandlers
+	conn    *gudp.ServerConn // conn is the listening connection for receiving and sending messages.
+	targets []*net.UDPAddr   // targets are the addresses that messages are sent to.
+	closed  *gtype.Bool      // closed marks the bus closed.
+}
+
+const (
+	// udpBusMaxMessageSize is the max size of a single UDP message, which is lesser than max UDP payload size.
+	udpBusMaxMessageSize = 60000
+)
+
+var (
+	// Check the implements for interface InvalidationBus.
+	_ InvalidationBus = (*InvalidationBusUdp)(nil)
+)
+
+// NewInvalidationBusUdp creates and returns an InvalidationBus using UDP.
+//
+// If `address` is a multicast address like "239.0.0.1:9527", it joins the multicast group
+// and sends messages to the group, in which all instances should use the same group address.
+// Or else it listens on `address` and sends messages to each of `peers` directly,
+// which is useful in networks that do not support multicast.
+func NewInvalidationBusUdp(address string, peers ...string) (*InvalidationBusUdp, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `net.ResolveUDPAddr failed for address "%s"`, address)
+	}
+	var (
+		listenedConn *net.UDPConn
+		targets      = make([]*net.UDPAddr, 0)
+	)
+	if addr.IP != nil && addr.IP.IsMulticast() {
+		if listenedConn, err = net.ListenMulticastUDP("udp", nil, addr); err != nil {
+			return nil, gerror.Wrapf(err, `net.ListenMulticastUDP failed for address "%s"`, address)
+		}
+		targets = append(targets, addr)
+	} else {
+		if listenedConn, err = net.ListenUDP("udp", addr); err != nil {
+			return nil, gerror.Wrapf(err, `net.ListenUDP failed for address "%s"`, address)
+		}
+	}
+	for _, peer := range peers {
+		peerAddr, err := net.ResolveUDPAddr("udp", peer)
+		if err != nil {
+			_ = listenedConn.Close()
+			return nil, gerror.Wrapf(err, `net.ResolveUDPAddr failed for address "%s"`, peer)
+		}
+		targets = append(targets, peerAddr)
+	}
+	bus := &InvalidationBusUdp{
+		conn:    gudp.NewServerConn(listenedConn),
+		targets: targets,
+		closed:  gtype.NewBool(),
+	}
+	go bus.receive()
+	return bus, nil
+}
+
+// Publish broadcasts the invalidation message to other instances.
+// The message is split into multiple messages if it exceeds the max UDP message size.
+func (b *InvalidationBusUdp) Publish(ctx context.Context, message InvalidationMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if len(data) > udpBusMaxMessageSize && len(message.Keys) > 1 {
+		half := len(message.Keys) / 2
+		if err = b.Publish(ctx, InvalidationMessage{
+			Source: message.Source,
+			Keys:   message.Keys[:half],
+		}); err != nil {
+			return err
+		}
+		return b.Publish(ctx, InvalidationMessage{
+			Source: message.Source,
+			Keys:   message.Keys[half:],
+		})
+	}
+	for _, target := range b.targets {
+		if e := b.conn.Send(data, target); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// GetListenedAddress retrieves and returns the address that current bus listens on.
+func (b *InvalidationBusUdp) GetListenedAddress() string {
+	return b.conn.LocalAddr().String()
+}
+
+// Close closes the bus.
+func (b *InvalidationBusUdp) Close(ctx context.Context) error {
+	b.closed.Set(true)
+	return b.conn.Close()
+}
+
+// receive loops receiving messages until the bus is closed.
+func (b *InvalidationBusUdp) receive() {
+	var ctx = context.Background()
+	for !b.closed.Val() {
+		data, _, err := b.conn.Recv(udpBusMaxMessageSize)
+		if err != nil {
+			if b.closed.Val() || gerror.Is(err, net.ErrClosed) {
+				return
+			}
+			intlog.Errorf(ctx, `receive invalidation message failed: %+v`, err)
+			continue
+		}
+		if err = b.dispatch(ctx, data); err != nil {
+			intlog.Errorf(ctx, `dispatch invalidation message failed: %+v`, err)
+		}
+	}
+}
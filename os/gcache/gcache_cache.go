@@ -15,6 +15,8 @@ import (
 // Cache struct.
 type Cache struct {
 	localAdapter
+	flight   cacheFlight   // flight manages the in-flight loading calls for GetOrSetFuncSingle and GetOrSetFuncStale.
+	observer cacheObserver // observer collects the statistics and calls the hooks, which is opt-in.
 }
 
 // localAdapter is alias of Adapter, for embedded attribute purpose only.
@@ -47,6 +49,9 @@ func NewWithAdapter(adapter Adapter) *Cache {
 // this setting function concurrently in multiple goroutines.
 func (c *Cache) SetAdapter(adapter Adapter) {
 	c.localAdapter = adapter
+	if c.observer.active.Val() {
+		c.activateObserver()
+	}
 }
 
 // GetAdapter returns the adapter that is set in current Cache.
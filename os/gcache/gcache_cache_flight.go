@@ -0,0 +1,189 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+)
+
+// cacheFlight manages the in-flight loading calls by key,
+// which makes sure that only one loading call is executing for the same key at the same time.
+type cacheFlight struct {
+	mu    sync.Mutex
+	calls map[interface{}]*cacheFlightCall
+}
+
+// cacheFlightCall is an in-flight or completed loading call.
+type cacheFlightCall struct {
+	wg    sync.WaitGroup
+	value *gvar.Var
+	err   error
+}
+
+// GetOrSetFuncSingle retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+//
+// Note that it differs from function `GetOrSetFunc` is that the function `f` is executed only once
+// for the same `key` concurrently, and other callers of the same `key` wait and share its result.
+// It differs from function `GetOrSetFuncLock` is that it does not block callers of other keys.
+// It works with any Adapter.
+func (c *Cache) GetOrSetFuncSingle(ctx context.Context, key interface{}, f Func, duration time.Duration) (*gvar.Var, error) {
+	v, err := c.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		return v, nil
+	}
+	return c.flight.do(key, func() (*gvar.Var, error) {
+		// Double check, as the value might be set by the previous call just finished.
+		v, err := c.localAdapter.Get(ctx, key)
+		if err != nil || v != nil {
+			return v, err
+		}
+		return c.loadAndSet(ctx, key, f, duration)
+	})
+}
+
+// GetOrSetFuncStale retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair turns stale after `softDuration` and expires after `duration`.
+//
+// The stale value is returned immediately, and exactly one background goroutine is started to
+// refresh the value using function `f`, which is also known as stale-while-revalidate.
+// The loading of missing values is executed only once for the same `key` like GetOrSetFuncSingle.
+//
+// The staleness is calculated using the remaining TTL of the `key` from the Adapter, so it works
+// with any Adapter, and the values can be read by other functions like Get as usual.
+// It acts like GetOrSetFuncSingle if `softDuration` <= 0 or `softDuration` >= `duration`.
+//
+// Note that the refreshing error is only logged, and the stale value is kept until it expires.
+func (c *Cache) GetOrSetFuncStale(ctx context.Context, key interface{}, f Func, softDuration, duration time.Duration) (*gvar.Var, error) {
+	if softDuration <= 0 || softDuration >= duration {
+		return c.GetOrSetFuncSingle(ctx, key, f, duration)
+	}
+	v, err := c.GetOrSetFuncSingle(ctx, key, f, duration)
+	if err != nil || v == nil {
+		return v, err
+	}
+	expire, err := c.GetExpire(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	// The key does not expire or does not exist, which is not set by this function.
+	if expire <= 0 || expire > duration {
+		return v, nil
+	}
+	if expire <= duration-softDuration {
+		// The refreshing should not be canceled along with the request.
+		refreshCtx := context.WithoutCancel(ctx)
+		c.flight.doAsync(key, func() (*gvar.Var, error) {
+			value, err := c.loadAndSet(refreshCtx, key, f, duration)
+			if err != nil {
+				intlog.Errorf(refreshCtx, `refresh stale cache of key "%v" failed: %+v`, key, err)
+			}
+			return value, err
+		})
+	}
+	return v, nil
+}
+
+// loadAndSet calls function `f` and sets its result to the cache if it is not nil.
+func (c *Cache) loadAndSet(ctx context.Context, key interface{}, f Func, duration time.Duration) (*gvar.Var, error) {
+	if c.observer.active.Val() {
+		f = c.observer.newLoad(f).Func
+	}
+	value, err := f(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	if err = c.Set(ctx, key, value, duration); err != nil {
+		return nil, err
+	}
+	return gvar.New(value), nil
+}
+
+// do executes function `fn` for `key`, and makes sure only one execution is in flight for the same `key`.
+// The duplicate callers wait for the in-flight execution and receive the same results.
+func (g *cacheFlight) do(key interface{}, fn func() (*gvar.Var, error)) (*gvar.Var, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+	call := g.add(key)
+	g.mu.Unlock()
+	if exception := g.call(key, call, fn); exception != nil {
+		panic(exception)
+	}
+	return call.value, call.err
+}
+
+// doAsync executes function `fn` for `key` in a new goroutine if no execution is in flight for `key`.
+// It returns false if there's already an execution in flight, or else it returns true.
+func (g *cacheFlight) doAsync(key interface{}, fn func() (*gvar.Var, error)) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.calls[key]; ok {
+		return false
+	}
+	call := g.add(key)
+	go func() {
+		if exception := g.call(key, call, fn); exception != nil {
+			intlog.Errorf(context.Background(), `%+v`, call.err)
+		}
+	}()
+	return true
+}
+
+// add creates and adds a new call for `key`, which should be called within lock.
+func (g *cacheFlight) add(key interface{}) *cacheFlightCall {
+	if g.calls == nil {
+		g.calls = make(map[interface{}]*cacheFlightCall)
+	}
+	call := &cacheFlightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	return call
+}
+
+// call executes function `fn` and releases the waiters of the call.
+// It recovers and returns the exception if `fn` panics, in which case the waiters receive an error.
+func (g *cacheFlight) call(key interface{}, call *cacheFlightCall, fn func() (*gvar.Var, error)) (exception interface{}) {
+	defer func() {
+		if exception = recover(); exception != nil {
+			call.err = gerror.NewCodef(gcode.CodeInternalPanic, `cache loading of key "%v" panics: %+v`, key, exception)
+		}
+		g.done(key, call)
+	}()
+	call.value, call.err = fn()
+	return nil
+}
+
+// done removes the call for `key` and releases its waiters.
+func (g *cacheFlight) done(key interface{}, call *cacheFlightCall) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	call.wg.Done()
+}
@@ -0,0 +1,106 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"time"
+
+	"github.com/ximplez-go/gf"
+	"github.com/ximplez-go/gf/os/gmetric"
+)
+
+// localMetricManager manages the metrics of Cache,
+// which are published only if stats is enabled for the Cache.
+type localMetricManager struct {
+	CacheHits         gmetric.Counter
+	CacheMisses       gmetric.Counter
+	CacheSets         gmetric.Counter
+	CacheEvictions    gmetric.Counter
+	CacheLoads        gmetric.Counter
+	CacheLoadDuration gmetric.Histogram
+}
+
+const (
+	instrument                  = "github.com/ximplez-go/gf/os/gcache.Cache"
+	metricAttrKeyCacheName      = "cache.name"
+	metricAttrKeyEvictionReason = "eviction.reason"
+	metricAttrKeyLoadError      = "error"
+)
+
+// metricManager records the hits, misses, sets, evictions and loads of caches with stats enabled.
+var metricManager = newMetricManager()
+
+// newMetricManager creates the access and eviction metrics of gcache.
+func newMetricManager() *localMetricManager {
+	meter := gmetric.GetGlobalProvider().Meter(gmetric.MeterOption{
+		Instrument:        instrument,
+		InstrumentVersion: gf.VERSION,
+	})
+	return &localMetricManager{
+		CacheHits: meter.MustCounter(
+			"gcache.hits",
+			gmetric.MetricOption{
+				Help: "Total number of reads that the key exists in the cache.",
+			},
+		),
+		CacheMisses: meter.MustCounter(
+			"gcache.misses",
+			gmetric.MetricOption{
+				Help: "Total number of reads that the key does not exist in the cache.",
+			},
+		),
+		CacheSets: meter.MustCounter(
+			"gcache.sets",
+			gmetric.MetricOption{
+				Help: "Total number of values set to the cache.",
+			},
+		),
+		CacheEvictions: meter.MustCounter(
+			"gcache.evictions",
+			gmetric.MetricOption{
+				Help: "Total number of items evicted from the cache by LRU or expiration.",
+			},
+		),
+		CacheLoads: meter.MustCounter(
+			"gcache.loads",
+			gmetric.MetricOption{
+				Help: "Total number of value loading function calls.",
+			},
+		),
+		CacheLoadDuration: meter.MustHistogram(
+			"gcache.load.duration",
+			gmetric.MetricOption{
+				Help: "Measures the duration of value loading function calls.",
+				Unit: "ms",
+				Buckets: []float64{
+					1, 5, 10, 25, 50, 75, 100, 250, 500, 750,
+					1000, 2500, 5000, 7500, 10000,
+				},
+			},
+		),
+	}
+}
+
+// newMetricOption creates and returns the metric operation option for cache named `name`.
+func (m *localMetricManager) newMetricOption(name string, attributes ...gmetric.Attribute) gmetric.Option {
+	return gmetric.Option{
+		Attributes: append(gmetric.Attributes{
+			gmetric.NewAttribute(metricAttrKeyCacheName, name),
+		}, attributes...),
+	}
+}
+
+// handleLoad records the metrics for a loading function call.
+func (m *localMetricManager) handleLoad(ctx context.Context, name string, duration time.Duration, err error) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	option := m.newMetricOption(name, gmetric.NewAttribute(metricAttrKeyLoadError, err != nil))
+	m.CacheLoads.Inc(ctx, option)
+	m.CacheLoadDuration.Record(float64(duration)/float64(time.Millisecond), option)
+}
@@ -49,6 +49,24 @@ func (c *Cache) MustGetOrSetFuncLock(ctx context.Context, key interface{}, f Fun
 	return v
 }
 
+// MustGetOrSetFuncSingle acts like GetOrSetFuncSingle, but it panics if any error occurs.
+func (c *Cache) MustGetOrSetFuncSingle(ctx context.Context, key interface{}, f Func, duration time.Duration) *gvar.Var {
+	v, err := c.GetOrSetFuncSingle(ctx, key, f, duration)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustGetOrSetFuncStale acts like GetOrSetFuncStale, but it panics if any error occurs.
+func (c *Cache) MustGetOrSetFuncStale(ctx context.Context, key interface{}, f Func, softDuration, duration time.Duration) *gvar.Var {
+	v, err := c.GetOrSetFuncStale(ctx, key, f, softDuration, duration)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
 // MustContains acts like Contains, but it panics if any error occurs.
 func (c *Cache) MustContains(ctx context.Context, key interface{}) bool {
 	v, err := c.Contains(ctx, key)
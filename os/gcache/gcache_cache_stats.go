@@ -0,0 +1,460 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/os/gmetric"
+)
+
+// Stats is the statistics of Cache, which is collected after Cache.EnableStats is called.
+type Stats struct {
+	Hits            int64         // Hits is the number of reads that the key exists.
+	Misses          int64         // Misses is the number of reads that the key does not exist.
+	Sets            int64         // Sets is the number of values set to the cache.
+	EvictionsLru    int64         // EvictionsLru is the number of items evicted by LRU.
+	EvictionsExpire int64         // EvictionsExpire is the number of items evicted by expiration.
+	Loads           int64         // Loads is the number of loading function calls, like the function of GetOrSetFunc.
+	LoadErrors      int64         // LoadErrors is the number of loading function calls that return error.
+	LoadDuration    time.Duration // LoadDuration is the total duration of loading function calls.
+}
+
+// HitRatio returns the ratio of hits to total reads, or 0 if there's no read.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// HookFunc is the hook function called for cache events of `key`-`value` pair.
+type HookFunc func(ctx context.Context, key, value interface{})
+
+// evictReason is the reason that an item is evicted from the cache.
+type evictReason int
+
+const (
+	evictReasonLru    evictReason = iota // The item is evicted by LRU.
+	evictReasonExpire                    // The item is evicted by expiration.
+)
+
+// evictHandler is the handler called by adapter when an item is evicted.
+type evictHandler func(ctx context.Context, key, value interface{}, reason evictReason)
+
+// evictNotifier is implemented by the adapters that can notify the evictions, like AdapterMemory.
+type evictNotifier interface {
+	setEvictHandler(handler evictHandler)
+}
+
+// cacheObserver collects the statistics and calls the hooks for Cache.
+type cacheObserver struct {
+	active          gtype.Bool // active marks either stats or any hook is enabled.
+	statsEnabled    gtype.Bool
+	hits            gtype.Int64
+	misses          gtype.Int64
+	sets            gtype.Int64
+	evictionsLru    gtype.Int64
+	evictionsExpire gtype.Int64
+	loads           gtype.Int64
+	loadErrors      gtype.Int64
+	loadDuration    gtype.Int64
+	mu              sync.RWMutex   // mu guards the attributes below.
+	name            string         // name is the cache name for metrics.
+	metricOption    gmetric.Option // metricOption is the metric option with cache name attribute.
+	onSet           []HookFunc
+	onEvict         []HookFunc
+	onExpire        []HookFunc
+}
+
+// EnableStats enables statistics collecting for the cache, which can be retrieved by Stats.
+// The optional parameter `name` specifies the cache name, which is used as attribute "cache.name"
+// of the metrics that are published automatically if the metric provider of gmetric is set.
+//
+// Note that the LRU and expiration evictions are only available for adapters supporting eviction
+// notification, like the memory adapter.
+func (c *Cache) EnableStats(name ...string) {
+	c.observer.mu.Lock()
+	if len(name) > 0 {
+		c.observer.name = name[0]
+	}
+	c.observer.metricOption = metricManager.newMetricOption(c.observer.name)
+	c.observer.mu.Unlock()
+	c.observer.statsEnabled.Set(true)
+	c.activateObserver()
+}
+
+// Stats returns the statistics of the cache collected since EnableStats is called.
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:            c.observer.hits.Val(),
+		Misses:          c.observer.misses.Val(),
+		Sets:            c.observer.sets.Val(),
+		EvictionsLru:    c.observer.evictionsLru.Val(),
+		EvictionsExpire: c.observer.evictionsExpire.Val(),
+		Loads:           c.observer.loads.Val(),
+		LoadErrors:      c.observer.loadErrors.Val(),
+		LoadDuration:    time.Duration(c.observer.loadDuration.Val()),
+	}
+}
+
+// OnSet registers `hook` which is called after a value is set to the cache,
+// including the values set by loading functions and Update.
+func (c *Cache) OnSet(hook HookFunc) {
+	c.observer.mu.Lock()
+	c.observer.onSet = append(c.observer.onSet, hook)
+	c.observer.mu.Unlock()
+	c.activateObserver()
+}
+
+// OnEvict registers `hook` which is called after an item is evicted by LRU.
+// It is useful for releasing the resources held by the cached values.
+//
+// Note that it is only available for adapters supporting eviction notification, like the memory adapter.
+func (c *Cache) OnEvict(hook HookFunc) {
+	c.observer.mu.Lock()
+	c.observer.onEvict = append(c.observer.onEvict, hook)
+	c.observer.mu.Unlock()
+	c.activateObserver()
+}
+
+// OnExpire registers `hook` which is called after an expired item is cleaned up from the cache.
+// It is useful for releasing the resources held by the cached values.
+//
+// Note that it is only available for adapters supporting eviction notification, like the memory adapter,
+// and the expired items are cleaned up asynchronously, so the hook might be called a few seconds later.
+func (c *Cache) OnExpire(hook HookFunc) {
+	c.observer.mu.Lock()
+	c.observer.onExpire = append(c.observer.onExpire, hook)
+	c.observer.mu.Unlock()
+	c.activateObserver()
+}
+
+// Get retrieves and returns the associated value of given `key`.
+// It returns nil if it does not exist, or its value is nil, or it's expired.
+// If you would like to check if the `key` exists in the cache, it's better using function Contains.
+func (c *Cache) Get(ctx context.Context, key interface{}) (*gvar.Var, error) {
+	v, err := c.localAdapter.Get(ctx, key)
+	if err == nil && c.observer.active.Val() {
+		c.observer.handleRead(ctx, v != nil)
+	}
+	return v, err
+}
+
+// Set sets cache with `key`-`value` pair, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *Cache) Set(ctx context.Context, key interface{}, value interface{}, duration time.Duration) error {
+	err := c.localAdapter.Set(ctx, key, value, duration)
+	if err == nil && c.observer.active.Val() && value != nil && duration >= 0 {
+		c.observer.handleSet(ctx, key, value)
+	}
+	return err
+}
+
+// SetMap batch sets cache with key-value pairs by `data` map, which is expired after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the keys of `data` if `duration` < 0 or given `value` is nil.
+func (c *Cache) SetMap(ctx context.Context, data map[interface{}]interface{}, duration time.Duration) error {
+	err := c.localAdapter.SetMap(ctx, data, duration)
+	if err == nil && c.observer.active.Val() && duration >= 0 {
+		for k, v := range data {
+			if v != nil {
+				c.observer.handleSet(ctx, k, v)
+			}
+		}
+	}
+	return err
+}
+
+// SetIfNotExist sets cache with `key`-`value` pair which is expired after `duration`
+// if `key` does not exist in the cache. It returns true the `key` does not exist in the
+// cache, and it sets `value` successfully to the cache, or else it returns false.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *Cache) SetIfNotExist(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (bool, error) {
+	ok, err := c.localAdapter.SetIfNotExist(ctx, key, value, duration)
+	if ok && err == nil && c.observer.active.Val() {
+		c.observer.handleSet(ctx, key, value)
+	}
+	return ok, err
+}
+
+// SetIfNotExistFunc sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+func (c *Cache) SetIfNotExistFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (bool, error) {
+	if !c.observer.active.Val() {
+		return c.localAdapter.SetIfNotExistFunc(ctx, key, f, duration)
+	}
+	load := c.observer.newLoad(f)
+	ok, err := c.localAdapter.SetIfNotExistFunc(ctx, key, load.Func, duration)
+	if ok && err == nil && load.value != nil {
+		c.observer.handleSet(ctx, key, load.value)
+	}
+	return ok, err
+}
+
+// SetIfNotExistFuncLock sets `key` with result of function `f` and returns true
+// if `key` does not exist in the cache, or else it does nothing and returns false if `key` already exists.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil.
+//
+// Note that it differs from function `SetIfNotExistFunc` is that the function `f` is executed within
+// writing mutex lock for concurrent safety purpose.
+func (c *Cache) SetIfNotExistFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (bool, error) {
+	if !c.observer.active.Val() {
+		return c.localAdapter.SetIfNotExistFuncLock(ctx, key, f, duration)
+	}
+	load := c.observer.newLoad(f)
+	ok, err := c.localAdapter.SetIfNotExistFuncLock(ctx, key, load.Func, duration)
+	if ok && err == nil && load.value != nil {
+		c.observer.handleSet(ctx, key, load.value)
+	}
+	return ok, err
+}
+
+// GetOrSet retrieves and returns the value of `key`, or sets `key`-`value` pair and
+// returns `value` if `key` does not exist in the cache. The key-value pair expires
+// after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *Cache) GetOrSet(ctx context.Context, key interface{}, value interface{}, duration time.Duration) (*gvar.Var, error) {
+	if !c.observer.active.Val() {
+		return c.localAdapter.GetOrSet(ctx, key, value, duration)
+	}
+	// The value setting is counted as miss but not as load.
+	// It calls GetOrSet of the adapter as it does without observer, so the function value is still
+	// evaluated by the adapter, and only the function value is wrapped for knowing whether it is called.
+	var (
+		missed   bool
+		observed = true
+	)
+	switch f := value.(type) {
+	case Func:
+		value = Func(func(ctx context.Context) (interface{}, error) {
+			missed = true
+			return f(ctx)
+		})
+	case nil:
+		// It deletes the key, which is neither a hit nor a miss.
+		observed = false
+	default:
+		// The plain value cannot tell whether it is set, so it checks the existence in advance.
+		ok, err := c.localAdapter.Contains(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		missed = !ok
+	}
+	v, err := c.localAdapter.GetOrSet(ctx, key, value, duration)
+	if err == nil && observed {
+		c.observer.handleRead(ctx, !missed)
+		if missed && v != nil && duration >= 0 {
+			c.observer.handleSet(ctx, key, v.Val())
+		}
+	}
+	return v, err
+}
+
+// GetOrSetFunc retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+func (c *Cache) GetOrSetFunc(ctx context.Context, key interface{}, f Func, duration time.Duration) (*gvar.Var, error) {
+	if !c.observer.active.Val() {
+		return c.localAdapter.GetOrSetFunc(ctx, key, f, duration)
+	}
+	load := c.observer.newLoad(f)
+	v, err := c.localAdapter.GetOrSetFunc(ctx, key, load.Func, duration)
+	c.observer.handleGetOrSet(ctx, key, load, err, duration)
+	return v, err
+}
+
+// GetOrSetFuncLock retrieves and returns the value of `key`, or sets `key` with result of
+// function `f` and returns its result if `key` does not exist in the cache. The key-value
+// pair expires after `duration`.
+//
+// It does not expire if `duration` == 0.
+// It deletes the `key` if `duration` < 0 or given `value` is nil, but it does nothing
+// if `value` is a function and the function result is nil.
+//
+// Note that it differs from function `GetOrSetFunc` is that the function `f` is executed within
+// writing mutex lock for concurrent safety purpose.
+func (c *Cache) GetOrSetFuncLock(ctx context.Context, key interface{}, f Func, duration time.Duration) (*gvar.Var, error) {
+	if !c.observer.active.Val() {
+		return c.localAdapter.GetOrSetFuncLock(ctx, key, f, duration)
+	}
+	load := c.observer.newLoad(f)
+	v, err := c.localAdapter.GetOrSetFuncLock(ctx, key, load.Func, duration)
+	c.observer.handleGetOrSet(ctx, key, load, err, duration)
+	return v, err
+}
+
+// Update updates the value of `key` without changing its expiration and returns the old value.
+// The returned value `exist` is false if the `key` does not exist in the cache.
+//
+// It deletes the `key` if given `value` is nil.
+// It does nothing if `key` does not exist in the cache.
+func (c *Cache) Update(ctx context.Context, key interface{}, value interface{}) (oldValue *gvar.Var, exist bool, err error) {
+	oldValue, exist, err = c.localAdapter.Update(ctx, key, value)
+	if exist && err == nil && value != nil && c.observer.active.Val() {
+		c.observer.handleSet(ctx, key, value)
+	}
+	return
+}
+
+// activateObserver activates the observer and registers the evict handler to the adapter.
+func (c *Cache) activateObserver() {
+	c.observer.active.Set(true)
+	if notifier, ok := c.localAdapter.(evictNotifier); ok {
+		notifier.setEvictHandler(c.observer.handleEvict)
+	}
+}
+
+// cacheObserverLoad wraps the loading function for statistics.
+type cacheObserverLoad struct {
+	observer *cacheObserver
+	f        Func
+	called   bool        // called marks the loading function is called.
+	value    interface{} // value is the result of the loading function.
+}
+
+// newLoad creates and returns a loading function wrapper for `f`.
+func (o *cacheObserver) newLoad(f Func) *cacheObserverLoad {
+	return &cacheObserverLoad{
+		observer: o,
+		f:        f,
+	}
+}
+
+// Func calls the loading function and collects its statistics.
+func (l *cacheObserverLoad) Func(ctx context.Context) (value interface{}, err error) {
+	l.called = true
+	start := time.Now()
+	value, err = l.f(ctx)
+	l.observer.handleLoad(ctx, time.Since(start), err)
+	if err == nil {
+		l.value = value
+	}
+	return
+}
+
+// handleRead collects statistics for reading of hit or miss.
+func (o *cacheObserver) handleRead(ctx context.Context, hit bool) {
+	if !o.statsEnabled.Val() {
+		return
+	}
+	if hit {
+		o.hits.Add(1)
+	} else {
+		o.misses.Add(1)
+	}
+	if gmetric.IsEnabled() {
+		if hit {
+			metricManager.CacheHits.Inc(ctx, o.getMetricOption())
+		} else {
+			metricManager.CacheMisses.Inc(ctx, o.getMetricOption())
+		}
+	}
+}
+
+// handleSet collects statistics and calls the hooks for value setting.
+func (o *cacheObserver) handleSet(ctx context.Context, key, value interface{}) {
+	if o.statsEnabled.Val() {
+		o.sets.Add(1)
+		if gmetric.IsEnabled() {
+			metricManager.CacheSets.Inc(ctx, o.getMetricOption())
+		}
+	}
+	o.mu.RLock()
+	hooks := o.onSet
+	o.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, key, value)
+	}
+}
+
+// handleLoad collects statistics for a loading function call.
+func (o *cacheObserver) handleLoad(ctx context.Context, duration time.Duration, err error) {
+	if !o.statsEnabled.Val() {
+		return
+	}
+	o.loads.Add(1)
+	o.loadDuration.Add(int64(duration))
+	if err != nil {
+		o.loadErrors.Add(1)
+	}
+	o.mu.RLock()
+	name := o.name
+	o.mu.RUnlock()
+	metricManager.handleLoad(ctx, name, duration, err)
+}
+
+// handleGetOrSet collects statistics and calls the hooks after GetOrSetFunc like functions.
+func (o *cacheObserver) handleGetOrSet(ctx context.Context, key interface{}, load *cacheObserverLoad, err error, duration time.Duration) {
+	if err != nil {
+		return
+	}
+	o.handleRead(ctx, !load.called)
+	if load.called && load.value != nil && duration >= 0 {
+		o.handleSet(ctx, key, load.value)
+	}
+}
+
+// handleEvict collects statistics and calls the hooks for evicted item.
+func (o *cacheObserver) handleEvict(ctx context.Context, key, value interface{}, reason evictReason) {
+	var (
+		hooks          []HookFunc
+		counter        = &o.evictionsLru
+		reasonAttrName = "lru"
+	)
+	o.mu.RLock()
+	if reason == evictReasonExpire {
+		hooks = o.onExpire
+		counter = &o.evictionsExpire
+		reasonAttrName = "expire"
+	} else {
+		hooks = o.onEvict
+	}
+	name := o.name
+	o.mu.RUnlock()
+	if o.statsEnabled.Val() {
+		counter.Add(1)
+		if gmetric.IsEnabled() {
+			metricManager.CacheEvictions.Inc(ctx, metricManager.newMetricOption(
+				name, gmetric.NewAttribute(metricAttrKeyEvictionReason, reasonAttrName),
+			))
+		}
+	}
+	for _, hook := range hooks {
+		hook(ctx, key, value)
+	}
+}
+
+// getMetricOption returns the metric option with cache name attribute.
+func (o *cacheObserver) getMetricOption() gmetric.Option {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.metricOption
+}
@@ -0,0 +1,197 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/os/gcache"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gkvdb"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func Test_AdapterKvdb_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp("gcache", gtime.TimestampNanoStr(), "cache.db")
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+
+		cache := gcache.NewWithAdapter(gcache.NewAdapterKvdb(db))
+		t.AssertNil(cache.Set(ctx, 1, 11, 0))
+		v, err := cache.Get(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(v.Int(), 11)
+
+		v, err = cache.Get(ctx, 2)
+		t.AssertNil(err)
+		t.Assert(v, nil)
+
+		// Map value is serialized as JSON.
+		t.AssertNil(cache.Set(ctx, "map", map[string]int{"a": 1}, 0))
+		v, err = cache.Get(ctx, "map")
+		t.AssertNil(err)
+		t.Assert(v.Map(), map[string]interface{}{"a": 1})
+
+		// nil value deletes the key.
+		t.AssertNil(cache.Set(ctx, 1, nil, 0))
+		ok, err := cache.Contains(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+
+		t.AssertNil(cache.SetMap(ctx, map[interface{}]interface{}{"k1": "v1", "k2": "v2"}, 0))
+		keys, err := cache.KeyStrings(ctx)
+		t.AssertNil(err)
+		t.AssertIN("k1", keys)
+		t.Assert(len(keys), 3)
+
+		v, err = cache.Remove(ctx, "k1", "k2")
+		t.AssertNil(err)
+		t.Assert(v, "v2")
+
+		ok, err = cache.SetIfNotExist(ctx, "k", "v1", 0)
+		t.AssertNil(err)
+		t.Assert(ok, true)
+		v, err = cache.GetOrSet(ctx, "k", "v2", 0)
+		t.AssertNil(err)
+		t.Assert(v, "v1")
+		t.AssertNil(db.Close())
+
+		// The cache is durable.
+		db, err = gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+		cache = gcache.NewWithAdapter(gcache.NewAdapterKvdb(db))
+		data, err := cache.Data(ctx)
+		t.AssertNil(err)
+		t.Assert(data, map[interface{}]interface{}{"map": `{"a":1}`, "k": "v1"})
+
+		t.AssertNil(cache.Clear(ctx))
+		size, err := cache.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 0)
+	})
+}
+
+func Test_AdapterKvdb_Expire(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp("gcache", gtime.TimestampNanoStr(), "cache.db")
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+
+		cache := gcache.NewWithAdapter(gcache.NewAdapterKvdb(db))
+		t.AssertNil(cache.Set(ctx, "k", "v", 200*time.Millisecond))
+		expire, err := cache.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.AssertGT(expire, 0)
+		t.AssertLE(expire, 200*time.Millisecond)
+
+		// Update keeps the expiration.
+		old, exist, err := cache.Update(ctx, "k", "v2")
+		t.AssertNil(err)
+		t.Assert(exist, true)
+		t.Assert(old, "v")
+		expire, err = cache.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.AssertGT(expire, 0)
+
+		time.Sleep(300 * time.Millisecond)
+		v, err := cache.Get(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(v, nil)
+
+		t.AssertNil(cache.Set(ctx, "k", "v", 0))
+		oldExpire, err := cache.UpdateExpire(ctx, "k", time.Second)
+		t.AssertNil(err)
+		t.Assert(oldExpire, time.Duration(0))
+		oldExpire, err = cache.UpdateExpire(ctx, "none", time.Second)
+		t.AssertNil(err)
+		t.Assert(oldExpire, time.Duration(-1))
+		_, err = cache.UpdateExpire(ctx, "k", -1)
+		t.AssertNil(err)
+		ok, err := cache.Contains(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+	})
+}
+
+func Test_AdapterKvdb_Prefix(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp("gcache", gtime.TimestampNanoStr(), "cache.db")
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+
+		var (
+			cache1 = gcache.NewWithAdapter(gcache.NewAdapterKvdb(db, "cache1:"))
+			cache2 = gcache.NewWithAdapter(gcache.NewAdapterKvdb(db, "cache2:"))
+		)
+		t.AssertNil(cache1.Set(ctx, "k", 1, 0))
+		t.AssertNil(cache2.Set(ctx, "k", 2, 0))
+
+		keys, err := cache1.Keys(ctx)
+		t.AssertNil(err)
+		t.Assert(keys, []interface{}{"k"})
+		values, err := cache2.Values(ctx)
+		t.AssertNil(err)
+		t.Assert(values, []interface{}{"2"})
+
+		t.AssertNil(cache1.Clear(ctx))
+		size, err := cache1.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 0)
+		size, err = cache2.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 1)
+	})
+}
+
+func Test_AdapterKvdb_FuncLock(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp("gcache", gtime.TimestampNanoStr(), "cache.db")
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+
+		var (
+			cache = gcache.NewWithAdapter(gcache.NewAdapterKvdb(db))
+			count = gtype.NewInt()
+			wg    sync.WaitGroup
+			f     = func(ctx context.Context) (interface{}, error) {
+				count.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				return 1, nil
+			}
+		)
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				v, err := cache.GetOrSetFuncLock(ctx, "get", f, 0)
+				t.AssertNil(err)
+				t.Assert(v.Int(), 1)
+			}()
+			go func() {
+				defer wg.Done()
+				_, err := cache.SetIfNotExistFuncLock(ctx, "set", f, 0)
+				t.AssertNil(err)
+			}()
+		}
+		wg.Wait()
+		// The function runs only once for each key.
+		t.Assert(count.Val(), 2)
+	})
+}
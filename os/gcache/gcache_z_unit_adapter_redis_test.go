@@ -0,0 +1,413 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/net/gresp"
+	"github.com/ximplez-go/gf/net/gtcp"
+	"github.com/ximplez-go/gf/os/gcache"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+var (
+	ctx = context.Background()
+)
+
+// respTestItem is the value item stored in respTestServer.
+type respTestItem struct {
+	value  string
+	expire time.Time
+}
+
+// respTestServer is an in-process RESP server stand-in for Redis,
+// which implements only the commands that AdapterRedis uses.
+type respTestServer struct {
+	mu     sync.Mutex
+	data   map[string]respTestItem
+	server *gtcp.Server
+}
+
+func newRespTestServer() *respTestServer {
+	s := &respTestServer{
+		data: make(map[string]respTestItem),
+	}
+	s.server = gtcp.NewServer(gtcp.FreePortAddress, func(conn *gtcp.Conn) {
+		defer conn.Close()
+		for {
+			value, err := gresp.ReadValue(conn)
+			if err != nil {
+				return
+			}
+			if err = conn.Send(gresp.EncodeValue(s.handle(gconv.Strings(value)))); err != nil {
+				return
+			}
+		}
+	})
+	go s.server.Run()
+	time.Sleep(100 * time.Millisecond)
+	return s
+}
+
+func (s *respTestServer) Close() {
+	_ = s.server.Close()
+}
+
+func (s *respTestServer) Address() string {
+	return s.server.GetListenedAddress()
+}
+
+// get returns the item of `key` if it exists and not expired. It should be called with lock.
+func (s *respTestServer) get(key string) (respTestItem, bool) {
+	item, ok := s.data[key]
+	if ok && !item.expire.IsZero() && !item.expire.After(time.Now()) {
+		delete(s.data, key)
+		return item, false
+	}
+	return item, ok
+}
+
+func (s *respTestServer) handle(args []string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch command := strings.ToUpper(args[0]); command {
+	case "SET":
+		var (
+			key             = args[1]
+			item            = respTestItem{value: args[2]}
+			old, exist      = s.get(key)
+			nx, xx, keepTTL bool
+			get             bool
+		)
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				nx = true
+			case "XX":
+				xx = true
+			case "KEEPTTL":
+				keepTTL = true
+			case "GET":
+				get = true
+			case "PX":
+				i++
+				item.expire = time.Now().Add(time.Duration(gconv.Int64(args[i])) * time.Millisecond)
+			}
+		}
+		if (nx && exist) || (xx && !exist) {
+			return nil
+		}
+		if keepTTL && exist {
+			item.expire = old.expire
+		}
+		s.data[key] = item
+		if get {
+			if exist {
+				return old.value
+			}
+			return nil
+		}
+		return gresp.SimpleString("OK")
+
+	case "GET":
+		if item, ok := s.get(args[1]); ok {
+			return item.value
+		}
+		return nil
+
+	case "MSET":
+		for i := 1; i+1 < len(args); i += 2 {
+			s.data[args[i]] = respTestItem{value: args[i+1]}
+		}
+		return gresp.SimpleString("OK")
+
+	case "MGET":
+		var values = make([]interface{}, 0)
+		for _, key := range args[1:] {
+			if item, ok := s.get(key); ok {
+				values = append(values, item.value)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		return values
+
+	case "DEL", "EXISTS":
+		var count int
+		for _, key := range args[1:] {
+			if _, ok := s.get(key); ok {
+				count++
+				if command == "DEL" {
+					delete(s.data, key)
+				}
+			}
+		}
+		return count
+
+	case "PTTL":
+		item, ok := s.get(args[1])
+		switch {
+		case !ok:
+			return -2
+		case item.expire.IsZero():
+			return -1
+		default:
+			return time.Until(item.expire).Milliseconds()
+		}
+
+	case "PEXPIRE", "PERSIST":
+		item, ok := s.get(args[1])
+		if !ok {
+			return 0
+		}
+		item.expire = time.Time{}
+		if command == "PEXPIRE" {
+			item.expire = time.Now().Add(time.Duration(gconv.Int64(args[2])) * time.Millisecond)
+		}
+		s.data[args[1]] = item
+		return 1
+
+	case "SCAN":
+		// It returns all matched keys in one iteration.
+		var (
+			prefix = strings.TrimSuffix(args[3], "*")
+			keys   = make([]string, 0)
+		)
+		prefix = strings.NewReplacer(`\\`, `\`, `\*`, `*`, `\?`, `?`, `\[`, `[`, `\]`, `]`).Replace(prefix)
+		for key := range s.data {
+			if _, ok := s.get(key); ok && strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		return []interface{}{"0", keys}
+
+	case "DBSIZE":
+		var count int
+		for key := range s.data {
+			if _, ok := s.get(key); ok {
+				count++
+			}
+		}
+		return count
+
+	case "FLUSHDB":
+		s.data = make(map[string]respTestItem)
+		return gresp.SimpleString("OK")
+
+	default:
+		return gresp.Error(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+}
+
+func newRedisCache(t *gtest.T, server *respTestServer, prefix ...string) *gcache.Cache {
+	client := gresp.New(gresp.Config{
+		Address: server.Address(),
+	})
+	t.AssertNE(client, nil)
+	return gcache.NewWithAdapter(gcache.NewAdapterRedis(client, prefix...))
+}
+
+func Test_AdapterRedis_Basic(t *testing.T) {
+	server := newRespTestServer()
+	defer server.Close()
+	gtest.C(t, func(t *gtest.T) {
+		cache := newRedisCache(t, server)
+		t.AssertNil(cache.Set(ctx, 1, 11, 0))
+		v, err := cache.Get(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(v.Int(), 11)
+
+		v, err = cache.Get(ctx, 2)
+		t.AssertNil(err)
+		t.Assert(v, nil)
+
+		// Map value is serialized as JSON.
+		t.AssertNil(cache.Set(ctx, "map", map[string]int{"a": 1}, 0))
+		v, err = cache.Get(ctx, "map")
+		t.AssertNil(err)
+		t.Assert(v.Map(), map[string]interface{}{"a": 1})
+
+		ok, err := cache.Contains(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(ok, true)
+
+		// nil value deletes the key.
+		t.AssertNil(cache.Set(ctx, 1, nil, 0))
+		ok, err = cache.Contains(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+
+		t.AssertNil(cache.SetMap(ctx, map[interface{}]interface{}{"k1": "v1", "k2": "v2"}, 0))
+		size, err := cache.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 3)
+
+		keys, err := cache.KeyStrings(ctx)
+		t.AssertNil(err)
+		t.Assert(keys, []string{"k1", "k2", "map"})
+
+		data, err := cache.Data(ctx)
+		t.AssertNil(err)
+		t.Assert(data["k1"], "v1")
+		t.Assert(data["k2"], "v2")
+
+		v, err = cache.Remove(ctx, "k1", "k2")
+		t.AssertNil(err)
+		t.Assert(v, "v2")
+
+		t.AssertNil(cache.Clear(ctx))
+		size, err = cache.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 0)
+	})
+}
+
+func Test_AdapterRedis_Expire(t *testing.T) {
+	server := newRespTestServer()
+	defer server.Close()
+	gtest.C(t, func(t *gtest.T) {
+		cache := newRedisCache(t, server)
+		t.AssertNil(cache.Set(ctx, "k", "v", 200*time.Millisecond))
+		expire, err := cache.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.AssertGT(expire, 0)
+		t.AssertLE(expire, 200*time.Millisecond)
+
+		expire, err = cache.GetExpire(ctx, "none")
+		t.AssertNil(err)
+		t.Assert(expire, time.Duration(-1))
+
+		// Update keeps the expiration.
+		old, exist, err := cache.Update(ctx, "k", "v2")
+		t.AssertNil(err)
+		t.Assert(exist, true)
+		t.Assert(old, "v")
+		expire, err = cache.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.AssertGT(expire, 0)
+
+		_, exist, err = cache.Update(ctx, "none", "v")
+		t.AssertNil(err)
+		t.Assert(exist, false)
+		ok, err := cache.Contains(ctx, "none")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+
+		time.Sleep(300 * time.Millisecond)
+		v, err := cache.Get(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(v, nil)
+
+		// UpdateExpire.
+		t.AssertNil(cache.Set(ctx, "k", "v", 0))
+		oldExpire, err := cache.UpdateExpire(ctx, "k", time.Second)
+		t.AssertNil(err)
+		t.Assert(oldExpire, time.Duration(0))
+		expire, err = cache.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.AssertGT(expire, 0)
+
+		oldExpire, err = cache.UpdateExpire(ctx, "k", 0)
+		t.AssertNil(err)
+		t.AssertGT(oldExpire, 0)
+		expire, err = cache.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(expire, time.Duration(0))
+
+		oldExpire, err = cache.UpdateExpire(ctx, "none", time.Second)
+		t.AssertNil(err)
+		t.Assert(oldExpire, time.Duration(-1))
+
+		_, err = cache.UpdateExpire(ctx, "k", -1)
+		t.AssertNil(err)
+		ok, err = cache.Contains(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+	})
+}
+
+func Test_AdapterRedis_SetIfNotExist(t *testing.T) {
+	server := newRespTestServer()
+	defer server.Close()
+	gtest.C(t, func(t *gtest.T) {
+		cache := newRedisCache(t, server)
+		ok, err := cache.SetIfNotExist(ctx, "k", "v1", 0)
+		t.AssertNil(err)
+		t.Assert(ok, true)
+
+		ok, err = cache.SetIfNotExist(ctx, "k", "v2", 0)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+
+		ok, err = cache.SetIfNotExistFunc(ctx, "f", func(ctx context.Context) (interface{}, error) {
+			return "fv", nil
+		}, time.Second)
+		t.AssertNil(err)
+		t.Assert(ok, true)
+
+		v, err := cache.GetOrSet(ctx, "k", "v3", 0)
+		t.AssertNil(err)
+		t.Assert(v, "v1")
+
+		v, err = cache.GetOrSet(ctx, "g", "v3", 0)
+		t.AssertNil(err)
+		t.Assert(v, "v3")
+
+		v, err = cache.GetOrSetFuncLock(ctx, "h", func(ctx context.Context) (interface{}, error) {
+			return 100, nil
+		}, 0)
+		t.AssertNil(err)
+		t.Assert(v, 100)
+
+		v, err = cache.GetOrSetFunc(ctx, "nil", func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}, 0)
+		t.AssertNil(err)
+		t.Assert(v, nil)
+		ok, err = cache.Contains(ctx, "nil")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+	})
+}
+
+func Test_AdapterRedis_Prefix(t *testing.T) {
+	server := newRespTestServer()
+	defer server.Close()
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache1 = newRedisCache(t, server, "cache1:")
+			cache2 = newRedisCache(t, server, "cache*2:")
+		)
+		t.AssertNil(cache1.Set(ctx, "k", 1, 0))
+		t.AssertNil(cache2.Set(ctx, "k", 2, 0))
+
+		keys, err := cache1.Keys(ctx)
+		t.AssertNil(err)
+		t.Assert(keys, []interface{}{"k"})
+
+		values, err := cache2.Values(ctx)
+		t.AssertNil(err)
+		t.Assert(values, []interface{}{"2"})
+
+		t.AssertNil(cache1.Clear(ctx))
+		size, err := cache1.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 0)
+		size, err = cache2.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 1)
+	})
+}
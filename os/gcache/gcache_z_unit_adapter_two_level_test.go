@@ -0,0 +1,255 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/net/gtcp"
+	"github.com/ximplez-go/gf/net/gudp"
+	"github.com/ximplez-go/gf/os/gcache"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func Test_AdapterTwoLevel_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			local  = gcache.NewAdapterMemoryLru(100)
+			remote = gcache.NewAdapterMemory()
+			cache  = gcache.NewWithAdapter(gcache.NewAdapterTwoLevel(local, remote))
+		)
+		defer cache.Close(ctx)
+
+		// Write through.
+		t.AssertNil(cache.Set(ctx, 1, 11, 0))
+		v, err := local.Get(ctx, "1")
+		t.AssertNil(err)
+		t.Assert(v, 11)
+		v, err = remote.Get(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(v, 11)
+
+		// Read through.
+		t.AssertNil(remote.Set(ctx, 2, 22, time.Second))
+		v, err = cache.Get(ctx, 2)
+		t.AssertNil(err)
+		t.Assert(v, 22)
+		expire, err := local.GetExpire(ctx, "2")
+		t.AssertNil(err)
+		t.AssertGT(expire, 0)
+		t.AssertLE(expire, time.Second)
+
+		v, err = cache.GetOrSet(ctx, 3, 33, 0)
+		t.AssertNil(err)
+		t.Assert(v, 33)
+		v, err = local.Get(ctx, "3")
+		t.AssertNil(err)
+		t.Assert(v, 33)
+
+		ok, err := cache.SetIfNotExist(ctx, 3, 333, 0)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+
+		// Update invalidates local copy.
+		_, exist, err := cache.Update(ctx, 3, 34)
+		t.AssertNil(err)
+		t.Assert(exist, true)
+		ok, err = local.Contains(ctx, "3")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+		v, err = cache.Get(ctx, 3)
+		t.AssertNil(err)
+		t.Assert(v, 34)
+
+		v, err = cache.Remove(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(v, 11)
+		ok, err = cache.Contains(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+
+		size, err := cache.Size(ctx)
+		t.AssertNil(err)
+		t.Assert(size, 2)
+
+		t.AssertNil(cache.Clear(ctx))
+		ok, err = cache.Contains(ctx, 3)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+	})
+}
+
+func Test_AdapterTwoLevel_LocalTTL(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			local  = gcache.NewAdapterMemory()
+			remote = gcache.NewAdapterMemory()
+			cache  = gcache.NewWithAdapter(gcache.NewAdapterTwoLevel(local, remote, gcache.AdapterTwoLevelOption{
+				LocalTTL: 500 * time.Millisecond,
+			}))
+		)
+		t.AssertNil(cache.Set(ctx, "k", "v", 0))
+		expire, err := local.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.AssertGT(expire, 0)
+		t.AssertLE(expire, 500*time.Millisecond)
+		expire, err = cache.GetExpire(ctx, "k")
+		t.AssertNil(err)
+		t.AssertGT(expire, time.Second)
+	})
+}
+
+// testTwoLevelInvalidation checks that local copies on peers are evicted by the invalidation broadcasting.
+func testTwoLevelInvalidation(t *gtest.T, bus1, bus2 gcache.InvalidationBus) {
+	var (
+		remote = gcache.NewAdapterMemory()
+		local1 = gcache.NewAdapterMemory()
+		local2 = gcache.NewAdapterMemory()
+		cache1 = gcache.NewWithAdapter(gcache.NewAdapterTwoLevel(local1, remote, gcache.AdapterTwoLevelOption{
+			Bus: bus1,
+		}))
+		cache2 = gcache.NewWithAdapter(gcache.NewAdapterTwoLevel(local2, remote, gcache.AdapterTwoLevelOption{
+			Bus: bus2,
+		}))
+	)
+	t.AssertNil(cache1.Set(ctx, "k", "v1", 0))
+	v, err := cache2.Get(ctx, "k")
+	t.AssertNil(err)
+	t.Assert(v, "v1")
+	ok, err := local2.Contains(ctx, "k")
+	t.AssertNil(err)
+	t.Assert(ok, true)
+
+	// Set on cache1 evicts the local copy of cache2.
+	t.AssertNil(cache1.Set(ctx, "k", "v2", 0))
+	time.Sleep(200 * time.Millisecond)
+	ok, err = local2.Contains(ctx, "k")
+	t.AssertNil(err)
+	t.Assert(ok, false)
+	ok, err = local1.Contains(ctx, "k")
+	t.AssertNil(err)
+	t.Assert(ok, true)
+	v, err = cache2.Get(ctx, "k")
+	t.AssertNil(err)
+	t.Assert(v, "v2")
+
+	// Remove on cache2 evicts the local copy of cache1.
+	_, err = cache2.Remove(ctx, "k")
+	t.AssertNil(err)
+	time.Sleep(200 * time.Millisecond)
+	v, err = cache1.Get(ctx, "k")
+	t.AssertNil(err)
+	t.Assert(v, nil)
+
+	// Clear on cache1 clears the local data of cache2.
+	t.AssertNil(local2.Set(ctx, "local", 1, 0))
+	t.AssertNil(cache1.Clear(ctx))
+	time.Sleep(200 * time.Millisecond)
+	size, err := local2.Size(ctx)
+	t.AssertNil(err)
+	t.Assert(size, 0)
+}
+
+func Test_AdapterTwoLevel_InvalidationBusTcp(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		ports, err := gtcp.GetFreePorts(2)
+		t.AssertNil(err)
+		var (
+			address1 = fmt.Sprintf("127.0.0.1:%d", ports[0])
+			address2 = fmt.Sprintf("127.0.0.1:%d", ports[1])
+		)
+		bus1, err := gcache.NewInvalidationBusTcp(address1, address2)
+		t.AssertNil(err)
+		defer bus1.Close(ctx)
+		bus2, err := gcache.NewInvalidationBusTcp(address2, address1)
+		t.AssertNil(err)
+		defer bus2.Close(ctx)
+		t.Assert(bus1.GetListenedAddress(), address1)
+		testTwoLevelInvalidation(t, bus1, bus2)
+	})
+}
+
+func Test_AdapterTwoLevel_InvalidationBusTcp_SlowPeer(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		// The slow peer accepts connections but never reads from them.
+		slow, err := net.Listen("tcp", "127.0.0.1:0")
+		t.AssertNil(err)
+		defer slow.Close()
+		go func() {
+			var conns []net.Conn
+			defer func() {
+				for _, conn := range conns {
+					conn.Close()
+				}
+			}()
+			for {
+				conn, err := slow.Accept()
+				if err != nil {
+					return
+				}
+				conns = append(conns, conn)
+			}
+		}()
+
+		ports, err := gtcp.GetFreePorts(2)
+		t.AssertNil(err)
+		var (
+			address1 = fmt.Sprintf("127.0.0.1:%d", ports[0])
+			address2 = fmt.Sprintf("127.0.0.1:%d", ports[1])
+			received = gtype.NewInt()
+		)
+		bus1, err := gcache.NewInvalidationBusTcp(address1, slow.Addr().String(), address2)
+		t.AssertNil(err)
+		defer bus1.Close(ctx)
+		bus2, err := gcache.NewInvalidationBusTcp(address2)
+		t.AssertNil(err)
+		defer bus2.Close(ctx)
+		bus2.Subscribe(func(ctx context.Context, message gcache.InvalidationMessage) {
+			received.Add(1)
+		})
+
+		// The messages are large enough to fill the socket buffers of the slow peer,
+		// but publishing does not wait for them to be sent.
+		message := gcache.InvalidationMessage{Keys: []string{strings.Repeat("k", 256*1024)}}
+		for i := 0; i < 100; i++ {
+			start := time.Now()
+			t.AssertNil(bus1.Publish(ctx, message))
+			t.AssertLT(time.Since(start), time.Second)
+		}
+
+		// The reachable peer is not affected by the slow one.
+		for i := 0; i < 50 && received.Val() < 100; i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(received.Val(), 100)
+	})
+}
+
+func Test_AdapterTwoLevel_InvalidationBusUdp(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		ports, err := gudp.GetFreePorts(2)
+		t.AssertNil(err)
+		var (
+			address1 = fmt.Sprintf("127.0.0.1:%d", ports[0])
+			address2 = fmt.Sprintf("127.0.0.1:%d", ports[1])
+		)
+		bus1, err := gcache.NewInvalidationBusUdp(address1, address2)
+		t.AssertNil(err)
+		defer bus1.Close(ctx)
+		bus2, err := gcache.NewInvalidationBusUdp(address2, address1)
+		t.AssertNil(err)
+		defer bus2.Close(ctx)
+		t.Assert(bus1.GetListenedAddress(), address1)
+		testTwoLevelInvalidation(t, bus1, bus2)
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/os/gcache"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func Test_Cache_GetOrSetFuncSingle(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache = gcache.New()
+			count = gtype.NewInt()
+			wg    sync.WaitGroup
+		)
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := cache.GetOrSetFuncSingle(ctx, "k", func(ctx context.Context) (interface{}, error) {
+					count.Add(1)
+					time.Sleep(100 * time.Millisecond)
+					return "v", nil
+				}, 0)
+				t.AssertNil(err)
+				t.Assert(v, "v")
+			}()
+		}
+		wg.Wait()
+		t.Assert(count.Val(), 1)
+		v, err := cache.Get(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(v, "v")
+	})
+
+	// It does not block other keys.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache   = gcache.New()
+			release = make(chan struct{})
+			started = make(chan struct{})
+		)
+		go func() {
+			_, _ = cache.GetOrSetFuncSingle(ctx, "slow", func(ctx context.Context) (interface{}, error) {
+				close(started)
+				<-release
+				return 1, nil
+			}, 0)
+		}()
+		<-started
+		v, err := cache.GetOrSetFuncSingle(ctx, "fast", func(ctx context.Context) (interface{}, error) {
+			return 2, nil
+		}, 0)
+		close(release)
+		t.AssertNil(err)
+		t.Assert(v, 2)
+	})
+
+	// Error and nil result.
+	gtest.C(t, func(t *gtest.T) {
+		cache := gcache.New()
+		_, err := cache.GetOrSetFuncSingle(ctx, "k", func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("error")
+		}, 0)
+		t.AssertNE(err, nil)
+		v, err := cache.GetOrSetFuncSingle(ctx, "k", func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}, 0)
+		t.AssertNil(err)
+		t.Assert(v, nil)
+		ok, err := cache.Contains(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+	})
+
+	// Panic releases the waiters.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache   = gcache.New()
+			started = make(chan struct{})
+			wg      sync.WaitGroup
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				t.AssertNE(recover(), nil)
+			}()
+			_, _ = cache.GetOrSetFuncSingle(ctx, "k", func(ctx context.Context) (interface{}, error) {
+				close(started)
+				time.Sleep(100 * time.Millisecond)
+				panic("panic")
+			}, 0)
+		}()
+		<-started
+		_, err := cache.GetOrSetFuncSingle(ctx, "k", func(ctx context.Context) (interface{}, error) {
+			return 1, nil
+		}, 0)
+		t.AssertNE(err, nil)
+		wg.Wait()
+	})
+}
+
+func Test_Cache_GetOrSetFuncStale(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache = gcache.New()
+			count = gtype.NewInt()
+			f     = func(ctx context.Context) (interface{}, error) {
+				time.Sleep(100 * time.Millisecond)
+				return count.Add(1), nil
+			}
+		)
+		v, err := cache.GetOrSetFuncStale(ctx, "k", f, 200*time.Millisecond, 10*time.Second)
+		t.AssertNil(err)
+		t.Assert(v, 1)
+
+		// Fresh.
+		v, err = cache.GetOrSetFuncStale(ctx, "k", f, 200*time.Millisecond, 10*time.Second)
+		t.AssertNil(err)
+		t.Assert(v, 1)
+		t.Assert(count.Val(), 1)
+
+		// Stale value is returned immediately, and refreshed only once in background.
+		time.Sleep(300 * time.Millisecond)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := cache.GetOrSetFuncStale(ctx, "k", f, 200*time.Millisecond, 10*time.Second)
+				t.AssertNil(err)
+				t.Assert(v, 1)
+			}()
+		}
+		wg.Wait()
+		time.Sleep(300 * time.Millisecond)
+		t.Assert(count.Val(), 2)
+		v, err = cache.GetOrSetFuncStale(ctx, "k", f, 200*time.Millisecond, 10*time.Second)
+		t.AssertNil(err)
+		t.Assert(v, 2)
+	})
+
+	// Works with other adapters.
+	server := newRespTestServer()
+	defer server.Close()
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache = newRedisCache(t, server)
+			count = gtype.NewInt()
+			f     = func(ctx context.Context) (interface{}, error) {
+				return count.Add(1), nil
+			}
+		)
+		v, err := cache.GetOrSetFuncStale(ctx, "k", f, 200*time.Millisecond, 10*time.Second)
+		t.AssertNil(err)
+		t.Assert(v, 1)
+		time.Sleep(300 * time.Millisecond)
+		v, err = cache.GetOrSetFuncStale(ctx, "k", f, 200*time.Millisecond, 10*time.Second)
+		t.AssertNil(err)
+		t.Assert(v, 1)
+		time.Sleep(100 * time.Millisecond)
+		t.Assert(count.Val(), 2)
+		v, err = cache.Get(ctx, "k")
+		t.AssertNil(err)
+		t.Assert(v, 2)
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcache_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/garray"
+	"github.com/ximplez-go/gf/container/gmap"
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/os/gcache"
+	"github.com/ximplez-go/gf/os/gmetric"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gregex"
+)
+
+func Test_Cache_Stats(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		cache := gcache.New()
+		cache.EnableStats("test")
+		v, err := cache.Get(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(v, nil)
+		t.AssertNil(cache.Set(ctx, 1, 11, 0))
+		v, err = cache.Get(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(v, 11)
+
+		v, err = cache.GetOrSetFunc(ctx, 2, func(ctx context.Context) (interface{}, error) {
+			time.Sleep(10 * time.Millisecond)
+			return 22, nil
+		}, 0)
+		t.AssertNil(err)
+		t.Assert(v, 22)
+		v, err = cache.GetOrSetFuncLock(ctx, 2, func(ctx context.Context) (interface{}, error) {
+			return 222, nil
+		}, 0)
+		t.AssertNil(err)
+		t.Assert(v, 22)
+		_, err = cache.GetOrSetFunc(ctx, 3, func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("error")
+		}, 0)
+		t.AssertNE(err, nil)
+
+		stats := cache.Stats()
+		t.Assert(stats.Hits, 2)
+		t.Assert(stats.Misses, 2)
+		t.Assert(stats.Sets, 2)
+		t.Assert(stats.Loads, 2)
+		t.Assert(stats.LoadErrors, 1)
+		t.AssertGE(stats.LoadDuration, 10*time.Millisecond)
+		t.Assert(stats.HitRatio(), 0.5)
+	})
+
+	// Stats is opt-in.
+	gtest.C(t, func(t *gtest.T) {
+		cache := gcache.New()
+		t.AssertNil(cache.Set(ctx, 1, 11, 0))
+		_, err := cache.Get(ctx, 1)
+		t.AssertNil(err)
+		t.Assert(cache.Stats(), gcache.Stats{})
+	})
+}
+
+func Test_Cache_Hooks(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache   = gcache.New(2)
+			sets    = garray.New(true)
+			evicted = gmap.New(true)
+		)
+		cache.EnableStats()
+		cache.OnSet(func(ctx context.Context, key, value interface{}) {
+			sets.Append(key)
+		})
+		cache.OnEvict(func(ctx context.Context, key, value interface{}) {
+			evicted.Set(key, value)
+		})
+		t.AssertNil(cache.Set(ctx, 1, 11, 0))
+		t.AssertNil(cache.Set(ctx, 2, 22, 0))
+		t.AssertNil(cache.Set(ctx, 3, 33, 0))
+		_, err := cache.GetOrSet(ctx, 4, 44, 0)
+		t.AssertNil(err)
+		t.Assert(sets.Slice(), []interface{}{1, 2, 3, 4})
+		t.Assert(evicted.Map(), map[interface{}]interface{}{1: 11, 2: 22})
+		t.Assert(cache.Stats().EvictionsLru, 2)
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache   = gcache.New()
+			expired = gmap.New(true)
+		)
+		cache.EnableStats()
+		cache.OnExpire(func(ctx context.Context, key, value interface{}) {
+			expired.Set(key, value)
+		})
+		t.AssertNil(cache.Set(ctx, 1, 11, 100*time.Millisecond))
+		t.AssertNil(cache.Set(ctx, 2, 22, 0))
+		for i := 0; i < 50 && expired.Size() == 0; i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(expired.Map(), map[interface{}]interface{}{1: 11})
+		t.Assert(cache.Stats().EvictionsExpire, 1)
+	})
+}
+
+func Test_Cache_Stats_Metrics(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		provider := gmetric.NewPrometheusProvider()
+		provider.SetAsGlobal()
+		defer provider.Shutdown(ctx)
+
+		cache := gcache.New()
+		cache.EnableStats("metrics")
+		_, err := cache.GetOrSetFunc(ctx, 1, func(ctx context.Context) (interface{}, error) {
+			return 11, nil
+		}, 0)
+		t.AssertNil(err)
+		_, err = cache.Get(ctx, 1)
+		t.AssertNil(err)
+
+		buffer := bytes.NewBuffer(nil)
+		t.AssertNil(provider.Export(ctx, buffer))
+		content := buffer.String()
+		t.Assert(gregex.IsMatchString(`gcache_hits\S*\{[^}]*cache_name="metrics"[^}]*\} 1`, content), true)
+		t.Assert(gregex.IsMatchString(`gcache_misses\S*\{[^}]*cache_name="metrics"[^}]*\} 1`, content), true)
+		t.Assert(gregex.IsMatchString(`gcache_load_duration_count\{[^}]*cache_name="metrics"[^}]*\} 1`, content), true)
+	})
+}
+
+func Test_Cache_Stats_GetOrSet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cache = gcache.New()
+			calls = gtype.NewInt()
+			sets  = gtype.NewInt()
+			wg    sync.WaitGroup
+		)
+		cache.EnableStats()
+		cache.OnSet(func(ctx context.Context, key, value interface{}) {
+			sets.Add(1)
+		})
+		// The function value is still evaluated within the lock of adapter, so it is called only once.
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := cache.GetOrSet(ctx, 1, gcache.Func(func(ctx context.Context) (interface{}, error) {
+					calls.Add(1)
+					time.Sleep(time.Millisecond)
+					return 11, nil
+				}), 0)
+				t.AssertNil(err)
+				t.Assert(v, 11)
+			}()
+		}
+		wg.Wait()
+		t.Assert(calls.Val(), 1)
+		t.Assert(sets.Val(), 1)
+		t.Assert(cache.Stats().Misses, 1)
+		t.Assert(cache.Stats().Hits, 99)
+
+		v, err := cache.GetOrSet(ctx, 2, 22, 0)
+		t.AssertNil(err)
+		t.Assert(v, 22)
+		v, err = cache.GetOrSet(ctx, 2, 33, 0)
+		t.AssertNil(err)
+		t.Assert(v, 22)
+		t.Assert(sets.Val(), 2)
+		t.Assert(cache.Stats().Misses, 2)
+		t.Assert(cache.Stats().Hits, 100)
+	})
+}
@@ -9,6 +9,7 @@ package gcfg
 
 import (
 	"context"
+	"sync"
 
 	"github.com/ximplez-go/gf/container/gvar"
 	"github.com/ximplez-go/gf/errors/gcode"
@@ -21,7 +22,10 @@ import (
 
 // Config is the configuration management object.
 type Config struct {
-	adapter Adapter
+	adapter   Adapter
+	decrypter Decrypter      // decrypter is the custom Decrypter for encrypted values, see SetDecrypter.
+	guardMu   sync.RWMutex   // guardMu protects guards.
+	guards    []bindingGuard // guards are the Binding objects created by BindAs, which refuse invalid configuration.
 }
 
 const (
@@ -93,6 +97,8 @@ func (c *Config) Available(ctx context.Context, resource ...string) (ok bool) {
 // Get retrieves and returns value by specified `pattern`.
 // It returns all values of current Json object if `pattern` is given empty or string ".".
 // It returns nil if no value found by `pattern`.
+// The encrypted values like "ENC(base64...)" are decrypted by the Decrypter, see SetDecrypter.
+// The invalid configuration refused by the Binding of BindAs is replaced with its last valid one.
 //
 // It returns a default value specified by `def` if value for `pattern` is not found.
 func (c *Config) Get(ctx context.Context, pattern string, def ...interface{}) (*gvar.Var, error) {
@@ -104,6 +110,10 @@ func (c *Config) Get(ctx context.Context, pattern string, def ...interface{}) (*
 	if err != nil {
 		return nil, err
 	}
+	value = c.guardValue(ctx, pattern, value)
+	if value, _, err = c.decryptValue(ctx, value); err != nil {
+		return nil, gerror.Wrapf(err, `decrypt configuration of pattern "%s" failed`, pattern)
+	}
 	if value == nil {
 		if len(def) > 0 {
 			return gvar.New(def[0]), nil
@@ -158,8 +168,23 @@ func (c *Config) GetWithCmd(ctx context.Context, pattern string, def ...interfac
 }
 
 // Data retrieves and returns all configuration data as map type.
+// The encrypted values like "ENC(base64...)" are decrypted in the returned data,
+// and the invalid configuration refused by the Binding of BindAs is replaced with its last valid one.
 func (c *Config) Data(ctx context.Context) (data map[string]interface{}, err error) {
-	return c.adapter.Data(ctx)
+	if data, err = c.adapter.Data(ctx); err != nil {
+		return nil, err
+	}
+	if guarded, ok := c.guardValue(ctx, "", data).(map[string]interface{}); ok {
+		data = guarded
+	}
+	value, changed, err := c.decryptValue(ctx, data)
+	if err != nil {
+		return nil, gerror.Wrap(err, `decrypt configuration data failed`)
+	}
+	if changed {
+		data = value.(map[string]interface{})
+	}
+	return data, nil
 }
 
 // MustGet acts as function Get, but it panics if error occurs.
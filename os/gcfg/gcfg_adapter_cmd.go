@@ -0,0 +1,71 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ximplez-go/gf/encoding/gjson"
+	"github.com/ximplez-go/gf/internal/command"
+)
+
+// AdapterCmd implements interface Adapter using command line options,
+// which is commonly used as the highest priority layer of AdapterLayered.
+//
+// The option name is split by char '.' for nesting and converted to lowercase as configuration key,
+// eg: "--database.default.link=xxx" is "database.default.link".
+// The options are parsed as the same as package gcmd.
+type AdapterCmd struct {
+	prefix string // prefix is the prefix of option names, which is removed from configuration key.
+}
+
+// NewAdapterCmd returns a new configuration management object using command line options.
+// The optional parameter `prefix` specifies the prefix of option names like "config.",
+// and it uses all options if no prefix given.
+func NewAdapterCmd(prefix ...string) *AdapterCmd {
+	a := &AdapterCmd{}
+	if len(prefix) > 0 {
+		a.prefix = prefix[0]
+	}
+	return a
+}
+
+// Available checks and returns whether there's any command line option with the prefix.
+func (a *AdapterCmd) Available(ctx context.Context, resource ...string) (ok bool) {
+	return len(a.data()) > 0
+}
+
+// Get retrieves and returns value by specified `pattern`, which is case-insensitive.
+func (a *AdapterCmd) Get(ctx context.Context, pattern string) (value interface{}, err error) {
+	return gjson.New(a.data()).Get(strings.ToLower(pattern)).Val(), nil
+}
+
+// Data retrieves and returns all configuration data of the command line options with the prefix.
+func (a *AdapterCmd) Data(ctx context.Context) (data map[string]interface{}, err error) {
+	return a.data(), nil
+}
+
+// data builds and returns the nested configuration data from command line options.
+func (a *AdapterCmd) data() map[string]interface{} {
+	data := make(map[string]interface{})
+	for name, value := range command.GetOptAll() {
+		if !strings.HasPrefix(name, a.prefix) {
+			continue
+		}
+		keys := make([]string, 0)
+		for _, key := range strings.Split(strings.TrimPrefix(name, a.prefix), ".") {
+			if key != "" {
+				keys = append(keys, strings.ToLower(key))
+			}
+		}
+		if len(keys) > 0 {
+			setConfigDataByKeys(data, keys, value)
+		}
+	}
+	return data
+}
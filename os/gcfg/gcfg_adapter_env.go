@@ -0,0 +1,77 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/ximplez-go/gf/encoding/gjson"
+)
+
+// AdapterEnv implements interface Adapter using environment variables with specified prefix,
+// which is commonly used as a layer of AdapterLayered.
+//
+// The prefix is removed from the environment variable name, and the rest is split by "__" for nesting
+// and converted to lowercase as configuration key, eg:
+// "APP_DATABASE__DEFAULT__LINK" with prefix "APP_" is "database.default.link",
+// "APP_SERVER__MAX_HEADER_BYTES" with prefix "APP_" is "server.max_header_bytes".
+//
+// The environment variables are read in time, so it reflects the changes of environment variables.
+type AdapterEnv struct {
+	prefix string // prefix is the prefix of environment variable names.
+}
+
+// NewAdapterEnv returns a new configuration management object using environment variables with `prefix`.
+// Note that it uses all environment variables if `prefix` is empty.
+func NewAdapterEnv(prefix string) *AdapterEnv {
+	return &AdapterEnv{
+		prefix: prefix,
+	}
+}
+
+// Available checks and returns whether there's any environment variable with the prefix.
+func (a *AdapterEnv) Available(ctx context.Context, resource ...string) (ok bool) {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, a.prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get retrieves and returns value by specified `pattern`, which is case-insensitive.
+func (a *AdapterEnv) Get(ctx context.Context, pattern string) (value interface{}, err error) {
+	return gjson.New(a.data()).Get(strings.ToLower(pattern)).Val(), nil
+}
+
+// Data retrieves and returns all configuration data of the environment variables with the prefix.
+func (a *AdapterEnv) Data(ctx context.Context) (data map[string]interface{}, err error) {
+	return a.data(), nil
+}
+
+// data builds and returns the nested configuration data from environment variables.
+func (a *AdapterEnv) data() map[string]interface{} {
+	data := make(map[string]interface{})
+	for _, env := range os.Environ() {
+		name, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(name, a.prefix) {
+			continue
+		}
+		keys := make([]string, 0)
+		for _, key := range strings.Split(strings.TrimPrefix(name, a.prefix), "__") {
+			if key != "" {
+				keys = append(keys, strings.ToLower(key))
+			}
+		}
+		if len(keys) > 0 {
+			setConfigDataByKeys(data, keys, value)
+		}
+	}
+	return data
+}
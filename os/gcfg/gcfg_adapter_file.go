@@ -8,6 +8,8 @@ package gcfg
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/ximplez-go/gf/container/garray"
 	"github.com/ximplez-go/gf/container/gmap"
@@ -25,18 +27,26 @@ import (
 
 // AdapterFile implements interface Adapter using file.
 type AdapterFile struct {
+	changeWatchers                         // Change callbacks for configuration files.
 	defaultFileNameOrPath string           // Default configuration file name or file path.
 	searchPaths           *garray.StrArray // Searching the path array.
 	jsonMap               *gmap.StrAnyMap  // The pared JSON objects for configuration files.
+	snapshots             *gmap.StrAnyMap  // The last notified configuration data for configuration files, for changes comparing.
+	pendings              *gmap.StrAnyMap  // The pending notifying timers for configuration files, for events debouncing.
 	violenceCheck         bool             // Whether it does violence check in value index searching. It affects the performance when set true(false in default).
 }
 
 const (
 	commandEnvKeyForFile = "gf.gcfg.file" // commandEnvKeyForFile is the configuration key for command argument or environment configuring file name.
 	commandEnvKeyForPath = "gf.gcfg.path" // commandEnvKeyForPath is the configuration key for command argument or environment configuring directory path.
+
+	fileChangeNotifyDelay = 100 * time.Millisecond // fileChangeNotifyDelay is the delay for reloading and notifying after configuration file changes.
 )
 
 var (
+	// Check the implements for interface WatchableAdapter.
+	_ WatchableAdapter = (*AdapterFile)(nil)
+
 	supportedFileTypes     = []string{"toml", "yaml", "yml", "json", "ini", "xml", "properties"} // All supported file types suffixes.
 	localInstances         = gmap.NewStrAnyMap(true)                                             // Instances map containing configuration instances.
 	customConfigContentMap = gmap.NewStrStrMap(true)                                             // Customized configuration content.
@@ -70,6 +80,8 @@ func NewAdapterFile(fileNameOrPath ...string) (*AdapterFile, error) {
 		defaultFileNameOrPath: usedFileNameOrPath,
 		searchPaths:           garray.NewStrArray(true),
 		jsonMap:               gmap.NewStrAnyMap(true),
+		snapshots:             gmap.NewStrAnyMap(true),
+		pendings:              gmap.NewStrAnyMap(true),
 	}
 	// Customized dir path from env/cmd.
 	if customPath := command.GetOptWithEnv(commandEnvKeyForPath); customPath != "" {
@@ -277,10 +289,16 @@ func (a *AdapterFile) getJson(fileNameOrPath ...string) (configJson *gjson.Json,
 		configJson.SetViolenceCheck(a.violenceCheck)
 		// Add monitor for this configuration file,
 		// any changes of this file will refresh its cache in the Config object.
+		// It is added only once for the file, as the cache is reloaded after changes.
 		if filePath != "" && !gres.Contains(filePath) {
-			_, err = gfsnotify.Add(filePath, func(event *gfsnotify.Event) {
-				a.jsonMap.Remove(usedFileNameOrPath)
-			})
+			_, err = gfsnotify.AddOnce(
+				fmt.Sprintf(`gcfg.AdapterFile.%p:%s:%s`, a, usedFileNameOrPath, filePath),
+				filePath,
+				func(event *gfsnotify.Event) {
+					oldJson := a.jsonMap.Remove(usedFileNameOrPath)
+					a.notifyFileChange(usedFileNameOrPath, oldJson)
+				},
+			)
 			if err != nil {
 				return nil
 			}
@@ -292,3 +310,65 @@ func (a *AdapterFile) getJson(fileNameOrPath ...string) (configJson *gjson.Json,
 	}
 	return
 }
+
+// notifyFileChange reloads the configuration of `fileNameOrPath` after fileChangeNotifyDelay, compares it
+// with the last notified data or `oldJson`, and notifies the change callbacks if there's any key changed.
+//
+// It delays the reloading as the file might be being written, which produces multiple events,
+// and the content read in the first event might be truncated. Each event of the same file
+// cancels the pending reloading and delays it again, so the reloading is done only once
+// after the file stays unchanged for fileChangeNotifyDelay.
+func (a *AdapterFile) notifyFileChange(fileNameOrPath string, oldJson interface{}) {
+	if !a.hasWatchers() {
+		return
+	}
+	if j, ok := oldJson.(*gjson.Json); ok && j != nil {
+		a.snapshots.SetIfNotExist(fileNameOrPath, j.Var().Map())
+	}
+	a.pendings.LockFunc(func(m map[string]interface{}) {
+		if v, ok := m[fileNameOrPath]; ok && v.(*time.Timer).Stop() {
+			v.(*time.Timer).Reset(fileChangeNotifyDelay)
+			return
+		}
+		var timer *time.Timer
+		timer = time.AfterFunc(fileChangeNotifyDelay, func() {
+			a.pendings.LockFunc(func(m map[string]interface{}) {
+				if v, ok := m[fileNameOrPath]; ok && v.(*time.Timer) == timer {
+					delete(m, fileNameOrPath)
+				}
+			})
+			a.reloadAndNotify(context.Background(), fileNameOrPath)
+		})
+		m[fileNameOrPath] = timer
+	})
+}
+
+// reloadAndNotify reloads the configuration of `fileNameOrPath`, compares it with the last notified data,
+// and notifies the change callbacks if there's any key changed.
+func (a *AdapterFile) reloadAndNotify(ctx context.Context, fileNameOrPath string) {
+	var (
+		oldData map[string]interface{}
+		newData map[string]interface{}
+	)
+	if v := a.snapshots.Get(fileNameOrPath); v != nil {
+		oldData = v.(map[string]interface{})
+	}
+	// It removes the cache which might be loaded from incomplete content during writing.
+	a.jsonMap.Remove(fileNameOrPath)
+	newJson, err := a.getJson(fileNameOrPath)
+	if err != nil {
+		// It keeps the old data for next comparing.
+		intlog.Errorf(ctx, `%+v`, err)
+		return
+	}
+	if newJson != nil {
+		newData = newJson.Var().Map()
+	}
+	a.snapshots.Set(fileNameOrPath, newData)
+	if keys := diffConfigKeys(oldData, newData); len(keys) > 0 {
+		a.notify(ctx, ChangeEvent{
+			Resource: fileNameOrPath,
+			Keys:     keys,
+		})
+	}
+}
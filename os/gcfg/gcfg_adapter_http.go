@@ -0,0 +1,248 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/encoding/gjson"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/os/gtimer"
+)
+
+// AdapterHttp implements interface Adapter using remote configuration content fetched from URL,
+// which polls the URL periodically and notifies the change callbacks if the content changes.
+// The configuration content supports the coding types as package `gjson`, like JSON/YAML/TOML.
+type AdapterHttp struct {
+	changeWatchers
+	url     string            // url is the URL of remote configuration content.
+	option  AdapterHttpOption // option is the option for fetching.
+	jsonVar *gvar.Var         // The pared JSON object for configuration content, type: *gjson.Json.
+	etag    *gtype.String     // etag is the ETag of last fetched content.
+	mu      sync.Mutex        // mu serializes the refreshing.
+	closed  *gtype.Bool       // closed marks the adapter closed, which stops polling.
+}
+
+// AdapterHttpOption is the option for AdapterHttp.
+type AdapterHttpOption struct {
+	// Interval is the polling interval, which is defaultHttpPollingInterval in default.
+	// It disables the polling if it is negative.
+	Interval time.Duration
+
+	// Timeout is the timeout for each fetching, which is defaultHttpTimeout in default.
+	Timeout time.Duration
+
+	// Header is the custom header for fetching, like authorization header.
+	Header http.Header
+
+	// Client is the custom HTTP client for fetching.
+	// It uses a new client with Timeout if it is nil.
+	Client *http.Client
+
+	// ContentType specifies the content type like "json", "yaml", "toml".
+	// It detects the content type from the response header and URL extension in default,
+	// and detects from content if it cannot be determined.
+	ContentType string
+}
+
+const (
+	defaultHttpPollingInterval = 10 * time.Second
+	defaultHttpTimeout         = 5 * time.Second
+)
+
+var (
+	// Check the implements for interface WatchableAdapter.
+	_ WatchableAdapter = (*AdapterHttp)(nil)
+
+	// httpMimeContentTypes maps the mime types to content types of package gjson.
+	httpMimeContentTypes = map[string]gjson.ContentType{
+		"application/json":   gjson.ContentTypeJson,
+		"text/json":          gjson.ContentTypeJson,
+		"application/yaml":   gjson.ContentTypeYaml,
+		"application/x-yaml": gjson.ContentTypeYaml,
+		"text/yaml":          gjson.ContentTypeYaml,
+		"text/x-yaml":        gjson.ContentTypeYaml,
+		"application/toml":   gjson.ContentTypeToml,
+		"text/toml":          gjson.ContentTypeToml,
+		"application/xml":    gjson.ContentTypeXml,
+		"text/xml":           gjson.ContentTypeXml,
+	}
+)
+
+// NewAdapterHttp returns a new configuration management object using remote content from `url`.
+// It fetches the content synchronously, and returns error if the first fetching fails.
+func NewAdapterHttp(ctx context.Context, url string, option ...AdapterHttpOption) (*AdapterHttp, error) {
+	a := &AdapterHttp{
+		url:     url,
+		jsonVar: gvar.New(nil, true),
+		etag:    gtype.NewString(),
+		closed:  gtype.NewBool(),
+	}
+	if len(option) > 0 {
+		a.option = option[0]
+	}
+	if a.option.Interval == 0 {
+		a.option.Interval = defaultHttpPollingInterval
+	}
+	if a.option.Timeout <= 0 {
+		a.option.Timeout = defaultHttpTimeout
+	}
+	if a.option.Client == nil {
+		a.option.Client = &http.Client{Timeout: a.option.Timeout}
+	}
+	if err := a.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	if a.option.Interval > 0 {
+		gtimer.AddSingleton(context.Background(), a.option.Interval, a.poll)
+	}
+	return a, nil
+}
+
+// Available checks and returns the backend configuration service is available.
+// It returns true if the remote configuration content has been fetched.
+func (a *AdapterHttp) Available(ctx context.Context, resource ...string) (ok bool) {
+	return !a.jsonVar.IsNil()
+}
+
+// Get retrieves and returns value by specified `pattern` in current resource.
+// Pattern like:
+// "x.y.z" for map item.
+// "x.0.y" for slice item.
+func (a *AdapterHttp) Get(ctx context.Context, pattern string) (value interface{}, err error) {
+	if a.jsonVar.IsNil() {
+		return nil, nil
+	}
+	return a.jsonVar.Val().(*gjson.Json).Get(pattern).Val(), nil
+}
+
+// Data retrieves and returns all configuration data in current resource as map.
+func (a *AdapterHttp) Data(ctx context.Context) (data map[string]interface{}, err error) {
+	if a.jsonVar.IsNil() {
+		return nil, nil
+	}
+	return a.jsonVar.Val().(*gjson.Json).Var().Map(), nil
+}
+
+// Refresh fetches the remote configuration content immediately, and notifies the change callbacks
+// if the content changes. It does nothing if the server responds 304 Not Modified for the ETag.
+func (a *AdapterHttp) Refresh(ctx context.Context) error {
+	keys, err := a.doRefresh(ctx)
+	if err != nil {
+		return err
+	}
+	// The callbacks are called without lock, so they can retrieve or refresh the configuration.
+	if len(keys) > 0 {
+		a.notify(ctx, ChangeEvent{
+			Resource: a.url,
+			Keys:     keys,
+		})
+	}
+	return nil
+}
+
+// doRefresh fetches and updates the remote configuration content within lock,
+// and returns the changed keys.
+func (a *AdapterHttp) doRefresh(ctx context.Context) (keys []string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `create request failed for url "%s"`, a.url)
+	}
+	for k, v := range a.option.Header {
+		request.Header[k] = v
+	}
+	if etag := a.etag.Val(); etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+	response, err := a.option.Client.Do(request)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `fetch configuration failed for url "%s"`, a.url)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		return nil, nil
+	case http.StatusOK:
+	default:
+		return nil, gerror.NewCodef(
+			gcode.CodeOperationFailed,
+			`fetch configuration failed for url "%s", unexpected status: %s`,
+			a.url, response.Status,
+		)
+	}
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `read configuration failed for url "%s"`, a.url)
+	}
+	var j *gjson.Json
+	if contentType := a.getContentType(response); contentType != "" {
+		j, err = gjson.LoadContentType(contentType, content, true)
+	} else {
+		j, err = gjson.LoadContent(content, true)
+	}
+	if err != nil {
+		return nil, gerror.Wrapf(err, `load configuration failed for url "%s"`, a.url)
+	}
+	oldJson, _ := a.jsonVar.Val().(*gjson.Json)
+	a.jsonVar.Set(j)
+	a.etag.Set(response.Header.Get("ETag"))
+	if oldJson == nil {
+		// It does not notify for the first fetching.
+		return nil, nil
+	}
+	return diffConfigKeys(oldJson.Var().Map(), j.Var().Map()), nil
+}
+
+// Close stops the polling of the adapter.
+func (a *AdapterHttp) Close() {
+	a.closed.Set(true)
+}
+
+// poll is the polling job for fetching remote configuration content periodically.
+func (a *AdapterHttp) poll(ctx context.Context) {
+	if a.closed.Val() {
+		gtimer.Exit()
+		return
+	}
+	if err := a.Refresh(ctx); err != nil {
+		intlog.Errorf(ctx, `%+v`, err)
+	}
+}
+
+// getContentType returns the content type of the response for package gjson,
+// or empty if it cannot be determined.
+func (a *AdapterHttp) getContentType(response *http.Response) gjson.ContentType {
+	if a.option.ContentType != "" {
+		return gjson.ContentType(a.option.ContentType)
+	}
+	if mediaType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type")); err == nil {
+		if contentType, ok := httpMimeContentTypes[mediaType]; ok {
+			return contentType
+		}
+	}
+	if u, err := url.Parse(a.url); err == nil {
+		if ext := path.Ext(u.Path); ext != "" {
+			if contentType := gjson.ContentType(ext[1:]); gjson.IsValidDataType(contentType) {
+				return contentType
+			}
+		}
+	}
+	return ""
+}
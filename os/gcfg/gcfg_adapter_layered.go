@@ -0,0 +1,325 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/command"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+// AdapterLayered implements interface Adapter by merging multiple configuration layers in priority order,
+// in which the later added layer has higher priority and overrides the same keys of lower layers.
+// The map values of different layers are merged deeply, and other values like slices are overridden as a whole.
+//
+// It implements interface WatchableAdapter, which forwards the changes of watchable layers
+// if the changed keys are not overridden by higher layers.
+type AdapterLayered struct {
+	changeWatchers
+	mu     sync.RWMutex
+	layers []AdapterLayer // layers are sorted by priority from low to high.
+}
+
+// AdapterLayer is a named configuration layer of AdapterLayered.
+type AdapterLayer struct {
+	Name    string  // Name is the unique name of the layer, which is returned by AdapterLayered.Source.
+	Adapter Adapter // Adapter is the configuration adapter of the layer.
+}
+
+// AdapterLayeredOption is the option for creating standard AdapterLayered by NewAdapterLayeredWithOption.
+type AdapterLayeredOption struct {
+	// FileName is the default configuration file name or path, which is DefaultConfigFileName in default.
+	FileName string
+
+	// Environment is the environment name like "prod", which adds configuration file like "config.prod.yaml"
+	// overriding the default configuration file. It is read from command option or environment variable
+	// "gf.gcfg.environment" in default, and the layer is not added if it is empty.
+	Environment string
+
+	// EnvPrefix is the prefix of environment variables overriding configuration files, like "APP_".
+	// The environment variables layer is not added if it is empty, see AdapterEnv.
+	EnvPrefix string
+
+	// Cmd specifies whether the command line options override all other layers, see AdapterCmd.
+	Cmd bool
+}
+
+const (
+	LayerNameFile = "file" // LayerNameFile is the layer name of default configuration file.
+	LayerNameEnv  = "env"  // LayerNameEnv is the layer name of environment variables.
+	LayerNameCmd  = "cmd"  // LayerNameCmd is the layer name of command line options.
+
+	commandEnvKeyForEnvironment = "gf.gcfg.environment" // commandEnvKeyForEnvironment is the configuration key for command argument or environment configuring environment name.
+)
+
+var (
+	// Check the implements for interface WatchableAdapter.
+	_ WatchableAdapter = (*AdapterLayered)(nil)
+)
+
+// NewAdapterLayered returns a new configuration management object merging `layers`,
+// in which the later layer has higher priority.
+func NewAdapterLayered(layers ...AdapterLayer) *AdapterLayered {
+	a := &AdapterLayered{}
+	for _, layer := range layers {
+		a.AddLayer(layer.Name, layer.Adapter)
+	}
+	return a
+}
+
+// NewAdapterLayeredWithOption returns a new configuration management object with standard layers
+// in priority order from low to high:
+// 1. The default configuration file, named LayerNameFile;
+// 2. The environment-specific configuration file like "config.prod.yaml", named "file.prod";
+// 3. The environment variables with prefix, named LayerNameEnv;
+// 4. The command line options, named LayerNameCmd.
+func NewAdapterLayeredWithOption(option ...AdapterLayeredOption) (*AdapterLayered, error) {
+	var usedOption AdapterLayeredOption
+	if len(option) > 0 {
+		usedOption = option[0]
+	}
+	if usedOption.FileName == "" {
+		usedOption.FileName = DefaultConfigFileName
+	}
+	if usedOption.Environment == "" {
+		usedOption.Environment = command.GetOptWithEnv(commandEnvKeyForEnvironment)
+	}
+	a := NewAdapterLayered()
+	adapterFile, err := NewAdapterFile(usedOption.FileName)
+	if err != nil {
+		return nil, err
+	}
+	a.AddLayer(LayerNameFile, adapterFile)
+	if usedOption.Environment != "" {
+		adapterEnvFile, err := NewAdapterFile(
+			getEnvironmentFileName(usedOption.FileName, usedOption.Environment),
+		)
+		if err != nil {
+			return nil, err
+		}
+		a.AddLayer(fmt.Sprintf(`%s.%s`, LayerNameFile, usedOption.Environment), adapterEnvFile)
+	}
+	if usedOption.EnvPrefix != "" {
+		a.AddLayer(LayerNameEnv, NewAdapterEnv(usedOption.EnvPrefix))
+	}
+	if usedOption.Cmd {
+		a.AddLayer(LayerNameCmd, NewAdapterCmd())
+	}
+	return a, nil
+}
+
+// AddLayer adds `adapter` named `name` as the highest priority layer.
+func (a *AdapterLayered) AddLayer(name string, adapter Adapter) {
+	a.mu.Lock()
+	index := len(a.layers)
+	a.layers = append(a.layers, AdapterLayer{
+		Name:    name,
+		Adapter: adapter,
+	})
+	a.mu.Unlock()
+	if watchable, ok := adapter.(WatchableAdapter); ok {
+		watchable.OnChange(fmt.Sprintf(`gcfg.AdapterLayered.%p`, a), func(ctx context.Context, event ChangeEvent) {
+			a.notifyLayerChange(ctx, index, event)
+		})
+	}
+}
+
+// Layers returns a copy of all layers sorted by priority from low to high.
+func (a *AdapterLayered) Layers() []AdapterLayer {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	layers := make([]AdapterLayer, len(a.layers))
+	copy(layers, a.layers)
+	return layers
+}
+
+// Available checks and returns whether any layer is available.
+func (a *AdapterLayered) Available(ctx context.Context, resource ...string) (ok bool) {
+	for _, layer := range a.Layers() {
+		if layer.Adapter.Available(ctx, resource...) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get retrieves and returns value by specified `pattern` from the highest priority layer that has it.
+// If the value is a map, it is deeply merged with the map values of the same `pattern` in lower layers.
+func (a *AdapterLayered) Get(ctx context.Context, pattern string) (value interface{}, err error) {
+	var (
+		layers = a.Layers()
+		maps   = make([]map[string]interface{}, 0)
+	)
+	for i := len(layers) - 1; i >= 0; i-- {
+		v, err := getLayerValue(ctx, layers[i], pattern)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			if len(maps) == 0 {
+				return v, nil
+			}
+			// The non-map value is overridden by higher layers.
+			break
+		}
+		maps = append(maps, m)
+	}
+	if len(maps) == 0 {
+		return nil, nil
+	}
+	merged := make(map[string]interface{})
+	for i := len(maps) - 1; i >= 0; i-- {
+		mergeConfigData(merged, maps[i])
+	}
+	return merged, nil
+}
+
+// Data retrieves and returns the deeply merged configuration data of all layers.
+func (a *AdapterLayered) Data(ctx context.Context) (data map[string]interface{}, err error) {
+	data = make(map[string]interface{})
+	for _, layer := range a.Layers() {
+		layerData, err := layer.Adapter.Data(ctx)
+		if err != nil {
+			if gerror.Code(err) == gcode.CodeNotFound {
+				continue
+			}
+			return nil, gerror.Wrapf(err, `retrieve data of configuration layer "%s" failed`, layer.Name)
+		}
+		mergeConfigData(data, layerData)
+	}
+	return data, nil
+}
+
+// Source returns the name of the highest priority layer that supplies the value of `pattern`.
+// It returns empty string if no layer has the value of `pattern`.
+func (a *AdapterLayered) Source(ctx context.Context, pattern string) (name string, err error) {
+	layers := a.Layers()
+	for i := len(layers) - 1; i >= 0; i-- {
+		v, err := getLayerValue(ctx, layers[i], pattern)
+		if err != nil {
+			return "", err
+		}
+		if v != nil {
+			return layers[i].Name, nil
+		}
+	}
+	return "", nil
+}
+
+// notifyLayerChange notifies the change callbacks with the changed keys of layer at `index`,
+// which are not overridden by higher layers.
+func (a *AdapterLayered) notifyLayerChange(ctx context.Context, index int, event ChangeEvent) {
+	var (
+		layers = a.Layers()
+		keys   = make([]string, 0, len(event.Keys))
+	)
+	for _, key := range event.Keys {
+		overridden := false
+		for i := index + 1; i < len(layers); i++ {
+			if v, _ := getLayerValue(ctx, layers[i], key); v != nil {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	a.notify(ctx, ChangeEvent{
+		Resource: event.Resource,
+		Keys:     keys,
+	})
+}
+
+// getLayerValue retrieves the value of `pattern` from `layer`.
+// It ignores the error of not found, like the configuration file of the layer does not exist.
+func getLayerValue(ctx context.Context, layer AdapterLayer, pattern string) (interface{}, error) {
+	v, err := layer.Adapter.Get(ctx, pattern)
+	if err != nil {
+		if gerror.Code(err) == gcode.CodeNotFound {
+			return nil, nil
+		}
+		return nil, gerror.Wrapf(err, `retrieve "%s" from configuration layer "%s" failed`, pattern, layer.Name)
+	}
+	return v, nil
+}
+
+// getEnvironmentFileName returns the environment-specific configuration file name of `fileNameOrPath`,
+// eg: "config" to "config.prod", "config.yaml" to "config.prod.yaml".
+func getEnvironmentFileName(fileNameOrPath, environment string) string {
+	extName := gfile.ExtName(fileNameOrPath)
+	if gstr.InArray(supportedFileTypes, extName) {
+		return fmt.Sprintf(
+			`%s.%s.%s`,
+			strings.TrimSuffix(fileNameOrPath, "."+extName), environment, extName,
+		)
+	}
+	return fmt.Sprintf(`%s.%s`, fileNameOrPath, environment)
+}
+
+// mergeConfigData merges `src` into `dst` deeply, in which the values of `src` override the ones of `dst`.
+// The keys are matched case-insensitively if there's no exactly matched key in `dst`,
+// as the keys of environment variables and command line options are in lowercase.
+// The maps of `src` are copied to `dst`, so that `src` would not be changed by later merging.
+func mergeConfigData(dst, src map[string]interface{}) {
+	for k, v := range src {
+		key := k
+		if _, ok := dst[key]; !ok {
+			for dstKey := range dst {
+				if strings.EqualFold(dstKey, k) {
+					key = dstKey
+					break
+				}
+			}
+		}
+		srcMap, ok := v.(map[string]interface{})
+		if !ok {
+			dst[key] = v
+			continue
+		}
+		merged := make(map[string]interface{})
+		if dstMap, ok := dst[key].(map[string]interface{}); ok {
+			mergeConfigData(merged, dstMap)
+		}
+		mergeConfigData(merged, srcMap)
+		dst[key] = merged
+	}
+}
+
+// setConfigDataByKeys sets `value` into `data` by nested `keys`.
+// The existing map value is not overridden by non-map value, so that the result is deterministic
+// for conflicts like "a=1" and "a.b=2".
+func setConfigDataByKeys(data map[string]interface{}, keys []string, value interface{}) {
+	for i, key := range keys {
+		if i == len(keys)-1 {
+			if _, ok := data[key].(map[string]interface{}); !ok {
+				data[key] = value
+			}
+			return
+		}
+		m, ok := data[key].(map[string]interface{})
+		if !ok {
+			m = make(map[string]interface{})
+			data[key] = m
+		}
+		data = m
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WatchableAdapter is the optional interface for adapters that support watching configuration changes.
+type WatchableAdapter interface {
+	Adapter
+
+	// OnChange registers callback `fn` with unique `name`, which is called with the changed keys
+	// after the configuration changes. It replaces the callback if `name` is already registered.
+	OnChange(name string, fn ChangeFunc)
+
+	// RemoveOnChange removes the callback registered with `name`.
+	RemoveOnChange(name string)
+}
+
+// ChangeFunc is the callback function for configuration changes.
+type ChangeFunc func(ctx context.Context, event ChangeEvent)
+
+// ChangeEvent is the event of configuration changes.
+type ChangeEvent struct {
+	Resource string   // Resource is the changed configuration resource, like file name or URL.
+	Keys     []string // Keys are the changed keys in pattern like "database.default.link", which are sorted.
+}
+
+// changeWatchers manages the change callbacks for WatchableAdapter implements.
+type changeWatchers struct {
+	mu       sync.RWMutex
+	names    []string              // names keeps the registering order of callbacks.
+	watchers map[string]ChangeFunc // watchers is the name to callback mapping.
+}
+
+// OnChange registers callback `fn` with unique `name`, which is called with the changed keys
+// after the configuration changes. It replaces the callback if `name` is already registered.
+func (w *changeWatchers) OnChange(name string, fn ChangeFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.watchers == nil {
+		w.watchers = make(map[string]ChangeFunc)
+	}
+	if _, ok := w.watchers[name]; !ok {
+		w.names = append(w.names, name)
+	}
+	w.watchers[name] = fn
+}
+
+// RemoveOnChange removes the callback registered with `name`.
+func (w *changeWatchers) RemoveOnChange(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.watchers[name]; !ok {
+		return
+	}
+	delete(w.watchers, name)
+	for i, v := range w.names {
+		if v == name {
+			w.names = append(w.names[:i:i], w.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// hasWatchers checks and returns whether there's any callback registered.
+func (w *changeWatchers) hasWatchers() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.names) > 0
+}
+
+// notify calls all callbacks with `event` in their registering order.
+func (w *changeWatchers) notify(ctx context.Context, event ChangeEvent) {
+	w.mu.RLock()
+	watchers := make([]ChangeFunc, 0, len(w.names))
+	for _, name := range w.names {
+		watchers = append(watchers, w.watchers[name])
+	}
+	w.mu.RUnlock()
+	for _, fn := range watchers {
+		fn(ctx, event)
+	}
+}
+
+// diffConfigKeys compares the configuration data `oldData` and `newData`,
+// and returns the sorted keys whose values are changed, added or deleted.
+// The nested maps are compared recursively, and other values like slices are compared as a whole.
+func diffConfigKeys(oldData, newData map[string]interface{}) []string {
+	var (
+		oldLeaves = make(map[string]interface{})
+		newLeaves = make(map[string]interface{})
+		keys      = make([]string, 0)
+	)
+	flattenConfigData("", oldData, oldLeaves)
+	flattenConfigData("", newData, newLeaves)
+	for k, v := range oldLeaves {
+		if newValue, ok := newLeaves[k]; !ok || !reflect.DeepEqual(v, newValue) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range newLeaves {
+		if _, ok := oldLeaves[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flattenConfigData flattens the nested map `data` into `leaves` using keys joined by char '.'.
+func flattenConfigData(prefix string, data map[string]interface{}, leaves map[string]interface{}) {
+	for k, v := range data {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			flattenConfigData(key, m, leaves)
+			continue
+		}
+		leaves[key] = v
+	}
+}
+
+// isPatternChanged checks and returns whether the value of `pattern` is changed by the changed `keys`.
+// It returns true for empty pattern or ".", which stands for the whole configuration.
+func isPatternChanged(pattern string, keys []string) bool {
+	if pattern == "" || pattern == "." {
+		return len(keys) > 0
+	}
+	for _, key := range keys {
+		if key == pattern || strings.HasPrefix(key, pattern+".") || strings.HasPrefix(pattern, key+".") {
+			return true
+		}
+	}
+	return false
+}
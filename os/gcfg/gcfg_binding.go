@@ -0,0 +1,281 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/encoding/gjson"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/util/gutil"
+)
+
+// Binding holds the validated configuration struct bound from a pattern by BindAs,
+// which is refreshed after configuration changes if the adapter is WatchableAdapter.
+//
+// The new configuration is refused if its validation fails, in which case Binding keeps
+// the last valid value, and reports the error by Err and the error callbacks. Once refused,
+// the Config also keeps serving the last valid configuration of the pattern in Get and Data
+// until the configuration becomes valid again.
+type Binding[T any] struct {
+	mu       sync.RWMutex
+	config   *Config
+	pattern  string
+	watch    string                                 // watch is the watcher name of Config.Watch, or empty if not watchable.
+	value    T                                      // value is the last valid value.
+	err      error                                  // err is the error of last refreshing.
+	checked  bool                                   // checked marks whether raw is checked.
+	raw      interface{}                            // raw is the copy of last checked configuration of pattern.
+	valid    interface{}                            // valid is the copy of last valid configuration of pattern.
+	version  int                                    // version increases after each checking of changed configuration.
+	notified int                                    // notified is the version that callbacks are called for.
+	onChange []func(ctx context.Context, value T)   // onChange are the callbacks after value is changed.
+	onError  []func(ctx context.Context, err error) // onError are the callbacks after refreshing fails.
+}
+
+// bindingGuard is the interface for Binding, which is used by Config to refuse invalid configuration.
+type bindingGuard interface {
+	// guard returns the pattern and its last valid configuration if current configuration is refused.
+	// It only reads the result of last checking, as it is called by every retrieving of Config.
+	guard(ctx context.Context) (pattern string, valid interface{}, refused bool)
+
+	// check rechecks the configuration after it changes, which is called before the watchers retrieve it.
+	check(ctx context.Context) error
+}
+
+// BindAs binds and validates the configuration of `pattern` to a new struct of type `T` using Config.Bind,
+// and returns the Binding holding it. It returns error if the binding or validation fails.
+//
+// If the adapter of `c` implements WatchableAdapter, the value is rebound and validated after the
+// configuration of `pattern` changes, and the invalid configuration is refused.
+func BindAs[T any](ctx context.Context, c *Config, pattern string) (*Binding[T], error) {
+	b := &Binding[T]{
+		config:  c,
+		pattern: pattern,
+	}
+	if err := b.check(ctx); err != nil {
+		return nil, err
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	b.notified = b.version
+	if _, ok := c.adapter.(WatchableAdapter); ok {
+		name, err := c.Watch(pattern, func(ctx context.Context, _ *gvar.Var) {
+			b.refresh(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		b.watch = name
+	}
+	c.addGuard(b)
+	return b, nil
+}
+
+// Get returns the last valid value.
+func (b *Binding[T]) Get() T {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.value
+}
+
+// Err returns the error of last refreshing after configuration changes, which is nil if it succeeds.
+func (b *Binding[T]) Err() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.err
+}
+
+// OnChange registers callback `fn` which is called with the new valid value after configuration changes.
+func (b *Binding[T]) OnChange(fn func(ctx context.Context, value T)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = append(b.onChange, fn)
+}
+
+// OnError registers callback `fn` which is called with the error if refreshing fails after configuration
+// changes, like *ValidationError for invalid configuration.
+func (b *Binding[T]) OnError(fn func(ctx context.Context, err error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onError = append(b.onError, fn)
+}
+
+// Close stops refreshing the value after configuration changes,
+// and the Config stops refusing invalid configuration of its pattern.
+func (b *Binding[T]) Close() error {
+	b.config.removeGuard(b)
+	if b.watch == "" {
+		return nil
+	}
+	return b.config.Unwatch(b.watch)
+}
+
+// guard implements interface bindingGuard.
+func (b *Binding[T]) guard(ctx context.Context) (pattern string, valid interface{}, refused bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.pattern, b.valid, b.err != nil
+}
+
+// check rebinds and validates the configuration if it is changed since last checking.
+// It is called by the binding, the refreshing and the watchers of Config after configuration changes,
+// so that the invalid configuration is refused before any watcher retrieves it.
+//
+// It returns the error of adapter if it fails retrieving the configuration, which changes nothing.
+func (b *Binding[T]) check(ctx context.Context) error {
+	raw, err := b.config.adapter.Get(ctx, b.pattern)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.checked && reflect.DeepEqual(raw, b.raw) {
+		return nil
+	}
+	// It copies the configuration, as the adapter might change its data in place.
+	raw = gutil.Copy(raw)
+	var value T
+	b.checked = true
+	b.raw = raw
+	b.err = b.bind(ctx, raw, &value)
+	b.version++
+	if b.err == nil {
+		b.value = value
+		b.valid = raw
+	}
+	return nil
+}
+
+// bind decrypts configuration `raw` and binds it to `pointer` with validation.
+func (b *Binding[T]) bind(ctx context.Context, raw interface{}, pointer *T) error {
+	value, _, err := b.config.decryptValue(ctx, raw)
+	if err != nil {
+		return err
+	}
+	var v *gvar.Var
+	if value != nil {
+		v = gvar.New(value)
+	}
+	return bindValue(ctx, b.pattern, v, pointer)
+}
+
+// refresh checks the configuration, and calls the callbacks if it is changed since last calling.
+func (b *Binding[T]) refresh(ctx context.Context) {
+	if err := b.check(ctx); err != nil {
+		intlog.Errorf(ctx, `%+v`, err)
+		return
+	}
+	b.mu.Lock()
+	if b.notified == b.version {
+		b.mu.Unlock()
+		return
+	}
+	b.notified = b.version
+	var (
+		err      = b.err
+		value    = b.value
+		onChange = b.onChange
+		onError  = b.onError
+	)
+	b.mu.Unlock()
+	if err != nil {
+		intlog.Errorf(ctx, `refuse invalid configuration of pattern "%s": %+v`, b.pattern, err)
+		for _, fn := range onError {
+			fn(ctx, err)
+		}
+		return
+	}
+	for _, fn := range onChange {
+		fn(ctx, value)
+	}
+}
+
+// addGuard adds `guard` for refusing invalid configuration.
+func (c *Config) addGuard(guard bindingGuard) {
+	c.guardMu.Lock()
+	defer c.guardMu.Unlock()
+	c.guards = append(c.guards, guard)
+}
+
+// removeGuard removes `guard` added by addGuard.
+func (c *Config) removeGuard(guard bindingGuard) {
+	c.guardMu.Lock()
+	defer c.guardMu.Unlock()
+	for i, v := range c.guards {
+		if v == guard {
+			c.guards = append(c.guards[:i:i], c.guards[i+1:]...)
+			break
+		}
+	}
+}
+
+// checkGuards rechecks the configuration of all guards, which is called after configuration changes.
+func (c *Config) checkGuards(ctx context.Context) {
+	c.guardMu.RLock()
+	guards := c.guards
+	c.guardMu.RUnlock()
+	for _, guard := range guards {
+		if err := guard.check(ctx); err != nil {
+			intlog.Errorf(ctx, `%+v`, err)
+		}
+	}
+}
+
+// guardValue replaces the refused configuration in `value` of `pattern` with the last valid configuration
+// of the guards. The `value` is copied before it is changed, as it might be the data of adapter.
+func (c *Config) guardValue(ctx context.Context, pattern string, value interface{}) interface{} {
+	c.guardMu.RLock()
+	guards := c.guards
+	c.guardMu.RUnlock()
+	pattern = strings.Trim(pattern, ".")
+	for _, guard := range guards {
+		guardPattern, valid, refused := guard.guard(ctx)
+		if !refused {
+			continue
+		}
+		guardPattern = strings.Trim(guardPattern, ".")
+		switch {
+		case guardPattern == pattern:
+			value = valid
+
+		case guardPattern == "" || strings.HasPrefix(pattern, guardPattern+"."):
+			// The pattern is under the refused configuration.
+			value = nil
+			if v := gjson.New(valid).Get(strings.TrimPrefix(pattern[len(guardPattern):], ".")); v != nil {
+				value = v.Val()
+			}
+
+		case pattern == "" || strings.HasPrefix(guardPattern, pattern+"."):
+			// The refused configuration is under the pattern.
+			var (
+				key = strings.TrimPrefix(guardPattern[len(pattern):], ".")
+				j   = gjson.New(copyConfigMap(value))
+			)
+			if valid == nil {
+				_ = j.Remove(key)
+			} else {
+				_ = j.Set(key, valid)
+			}
+			value = j.Interface()
+		}
+	}
+	return value
+}
+
+// copyConfigMap returns a deep copy of `value` if it is map, or else a new empty map.
+func copyConfigMap(value interface{}) map[string]interface{} {
+	if m, ok := gutil.Copy(value).(map[string]interface{}); ok && m != nil {
+		return m
+	}
+	return make(map[string]interface{})
+}
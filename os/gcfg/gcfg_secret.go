@@ -0,0 +1,261 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"sync"
+
+	"github.com/ximplez-go/gf/crypto/gaead"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/command"
+	"github.com/ximplez-go/gf/os/gfile"
+)
+
+// Decrypter is the interface for decrypting the encrypted configuration values like "ENC(base64...)".
+type Decrypter interface {
+	// Decrypt decrypts and returns the plain text of `cipherText`.
+	Decrypt(ctx context.Context, cipherText []byte) (plainText []byte, err error)
+}
+
+// Encrypter is the interface for encrypting configuration values, which is used by EncryptValue.
+type Encrypter interface {
+	// Encrypt encrypts and returns the cipher text of `plainText`.
+	Encrypt(ctx context.Context, plainText []byte) (cipherText []byte, err error)
+}
+
+// Secret implements interface Decrypter and Encrypter using gaead.Keyring,
+// whose ciphertext contains the key id, so that the keys can be rotated.
+type Secret struct {
+	keyring *gaead.Keyring
+}
+
+const (
+	encryptedValuePrefix = "ENC(" // encryptedValuePrefix is the prefix of encrypted configuration value.
+	encryptedValueSuffix = ")"    // encryptedValueSuffix is the suffix of encrypted configuration value.
+
+	commandEnvKeyForSecretKey     = "gf.gcfg.secret.key"     // commandEnvKeyForSecretKey is the configuration key for command argument or environment configuring base64 encoded secret key.
+	commandEnvKeyForSecretKeyFile = "gf.gcfg.secret.keyfile" // commandEnvKeyForSecretKeyFile is the configuration key for command argument or environment configuring secret key file path.
+	commandEnvKeyForSecretKeyId   = "gf.gcfg.secret.keyid"   // commandEnvKeyForSecretKeyId is the configuration key for command argument or environment configuring secret key id.
+
+	defaultSecretKeyId = "default" // defaultSecretKeyId is the default key id of the key from command argument or environment.
+)
+
+var (
+	// Check the implements for interface Decrypter and Encrypter.
+	_ Decrypter = (*Secret)(nil)
+	_ Encrypter = (*Secret)(nil)
+
+	// defaultDecrypter is the Decrypter for all Config objects without custom Decrypter.
+	defaultDecrypter Decrypter
+
+	// defaultDecrypterMu protects defaultDecrypter.
+	defaultDecrypterMu sync.RWMutex
+)
+
+// NewSecret creates and returns a Secret with `keyring`, which encrypts using the primary key of `keyring`,
+// and decrypts using the key specified by the ciphertext.
+func NewSecret(keyring *gaead.Keyring) *Secret {
+	return &Secret{keyring: keyring}
+}
+
+// NewSecretFromEnv creates and returns a Secret with the base64 encoded AES key from command option
+// or environment variable "gf.gcfg.secret.key", or the key file from "gf.gcfg.secret.keyfile"
+// whose content is the base64 encoded key, eg: environment variable GF_GCFG_SECRET_KEY.
+// The key id is from "gf.gcfg.secret.keyid", which is "default" if it is not configured.
+func NewSecretFromEnv() (*Secret, error) {
+	encodedKey := command.GetOptWithEnv(commandEnvKeyForSecretKey)
+	if encodedKey == "" {
+		if keyFile := command.GetOptWithEnv(commandEnvKeyForSecretKeyFile); keyFile != "" {
+			if !gfile.Exists(keyFile) {
+				return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `secret key file "%s" does not exist`, keyFile)
+			}
+			encodedKey = gfile.GetContents(keyFile)
+		}
+	}
+	encodedKey = strings.TrimSpace(encodedKey)
+	if encodedKey == "" {
+		return nil, gerror.NewCodef(
+			gcode.CodeInvalidConfiguration,
+			`secret key is not configured, which can be configured by command option or environment "%s" or "%s"`,
+			commandEnvKeyForSecretKey, commandEnvKeyForSecretKeyFile,
+		)
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, gerror.WrapCode(gcode.CodeInvalidConfiguration, err, `decode base64 secret key failed`)
+	}
+	keyId := strings.TrimSpace(command.GetOptWithEnv(commandEnvKeyForSecretKeyId, defaultSecretKeyId))
+	keyring, err := gaead.NewKeyring(&gaead.Key{
+		Id:        keyId,
+		Algorithm: gaead.AlgorithmAesGcm,
+		Secret:    key,
+	})
+	if err != nil {
+		return nil, gerror.WrapCode(gcode.CodeInvalidConfiguration, err, `invalid secret key`)
+	}
+	return NewSecret(keyring), nil
+}
+
+// Decrypt implements interface Decrypter.
+func (s *Secret) Decrypt(ctx context.Context, cipherText []byte) (plainText []byte, err error) {
+	return s.keyring.Decrypt(cipherText)
+}
+
+// Encrypt implements interface Encrypter.
+func (s *Secret) Encrypt(ctx context.Context, plainText []byte) (cipherText []byte, err error) {
+	return s.keyring.Encrypt(plainText)
+}
+
+// SetDecrypter sets the default Decrypter for all Config objects that have no custom Decrypter.
+// It uses Secret from NewSecretFromEnv in default, which is created when any encrypted value is read.
+func SetDecrypter(decrypter Decrypter) {
+	defaultDecrypterMu.Lock()
+	defer defaultDecrypterMu.Unlock()
+	defaultDecrypter = decrypter
+}
+
+// SetDecrypter sets the custom Decrypter of the current Config object.
+func (c *Config) SetDecrypter(decrypter Decrypter) {
+	c.decrypter = decrypter
+}
+
+// IsEncryptedValue checks and returns whether `value` is an encrypted value like "ENC(base64...)".
+func IsEncryptedValue(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, encryptedValuePrefix) && strings.HasSuffix(value, encryptedValueSuffix)
+}
+
+// EncryptValue encrypts `value` and returns the encrypted value like "ENC(base64...)",
+// which can be written into configuration files.
+// It uses Secret from NewSecretFromEnv if `encrypter` is not given.
+func EncryptValue(ctx context.Context, value string, encrypter ...Encrypter) (string, error) {
+	var usedEncrypter Encrypter
+	if len(encrypter) > 0 && encrypter[0] != nil {
+		usedEncrypter = encrypter[0]
+	} else {
+		secret, err := NewSecretFromEnv()
+		if err != nil {
+			return "", err
+		}
+		usedEncrypter = secret
+	}
+	cipherText, err := usedEncrypter.Encrypt(ctx, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(cipherText) + encryptedValueSuffix, nil
+}
+
+// getDecrypter returns the Decrypter of the current Config object.
+func (c *Config) getDecrypter() (Decrypter, error) {
+	if c.decrypter != nil {
+		return c.decrypter, nil
+	}
+	defaultDecrypterMu.RLock()
+	decrypter := defaultDecrypter
+	defaultDecrypterMu.RUnlock()
+	if decrypter != nil {
+		return decrypter, nil
+	}
+	defaultDecrypterMu.Lock()
+	defer defaultDecrypterMu.Unlock()
+	if defaultDecrypter == nil {
+		secret, err := NewSecretFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		defaultDecrypter = secret
+	}
+	return defaultDecrypter, nil
+}
+
+// decryptValue decrypts the encrypted values in `value` recursively, which can be string, map or slice.
+// It returns `value` itself if there's no encrypted value, or else it returns a copy of `value` with
+// decrypted values, so that the underlying configuration data is not changed.
+func (c *Config) decryptValue(ctx context.Context, value interface{}) (result interface{}, changed bool, err error) {
+	switch v := value.(type) {
+	case string:
+		if !IsEncryptedValue(v) {
+			return v, false, nil
+		}
+		plainText, err := c.decryptString(ctx, v)
+		if err != nil {
+			return nil, false, err
+		}
+		return plainText, true, nil
+
+	case map[string]interface{}:
+		var m map[string]interface{}
+		for key, item := range v {
+			newItem, itemChanged, err := c.decryptValue(ctx, item)
+			if err != nil {
+				return nil, false, gerror.Wrapf(err, `decrypt configuration "%s" failed`, key)
+			}
+			if !itemChanged {
+				continue
+			}
+			if m == nil {
+				m = make(map[string]interface{}, len(v))
+				for k, vv := range v {
+					m[k] = vv
+				}
+			}
+			m[key] = newItem
+		}
+		if m == nil {
+			return v, false, nil
+		}
+		return m, true, nil
+
+	case []interface{}:
+		var s []interface{}
+		for i, item := range v {
+			newItem, itemChanged, err := c.decryptValue(ctx, item)
+			if err != nil {
+				return nil, false, gerror.Wrapf(err, `decrypt configuration "%d" failed`, i)
+			}
+			if !itemChanged {
+				continue
+			}
+			if s == nil {
+				s = make([]interface{}, len(v))
+				copy(s, v)
+			}
+			s[i] = newItem
+		}
+		if s == nil {
+			return v, false, nil
+		}
+		return s, true, nil
+
+	default:
+		return value, false, nil
+	}
+}
+
+// decryptString decrypts the encrypted value like "ENC(base64...)".
+func (c *Config) decryptString(ctx context.Context, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	encoded := value[len(encryptedValuePrefix) : len(value)-len(encryptedValueSuffix)]
+	cipherText, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", gerror.WrapCode(gcode.CodeInvalidConfiguration, err, `decode base64 encrypted value failed`)
+	}
+	decrypter, err := c.getDecrypter()
+	if err != nil {
+		return "", err
+	}
+	plainText, err := decrypter.Decrypt(ctx, cipherText)
+	if err != nil {
+		return "", gerror.WrapCode(gcode.CodeInvalidConfiguration, err, `decrypt encrypted value failed`)
+	}
+	return string(plainText), nil
+}
@@ -0,0 +1,278 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/text/gstr"
+	"github.com/ximplez-go/gf/util/gconv"
+	"github.com/ximplez-go/gf/util/gtag"
+	"github.com/ximplez-go/gf/util/gutil"
+	"github.com/ximplez-go/gf/util/gvalid"
+)
+
+// ValidationError is the error of configuration validation, which contains all rule failures.
+type ValidationError struct {
+	failures []ValidationFailure
+}
+
+// ValidationFailure is a failed validation rule of configuration.
+type ValidationFailure struct {
+	Path    string // Path is the configuration path of the failed value, like "database.default.link".
+	Rule    string // Rule is the name of failed rule, like "required".
+	Message string // Message is the error message of the failed rule.
+}
+
+// Code returns the error code gcode.CodeValidationFailed.
+func (e *ValidationError) Code() gcode.Code {
+	return gcode.CodeValidationFailed
+}
+
+// Failures returns all rule failures sorted by configuration path.
+func (e *ValidationError) Failures() []ValidationFailure {
+	return e.failures
+}
+
+// Error implements the interface error, which joins all rule failures.
+func (e *ValidationError) Error() string {
+	items := make([]string, 0, len(e.failures))
+	for _, failure := range e.failures {
+		items = append(items, fmt.Sprintf(`%s: %s`, failure.Path, failure.Message))
+	}
+	return `configuration validation failed: ` + strings.Join(items, "; ")
+}
+
+// Bind retrieves the configuration value of `pattern`, validates it with the validation tags
+// of struct `pointer` like `v:"required"`, and converts it to `pointer` using gconv.Struct.
+//
+// The validation rules are checked against the configuration values, in which the configuration key
+// of the struct attribute is matched as gconv.Struct does. The nested struct, struct pointer,
+// struct slice and struct map attributes are validated recursively.
+//
+// It returns *ValidationError containing all rule failures with their configuration paths
+// if validation fails, in which case `pointer` is not changed.
+func (c *Config) Bind(ctx context.Context, pattern string, pointer interface{}) error {
+	value, err := c.Get(ctx, pattern)
+	if err != nil {
+		return err
+	}
+	return bindValue(ctx, pattern, value, pointer)
+}
+
+// bindValue validates configuration `value` of `pattern` with the validation tags of struct `pointer`,
+// and converts it to `pointer` if validation passes.
+func bindValue(ctx context.Context, pattern string, value *gvar.Var, pointer interface{}) (err error) {
+	reflectValue := reflect.ValueOf(pointer)
+	if reflectValue.Kind() != reflect.Ptr || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		return gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pointer "%T", which should be pointer of struct`, pointer)
+	}
+	var data map[string]interface{}
+	if value != nil {
+		data = value.Map()
+	}
+	var (
+		structType = reflectValue.Elem().Type()
+		path       = pattern
+		failures   = make([]ValidationFailure, 0)
+	)
+	if path == "." {
+		path = ""
+	}
+	if err = validateConfigStruct(ctx, path, data, structType, &failures); err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		sort.SliceStable(failures, func(i, j int) bool {
+			return failures[i].Path < failures[j].Path
+		})
+		return &ValidationError{failures: failures}
+	}
+	// It converts to a new object firstly, so that `pointer` is not changed if conversion fails.
+	newValue := reflect.New(structType)
+	if err = gconv.Struct(data, newValue.Interface()); err != nil {
+		return gerror.Wrapf(err, `convert configuration of pattern "%s" to "%T" failed`, pattern, pointer)
+	}
+	reflectValue.Elem().Set(newValue.Elem())
+	return nil
+}
+
+// validateConfigStruct validates configuration `data` at `path` with the validation tags of `structType`,
+// and appends the rule failures to `failures`.
+func validateConfigStruct(
+	ctx context.Context, path string, data map[string]interface{}, structType reflect.Type, failures *[]ValidationFailure,
+) error {
+	var (
+		rules    = make(map[string]string)
+		messages = make(gvalid.CustomMsg)
+	)
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup(gtag.NoValidation); ok {
+			continue
+		}
+		if field.Anonymous && getStructType(field.Type) != nil {
+			// The attributes of embedded struct are considered as attributes of its parent struct.
+			if err := validateConfigStruct(ctx, path, data, getStructType(field.Type), failures); err != nil {
+				return err
+			}
+			continue
+		}
+		key, value := getConfigKeyAndValue(data, field)
+		if tag := getValidationTag(field); tag != "" {
+			_, rule, msg := gvalid.ParseTagValue(tag)
+			rules[key] = rule
+			if msg != "" {
+				messages[key] = getRuleMessages(rule, msg)
+			}
+		}
+		if err := validateConfigValue(ctx, joinConfigPath(path, key), value, field.Type, failures); err != nil {
+			return err
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	validationErr := gvalid.New().Data(data).Rules(rules).Messages(messages).Run(ctx)
+	if validationErr == nil {
+		return nil
+	}
+	if gerror.Code(validationErr) != gcode.CodeValidationFailed {
+		return validationErr
+	}
+	for key, ruleErrors := range validationErr.Maps() {
+		ruleNames := make([]string, 0, len(ruleErrors))
+		for rule := range ruleErrors {
+			ruleNames = append(ruleNames, rule)
+		}
+		sort.Strings(ruleNames)
+		for _, rule := range ruleNames {
+			*failures = append(*failures, ValidationFailure{
+				Path:    joinConfigPath(path, key),
+				Rule:    rule,
+				Message: ruleErrors[rule].Error(),
+			})
+		}
+	}
+	return nil
+}
+
+// validateConfigValue validates configuration `value` at `path` recursively
+// if `fieldType` is struct, struct pointer, struct slice or struct map.
+func validateConfigValue(
+	ctx context.Context, path string, value interface{}, fieldType reflect.Type, failures *[]ValidationFailure,
+) error {
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		return validateConfigStruct(ctx, path, gconv.Map(value), fieldType, failures)
+
+	case reflect.Ptr:
+		// The absent configuration of struct pointer is considered optional.
+		if structType := getStructType(fieldType); structType != nil && value != nil {
+			return validateConfigStruct(ctx, path, gconv.Map(value), structType, failures)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if structType := getStructType(fieldType.Elem()); structType != nil {
+			for i, item := range gconv.Interfaces(value) {
+				if err := validateConfigStruct(
+					ctx, joinConfigPath(path, gconv.String(i)), gconv.Map(item), structType, failures,
+				); err != nil {
+					return err
+				}
+			}
+		}
+
+	case reflect.Map:
+		if structType := getStructType(fieldType.Elem()); structType != nil {
+			for k, item := range gconv.Map(value) {
+				if err := validateConfigStruct(
+					ctx, joinConfigPath(path, k), gconv.Map(item), structType, failures,
+				); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getConfigKeyAndValue returns the configuration key and value in `data` for struct attribute `field`.
+// The key is matched as gconv.Struct does, which is the name of priority tags like `json`,
+// or the possible key ignoring cases and symbols. It returns the attribute name in lower camel case
+// as key if it is not found in `data`.
+func getConfigKeyAndValue(data map[string]interface{}, field reflect.StructField) (key string, value interface{}) {
+	for _, tagName := range gtag.StructTagPriority {
+		if tagValue := strings.Split(field.Tag.Get(tagName), ",")[0]; tagValue != "" && tagValue != "-" {
+			return tagValue, data[tagValue]
+		}
+	}
+	if foundKey, foundValue := gutil.MapPossibleItemByKey(data, field.Name); foundKey != "" {
+		return foundKey, foundValue
+	}
+	return gstr.CaseCamelLower(field.Name), nil
+}
+
+// getValidationTag returns the validation tag value of struct attribute `field`.
+func getValidationTag(field reflect.StructField) string {
+	for _, tagName := range gvalid.GetTags() {
+		if tagValue := field.Tag.Get(tagName); tagValue != "" {
+			return tagValue
+		}
+	}
+	return ""
+}
+
+// getRuleMessages returns the custom messages of `rule` from tag message `msg`, which are separated by char '|'
+// as the rules, like rule "required|min:1" and message "link is required|link is too short".
+func getRuleMessages(rule, msg string) map[string]string {
+	var (
+		ruleMessages = make(map[string]string)
+		msgArray     = strings.Split(msg, "|")
+	)
+	for i, ruleItem := range strings.Split(rule, "|") {
+		if i >= len(msgArray) {
+			break
+		}
+		if msgItem := strings.TrimSpace(msgArray[i]); msgItem != "" {
+			ruleMessages[strings.TrimSpace(strings.Split(ruleItem, ":")[0])] = msgItem
+		}
+	}
+	return ruleMessages
+}
+
+// getStructType returns the struct type of `t` or its element type if it is pointer, or else nil.
+func getStructType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		return t
+	}
+	return nil
+}
+
+// joinConfigPath joins configuration `path` and `key` with char '.'.
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,123 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/util/guid"
+)
+
+// Watch registers `callback` which is called with the new value of `pattern` after it changes.
+// The `pattern` is like "database.default", and the callback is called if any key under it changes.
+// It watches all configuration changes if `pattern` is empty or ".".
+//
+// It returns the unique name of the watcher, which can be used for Unwatch.
+// It returns error if the adapter of current Config does not implement WatchableAdapter.
+func (c *Config) Watch(pattern string, callback func(ctx context.Context, value *gvar.Var)) (name string, err error) {
+	adapter, err := c.getWatchableAdapter()
+	if err != nil {
+		return "", err
+	}
+	name = guid.S()
+	adapter.OnChange(name, func(ctx context.Context, event ChangeEvent) {
+		if !isPatternChanged(pattern, event.Keys) {
+			return
+		}
+		// The bindings refuse the invalid configuration before it is retrieved.
+		c.checkGuards(ctx)
+		value, err := c.Get(ctx, pattern)
+		if err != nil {
+			intlog.Errorf(ctx, `%+v`, err)
+			return
+		}
+		callback(ctx, value)
+	})
+	return name, nil
+}
+
+// WatchAs registers typed `callback` which is called with the new value of `pattern` after it changes,
+// in which the value is converted to type `T`, like struct, map or basic types.
+// It acts like Config.Watch, but it does not call `callback` if the value conversion fails.
+func WatchAs[T any](c *Config, pattern string, callback func(ctx context.Context, value T)) (name string, err error) {
+	return c.Watch(pattern, func(ctx context.Context, v *gvar.Var) {
+		var value T
+		if v != nil {
+			if err := v.Scan(&value); err != nil {
+				intlog.Errorf(ctx, `convert configuration value of pattern "%s" failed: %+v`, pattern, err)
+				return
+			}
+		}
+		callback(ctx, value)
+	})
+}
+
+// WatchChan returns a channel which receives the configuration change events
+// until `ctx` is done, after which the channel is closed.
+//
+// Note that the sending is blocked if the channel is not consumed in time,
+// which also blocks the other watchers of the adapter.
+func (c *Config) WatchChan(ctx context.Context) (<-chan ChangeEvent, error) {
+	adapter, err := c.getWatchableAdapter()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		mu     sync.Mutex
+		closed bool
+		name   = guid.S()
+		ch     = make(chan ChangeEvent, 1)
+	)
+	adapter.OnChange(name, func(_ context.Context, event ChangeEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- event:
+		case <-ctx.Done():
+		}
+	})
+	go func() {
+		<-ctx.Done()
+		adapter.RemoveOnChange(name)
+		mu.Lock()
+		closed = true
+		close(ch)
+		mu.Unlock()
+	}()
+	return ch, nil
+}
+
+// Unwatch removes the watcher registered by Watch or WatchAs with `name`.
+func (c *Config) Unwatch(name string) error {
+	adapter, err := c.getWatchableAdapter()
+	if err != nil {
+		return err
+	}
+	adapter.RemoveOnChange(name)
+	return nil
+}
+
+// getWatchableAdapter returns the adapter of current Config as WatchableAdapter.
+func (c *Config) getWatchableAdapter() (WatchableAdapter, error) {
+	adapter, ok := c.adapter.(WatchableAdapter)
+	if !ok {
+		return nil, gerror.NewCodef(
+			gcode.CodeNotSupported,
+			`adapter "%T" does not support watching configuration changes`,
+			c.adapter,
+		)
+	}
+	return adapter, nil
+}
@@ -0,0 +1,203 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/internal/command"
+	"github.com/ximplez-go/gf/os/gcfg"
+	"github.com/ximplez-go/gf/os/genv"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+func Test_AdapterLayered(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		defaults, err := gcfg.NewAdapterContent(`
+database:
+  default:
+    link: "mysql:root@tcp(127.0.0.1:3306)/test"
+    maxIdle: 10
+    debug: false
+server:
+  address: ":8000"
+list: [1, 2, 3]
+`)
+		t.AssertNil(err)
+		prod, err := gcfg.NewAdapterContent(`
+database:
+  default:
+    debug: true
+list: [4]
+`)
+		t.AssertNil(err)
+		var (
+			adapter = gcfg.NewAdapterLayered(
+				gcfg.AdapterLayer{Name: "defaults", Adapter: defaults},
+				gcfg.AdapterLayer{Name: "prod", Adapter: prod},
+			)
+			config = gcfg.NewWithAdapter(adapter)
+		)
+		t.Assert(config.Available(ctx), true)
+		t.Assert(config.MustGet(ctx, "database.default.debug"), true)
+		t.Assert(config.MustGet(ctx, "database.default.maxIdle"), 10)
+		t.Assert(config.MustGet(ctx, "list"), []int{4})
+		t.Assert(config.MustGet(ctx, "database.default").Map(), map[string]interface{}{
+			"link":    "mysql:root@tcp(127.0.0.1:3306)/test",
+			"maxIdle": 10,
+			"debug":   true,
+		})
+		t.Assert(config.MustGet(ctx, "none"), nil)
+		data, err := config.Data(ctx)
+		t.AssertNil(err)
+		t.Assert(data, map[string]interface{}{
+			"database": map[string]interface{}{
+				"default": map[string]interface{}{
+					"link":    "mysql:root@tcp(127.0.0.1:3306)/test",
+					"maxIdle": 10,
+					"debug":   true,
+				},
+			},
+			"server": map[string]interface{}{
+				"address": ":8000",
+			},
+			"list": []int{4},
+		})
+		// The data of layers is not changed by merging.
+		v, err := defaults.Get(ctx, "database.default.debug")
+		t.AssertNil(err)
+		t.Assert(v, false)
+
+		source, err := adapter.Source(ctx, "database.default.debug")
+		t.AssertNil(err)
+		t.Assert(source, "prod")
+		source, err = adapter.Source(ctx, "server.address")
+		t.AssertNil(err)
+		t.Assert(source, "defaults")
+		source, err = adapter.Source(ctx, "none")
+		t.AssertNil(err)
+		t.Assert(source, "")
+	})
+}
+
+func Test_AdapterLayered_EnvAndCmd(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		defaults, err := gcfg.NewAdapterContent(`
+database:
+  default:
+    link: "mysql:root@tcp(127.0.0.1:3306)/test"
+    maxIdle: 10
+server:
+  address: ":8000"
+`)
+		t.AssertNil(err)
+		t.AssertNil(genv.SetMap(map[string]string{
+			"GCFG_TEST_DATABASE__DEFAULT__MAXIDLE": "20",
+			"GCFG_TEST_SERVER__ADDRESS":            ":8080",
+			"GCFG_TEST_SERVER__MAX_HEADER_BYTES":   "1024",
+		}))
+		defer genv.Remove(
+			"GCFG_TEST_DATABASE__DEFAULT__MAXIDLE",
+			"GCFG_TEST_SERVER__ADDRESS",
+			"GCFG_TEST_SERVER__MAX_HEADER_BYTES",
+		)
+		command.Init("main", "--server.address=:8888")
+		defer command.Init(os.Args...)
+
+		var (
+			adapter = gcfg.NewAdapterLayered(
+				gcfg.AdapterLayer{Name: gcfg.LayerNameFile, Adapter: defaults},
+				gcfg.AdapterLayer{Name: gcfg.LayerNameEnv, Adapter: gcfg.NewAdapterEnv("GCFG_TEST_")},
+			)
+			config = gcfg.NewWithAdapter(adapter)
+		)
+		t.Assert(config.MustGet(ctx, "database.default.maxIdle"), 20)
+		t.Assert(config.MustGet(ctx, "server.address"), ":8080")
+		t.Assert(config.MustGet(ctx, "server.max_header_bytes"), 1024)
+		t.Assert(config.MustGet(ctx, "database.default").Map(), map[string]interface{}{
+			"link":    "mysql:root@tcp(127.0.0.1:3306)/test",
+			"maxIdle": "20",
+		})
+		source, err := adapter.Source(ctx, "database.default.maxIdle")
+		t.AssertNil(err)
+		t.Assert(source, gcfg.LayerNameEnv)
+
+		adapter.AddLayer(gcfg.LayerNameCmd, gcfg.NewAdapterCmd())
+		t.Assert(config.MustGet(ctx, "server.address"), ":8888")
+		source, err = adapter.Source(ctx, "server.address")
+		t.AssertNil(err)
+		t.Assert(source, gcfg.LayerNameCmd)
+		t.Assert(len(adapter.Layers()), 3)
+	})
+}
+
+func Test_AdapterLayered_WithOption(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			dir     = gfile.Temp(gtime.TimestampNanoStr())
+			path    = gfile.Join(dir, "config.yaml")
+			content = `
+app:
+  name: "test"
+  debug: false
+`
+		)
+		t.AssertNil(gfile.PutContents(path, content))
+		t.AssertNil(gfile.PutContents(gfile.Join(dir, "config.prod.yaml"), "app:\n  debug: true\n"))
+		defer gfile.Remove(dir)
+
+		adapter, err := gcfg.NewAdapterLayeredWithOption(gcfg.AdapterLayeredOption{
+			FileName:    path,
+			Environment: "prod",
+		})
+		t.AssertNil(err)
+		config := gcfg.NewWithAdapter(adapter)
+		t.Assert(config.MustGet(ctx, "app.name"), "test")
+		t.Assert(config.MustGet(ctx, "app.debug"), true)
+		source, err := adapter.Source(ctx, "app.debug")
+		t.AssertNil(err)
+		t.Assert(source, "file.prod")
+
+		// Changes overridden by higher layers are not notified.
+		var keys = gvar.New(nil, true)
+		adapter.OnChange("test", func(ctx context.Context, event gcfg.ChangeEvent) {
+			keys.Set(event.Keys)
+		})
+		t.AssertNil(gfile.PutContents(path, gstr.Replace(content, `debug: false`, `debug: "no"`)))
+		time.Sleep(time.Second)
+		t.Assert(keys.Val(), nil)
+		t.AssertNil(gfile.PutContents(path, gstr.Replace(content, `"test"`, `"test2"`)))
+		for i := 0; i < 50 && keys.IsNil(); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(keys.Strings(), []string{"app.name"})
+		t.Assert(config.MustGet(ctx, "app.name"), "test2")
+	})
+
+	// The environment-specific configuration file does not exist.
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp(gtime.TimestampNanoStr(), "config.yaml")
+		t.AssertNil(gfile.PutContents(path, "app:\n  name: test\n"))
+		defer gfile.Remove(gfile.Dir(path))
+
+		adapter, err := gcfg.NewAdapterLayeredWithOption(gcfg.AdapterLayeredOption{
+			FileName:    path,
+			Environment: "dev",
+		})
+		t.AssertNil(err)
+		config := gcfg.NewWithAdapter(adapter)
+		t.Assert(config.MustGet(ctx, "app.name"), "test")
+		t.Assert(config.MustGet(ctx, "app.debug"), nil)
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ximplez-go/gf/crypto/gaead"
+	"github.com/ximplez-go/gf/encoding/gbase64"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gcfg"
+	"github.com/ximplez-go/gf/os/genv"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func newTestSecret(t *gtest.T, keys ...*gaead.Key) (*gcfg.Secret, *gaead.Keyring) {
+	keyring, err := gaead.NewKeyring(keys[0], keys[1:]...)
+	t.AssertNil(err)
+	return gcfg.NewSecret(keyring), keyring
+}
+
+func Test_Secret(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		secret, _ := newTestSecret(t, &gaead.Key{
+			Id:        "k1",
+			Algorithm: gaead.AlgorithmAesGcm,
+			Secret:    []byte("12345678901234567890123456789012"),
+		})
+		encrypted, err := gcfg.EncryptValue(ctx, "123456", secret)
+		t.AssertNil(err)
+		t.Assert(gcfg.IsEncryptedValue(encrypted), true)
+		t.Assert(gcfg.IsEncryptedValue("123456"), false)
+
+		adapter, err := gcfg.NewAdapterContent(fmt.Sprintf(`
+database:
+  default:
+    user: "root"
+    pass: "%s"
+  slaves:
+    - pass: "%s"
+`, encrypted, encrypted))
+		t.AssertNil(err)
+		config := gcfg.NewWithAdapter(adapter)
+		config.SetDecrypter(secret)
+		t.Assert(config.MustGet(ctx, "database.default.pass"), "123456")
+		t.Assert(config.MustGet(ctx, "database.default").Map()["pass"], "123456")
+		t.Assert(config.MustGet(ctx, "database.slaves.0.pass"), "123456")
+		data, err := config.Data(ctx)
+		t.AssertNil(err)
+		t.Assert(data["database"], map[string]interface{}{
+			"default": map[string]interface{}{
+				"user": "root",
+				"pass": "123456",
+			},
+			"slaves": []interface{}{
+				map[string]interface{}{"pass": "123456"},
+			},
+		})
+		// The underlying configuration data is not changed.
+		value, err := adapter.Get(ctx, "database.default.pass")
+		t.AssertNil(err)
+		t.Assert(value, encrypted)
+
+		// Wrong key.
+		wrongSecret, _ := newTestSecret(t, &gaead.Key{
+			Id:        "k1",
+			Algorithm: gaead.AlgorithmAesGcm,
+			Secret:    []byte("1234567890123456"),
+		})
+		config.SetDecrypter(wrongSecret)
+		_, err = config.Get(ctx, "database.default.pass")
+		t.Assert(gerror.Code(err), gcode.CodeInvalidConfiguration)
+		_, err = config.Data(ctx)
+		t.AssertNE(err, nil)
+	})
+
+}
+
+func Test_Secret_Rotation(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		oldKey, err := gaead.GenerateKey("k1", gaead.AlgorithmAesGcm)
+		t.AssertNil(err)
+		newKey, err := gaead.GenerateKey("k2", gaead.AlgorithmXChaCha20Poly1305)
+		t.AssertNil(err)
+		secret, keyring := newTestSecret(t, oldKey)
+		oldEncrypted, err := gcfg.EncryptValue(ctx, "old", secret)
+		t.AssertNil(err)
+
+		// The values encrypted by old key are still decrypted after the new key becomes primary.
+		t.AssertNil(keyring.Add(newKey))
+		t.AssertNil(keyring.SetPrimary("k2"))
+		newEncrypted, err := gcfg.EncryptValue(ctx, "new", secret)
+		t.AssertNil(err)
+		adapter, err := gcfg.NewAdapterContent(fmt.Sprintf(`{"old": "%s", "new": "%s"}`, oldEncrypted, newEncrypted))
+		t.AssertNil(err)
+		config := gcfg.NewWithAdapter(adapter)
+		config.SetDecrypter(secret)
+		t.Assert(config.MustGet(ctx, "old"), "old")
+		t.Assert(config.MustGet(ctx, "new"), "new")
+
+		// The values encrypted by removed key cannot be decrypted.
+		t.AssertNil(keyring.Remove("k1"))
+		_, err = config.Get(ctx, "old")
+		t.Assert(gerror.Code(err), gcode.CodeInvalidConfiguration)
+		t.Assert(config.MustGet(ctx, "new"), "new")
+	})
+}
+
+func Test_Secret_KeyFromEnv(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp(gtime.TimestampNanoStr(), "secret.key")
+		t.AssertNil(gfile.PutContents(path, gbase64.EncodeToString([]byte("1234567890123456"))+"\n"))
+		defer gfile.Remove(gfile.Dir(path))
+		t.AssertNil(genv.Set("GF_GCFG_SECRET_KEYFILE", path))
+		defer genv.Remove("GF_GCFG_SECRET_KEYFILE")
+
+		encrypted, err := gcfg.EncryptValue(ctx, "123456")
+		t.AssertNil(err)
+		adapter, err := gcfg.NewAdapterContent(fmt.Sprintf(`{"pass": "%s"}`, encrypted))
+		t.AssertNil(err)
+		t.Assert(gcfg.NewWithAdapter(adapter).MustGet(ctx, "pass"), "123456")
+
+		secret, err := gcfg.NewSecretFromEnv()
+		t.AssertNil(err)
+		gcfg.SetDecrypter(secret)
+		defer gcfg.SetDecrypter(nil)
+		t.Assert(gcfg.NewWithAdapter(adapter).MustGet(ctx, "pass"), "123456")
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		t.AssertNil(genv.Set("GF_GCFG_SECRET_KEY", "invalid base64"))
+		defer genv.Remove("GF_GCFG_SECRET_KEY")
+		_, err := gcfg.NewSecretFromEnv()
+		t.Assert(gerror.Code(err), gcode.CodeInvalidConfiguration)
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		t.AssertNil(genv.Set("GF_GCFG_SECRET_KEY", gbase64.EncodeToString([]byte("123"))))
+		defer genv.Remove("GF_GCFG_SECRET_KEY")
+		_, err := gcfg.NewSecretFromEnv()
+		t.Assert(gerror.Code(err), gcode.CodeInvalidConfiguration)
+	})
+}
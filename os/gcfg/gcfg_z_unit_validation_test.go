@@ -0,0 +1,199 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/garray"
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gcfg"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+type testServerConfig struct {
+	Address string `v:"required"`
+	Timeout int    `v:"min:1#timeout should be positive"`
+	Nodes   []testServerNode
+	Log     *testServerLog
+}
+
+type testServerNode struct {
+	Name   string `v:"required"`
+	Weight int    `json:"weight" v:"between:1,100"`
+}
+
+type testServerLog struct {
+	Path string `v:"required"`
+}
+
+func Test_Bind(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		adapter, err := gcfg.NewAdapterContent(`
+server:
+  address: ":8000"
+  timeout: 10
+  nodes:
+    - name: "a"
+      weight: 10
+    - name: "b"
+      weight: 20
+`)
+		t.AssertNil(err)
+		var (
+			config = gcfg.NewWithAdapter(adapter)
+			server testServerConfig
+		)
+		t.AssertNil(config.Bind(ctx, "server", &server))
+		t.Assert(server.Address, ":8000")
+		t.Assert(server.Timeout, 10)
+		t.Assert(len(server.Nodes), 2)
+		t.Assert(server.Nodes[1].Name, "b")
+		t.Assert(server.Log, nil)
+	})
+
+	// All rule failures with their configuration paths.
+	gtest.C(t, func(t *gtest.T) {
+		adapter, err := gcfg.NewAdapterContent(`
+server:
+  adress: ":8000"
+  timeout: 0
+  nodes:
+    - name: "a"
+      weight: 1000
+    - weight: 20
+  log:
+    level: "all"
+`)
+		t.AssertNil(err)
+		var (
+			config = gcfg.NewWithAdapter(adapter)
+			server = testServerConfig{Address: ":8080"}
+		)
+		err = config.Bind(ctx, "server", &server)
+		t.AssertNE(err, nil)
+		t.Assert(gerror.Code(err), gcode.CodeValidationFailed)
+		validationErr, ok := err.(*gcfg.ValidationError)
+		t.Assert(ok, true)
+		paths := make([]string, 0)
+		for _, failure := range validationErr.Failures() {
+			paths = append(paths, failure.Path)
+		}
+		t.Assert(paths, []string{
+			"server.address",
+			"server.log.path",
+			"server.nodes.0.weight",
+			"server.nodes.1.name",
+			"server.timeout",
+		})
+		t.Assert(validationErr.Failures()[4], gcfg.ValidationFailure{
+			Path:    "server.timeout",
+			Rule:    "min",
+			Message: "timeout should be positive",
+		})
+		t.Assert(gstr.Contains(err.Error(), "server.nodes.1.name: "), true)
+		// The pointer is not changed if validation fails.
+		t.Assert(server.Address, ":8080")
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		adapter, err := gcfg.NewAdapterContent(`{"address": ":8000"}`)
+		t.AssertNil(err)
+		config := gcfg.NewWithAdapter(adapter)
+		t.Assert(gerror.Code(config.Bind(ctx, ".", nil)), gcode.CodeInvalidParameter)
+		var server testServerConfig
+		t.AssertNil(config.Bind(ctx, ".", &server))
+		t.Assert(server.Address, ":8000")
+	})
+}
+
+func Test_BindAs_HotReload(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			path    = gfile.Temp(gtime.TimestampNanoStr(), "config.yaml")
+			content = `
+server:
+  address: ":8000"
+  timeout: 10
+`
+		)
+		t.AssertNil(gfile.PutContents(path, content))
+		defer gfile.Remove(gfile.Dir(path))
+
+		adapter, err := gcfg.NewAdapterFile(path)
+		t.AssertNil(err)
+		var (
+			config  = gcfg.NewWithAdapter(adapter)
+			changed = gvar.New(nil, true)
+			errored = gvar.New(nil, true)
+			watched = garray.New(true)
+		)
+		// The watcher registered before binding also retrieves the valid configuration.
+		_, err = config.Watch("server.timeout", func(ctx context.Context, value *gvar.Var) {
+			watched.Append(value.Int())
+		})
+		t.AssertNil(err)
+		binding, err := gcfg.BindAs[testServerConfig](ctx, config, "server")
+		t.AssertNil(err)
+		defer binding.Close()
+		t.Assert(binding.Get().Timeout, 10)
+		binding.OnChange(func(ctx context.Context, value testServerConfig) {
+			changed.Set(value.Timeout)
+		})
+		binding.OnError(func(ctx context.Context, err error) {
+			errored.Set(err)
+		})
+
+		// Invalid configuration is refused.
+		t.AssertNil(gfile.PutContents(path, gstr.Replace(content, "timeout: 10", "timeout: -1")))
+		for i := 0; i < 50 && errored.IsNil(); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(gerror.Code(binding.Err()), gcode.CodeValidationFailed)
+		t.Assert(gerror.Code(errored.Val().(error)), gcode.CodeValidationFailed)
+		t.Assert(changed.Val(), nil)
+		t.Assert(binding.Get().Timeout, 10)
+		t.Assert(config.MustGet(ctx, "server.timeout"), 10)
+		t.Assert(config.MustGet(ctx, "server").Map()["timeout"], 10)
+		t.Assert(config.MustData(ctx)["server"].(map[string]interface{})["timeout"], 10)
+		t.Assert(watched.Slice(), []interface{}{10})
+
+		// Valid configuration is swapped in.
+		t.AssertNil(gfile.PutContents(path, gstr.Replace(content, "timeout: 10", "timeout: 20")))
+		for i := 0; i < 50 && changed.IsNil(); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(changed.Val(), 20)
+		t.AssertNil(binding.Err())
+		t.Assert(binding.Get().Timeout, 20)
+		t.Assert(config.MustGet(ctx, "server.timeout"), 20)
+		t.Assert(watched.Slice(), []interface{}{10, 20})
+
+		// The invalid configuration is served after binding is closed.
+		t.AssertNil(binding.Close())
+		t.AssertNil(gfile.PutContents(path, gstr.Replace(content, "timeout: 10", "timeout: -1")))
+		for i := 0; i < 50 && watched.Len() < 3; i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Assert(config.MustGet(ctx, "server.timeout"), -1)
+	})
+
+	// Invalid configuration at load time.
+	gtest.C(t, func(t *gtest.T) {
+		adapter, err := gcfg.NewAdapterContent(`{"server": {"timeout": 10}}`)
+		t.AssertNil(err)
+		_, err = gcfg.BindAs[testServerConfig](ctx, gcfg.NewWithAdapter(adapter), "server")
+		t.Assert(gerror.Code(err), gcode.CodeValidationFailed)
+	})
+}
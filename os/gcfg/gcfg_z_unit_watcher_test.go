@@ -0,0 +1,242 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcfg_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/garray"
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/container/gvar"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gcfg"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+type testDatabaseConfig struct {
+	Link  string
+	Debug bool
+}
+
+func Test_Watch_AdapterFile(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			path    = gfile.Temp(gtime.TimestampNanoStr(), "config.yaml")
+			content = `
+database:
+  default:
+    link: "mysql:root@tcp(127.0.0.1:3306)/test"
+    debug: false
+server:
+  address: ":8000"
+`
+		)
+		t.AssertNil(gfile.PutContents(path, content))
+		defer gfile.Remove(gfile.Dir(path))
+
+		adapter, err := gcfg.NewAdapterFile(path)
+		t.AssertNil(err)
+		var (
+			config   = gcfg.NewWithAdapter(adapter)
+			values   = garray.New(true)
+			typed    = gvar.New(nil, true)
+			watchCtx context.Context
+			cancel   context.CancelFunc
+		)
+		t.Assert(config.MustGet(ctx, "database.default.debug"), false)
+
+		_, err = config.Watch("database.default", func(ctx context.Context, value *gvar.Var) {
+			values.Append(value.Map()["debug"])
+		})
+		t.AssertNil(err)
+		_, err = gcfg.WatchAs(config, "database.default", func(ctx context.Context, value testDatabaseConfig) {
+			typed.Set(value)
+		})
+		t.AssertNil(err)
+		watchCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		events, err := config.WatchChan(watchCtx)
+		t.AssertNil(err)
+
+		// Changes of other keys.
+		t.AssertNil(gfile.PutContents(path, gstr.Replace(content, `":8000"`, `":8080"`)))
+		select {
+		case event := <-events:
+			t.Assert(event.Keys, []string{"server.address"})
+		case <-time.After(5 * time.Second):
+			t.Error("watching timeout")
+		}
+		t.Assert(values.Len(), 0)
+		t.Assert(config.MustGet(ctx, "server.address"), ":8080")
+
+		// Changes of watched keys.
+		t.AssertNil(gfile.PutContents(path, gstr.Replace(content, `debug: false`, `debug: true`)))
+		select {
+		case event := <-events:
+			t.Assert(event.Keys, []string{"database.default.debug", "server.address"})
+		case <-time.After(5 * time.Second):
+			t.Error("watching timeout")
+		}
+		t.Assert(values.Slice(), []interface{}{true})
+		t.Assert(typed.Val(), testDatabaseConfig{
+			Link:  "mysql:root@tcp(127.0.0.1:3306)/test",
+			Debug: true,
+		})
+	})
+}
+
+func Test_Watch_AdapterHttp(t *testing.T) {
+	var (
+		version  = gtype.NewInt(1)
+		requests = gtype.NewInt()
+		notMod   = gtype.NewInt()
+		server   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			etag := fmt.Sprintf(`"v%d"`, version.Val())
+			if r.Header.Get("If-None-Match") == etag {
+				notMod.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = fmt.Fprintf(w, "app:\n  name: test\n  version: %d\n", version.Val())
+		}))
+	)
+	defer server.Close()
+
+	gtest.C(t, func(t *gtest.T) {
+		adapter, err := gcfg.NewAdapterHttp(ctx, server.URL+"/config", gcfg.AdapterHttpOption{
+			Interval: 100 * time.Millisecond,
+		})
+		t.AssertNil(err)
+		defer adapter.Close()
+		t.Assert(adapter.Available(ctx), true)
+
+		var (
+			config = gcfg.NewWithAdapter(adapter)
+			keys   = gvar.New(nil, true)
+			value  = gtype.NewInt()
+		)
+		t.Assert(config.MustGet(ctx, "app.version"), 1)
+		adapter.OnChange("test", func(ctx context.Context, event gcfg.ChangeEvent) {
+			keys.Set(event.Keys)
+		})
+		_, err = gcfg.WatchAs(config, "app.version", func(ctx context.Context, v int) {
+			value.Set(v)
+		})
+		t.AssertNil(err)
+
+		time.Sleep(300 * time.Millisecond)
+		t.AssertGT(notMod.Val(), 0)
+		t.Assert(value.Val(), 0)
+
+		version.Set(2)
+		time.Sleep(300 * time.Millisecond)
+		t.Assert(keys.Strings(), []string{"app.version"})
+		t.Assert(value.Val(), 2)
+		t.Assert(config.MustGet(ctx, "app.version"), 2)
+	})
+
+	// The first fetching fails.
+	gtest.C(t, func(t *gtest.T) {
+		closedServer := httptest.NewServer(http.NotFoundHandler())
+		closedServer.Close()
+		_, err := gcfg.NewAdapterHttp(ctx, closedServer.URL+"/config", gcfg.AdapterHttpOption{
+			Timeout: time.Second,
+		})
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Watch_AdapterHttp_RefreshInCallback(t *testing.T) {
+	var (
+		version = gtype.NewInt(1)
+		server  = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = fmt.Fprintf(w, "app:\n  version: %d\n", version.Val())
+		}))
+	)
+	defer server.Close()
+
+	gtest.C(t, func(t *gtest.T) {
+		adapter, err := gcfg.NewAdapterHttp(ctx, server.URL+"/config", gcfg.AdapterHttpOption{
+			Interval: time.Hour,
+		})
+		t.AssertNil(err)
+		defer adapter.Close()
+
+		var (
+			config = gcfg.NewWithAdapter(adapter)
+			done   = make(chan struct{}, 1)
+		)
+		// The callback retrieves and refreshes the configuration, which should not block.
+		adapter.OnChange("test", func(ctx context.Context, event gcfg.ChangeEvent) {
+			_, _ = config.Get(ctx, "app.version")
+			_ = adapter.Refresh(ctx)
+			done <- struct{}{}
+		})
+		version.Set(2)
+		go func() {
+			_ = adapter.Refresh(ctx)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("refreshing in callback is blocked")
+		}
+		t.Assert(config.MustGet(ctx, "app.version"), 2)
+	})
+}
+
+func Test_Watch_AdapterFile_Debounce(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			path = gfile.Temp(gtime.TimestampNanoStr(), "config.yaml")
+		)
+		t.AssertNil(gfile.PutContents(path, "version: 0\n"))
+		defer gfile.Remove(gfile.Dir(path))
+
+		adapter, err := gcfg.NewAdapterFile(path)
+		t.AssertNil(err)
+		var (
+			config = gcfg.NewWithAdapter(adapter)
+			count  = gtype.NewInt()
+		)
+		t.Assert(config.MustGet(ctx, "version"), 0)
+		adapter.OnChange("test", func(ctx context.Context, event gcfg.ChangeEvent) {
+			count.Add(1)
+		})
+		// Multiple writes in a short time are notified only once.
+		for i := 1; i <= 5; i++ {
+			t.AssertNil(gfile.PutContents(path, fmt.Sprintf("version: %d\n", i)))
+			time.Sleep(50 * time.Millisecond)
+		}
+		time.Sleep(time.Second)
+		t.Assert(count.Val(), 1)
+		t.Assert(config.MustGet(ctx, "version"), 5)
+	})
+}
+
+func Test_Watch_NotSupported(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		adapter, err := gcfg.NewAdapterContent(`{"a": 1}`)
+		t.AssertNil(err)
+		config := gcfg.NewWithAdapter(adapter)
+		_, err = config.Watch("a", func(ctx context.Context, value *gvar.Var) {})
+		t.Assert(gerror.Code(err), gcode.CodeNotSupported)
+	})
+}
@@ -37,6 +37,26 @@ func GetLogger() glog.ILogger {
 	return defaultCron.GetLogger()
 }
 
+// SetStore sets the global store persisting the running state of entries in the cron.
+func SetStore(store Store) {
+	defaultCron.SetStore(store)
+}
+
+// GetStore returns the global store persisting the running state of entries in the cron.
+func GetStore() Store {
+	return defaultCron.GetStore()
+}
+
+// SetLocker sets the global distributed locker of entry executions in the cron.
+func SetLocker(locker Locker) {
+	defaultCron.SetLocker(locker)
+}
+
+// GetLocker returns the global distributed locker of entry executions in the cron.
+func GetLocker() Locker {
+	return defaultCron.GetLocker()
+}
+
 // Add adds a timed task to default cron object.
 // A unique `name` can be bound with the timed task.
 // It returns and error if the `name` is already used.
@@ -44,6 +64,14 @@ func Add(ctx context.Context, pattern string, job JobFunc, name ...string) (*Ent
 	return defaultCron.Add(ctx, pattern, job, name...)
 }
 
+// AddWithError adds a timed task of which the job returns error, to default cron object.
+// The execution fails if the job returns error, which is recorded in history and retried by RetryPolicy.
+// A unique `name` can be bound with the timed task.
+// It returns and error if the `name` is already used.
+func AddWithError(ctx context.Context, pattern string, job ErrorJobFunc, name ...string) (*Entry, error) {
+	return defaultCron.AddWithError(ctx, pattern, job, name...)
+}
+
 // AddSingleton adds a singleton timed task, to default cron object.
 // A singleton timed task is that can only be running one single instance at the same time.
 // A unique `name` can be bound with the timed task.
@@ -109,6 +137,11 @@ func Entries() []*Entry {
 	return defaultCron.Entries()
 }
 
+// History returns the recent executions of all entries of default cron object.
+func History() []Execution {
+	return defaultCron.History()
+}
+
 // Start starts running the specified timed task named `name`.
 // If no`name` specified, it starts the entire cron.
 func Start(name ...string) {
@@ -125,3 +158,15 @@ func Stop(name ...string) {
 func StopGracefully() {
 	defaultCron.StopGracefully()
 }
+
+// NextTimes returns the next `n` activated times of `pattern` after time `from`,
+// which is usually used for previewing the schedule of the pattern.
+// The returned times are in the time zone of the pattern, and the size of them might be less than `n`
+// if the pattern cannot be activated in five years.
+func NextTimes(pattern string, from time.Time, n int) ([]time.Time, error) {
+	schedule, err := newSchedule(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.NextTimes(from, n), nil
+}
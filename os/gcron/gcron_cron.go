@@ -8,6 +8,7 @@ package gcron
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -24,6 +25,8 @@ type Cron struct {
 	status    *gtype.Int      // Timed task status(0: Not Start; 1: Running; 2: Stopped; -1: Closed)
 	entries   *gmap.StrAnyMap // All timed task entries.
 	logger    glog.ILogger    // Logger, it is nil in default.
+	store     Store           // Store persisting the state of entries, it is nil in default.
+	locker    Locker          // Locker ensuring executions run in only one replica, it is nil in default.
 	jobWaiter sync.WaitGroup  // Graceful shutdown when cron jobs are stopped.
 }
 
@@ -46,6 +49,34 @@ func (c *Cron) GetLogger() glog.ILogger {
 	return c.logger
 }
 
+// SetStore sets the store persisting the running state of entries,
+// which enables catching up the executions missed during process downtime, see Entry.SetMisfirePolicy.
+// Note that only the state of entry added with custom name is persisted.
+func (c *Cron) SetStore(store Store) {
+	c.store = store
+}
+
+// GetStore returns the store persisting the running state of entries.
+func (c *Cron) GetStore() Store {
+	return c.store
+}
+
+// SetLocker sets the distributed locker of entry executions,
+// which ensures that each scheduled execution runs in only one of the replicas.
+// The job context is canceled if the lease of the execution is lost during running.
+// Note that only the entry added with custom name is locked, and the entry of interval pattern like
+// "@every 1h" is not supported, as its schedule starts from its adding time which differs across replicas.
+// Adding such entry with custom name returns error after the locker is set, and the entries added before
+// are not locked.
+func (c *Cron) SetLocker(locker Locker) {
+	c.locker = locker
+}
+
+// GetLocker returns the distributed locker of entry executions.
+func (c *Cron) GetLocker() Locker {
+	return c.locker
+}
+
 // AddEntry creates and returns a new Entry object.
 func (c *Cron) AddEntry(
 	ctx context.Context,
@@ -83,6 +114,25 @@ func (c *Cron) Add(ctx context.Context, pattern string, job JobFunc, name ...str
 	return c.AddEntry(ctx, pattern, job, -1, false, name...)
 }
 
+// AddWithError adds a timed task of which the job returns error.
+// The execution fails if the job returns error, which is recorded in history and retried by RetryPolicy.
+// A unique `name` can be bound with the timed task.
+// It returns and error if the `name` is already used.
+func (c *Cron) AddWithError(ctx context.Context, pattern string, job ErrorJobFunc, name ...string) (*Entry, error) {
+	var entryName string
+	if len(name) > 0 {
+		entryName = name[0]
+	}
+	return c.doAddEntry(doAddEntryInput{
+		Name:     entryName,
+		ErrorJob: job,
+		Ctx:      ctx,
+		Times:    -1,
+		Pattern:  pattern,
+		Infinite: true,
+	})
+}
+
 // AddSingleton adds a singleton timed task.
 // A singleton timed task is that can only be running one single instance at the same time.
 // A unique `name` can be bound with the timed task.
@@ -235,3 +285,15 @@ func (c *Cron) Entries() []*Entry {
 	})
 	return entries
 }
+
+// History returns the recent executions of all entries in ascending order of start time.
+func (c *Cron) History() []Execution {
+	var history []Execution
+	for _, entry := range c.Entries() {
+		history = append(history, entry.History()...)
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].StartTime.Before(history[j].StartTime)
+	})
+	return history
+}
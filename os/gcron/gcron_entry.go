@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/ximplez-go/gf/container/gtype"
@@ -24,22 +25,37 @@ import (
 // JobFunc is the timing called job function in cron.
 type JobFunc = gtimer.JobFunc
 
+// ErrorJobFunc is the timing called job function in cron which returns error,
+// the execution of which fails and is retried by RetryPolicy if the error is not nil.
+type ErrorJobFunc func(ctx context.Context) error
+
 // Entry is timing task entry.
 type Entry struct {
-	cron         *Cron         // Cron object belonged to.
-	timerEntry   *gtimer.Entry // Associated timer Entry.
-	schedule     *cronSchedule // Timed schedule object.
-	jobName      string        // Callback function name(address info).
-	times        *gtype.Int    // Running times limit.
-	infinite     *gtype.Bool   // No times limit.
-	Name         string        // Entry name.
-	RegisterTime time.Time     // Registered time.
-	Job          JobFunc       `json:"-"` // Callback function.
+	cron               *Cron            // Cron object belonged to.
+	timerEntry         *gtimer.Entry    // Associated timer Entry.
+	schedule           *cronSchedule    // Timed schedule object.
+	jobName            string           // Callback function name(address info).
+	times              *gtype.Int       // Running times limit.
+	infinite           *gtype.Bool      // No times limit.
+	misfirePolicy      *gtype.Int       // Policy handling the missed executions, see MisfirePolicyXXX.
+	lastCheckTimestamp *gtype.Int64     // Last checking timestamp in seconds, for missed executions detection.
+	customName         bool             // Whether the entry has custom name, only which is persisted to Store and locked by Locker.
+	timeout            *gtype.Int64     // Execution timeout in nanoseconds, no timeout if it is not positive.
+	retryPolicy        *gtype.Interface // Retry policy for failed executions, see RetryPolicy.
+	stateMu            sync.Mutex       // Mutex for state and history.
+	state              EntryState       // Running state.
+	historySize        int              // Max size of execution history.
+	history            []Execution      // Recent executions in ascending order of start time.
+	Name               string           // Entry name.
+	RegisterTime       time.Time        // Registered time.
+	Job                JobFunc          `json:"-"` // Callback function.
+	errorJob           ErrorJobFunc     // Callback function returning error, which is nil if the entry is added with JobFunc.
 }
 
 type doAddEntryInput struct {
 	Name        string          // Name names this entry for manual control.
 	Job         JobFunc         // Job is the callback function for timed task execution.
+	ErrorJob    ErrorJobFunc    // ErrorJob is the callback function returning error, which is used instead of Job if it is not nil.
 	Ctx         context.Context // The context for the job.
 	Times       int             // Times specifies the running limit times for the entry.
 	Pattern     string          // Pattern is the crontab style string for scheduler.
@@ -62,18 +78,39 @@ func (c *Cron) doAddEntry(in doAddEntryInput) (*Entry, error) {
 	if err != nil {
 		return nil, err
 	}
+	if in.Name != "" && schedule.everySeconds != 0 && c.GetLocker() != nil {
+		return nil, gerror.NewCodef(
+			gcode.CodeNotSupported,
+			`interval pattern "%s" of cron job "%s" is not supported by Locker, as its schedule is not aligned across replicas`,
+			in.Pattern, in.Name,
+		)
+	}
+	var jobPointer = reflect.ValueOf(in.Job).Pointer()
+	if in.ErrorJob != nil {
+		jobPointer = reflect.ValueOf(in.ErrorJob).Pointer()
+		in.Job = func(ctx context.Context) {
+			_ = in.ErrorJob(ctx)
+		}
+	}
 	// No limit for `times`, for timer checking scheduling every second.
 	entry := &Entry{
-		cron:         c,
-		schedule:     schedule,
-		jobName:      runtime.FuncForPC(reflect.ValueOf(in.Job).Pointer()).Name(),
-		times:        gtype.NewInt(in.Times),
-		infinite:     gtype.NewBool(in.Infinite),
-		RegisterTime: time.Now(),
-		Job:          in.Job,
+		cron:               c,
+		schedule:           schedule,
+		jobName:            runtime.FuncForPC(jobPointer).Name(),
+		times:              gtype.NewInt(in.Times),
+		infinite:           gtype.NewBool(in.Infinite),
+		misfirePolicy:      gtype.NewInt(int(MisfirePolicySkip)),
+		lastCheckTimestamp: gtype.NewInt64(),
+		timeout:            gtype.NewInt64(),
+		retryPolicy:        gtype.NewInterface(RetryPolicy{}),
+		historySize:        defaultHistorySize,
+		RegisterTime:       time.Now(),
+		Job:                in.Job,
+		errorJob:           in.ErrorJob,
 	}
 	if in.Name != "" {
 		entry.Name = in.Name
+		entry.customName = true
 	} else {
 		entry.Name = "cron-" + gconv.String(c.idGen.Add(1))
 	}
@@ -136,13 +173,33 @@ func (e *Entry) Close() {
 	e.timerEntry.Close()
 }
 
+// NextTimes returns the next `n` activated times of the entry from now.
+// The size of returned times is limited by the remaining running times if the entry has times limit.
+func (e *Entry) NextTimes(n int) []time.Time {
+	if !e.infinite.Val() {
+		if times := e.times.Val(); times < n {
+			n = times
+		}
+	}
+	if n <= 0 {
+		return nil
+	}
+	return e.schedule.NextTimes(time.Now(), n)
+}
+
 // checkAndRun is the core timing task check logic.
 // This function is called every second.
 func (e *Entry) checkAndRun(ctx context.Context) {
 	currentTime := time.Now()
+	e.checkAndRunMissed(ctx, currentTime)
 	if !e.schedule.checkMeetAndUpdateLastSeconds(ctx, currentTime) {
 		return
 	}
+	e.run(ctx, currentTime)
+}
+
+// run runs the job of the entry at `runTime` according to the cron status and running times limit.
+func (e *Entry) run(ctx context.Context, runTime time.Time) {
 	switch e.cron.status.Val() {
 	case StatusStopped:
 		return
@@ -155,21 +212,13 @@ func (e *Entry) checkAndRun(ctx context.Context) {
 		e.cron.jobWaiter.Add(1)
 		defer func() {
 			e.cron.jobWaiter.Done()
-			if exception := recover(); exception != nil {
-				// Exception caught, it logs the error content to logger in default behavior.
-				e.logErrorf(ctx,
-					`cron job "%s(%s)" end with error: %+v`,
-					e.jobName, e.schedule.pattern, exception,
-				)
-			} else {
-				e.logDebugf(ctx, `cron job "%s" ends`, e.getJobNameWithPattern())
-			}
 			if e.timerEntry.Status() == StatusClosed {
 				e.Close()
 			}
 		}()
 
-		// Running times check.
+		// Running times check, which is done before locking, so that the lease is never acquired
+		// for the execution not running, and each replica counts the scheduled executions.
 		if !e.infinite.Val() {
 			times := e.times.Add(-1)
 			if times <= 0 {
@@ -178,8 +227,53 @@ func (e *Entry) checkAndRun(ctx context.Context) {
 				}
 			}
 		}
+		lease, ok := e.lock(ctx, runTime)
+		if !ok {
+			return
+		}
+		if lease != nil {
+			var cancel context.CancelCauseFunc
+			ctx, cancel = context.WithCancelCause(ctx)
+			defer e.unlock(ctx, lease, cancel)
+			go func() {
+				select {
+				case <-lease.Done():
+					cancel(gerror.NewCodef(gcode.CodeOperationFailed, `lease of cron job "%s" is lost`, e.Name))
+				case <-ctx.Done():
+				}
+			}()
+		}
 		e.logDebugf(ctx, `cron job "%s" starts`, e.getJobNameWithPattern())
-		e.Job(ctx)
+		execution := e.execute(ctx, runTime)
+		e.updateState(ctx, runTime, execution.Error)
+	}
+}
+
+// lock acquires the lease of the execution scheduled at `tick` if the cron has a Locker set.
+// It returns false if the execution should not run in current process.
+// The entry of interval pattern is not locked, as its schedule is not aligned across replicas.
+func (e *Entry) lock(ctx context.Context, tick time.Time) (lease Lease, ok bool) {
+	locker := e.cron.GetLocker()
+	if locker == nil || !e.customName || e.schedule.everySeconds != 0 {
+		return nil, true
+	}
+	lease, err := locker.Lock(ctx, e.Name, tick)
+	if err != nil {
+		e.logErrorf(ctx, `lock cron job "%s" failed: %+v`, e.Name, err)
+		return nil, false
+	}
+	if lease == nil {
+		e.logDebugf(ctx, `cron job "%s" is run by other instance`, e.getJobNameWithPattern())
+		return nil, false
+	}
+	return lease, true
+}
+
+// unlock cancels the job context and releases the lease after the job ends.
+func (e *Entry) unlock(ctx context.Context, lease Lease, cancel context.CancelCauseFunc) {
+	cancel(nil)
+	if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+		e.logErrorf(ctx, `unlock cron job "%s" failed: %+v`, e.Name, err)
 	}
 }
 
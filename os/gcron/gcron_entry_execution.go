@@ -0,0 +1,217 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Outcomes of job execution.
+const (
+	ExecutionOutcomeSuccess   = "success"   // The job returns normally.
+	ExecutionOutcomeFailure   = "failure"   // The job panics or returns error.
+	ExecutionOutcomeTimeout   = "timeout"   // The job returns after its context is timed out.
+	ExecutionOutcomeCancelled = "cancelled" // The job returns after its context is cancelled, eg: the lease is lost.
+)
+
+const (
+	// Default max size of execution history of each entry.
+	defaultHistorySize = 10
+)
+
+// Execution is the record of a single job execution, each retry of which has its own record.
+type Execution struct {
+	EntryName     string        // Name of the entry.
+	ScheduledTime time.Time     // Scheduled time of the execution.
+	StartTime     time.Time     // Time when the job starts.
+	Duration      time.Duration // Running duration of the job.
+	Attempt       int           // Attempt number starting from 1, which is greater than 1 for retries.
+	Outcome       string        // Outcome of the execution, see ExecutionOutcomeXXX.
+	Error         string        // Error of the execution, which is empty if it succeeded.
+}
+
+// RetryPolicy specifies how the failed or timed out executions are retried.
+type RetryPolicy struct {
+	Count       int           // Max retry count, no retry if it is not positive.
+	Interval    time.Duration // Interval before the first retry.
+	MaxInterval time.Duration // Max interval between retries, no limit if it is not positive.
+	Multiplier  float64       // Multiplier of interval for each retry, fixed interval if it is not greater than 1.
+}
+
+// SetTimeout sets the timeout of each execution, after which the context passed to the job is cancelled.
+// Note that the job should respect the context, as it cannot be forcibly stopped.
+// It disables timeout if `timeout` is not positive.
+func (e *Entry) SetTimeout(timeout time.Duration) {
+	e.timeout.Set(int64(timeout))
+}
+
+// GetTimeout returns the timeout of each execution.
+func (e *Entry) GetTimeout() time.Duration {
+	return time.Duration(e.timeout.Val())
+}
+
+// SetRetryPolicy sets the retry policy for failed or timed out executions.
+func (e *Entry) SetRetryPolicy(policy RetryPolicy) {
+	e.retryPolicy.Set(policy)
+}
+
+// GetRetryPolicy returns the retry policy for failed or timed out executions.
+func (e *Entry) GetRetryPolicy() RetryPolicy {
+	return e.retryPolicy.Val().(RetryPolicy)
+}
+
+// SetHistorySize sets the max size of execution history, which is 10 in default.
+// It disables history if `size` is not positive.
+func (e *Entry) SetHistorySize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+	e.historySize = size
+	if len(e.history) > size {
+		e.history = append([]Execution(nil), e.history[len(e.history)-size:]...)
+	}
+}
+
+// History returns the recent executions of the entry in ascending order of start time.
+func (e *Entry) History() []Execution {
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+	return append([]Execution(nil), e.history...)
+}
+
+// execute runs the job and retries it according to the retry policy,
+// and returns the execution of the last attempt.
+func (e *Entry) execute(ctx context.Context, scheduledTime time.Time) (execution Execution) {
+	policy := e.GetRetryPolicy()
+	for attempt := 1; ; attempt++ {
+		execution = e.executeOnce(ctx, scheduledTime, attempt)
+		// The cancelled execution is not retried, as the context is done.
+		if execution.Outcome == ExecutionOutcomeSuccess || execution.Outcome == ExecutionOutcomeCancelled ||
+			attempt > policy.Count {
+			return
+		}
+		interval := policy.getInterval(attempt)
+		e.logDebugf(
+			ctx, `cron job "%s" retries in %s for attempt %d`,
+			e.getJobNameWithPattern(), interval, attempt+1,
+		)
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// executeOnce runs the job once, and records the execution to history and metrics.
+// The panic of the job is recovered and recorded as failure, so is the error returned by ErrorJobFunc.
+func (e *Entry) executeOnce(ctx context.Context, scheduledTime time.Time, attempt int) (execution Execution) {
+	var (
+		jobCtx  = ctx
+		jobErr  error
+		timeout = e.GetTimeout()
+	)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		jobCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	execution = Execution{
+		EntryName:     e.Name,
+		ScheduledTime: scheduledTime,
+		StartTime:     time.Now(),
+		Attempt:       attempt,
+	}
+	defer func() {
+		execution.Duration = time.Since(execution.StartTime)
+		if exception := recover(); exception != nil {
+			// Exception caught, it logs the error content to logger in default behavior.
+			execution.Outcome = ExecutionOutcomeFailure
+			execution.Error = fmt.Sprintf(`%+v`, exception)
+			e.logErrorf(ctx,
+				`cron job "%s(%s)" end with error: %+v`,
+				e.jobName, e.schedule.pattern, exception,
+			)
+		} else if timeout > 0 && ctx.Err() == nil && errors.Is(jobCtx.Err(), context.DeadlineExceeded) {
+			execution.Outcome = ExecutionOutcomeTimeout
+			execution.Error = fmt.Sprintf(`timeout after %s`, timeout)
+			e.logErrorf(ctx,
+				`cron job "%s(%s)" end with error: %s`,
+				e.jobName, e.schedule.pattern, execution.Error,
+			)
+		} else if ctx.Err() != nil {
+			// The job might not complete its work as its context is cancelled,
+			// which should not be recorded as success.
+			execution.Outcome = ExecutionOutcomeCancelled
+			execution.Error = fmt.Sprintf(`cancelled: %v`, context.Cause(ctx))
+			e.logErrorf(ctx,
+				`cron job "%s(%s)" end with error: %s`,
+				e.jobName, e.schedule.pattern, execution.Error,
+			)
+		} else if jobErr != nil {
+			execution.Outcome = ExecutionOutcomeFailure
+			execution.Error = jobErr.Error()
+			e.logErrorf(ctx,
+				`cron job "%s(%s)" end with error: %+v`,
+				e.jobName, e.schedule.pattern, jobErr,
+			)
+		} else {
+			execution.Outcome = ExecutionOutcomeSuccess
+			e.logDebugf(ctx, `cron job "%s" ends`, e.getJobNameWithPattern())
+		}
+		e.addHistory(execution)
+		metricManager.handleExecution(ctx, execution)
+	}()
+	if e.errorJob != nil {
+		jobErr = e.errorJob(jobCtx)
+	} else {
+		e.Job(jobCtx)
+	}
+	return
+}
+
+// addHistory appends `execution` to history, and drops the oldest one if history is full.
+func (e *Entry) addHistory(execution Execution) {
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+	if e.historySize <= 0 {
+		return
+	}
+	if len(e.history) >= e.historySize {
+		copy(e.history, e.history[len(e.history)-e.historySize+1:])
+		e.history = e.history[:e.historySize-1]
+	}
+	e.history = append(e.history, execution)
+}
+
+// getInterval returns the interval before the retry after attempt `attempt`.
+func (p RetryPolicy) getInterval(attempt int) time.Duration {
+	interval := p.Interval
+	if p.Multiplier > 1 {
+		// It avoids overflow of large attempts.
+		if value := float64(p.Interval) * math.Pow(p.Multiplier, float64(attempt-1)); value < math.MaxInt64 {
+			interval = time.Duration(value)
+		} else {
+			interval = time.Duration(math.MaxInt64)
+		}
+	}
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		interval = p.MaxInterval
+	}
+	if interval < 0 {
+		interval = 0
+	}
+	return interval
+}
@@ -0,0 +1,89 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"time"
+
+	"github.com/ximplez-go/gf"
+	"github.com/ximplez-go/gf/os/gmetric"
+)
+
+// localMetricManager manages the metrics of job executions.
+type localMetricManager struct {
+	JobRuns     gmetric.Counter
+	JobRetries  gmetric.Counter
+	JobDuration gmetric.Histogram
+}
+
+const (
+	instrument              = "github.com/ximplez-go/gf/os/gcron.Cron"
+	metricAttrKeyJobName    = "cron.job.name"
+	metricAttrKeyJobOutcome = "cron.job.outcome"
+)
+
+// metricManager records the runs, retries and durations of jobs, labelled by job name and outcome.
+var metricManager = newMetricManager()
+
+// newMetricManager creates the job execution metrics of gcron.
+func newMetricManager() *localMetricManager {
+	meter := gmetric.GetGlobalProvider().Meter(gmetric.MeterOption{
+		Instrument:        instrument,
+		InstrumentVersion: gf.VERSION,
+	})
+	return &localMetricManager{
+		JobRuns: meter.MustCounter(
+			"gcron.job.runs",
+			gmetric.MetricOption{
+				Help: "Total number of job executions, including retries.",
+			},
+		),
+		JobRetries: meter.MustCounter(
+			"gcron.job.retries",
+			gmetric.MetricOption{
+				Help: "Total number of job executions that are retries of failed or timed out executions.",
+			},
+		),
+		JobDuration: meter.MustHistogram(
+			"gcron.job.duration",
+			gmetric.MetricOption{
+				Help: "Measures the duration of job executions.",
+				Unit: "ms",
+				Buckets: []float64{
+					1, 5, 10, 25, 50, 75, 100, 250, 500, 750,
+					1000, 2500, 5000, 7500, 10000, 30000, 60000,
+				},
+			},
+		),
+	}
+}
+
+// newMetricOption creates and returns the metric operation option for entry named `name`.
+func (m *localMetricManager) newMetricOption(name string, attributes ...gmetric.Attribute) gmetric.Option {
+	return gmetric.Option{
+		Attributes: append(gmetric.Attributes{
+			gmetric.NewAttribute(metricAttrKeyJobName, name),
+		}, attributes...),
+	}
+}
+
+// handleExecution records the metrics for a job execution.
+func (m *localMetricManager) handleExecution(ctx context.Context, execution Execution) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	option := m.newMetricOption(
+		execution.EntryName,
+		gmetric.NewAttribute(metricAttrKeyJobOutcome, execution.Outcome),
+	)
+	m.JobRuns.Inc(ctx, option)
+	if execution.Attempt > 1 {
+		m.JobRetries.Inc(ctx, option)
+	}
+	m.JobDuration.Record(float64(execution.Duration)/float64(time.Millisecond), option)
+}
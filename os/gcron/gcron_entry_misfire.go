@@ -0,0 +1,134 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"time"
+)
+
+// MisfirePolicy specifies how the entry handles the executions missed for process downtime or clock jumping.
+type MisfirePolicy int
+
+const (
+	MisfirePolicySkip    MisfirePolicy = iota // Skips all missed executions, which is the default policy.
+	MisfirePolicyRunOnce                      // Runs once for all missed executions.
+	MisfirePolicyRunAll                       // Runs every missed execution, at most maxMisfireRuns times.
+)
+
+const (
+	// Max missed executions that MisfirePolicyRunAll runs.
+	maxMisfireRuns = 1000
+
+	// Checking latency in seconds which is tolerant, see getAndUpdateLastCheckTimestamp.
+	misfireLatencySeconds = 3
+)
+
+// SetMisfirePolicy sets the policy handling the missed executions of the entry.
+// The executions missed during process downtime can only be detected if the cron has a Store set
+// and the entry is added with a custom name, and the policy should be set right after the entry is added,
+// as the detection is done in the first checking of the entry.
+func (e *Entry) SetMisfirePolicy(policy MisfirePolicy) {
+	e.misfirePolicy.Set(int(policy))
+}
+
+// GetMisfirePolicy returns the policy handling the missed executions of the entry.
+func (e *Entry) GetMisfirePolicy() MisfirePolicy {
+	return MisfirePolicy(e.misfirePolicy.Val())
+}
+
+// State returns the running state of the entry.
+func (e *Entry) State() EntryState {
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+	return e.state
+}
+
+// checkAndRunMissed checks the executions missed since last checking, and runs them according to the misfire policy.
+// The last running time from Store is used as the last checking time for the first checking.
+func (e *Entry) checkAndRunMissed(ctx context.Context, currentTime time.Time) {
+	lastCheckTimestamp := e.lastCheckTimestamp.Set(currentTime.Unix())
+	if lastCheckTimestamp == 0 {
+		lastCheckTimestamp = e.loadState(ctx)
+	}
+	if lastCheckTimestamp == 0 || currentTime.Unix()-lastCheckTimestamp <= misfireLatencySeconds {
+		return
+	}
+	var (
+		policy   = e.GetMisfirePolicy()
+		limit    = 1
+		lastTime = time.Unix(lastCheckTimestamp, 0)
+	)
+	if policy == MisfirePolicyRunAll {
+		limit = maxMisfireRuns
+	}
+	missedTimes := e.schedule.getTimesBetween(lastTime, currentTime, limit)
+	if len(missedTimes) == 0 {
+		return
+	}
+	switch policy {
+	case MisfirePolicyRunOnce, MisfirePolicyRunAll:
+		e.logDebugf(
+			ctx, `cron job "%s" runs %d missed executions since %s`,
+			e.getJobNameWithPattern(), len(missedTimes), lastTime.Format(time.RFC3339),
+		)
+		for _, missedTime := range missedTimes {
+			e.run(ctx, missedTime)
+		}
+		// It marks the last missed execution as met, which does not block the execution due at current time,
+		// as the missed times are before current time exclusively.
+		e.schedule.lastMeetTimestamp.Set(missedTimes[len(missedTimes)-1].Unix())
+
+	default:
+		e.logDebugf(
+			ctx, `cron job "%s" skips missed executions since %s`,
+			e.getJobNameWithPattern(), lastTime.Format(time.RFC3339),
+		)
+	}
+}
+
+// loadState loads the entry state from Store, and returns the last running timestamp in seconds.
+// It returns 0 if the state is not persisted.
+func (e *Entry) loadState(ctx context.Context) int64 {
+	store := e.cron.GetStore()
+	if store == nil || !e.customName {
+		return 0
+	}
+	state, err := store.Get(ctx, e.Name)
+	if err != nil {
+		e.logErrorf(ctx, `load state of cron job "%s" failed: %+v`, e.Name, err)
+		return 0
+	}
+	if state == nil {
+		return 0
+	}
+	e.stateMu.Lock()
+	e.state = *state
+	e.stateMu.Unlock()
+	if state.LastRunTime.IsZero() {
+		return 0
+	}
+	return state.LastRunTime.Unix()
+}
+
+// updateState updates the entry state after running, and saves it to Store.
+func (e *Entry) updateState(ctx context.Context, runTime time.Time, lastError string) {
+	e.stateMu.Lock()
+	e.state.LastRunTime = runTime
+	e.state.RunCount++
+	e.state.LastError = lastError
+	state := e.state
+	e.stateMu.Unlock()
+
+	store := e.cron.GetStore()
+	if store == nil || !e.customName {
+		return
+	}
+	if err := store.Set(ctx, e.Name, &state); err != nil {
+		e.logErrorf(ctx, `save state of cron job "%s" failed: %+v`, e.Name, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/util/guid"
+)
+
+// Locker is the interface for distributed locking of entry executions,
+// which ensures that each scheduled execution runs in only one of the replicas.
+type Locker interface {
+	// Lock tries to acquire the lease of the execution of entry `name` scheduled at `tick`.
+	// It returns nil Lease and nil error if the execution is acquired by others.
+	Lock(ctx context.Context, name string, tick time.Time) (Lease, error)
+}
+
+// Lease is the acquired lease of an entry execution.
+type Lease interface {
+	// Done returns a channel that is closed when the lease is lost, which cancels the job context.
+	// It returns nil if the lease cannot be lost.
+	Done() <-chan struct{}
+
+	// Release releases the lease after the job ends.
+	Release(ctx context.Context) error
+}
+
+// LeaseStore is the interface of external stores like redis or database,
+// which NewLeaseLocker uses to implement Locker.
+type LeaseStore interface {
+	// Acquire sets `key` with `owner` expiring in `ttl` if `key` does not exist or is expired.
+	// It returns true if `key` is acquired by `owner`.
+	Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+
+	// Renew extends the expiration of `key` to `ttl` if it is still owned by `owner`.
+	// It returns false if `key` is not owned by `owner` any more.
+	Renew(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+}
+
+const (
+	defaultLeaseTTL = 30 * time.Second
+)
+
+// leaseLocker is the Locker implementer based on LeaseStore.
+type leaseLocker struct {
+	store LeaseStore
+	ttl   time.Duration
+	owner string // Unique owner of current process.
+}
+
+// leaseLockerLease is the Lease of leaseLocker, which renews itself in background until released.
+type leaseLockerLease struct {
+	locker      *leaseLocker
+	key         string
+	done        chan struct{} // Closed when the lease is lost.
+	release     chan struct{} // Closed when the lease is released.
+	releaseOnce sync.Once
+}
+
+// NewLeaseLocker creates and returns a Locker based on external LeaseStore.
+// Each execution acquires a key of entry name and scheduled time expiring in `ttl`, which is 30 seconds in default.
+// The key is renewed every third of `ttl` while the job is running, and it is kept till expiration after
+// the job ends, which prevents the replicas checking the schedule a bit later from running the execution again.
+// Note that the clocks of all replicas should be synchronized.
+func NewLeaseLocker(store LeaseStore, ttl ...time.Duration) Locker {
+	locker := &leaseLocker{
+		store: store,
+		ttl:   defaultLeaseTTL,
+		owner: guid.S(),
+	}
+	if len(ttl) > 0 && ttl[0] > 0 {
+		locker.ttl = ttl[0]
+	}
+	return locker
+}
+
+// Lock tries to acquire the lease of the execution of entry `name` scheduled at `tick`.
+func (l *leaseLocker) Lock(ctx context.Context, name string, tick time.Time) (Lease, error) {
+	key := fmt.Sprintf(`%s@%d`, name, tick.Unix())
+	ok, err := l.store.Acquire(ctx, key, l.owner, l.ttl)
+	if err != nil || !ok {
+		return nil, err
+	}
+	lease := &leaseLockerLease{
+		locker:  l,
+		key:     key,
+		done:    make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	go lease.renew(context.WithoutCancel(ctx))
+	return lease, nil
+}
+
+// Done returns a channel that is closed when the lease is lost.
+func (l *leaseLockerLease) Done() <-chan struct{} {
+	return l.done
+}
+
+// Release stops renewing the lease.
+func (l *leaseLockerLease) Release(ctx context.Context) error {
+	l.releaseOnce.Do(func() {
+		close(l.release)
+	})
+	return nil
+}
+
+// renew renews the lease every third of ttl until it is released or lost.
+func (l *leaseLockerLease) renew(ctx context.Context) {
+	ticker := time.NewTicker(l.locker.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.release:
+			return
+		case <-ticker.C:
+			ok, err := l.locker.store.Renew(ctx, l.key, l.locker.owner, l.locker.ttl)
+			if err != nil || !ok {
+				close(l.done)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gfile"
+)
+
+// FileLocker is the Locker implementer using file locks, which is for multiple processes on the same host.
+// Each entry has a lock file in the directory, which is locked while the job is running,
+// and records the latest scheduled time that runs, which prevents the processes checking the schedule
+// a bit later from running the execution again.
+// Note that it is supported on Windows and the unix-like systems of flock, and locking returns error
+// on other platforms.
+type FileLocker struct {
+	dir string // Directory of the lock files.
+}
+
+// fileLease is the Lease of FileLocker, which cannot be lost until the process exits.
+type fileLease struct {
+	file *os.File
+}
+
+var _ Locker = (*FileLocker)(nil)
+
+// NewFileLocker creates and returns a FileLocker storing lock files in directory `dir`.
+func NewFileLocker(dir string) *FileLocker {
+	return &FileLocker{
+		dir: dir,
+	}
+}
+
+// Lock tries to acquire the lease of the execution of entry `name` scheduled at `tick`.
+func (l *FileLocker) Lock(ctx context.Context, name string, tick time.Time) (Lease, error) {
+	if !gfile.Exists(l.dir) {
+		if err := gfile.Mkdir(l.dir); err != nil {
+			return nil, err
+		}
+	}
+	path := filepath.Join(l.dir, url.QueryEscape(name)+".lock")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `open cron lock file "%s" failed`, path)
+	}
+	locked, err := lockFile(file)
+	if err != nil || !locked {
+		_ = file.Close()
+		if err != nil {
+			return nil, gerror.Wrapf(err, `lock cron lock file "%s" failed`, path)
+		}
+		return nil, nil
+	}
+	lease := &fileLease{file: file}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		_ = lease.Release(ctx)
+		return nil, gerror.Wrapf(err, `read cron lock file "%s" failed`, path)
+	}
+	// The execution has already run in other process.
+	if lastTick, _ := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64); lastTick >= tick.Unix() {
+		_ = lease.Release(ctx)
+		return nil, nil
+	}
+	if err = file.Truncate(0); err == nil {
+		_, err = file.WriteAt([]byte(strconv.FormatInt(tick.Unix(), 10)), 0)
+	}
+	if err != nil {
+		_ = lease.Release(ctx)
+		return nil, gerror.Wrapf(err, `write cron lock file "%s" failed`, path)
+	}
+	return lease, nil
+}
+
+// Done returns nil, as the lease cannot be lost until the process exits.
+func (l *fileLease) Done() <-chan struct{} {
+	return nil
+}
+
+// Release unlocks and closes the lock file.
+func (l *fileLease) Release(ctx context.Context) error {
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return gerror.Wrapf(err, `release cron lock file "%s" failed`, l.file.Name())
+	}
+	return nil
+}
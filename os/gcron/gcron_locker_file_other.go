@@ -0,0 +1,27 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+//go:build !windows && !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package gcron
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// lockFile returns error, as file locking is not supported on current platform.
+func lockFile(file *os.File) (bool, error) {
+	return false, gerror.NewCodef(gcode.CodeNotSupported, `file locking is not supported on %s`, runtime.GOOS)
+}
+
+// unlockFile returns error, as file locking is not supported on current platform.
+func unlockFile(file *os.File) error {
+	return gerror.NewCodef(gcode.CodeNotSupported, `file locking is not supported on %s`, runtime.GOOS)
+}
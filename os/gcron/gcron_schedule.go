@@ -30,6 +30,13 @@ type cronSchedule struct {
 	dayMap          map[int]struct{} // Job can run in these day numbers.
 	weekMap         map[int]struct{} // Job can run in these week numbers.
 	monthMap        map[int]struct{} // Job can run in these moth numbers.
+	location        *time.Location   // Location the pattern is evaluated in, which is time.Local if nil.
+
+	// Extended tokens of day and week fields.
+	lastDayOfMonth     bool                    // Job can run in the last day of month, which is "L" in day field.
+	lastWeekdayOfMonth bool                    // Job can run in the last weekday of month, which is "LW" in day field.
+	nearestWeekdayMap  map[int]struct{}        // Job can run in the nearest weekday of these day numbers, like "15W" in day field.
+	nthWeekdayMap      map[nthWeekday]struct{} // Job can run in these nth weekdays of month, like "5#3" or "5L" in week field.
 
 	// This field stores the timestamp that meets schedule latest.
 	lastMeetTimestamp *gtype.Int64
@@ -38,6 +45,12 @@ type cronSchedule struct {
 	lastCheckTimestamp *gtype.Int64
 }
 
+// nthWeekday is the nth weekday of month, in which nth -1 means the last one.
+type nthWeekday struct {
+	weekday int
+	nth     int
+}
+
 type patternItemType int
 
 const (
@@ -51,7 +64,13 @@ const (
 
 const (
 	// regular expression for cron pattern, which contains 6 parts of time units.
-	regexForCron = `^([\-/\d\*,#]+)\s+([\-/\d\*,]+)\s+([\-/\d\*,]+)\s+([\-/\d\*\?,]+)\s+([\-/\d\*,A-Za-z]+)\s+([\-/\d\*\?,A-Za-z]+)$`
+	regexForCron = `^([\-/\d\*,#]+)\s+([\-/\d\*,]+)\s+([\-/\d\*,]+)\s+([\-/\d\*\?,LlWw]+)\s+([\-/\d\*,A-Za-z]+)\s+([\-/\d\*\?,#A-Za-z]+)$`
+)
+
+const (
+	// Prefixes of pattern specifying the time zone of the pattern, like: CRON_TZ=Asia/Shanghai 0 30 * * * *
+	patternLocationPrefix      = "CRON_TZ="
+	patternLocationPrefixShort = "TZ="
 )
 
 var (
@@ -118,8 +137,17 @@ var (
 )
 
 // newSchedule creates and returns a schedule object for given cron pattern.
+// The pattern can be prefixed with "CRON_TZ=" or "TZ=" specifying its time zone, like:
+// CRON_TZ=Asia/Shanghai 0 30 * * * *
 func newSchedule(pattern string) (*cronSchedule, error) {
-	var currentTimestamp = time.Now().Unix()
+	var (
+		currentTimestamp = time.Now().Unix()
+		rawPattern       = pattern
+	)
+	location, pattern, err := parsePatternLocation(pattern)
+	if err != nil {
+		return nil, err
+	}
 	// Check given `pattern` if the predefined patterns.
 	if match, _ := gregex.MatchString(`(@\w+)\s*(\w*)\s*`, pattern); len(match) > 0 {
 		key := strings.ToLower(match[1])
@@ -133,29 +161,28 @@ func newSchedule(pattern string) (*cronSchedule, error) {
 			return &cronSchedule{
 				createTimestamp:    currentTimestamp,
 				everySeconds:       int64(d.Seconds()),
-				pattern:            pattern,
+				pattern:            rawPattern,
+				location:           location,
 				lastMeetTimestamp:  gtype.NewInt64(currentTimestamp),
 				lastCheckTimestamp: gtype.NewInt64(currentTimestamp),
 			}, nil
 		} else {
-			return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern: "%s"`, pattern)
+			return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern: "%s"`, rawPattern)
 		}
 	}
 	// Handle given `pattern` as common 6 parts pattern.
 	match, _ := gregex.MatchString(regexForCron, pattern)
 	if len(match) != 7 {
-		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern: "%s"`, pattern)
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern: "%s"`, rawPattern)
+	}
+	var cs = &cronSchedule{
+		createTimestamp:    currentTimestamp,
+		everySeconds:       0,
+		pattern:            rawPattern,
+		location:           location,
+		lastMeetTimestamp:  gtype.NewInt64(currentTimestamp),
+		lastCheckTimestamp: gtype.NewInt64(currentTimestamp),
 	}
-	var (
-		err error
-		cs  = &cronSchedule{
-			createTimestamp:    currentTimestamp,
-			everySeconds:       0,
-			pattern:            pattern,
-			lastMeetTimestamp:  gtype.NewInt64(currentTimestamp),
-			lastCheckTimestamp: gtype.NewInt64(currentTimestamp),
-		}
-	)
 
 	// Second.
 	if match[1] == "#" {
@@ -177,29 +204,140 @@ func newSchedule(pattern string) (*cronSchedule, error) {
 		return nil, err
 	}
 	// Day.
-	cs.dayMap, err = parsePatternItem(match[4], 1, 31, true, patternItemTypeDay)
+	dayItem, err := cs.parseDayExtendedItems(match[4])
 	if err != nil {
 		return nil, err
 	}
+	if cs.dayMap, err = parsePatternItem(dayItem, 1, 31, true, patternItemTypeDay); err != nil {
+		return nil, err
+	}
 	// Month.
 	cs.monthMap, err = parsePatternItem(match[5], 1, 12, false, patternItemTypeMonth)
 	if err != nil {
 		return nil, err
 	}
 	// Week.
-	cs.weekMap, err = parsePatternItem(match[6], 0, 6, true, patternItemTypeWeek)
+	weekItem, err := cs.parseWeekExtendedItems(match[6])
 	if err != nil {
 		return nil, err
 	}
+	if cs.weekMap, err = parsePatternItem(weekItem, 0, 6, true, patternItemTypeWeek); err != nil {
+		return nil, err
+	}
 	return cs, nil
 }
 
+// parsePatternLocation parses the time zone prefix of `pattern`,
+// and returns the location and the pattern without the prefix.
+// The returned location is nil if `pattern` has no time zone prefix.
+func parsePatternLocation(pattern string) (*time.Location, string, error) {
+	pattern = strings.TrimSpace(pattern)
+	for _, prefix := range []string{patternLocationPrefix, patternLocationPrefixShort} {
+		if !strings.HasPrefix(pattern, prefix) {
+			continue
+		}
+		index := strings.IndexAny(pattern, " \t")
+		if index == -1 {
+			return nil, "", gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern: "%s"`, pattern)
+		}
+		name := pattern[len(prefix):index]
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, "", gerror.WrapCodef(
+				gcode.CodeInvalidParameter, err, `invalid time zone "%s" of pattern: "%s"`, name, pattern,
+			)
+		}
+		return location, strings.TrimSpace(pattern[index:]), nil
+	}
+	return nil, pattern, nil
+}
+
+// parseDayExtendedItems parses the extended items "L", "LW" and "nW" of day field `item`,
+// and returns the remaining common items.
+func (s *cronSchedule) parseDayExtendedItems(item string) (string, error) {
+	var commonItems = make([]string, 0)
+	for _, itemElem := range strings.Split(item, ",") {
+		upperItemElem := strings.ToUpper(itemElem)
+		switch {
+		case upperItemElem == "L":
+			s.lastDayOfMonth = true
+
+		case upperItemElem == "LW":
+			s.lastWeekdayOfMonth = true
+
+		case strings.HasSuffix(upperItemElem, "W"):
+			day, err := strconv.Atoi(upperItemElem[:len(upperItemElem)-1])
+			if err != nil || day < 1 || day > 31 {
+				return "", gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern item: "%s"`, itemElem)
+			}
+			if s.nearestWeekdayMap == nil {
+				s.nearestWeekdayMap = make(map[int]struct{})
+			}
+			s.nearestWeekdayMap[day] = struct{}{}
+
+		case strings.ContainsAny(upperItemElem, "LW"):
+			return "", gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern item: "%s"`, itemElem)
+
+		default:
+			commonItems = append(commonItems, itemElem)
+		}
+	}
+	return strings.Join(commonItems, ","), nil
+}
+
+// parseWeekExtendedItems parses the extended items "w#n" and "wL" of week field `item`,
+// and returns the remaining common items.
+func (s *cronSchedule) parseWeekExtendedItems(item string) (string, error) {
+	var commonItems = make([]string, 0)
+	for _, itemElem := range strings.Split(item, ",") {
+		var (
+			weekdayValue string
+			nth          int
+			err          error
+		)
+		switch {
+		case strings.Contains(itemElem, "#"):
+			// Example: 5#3, Fri#3
+			array := strings.Split(itemElem, "#")
+			if len(array) != 2 {
+				return "", gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern item: "%s"`, itemElem)
+			}
+			weekdayValue = array[0]
+			if nth, err = strconv.Atoi(array[1]); err != nil || nth < 1 || nth > 5 {
+				return "", gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern item: "%s"`, itemElem)
+			}
+
+		case len(itemElem) > 1 && strings.HasSuffix(strings.ToUpper(itemElem), "L"):
+			// Example: 5L, FriL
+			weekdayValue = itemElem[:len(itemElem)-1]
+			nth = -1
+
+		default:
+			commonItems = append(commonItems, itemElem)
+			continue
+		}
+		weekday, err := parseWeekAndMonthNameToInt(weekdayValue, patternItemTypeWeek)
+		if err != nil || weekday < 0 || weekday > 6 {
+			return "", gerror.NewCodef(gcode.CodeInvalidParameter, `invalid pattern item: "%s"`, itemElem)
+		}
+		if s.nthWeekdayMap == nil {
+			s.nthWeekdayMap = make(map[nthWeekday]struct{})
+		}
+		s.nthWeekdayMap[nthWeekday{weekday: weekday, nth: nth}] = struct{}{}
+	}
+	return strings.Join(commonItems, ","), nil
+}
+
 // parsePatternItem parses every item in the pattern and returns the result as map, which is used for indexing.
 func parsePatternItem(
 	item string, min int, max int,
 	allowQuestionMark bool, itemType patternItemType,
 ) (itemMap map[int]struct{}, err error) {
 	itemMap = make(map[int]struct{}, max-min+1)
+	// All items are extended ones, like "L" of day field.
+	if item == "" {
+		return itemMap, nil
+	}
 	if item == "*" || (allowQuestionMark && item == "?") {
 		for i := min; i <= max; i++ {
 			itemMap[i] = struct{}{}
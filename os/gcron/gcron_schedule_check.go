@@ -16,6 +16,7 @@ import (
 // checkMeetAndUpdateLastSeconds checks if the given time `t` meets the runnable point for the job.
 // This function is called every second.
 func (s *cronSchedule) checkMeetAndUpdateLastSeconds(ctx context.Context, currentTime time.Time) (ok bool) {
+	currentTime = currentTime.In(s.getLocation())
 	var (
 		lastCheckTimestamp = s.getAndUpdateLastCheckTimestamp(ctx, currentTime)
 		lastCheckTime      = gtime.NewFromTimeStamp(lastCheckTimestamp)
@@ -43,6 +44,17 @@ func (s *cronSchedule) checkMinIntervalAndItemMapMeet(
 		}
 		return false
 	}
+	// It does not run again in the repeated wall clock times of daylight saving transition.
+	if !s.isHourWildcard() && isInRepeatedWallTime(currentTime) {
+		return false
+	}
+	if s.checkItemsMeet(lastMeetTime, currentTime) {
+		return true
+	}
+	return s.checkSkippedWallTimeMeet(lastMeetTime, currentTime)
+}
+
+func (s *cronSchedule) checkItemsMeet(lastMeetTime, currentTime time.Time) (ok bool) {
 	if !s.checkMeetSecond(lastMeetTime, currentTime) {
 		return false
 	}
@@ -64,6 +76,20 @@ func (s *cronSchedule) checkMinIntervalAndItemMapMeet(
 	return true
 }
 
+// checkSkippedWallTimeMeet checks whether any wall clock time skipped just before `currentTime` for daylight saving
+// transition meets the pattern, as the job of such time runs at the first second after the transition.
+func (s *cronSchedule) checkSkippedWallTimeMeet(lastMeetTime, currentTime time.Time) bool {
+	from, to, ok := getSkippedWallTimeRange(currentTime)
+	if !ok || lastMeetTime.Unix() >= currentTime.Unix() {
+		return false
+	}
+	if s.ignoreSeconds && currentTime.Unix()-lastMeetTime.Unix() < 60 {
+		return false
+	}
+	wallTime := s.nextWallTime(from)
+	return !wallTime.IsZero() && wallTime.Before(to)
+}
+
 func (s *cronSchedule) checkMeetSecond(lastMeetTime, currentTime time.Time) (ok bool) {
 	if s.ignoreSeconds {
 		if currentTime.Unix()-lastMeetTime.Unix() < 60 {
@@ -72,7 +98,7 @@ func (s *cronSchedule) checkMeetSecond(lastMeetTime, currentTime time.Time) (ok
 	} else {
 		// If this pattern is set in precise second time,
 		// it is not allowed executed in the same time.
-		if len(s.secondMap) == 1 && lastMeetTime.Format(time.RFC3339) == currentTime.Format(time.RFC3339) {
+		if len(s.secondMap) == 1 && lastMeetTime.Unix() == currentTime.Unix() {
 			return false
 		}
 		if !s.keyMatch(s.secondMap, currentTime.Second()) {
@@ -97,10 +123,26 @@ func (s *cronSchedule) checkMeetHour(currentTime time.Time) (ok bool) {
 }
 
 func (s *cronSchedule) checkMeetDay(currentTime time.Time) (ok bool) {
-	if !s.keyMatch(s.dayMap, currentTime.Day()) {
+	var day = currentTime.Day()
+	if s.keyMatch(s.dayMap, day) {
+		return true
+	}
+	if !s.lastDayOfMonth && !s.lastWeekdayOfMonth && len(s.nearestWeekdayMap) == 0 {
 		return false
 	}
-	return true
+	var lastDay = getLastDayOfMonth(currentTime)
+	if s.lastDayOfMonth && day == lastDay {
+		return true
+	}
+	if s.lastWeekdayOfMonth && day == getNearestWeekday(currentTime, lastDay) {
+		return true
+	}
+	for nearestDay := range s.nearestWeekdayMap {
+		if nearestDay <= lastDay && day == getNearestWeekday(currentTime, nearestDay) {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *cronSchedule) checkMeetMonth(currentTime time.Time) (ok bool) {
@@ -111,10 +153,21 @@ func (s *cronSchedule) checkMeetMonth(currentTime time.Time) (ok bool) {
 }
 
 func (s *cronSchedule) checkMeetWeek(currentTime time.Time) (ok bool) {
-	if !s.keyMatch(s.weekMap, int(currentTime.Weekday())) {
+	var weekday = int(currentTime.Weekday())
+	if s.keyMatch(s.weekMap, weekday) {
+		return true
+	}
+	if len(s.nthWeekdayMap) == 0 {
 		return false
 	}
-	return true
+	var day = currentTime.Day()
+	if _, ok = s.nthWeekdayMap[nthWeekday{weekday: weekday, nth: (day-1)/7 + 1}]; ok {
+		return true
+	}
+	if day+7 > getLastDayOfMonth(currentTime) {
+		_, ok = s.nthWeekdayMap[nthWeekday{weekday: weekday, nth: -1}]
+	}
+	return ok
 }
 
 func (s *cronSchedule) keyMatch(m map[int]struct{}, key int) bool {
@@ -142,7 +195,7 @@ func (s *cronSchedule) checkItemMapMeet(lastMeetTime, currentTime time.Time) (ok
 		return false
 	}
 	// day.
-	if !s.keyMatch(s.dayMap, currentTime.Day()) {
+	if !s.checkMeetDay(currentTime) {
 		return false
 	}
 	// month.
@@ -150,8 +203,32 @@ func (s *cronSchedule) checkItemMapMeet(lastMeetTime, currentTime time.Time) (ok
 		return false
 	}
 	// week.
-	if !s.keyMatch(s.weekMap, int(currentTime.Weekday())) {
+	if !s.checkMeetWeek(currentTime) {
 		return false
 	}
 	return true
 }
+
+// getLastDayOfMonth returns the last day number of the month of `t`.
+func getLastDayOfMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// getNearestWeekday returns the day number of the weekday nearest to `day` in the month of `t`,
+// which does not cross the month boundary.
+func getNearestWeekday(t time.Time, day int) int {
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == getLastDayOfMonth(t) {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"time"
+)
+
+// getLocation returns the location the pattern is evaluated in.
+func (s *cronSchedule) getLocation() *time.Location {
+	if s.location != nil {
+		return s.location
+	}
+	return time.Local
+}
+
+// isHourWildcard checks whether the pattern runs in every hour.
+// The jobs of such patterns run in both of the repeated wall clock times of daylight saving transition,
+// while the others run only once.
+func (s *cronSchedule) isHourWildcard() bool {
+	return len(s.hourMap) == 24
+}
+
+// getWallTime returns the wall clock time of `t` with zone `offset` in seconds,
+// which is represented in UTC for calculation that is not affected by daylight saving transition.
+func getWallTime(t time.Time, offset int) time.Time {
+	return time.Unix(t.Unix()+int64(offset), 0).UTC()
+}
+
+// getZoneStartAndPreviousOffset returns the start time of the zone of `t` and the zone offset before it.
+// The returned start time is zero if the zone of `t` has no start time.
+func getZoneStartAndPreviousOffset(t time.Time) (start time.Time, previousOffset int) {
+	start, _ = t.ZoneBounds()
+	if start.IsZero() {
+		return
+	}
+	_, previousOffset = start.Add(-time.Second).Zone()
+	return
+}
+
+// isInRepeatedWallTime checks whether `t` is in the second occurrence of the repeated wall clock times,
+// which happens when the clock is set back for daylight saving transition.
+// Example for America/New_York:
+// 2024-11-03 01:59:59 EDT -> 2024-11-03 01:00:00 EST, time range [01:00:00, 02:00:00) EST is repeated.
+func isInRepeatedWallTime(t time.Time) bool {
+	start, previousOffset := getZoneStartAndPreviousOffset(t)
+	if start.IsZero() {
+		return false
+	}
+	_, offset := t.Zone()
+	return previousOffset > offset && t.Before(start.Add(time.Duration(previousOffset-offset)*time.Second))
+}
+
+// getSkippedWallTimeRange returns the wall clock time range [from, to) skipped just before `t`,
+// which happens when the clock is set forward for daylight saving transition.
+// Example for America/New_York:
+// 2024-03-10 01:59:59 EST -> 2024-03-10 03:00:00 EDT, time range [02:00:00, 03:00:00) is skipped.
+// It returns false if `t` is not in the first second after the skipped time range.
+func getSkippedWallTimeRange(t time.Time) (from, to time.Time, ok bool) {
+	start, previousOffset := getZoneStartAndPreviousOffset(t)
+	if start.IsZero() || start.Unix() != t.Unix() {
+		return
+	}
+	_, offset := t.Zone()
+	if previousOffset >= offset {
+		return
+	}
+	return getWallTime(start, previousOffset), getWallTime(start, offset), true
+}
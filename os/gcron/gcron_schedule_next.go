@@ -10,70 +10,149 @@ import (
 	"time"
 )
 
+const (
+	// Max zone transitions that Next searches through, which avoids endless searching.
+	maxNextZoneTransitions = 32
+)
+
 // Next returns the next time this schedule is activated, greater than the given
 // time.  If no time can be found to satisfy the schedule, return the zero time.
+// The returned time is in the location of the schedule, and it handles daylight saving transitions:
+// the job of wall clock time skipped by the transition runs at the transition time, and the job of
+// repeated wall clock time runs only once unless the pattern runs in every hour.
 func (s *cronSchedule) Next(lastMeetTime time.Time) time.Time {
+	var loc = s.getLocation()
 	if s.everySeconds != 0 {
 		var (
 			diff  = lastMeetTime.Unix() - s.createTimestamp
-			count = diff/s.everySeconds + 1
+			count = int64(1)
 		)
-		return lastMeetTime.Add(time.Duration(count*s.everySeconds) * time.Second)
+		if diff >= 0 {
+			count = diff/s.everySeconds + 1
+		}
+		return time.Unix(s.createTimestamp+count*s.everySeconds, 0).In(loc)
 	}
 
-	var currentTime = lastMeetTime
+	var (
+		currentTime = lastMeetTime.In(loc)
+		_, offset   = currentTime.Zone()
+		fromWall    = getWallTime(currentTime, offset)
+	)
 	if s.ignoreSeconds {
 		// Start at the earliest possible time (the upcoming minute).
-		currentTime = currentTime.Add(1*time.Minute - time.Duration(currentTime.Nanosecond())*time.Nanosecond)
+		fromWall = fromWall.Truncate(time.Minute).Add(time.Minute)
 	} else {
 		// Start at the earliest possible time (the upcoming second).
-		currentTime = currentTime.Add(1*time.Second - time.Duration(currentTime.Nanosecond())*time.Nanosecond)
+		fromWall = fromWall.Add(time.Second)
+	}
+	for i := 0; i < maxNextZoneTransitions; i++ {
+		var (
+			_, zoneEnd = currentTime.ZoneBounds()
+			nextWall   = s.nextWallTime(fromWall)
+		)
+		if nextWall.IsZero() {
+			return time.Time{}
+		}
+		nextTime := time.Unix(nextWall.Unix()-int64(offset), 0).In(loc)
+		if zoneEnd.IsZero() || nextTime.Before(zoneEnd) {
+			if s.isHourWildcard() || !isInRepeatedWallTime(nextTime) {
+				return nextTime
+			}
+			// It skips the repeated wall clock times till the end of them.
+			zoneStart, previousOffset := getZoneStartAndPreviousOffset(nextTime)
+			fromWall = getWallTime(zoneStart, previousOffset)
+			continue
+		}
+		// The zone transits before the next time.
+		_, zoneEndOffset := zoneEnd.Zone()
+		if zoneEndOffset > offset {
+			// The job of skipped wall clock times runs at the transition time.
+			var (
+				skippedFromWall = getWallTime(zoneEnd, offset)
+				skippedToWall   = getWallTime(zoneEnd, zoneEndOffset)
+			)
+			if skippedWall := s.nextWallTime(skippedFromWall); !skippedWall.IsZero() && skippedWall.Before(skippedToWall) {
+				return zoneEnd.In(loc)
+			}
+		}
+		currentTime = zoneEnd.In(loc)
+		offset = zoneEndOffset
+		fromWall = getWallTime(currentTime, offset)
 	}
+	return time.Time{}
+}
 
+// nextWallTime returns the earliest wall clock time not before `fromWall` that meets the pattern,
+// in which `fromWall` and the returned time are wall clock times represented in UTC.
+// It returns zero time if no time can be found in five years.
+func (s *cronSchedule) nextWallTime(fromWall time.Time) time.Time {
 	var (
-		loc       = currentTime.Location()
-		yearLimit = currentTime.Year() + 5
+		currentWall = fromWall
+		yearLimit   = fromWall.Year() + 5
 	)
 
 WRAP:
-	if currentTime.Year() > yearLimit {
-		return currentTime // who will care the job that run in five years later
+	if currentWall.Year() > yearLimit {
+		return time.Time{} // who will care the job that run in five years later
 	}
 
-	for !s.checkMeetMonth(currentTime) {
-		currentTime = currentTime.AddDate(0, 1, 0)
-		currentTime = time.Date(currentTime.Year(), currentTime.Month(), 1, 0, 0, 0, 0, loc)
-		if currentTime.Month() == time.January {
+	for !s.checkMeetMonth(currentWall) {
+		currentWall = time.Date(currentWall.Year(), currentWall.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		if currentWall.Month() == time.January {
 			goto WRAP
 		}
 	}
-	for !s.checkMeetWeek(currentTime) || !s.checkMeetDay(currentTime) {
-		currentTime = currentTime.AddDate(0, 0, 1)
-		currentTime = time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, loc)
-		if currentTime.Day() == 1 {
+	for !s.checkMeetWeek(currentWall) || !s.checkMeetDay(currentWall) {
+		currentWall = time.Date(currentWall.Year(), currentWall.Month(), currentWall.Day()+1, 0, 0, 0, 0, time.UTC)
+		if currentWall.Day() == 1 {
 			goto WRAP
 		}
 	}
-	for !s.checkMeetHour(currentTime) {
-		currentTime = currentTime.Add(time.Hour)
-		currentTime = currentTime.Truncate(time.Hour)
-		if currentTime.Hour() == 0 {
+	for !s.checkMeetHour(currentWall) {
+		currentWall = currentWall.Add(time.Hour).Truncate(time.Hour)
+		if currentWall.Hour() == 0 {
 			goto WRAP
 		}
 	}
-	for !s.checkMeetMinute(currentTime) {
-		currentTime = currentTime.Add(1 * time.Minute)
-		currentTime = currentTime.Truncate(time.Minute)
-		if currentTime.Minute() == 0 {
+	for !s.checkMeetMinute(currentWall) {
+		currentWall = currentWall.Add(time.Minute).Truncate(time.Minute)
+		if currentWall.Minute() == 0 {
 			goto WRAP
 		}
 	}
-
-	for !s.checkMeetSecond(lastMeetTime, currentTime) {
-		currentTime = currentTime.Add(1 * time.Second)
-		if currentTime.Second() == 0 {
+	if s.ignoreSeconds {
+		return currentWall
+	}
+	for !s.keyMatch(s.secondMap, currentWall.Second()) {
+		currentWall = currentWall.Add(time.Second)
+		if currentWall.Second() == 0 {
 			goto WRAP
 		}
 	}
-	return currentTime.In(loc)
+	return currentWall
+}
+
+// NextTimes returns the next `n` activated times after time `from`.
+func (s *cronSchedule) NextTimes(from time.Time, n int) []time.Time {
+	var times = make([]time.Time, 0, n)
+	for len(times) < n {
+		if from = s.Next(from); from.IsZero() {
+			break
+		}
+		times = append(times, from)
+	}
+	return times
+}
+
+// getTimesBetween returns the activated times between time `from` and `to` in seconds exclusively,
+// the size of which is at most `limit`.
+func (s *cronSchedule) getTimesBetween(from, to time.Time, limit int) []time.Time {
+	var times = make([]time.Time, 0)
+	for len(times) < limit {
+		if from = s.Next(from); from.IsZero() || from.Unix() >= to.Unix() {
+			break
+		}
+		times = append(times, from)
+	}
+	return times
 }
@@ -0,0 +1,127 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/os/gfile"
+)
+
+// Store is the interface for persisting the running state of cron entries,
+// which is used for catching up the executions missed during process downtime.
+type Store interface {
+	// Get retrieves and returns the state of entry `name`.
+	// It returns nil if the state does not exist.
+	Get(ctx context.Context, name string) (*EntryState, error)
+
+	// Set saves the state of entry `name`.
+	Set(ctx context.Context, name string, state *EntryState) error
+}
+
+// EntryState is the running state of cron entry.
+type EntryState struct {
+	LastRunTime time.Time `json:"lastRunTime"` // Scheduled time of the last running.
+	RunCount    int64     `json:"runCount"`    // Total running count.
+	LastError   string    `json:"lastError"`   // Error of the last running, which is empty if it succeeded.
+}
+
+// FileStore is the Store implementer saving states of all entries in a json file.
+type FileStore struct {
+	mu     sync.Mutex
+	path   string                 // Path of the json file.
+	states map[string]*EntryState // States loaded from file, which is nil if not loaded yet.
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore creates and returns a FileStore saving states in file `path`.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		path: path,
+	}
+}
+
+// Get retrieves and returns the state of entry `name`.
+// It returns nil if the state does not exist.
+func (s *FileStore) Get(ctx context.Context, name string) (*EntryState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if state, ok := s.states[name]; ok {
+		stateCopy := *state
+		return &stateCopy, nil
+	}
+	return nil, nil
+}
+
+// Set saves the state of entry `name` to the file.
+func (s *FileStore) Set(ctx context.Context, name string, state *EntryState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	stateCopy := *state
+	s.states[name] = &stateCopy
+	return s.save()
+}
+
+// load loads the states from file if they are not loaded yet.
+func (s *FileStore) load() error {
+	if s.states != nil {
+		return nil
+	}
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.states = make(map[string]*EntryState)
+			return nil
+		}
+		return gerror.Wrapf(err, `read cron state file "%s" failed`, s.path)
+	}
+	var states map[string]*EntryState
+	if len(content) > 0 {
+		if err = json.Unmarshal(content, &states); err != nil {
+			return gerror.Wrapf(err, `invalid cron state file "%s"`, s.path)
+		}
+	}
+	if states == nil {
+		states = make(map[string]*EntryState)
+	}
+	s.states = states
+	return nil
+}
+
+// save writes the states to a temporary file and renames it to the file,
+// which keeps the file complete even if the process crashes in writing.
+func (s *FileStore) save() error {
+	content, err := json.MarshalIndent(s.states, "", "\t")
+	if err != nil {
+		return gerror.Wrap(err, `marshal cron states failed`)
+	}
+	if dir := gfile.Dir(s.path); !gfile.Exists(dir) {
+		if err = gfile.Mkdir(dir); err != nil {
+			return err
+		}
+	}
+	tmpPath := s.path + ".tmp"
+	if err = os.WriteFile(tmpPath, content, 0666); err != nil {
+		return gerror.Wrapf(err, `write cron state file "%s" failed`, tmpPath)
+	}
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		return gerror.Wrapf(err, `rename cron state file "%s" to "%s" failed`, tmpPath, s.path)
+	}
+	return nil
+}
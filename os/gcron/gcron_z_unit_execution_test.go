@@ -0,0 +1,247 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/os/gmetric"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gregex"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+func TestEntry_Timeout(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx     = context.Background()
+			cron    = New()
+			errChan = make(chan error, 1)
+		)
+		entry, err := cron.AddOnce(ctx, "* * * * * *", func(ctx context.Context) {
+			select {
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+			case <-time.After(3 * time.Second):
+				errChan <- nil
+			}
+		}, "timeout")
+		t.AssertNil(err)
+		entry.SetTimeout(100 * time.Millisecond)
+		t.Assert(entry.GetTimeout(), 100*time.Millisecond)
+
+		select {
+		case err = <-errChan:
+			t.Assert(err, context.DeadlineExceeded)
+		case <-time.After(3 * time.Second):
+			t.Error("job is not run")
+		}
+		time.Sleep(100 * time.Millisecond)
+		history := entry.History()
+		t.Assert(len(history), 1)
+		t.Assert(history[0].EntryName, "timeout")
+		t.Assert(history[0].Attempt, 1)
+		t.Assert(history[0].Outcome, ExecutionOutcomeTimeout)
+		t.AssertGE(history[0].Duration, 100*time.Millisecond)
+		t.AssertNE(entry.State().LastError, "")
+	})
+}
+
+func TestEntry_Cancelled(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			cron        = New()
+			ctx, cancel = context.WithCancelCause(context.Background())
+			attempts    = 0
+		)
+		entry, err := cron.Add(ctx, "0 0 0 * * *", func(ctx context.Context) {
+			attempts++
+			cancel(errors.New("lease is lost"))
+		}, "cancelled")
+		t.AssertNil(err)
+		defer entry.Close()
+		entry.SetRetryPolicy(RetryPolicy{Count: 3})
+
+		execution := entry.execute(ctx, time.Now())
+		t.Assert(attempts, 1)
+		t.Assert(execution.Outcome, ExecutionOutcomeCancelled)
+		t.Assert(gstr.Contains(execution.Error, "lease is lost"), true)
+		history := entry.History()
+		t.Assert(len(history), 1)
+		t.Assert(history[0].Outcome, ExecutionOutcomeCancelled)
+	})
+}
+
+func TestEntry_RetryPolicy(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.Background()
+			cron     = New()
+			attempts = 0
+			doneChan = make(chan struct{})
+		)
+		entry, err := cron.AddOnce(ctx, "* * * * * *", func(ctx context.Context) {
+			attempts++
+			if attempts < 3 {
+				panic("error")
+			}
+			close(doneChan)
+		}, "retry")
+		t.AssertNil(err)
+		entry.SetRetryPolicy(RetryPolicy{
+			Count:      3,
+			Interval:   50 * time.Millisecond,
+			Multiplier: 2,
+		})
+		t.Assert(entry.GetRetryPolicy().Count, 3)
+
+		select {
+		case <-doneChan:
+		case <-time.After(3 * time.Second):
+			t.Error("job is not retried")
+		}
+		time.Sleep(100 * time.Millisecond)
+		history := entry.History()
+		t.Assert(len(history), 3)
+		for i, outcome := range []string{
+			ExecutionOutcomeFailure, ExecutionOutcomeFailure, ExecutionOutcomeSuccess,
+		} {
+			t.Assert(history[i].Attempt, i+1)
+			t.Assert(history[i].Outcome, outcome)
+			t.Assert(history[i].ScheduledTime, history[0].ScheduledTime)
+		}
+		t.Assert(history[0].Error, "error")
+		t.AssertGE(history[2].StartTime.Sub(history[1].StartTime), 100*time.Millisecond)
+		t.Assert(entry.State().RunCount, 1)
+		t.Assert(entry.State().LastError, "")
+	})
+}
+
+func TestEntry_RetryPolicy_Error(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.Background()
+			cron     = New()
+			attempts = 0
+		)
+		entry, err := cron.AddWithError(ctx, "0 0 0 * * *", func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("failed")
+			}
+			return nil
+		}, "retry-error")
+		t.AssertNil(err)
+		defer entry.Close()
+		entry.SetRetryPolicy(RetryPolicy{
+			Count:    3,
+			Interval: 10 * time.Millisecond,
+		})
+		t.Assert(gstr.Contains(entry.jobName, "TestEntry_RetryPolicy_Error"), true)
+
+		execution := entry.execute(ctx, time.Now())
+		t.Assert(attempts, 3)
+		t.Assert(execution.Outcome, ExecutionOutcomeSuccess)
+		t.Assert(execution.Attempt, 3)
+		history := entry.History()
+		t.Assert(len(history), 3)
+		for i := 0; i < 2; i++ {
+			t.Assert(history[i].Outcome, ExecutionOutcomeFailure)
+			t.Assert(history[i].Error, "failed")
+		}
+		t.Assert(history[2].Outcome, ExecutionOutcomeSuccess)
+		t.Assert(history[2].Error, "")
+	})
+}
+
+func TestRetryPolicy_getInterval(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		policy := RetryPolicy{Interval: time.Second}
+		t.Assert(policy.getInterval(1), time.Second)
+		t.Assert(policy.getInterval(5), time.Second)
+
+		policy = RetryPolicy{Interval: time.Second, Multiplier: 2, MaxInterval: 5 * time.Second}
+		t.Assert(policy.getInterval(1), time.Second)
+		t.Assert(policy.getInterval(2), 2*time.Second)
+		t.Assert(policy.getInterval(3), 4*time.Second)
+		t.Assert(policy.getInterval(4), 5*time.Second)
+		t.Assert(policy.getInterval(1000), 5*time.Second)
+	})
+}
+
+func TestCron_History(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx  = context.Background()
+			cron = New()
+			now  = time.Now()
+		)
+		entry1, err := cron.Add(ctx, "0 0 0 1 1 *", func(ctx context.Context) {}, "job1")
+		t.AssertNil(err)
+		entry2, err := cron.Add(ctx, "0 0 0 1 1 *", func(ctx context.Context) {}, "job2")
+		t.AssertNil(err)
+		defer cron.Close()
+
+		entry1.SetHistorySize(2)
+		for i := 0; i < 3; i++ {
+			entry1.addHistory(Execution{EntryName: "job1", StartTime: now.Add(time.Duration(i*2) * time.Second)})
+			entry2.addHistory(Execution{EntryName: "job2", StartTime: now.Add(time.Duration(i*2+1) * time.Second)})
+		}
+		t.Assert(len(entry1.History()), 2)
+		t.Assert(entry1.History()[0].StartTime, now.Add(2*time.Second))
+		t.Assert(len(entry2.History()), 3)
+
+		history := cron.History()
+		t.Assert(len(history), 5)
+		for i, execution := range history {
+			t.Assert(execution.StartTime, now.Add(time.Duration(i+1)*time.Second))
+		}
+
+		entry2.SetHistorySize(1)
+		t.Assert(len(entry2.History()), 1)
+		t.Assert(entry2.History()[0].StartTime, now.Add(5*time.Second))
+		entry2.SetHistorySize(0)
+		entry2.addHistory(Execution{EntryName: "job2", StartTime: now})
+		t.Assert(len(entry2.History()), 0)
+	})
+}
+
+func TestEntry_Metrics(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.Background()
+			cron     = New()
+			doneChan = make(chan struct{})
+		)
+		provider := gmetric.NewPrometheusProvider()
+		provider.SetAsGlobal()
+		defer provider.Shutdown(ctx)
+
+		_, err := cron.AddOnce(ctx, "* * * * * *", func(ctx context.Context) {
+			close(doneChan)
+		}, "metrics")
+		t.AssertNil(err)
+		select {
+		case <-doneChan:
+		case <-time.After(3 * time.Second):
+			t.Error("job is not run")
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		buffer := bytes.NewBuffer(nil)
+		t.AssertNil(provider.Export(ctx, buffer))
+		content := buffer.String()
+		t.Assert(gregex.IsMatchString(
+			`gcron_job_runs\S*\{[^}]*cron_job_name="metrics"[^}]*cron_job_outcome="success"[^}]*\} 1`, content,
+		), true)
+		t.Assert(gregex.IsMatchString(`gcron_job_duration\S*_count\{[^}]*cron_job_name="metrics"`, content), true)
+	})
+}
@@ -0,0 +1,237 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gstr"
+)
+
+// testLeaseStore is the in-memory LeaseStore for testing.
+type testLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]testLeaseRecord
+}
+
+type testLeaseRecord struct {
+	owner  string
+	expire time.Time
+}
+
+func newTestLeaseStore() *testLeaseStore {
+	return &testLeaseStore{leases: make(map[string]testLeaseRecord)}
+}
+
+func (s *testLeaseStore) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.leases[key]; ok && record.expire.After(time.Now()) {
+		return false, nil
+	}
+	s.leases[key] = testLeaseRecord{owner: owner, expire: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (s *testLeaseStore) Renew(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.leases[key]; !ok || record.owner != owner {
+		return false, nil
+	}
+	s.leases[key] = testLeaseRecord{owner: owner, expire: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (s *testLeaseStore) revoke() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.leases {
+		s.leases[key] = testLeaseRecord{owner: "other", expire: time.Now().Add(time.Minute)}
+	}
+}
+
+func TestFileLocker(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx    = context.Background()
+			dir    = gfile.Temp(gtime.TimestampNanoStr())
+			locker = NewFileLocker(dir)
+			tick   = time.Now()
+		)
+		defer gfile.Remove(dir)
+
+		lease, err := locker.Lock(ctx, "job", tick)
+		t.AssertNil(err)
+		t.AssertNE(lease, nil)
+		t.AssertNil(lease.Done())
+		// Locked by others.
+		lease2, err := NewFileLocker(dir).Lock(ctx, "job", tick)
+		t.AssertNil(err)
+		t.AssertNil(lease2)
+		t.AssertNil(lease.Release(ctx))
+
+		// The execution has already run.
+		lease2, err = NewFileLocker(dir).Lock(ctx, "job", tick)
+		t.AssertNil(err)
+		t.AssertNil(lease2)
+
+		// Next execution.
+		lease2, err = NewFileLocker(dir).Lock(ctx, "job", tick.Add(time.Second))
+		t.AssertNil(err)
+		t.AssertNE(lease2, nil)
+		t.AssertNil(lease2.Release(ctx))
+	})
+}
+
+func TestFileLocker_Replicas(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.Background()
+			dir      = gfile.Temp(gtime.TimestampNanoStr())
+			mu       sync.Mutex
+			runTimes = make(map[int64]int)
+			replicas = make([]*Cron, 0)
+		)
+		defer gfile.Remove(dir)
+		for i := 0; i < 3; i++ {
+			cron := New()
+			cron.SetLocker(NewFileLocker(dir))
+			_, err := cron.Add(ctx, "* * * * * *", func(ctx context.Context) {
+				mu.Lock()
+				runTimes[time.Now().Unix()]++
+				mu.Unlock()
+			}, "job")
+			t.AssertNil(err)
+			replicas = append(replicas, cron)
+		}
+		time.Sleep(3500 * time.Millisecond)
+		for _, cron := range replicas {
+			cron.Remove("job")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		t.AssertGE(len(runTimes), 2)
+		for _, count := range runTimes {
+			t.Assert(count, 1)
+		}
+	})
+}
+
+func TestFileLocker_TimesExhausted(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx     = context.Background()
+			dir     = gfile.Temp(gtime.TimestampNanoStr())
+			cron    = New()
+			counter = gtype.NewInt()
+			tick    = time.Now()
+		)
+		defer gfile.Remove(dir)
+		cron.SetLocker(NewFileLocker(dir))
+		entry, err := cron.AddTimes(ctx, "0 0 0 * * *", 1, func(ctx context.Context) {
+			counter.Add(1)
+		}, "job")
+		t.AssertNil(err)
+		defer entry.Close()
+
+		entry.run(ctx, tick)
+		entry.run(ctx, tick.Add(time.Second))
+		t.Assert(counter.Val(), 1)
+
+		// The lock file is not locked by the execution not running.
+		lease, err := NewFileLocker(dir).Lock(ctx, "job", tick.Add(2*time.Second))
+		t.AssertNil(err)
+		t.AssertNE(lease, nil)
+		t.AssertNil(lease.Release(ctx))
+	})
+}
+
+func TestLocker_IntervalPattern(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx  = context.Background()
+			dir  = gfile.Temp(gtime.TimestampNanoStr())
+			cron = New()
+			job  = func(ctx context.Context) {}
+		)
+		defer gfile.Remove(dir)
+		entry, err := cron.Add(ctx, "@every 1h", job, "job1")
+		t.AssertNil(err)
+		defer entry.Close()
+
+		cron.SetLocker(NewFileLocker(dir))
+		_, err = cron.Add(ctx, "@every 1h", job, "job2")
+		t.AssertNE(err, nil)
+		// The entry added before is not locked.
+		lease, ok := entry.lock(ctx, time.Now())
+		t.Assert(ok, true)
+		t.AssertNil(lease)
+		// The entry without custom name is not locked.
+		entry, err = cron.Add(ctx, "@every 1h", job)
+		t.AssertNil(err)
+		entry.Close()
+	})
+}
+
+func TestLeaseLocker(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx   = context.Background()
+			store = newTestLeaseStore()
+			tick  = time.Now()
+		)
+		lease, err := NewLeaseLocker(store).Lock(ctx, "job", tick)
+		t.AssertNil(err)
+		t.AssertNE(lease, nil)
+		// Acquired by other replica.
+		lease2, err := NewLeaseLocker(store).Lock(ctx, "job", tick)
+		t.AssertNil(err)
+		t.AssertNil(lease2)
+		t.AssertNil(lease.Release(ctx))
+		// The lease is kept till expiration after released.
+		lease2, err = NewLeaseLocker(store).Lock(ctx, "job", tick)
+		t.AssertNil(err)
+		t.AssertNil(lease2)
+	})
+	// Lease loss cancels the job context.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx     = context.Background()
+			store   = newTestLeaseStore()
+			cron    = New()
+			errChan = make(chan error, 1)
+		)
+		cron.SetLocker(NewLeaseLocker(store, 300*time.Millisecond))
+		_, err := cron.AddOnce(ctx, "* * * * * *", func(ctx context.Context) {
+			store.revoke()
+			select {
+			case <-ctx.Done():
+				errChan <- context.Cause(ctx)
+			case <-time.After(3 * time.Second):
+				errChan <- nil
+			}
+		}, "job")
+		t.AssertNil(err)
+
+		select {
+		case err = <-errChan:
+			t.AssertNE(err, nil)
+			t.Assert(gstr.Contains(err.Error(), "lost"), true)
+		case <-time.After(5 * time.Second):
+			t.Error("job is not run")
+		}
+	})
+}
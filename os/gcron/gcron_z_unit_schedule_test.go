@@ -85,6 +85,30 @@ func TestNext(t *testing.T) {
 		// Ignore seconds.
 		{"Mon Jul 9 23:35 2012", "# * * * * *", "Mon Jul 9 23:36 2012"},
 		{"Mon Jul 9 23:35 2012", "# */2 * * * *", "Mon Jul 9 23:36 2012"},
+
+		// Last day of month.
+		{"Mon Jul 9 23:35 2012", "0 0 0 L * *", "Tue Jul 31 00:00 2012"},
+		{"Tue Jul 31 00:00 2012", "0 0 0 L * *", "Fri Aug 31 00:00 2012"},
+		{"Wed Feb 1 00:00 2012", "0 0 0 L * *", "Wed Feb 29 00:00 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 L,15 * *", "Sun Jul 15 00:00 2012"},
+
+		// Nearest weekday.
+		{"Sat Sep 1 00:00 2012", "0 0 0 LW * *", "Fri Sep 28 00:00 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 15W * *", "Mon Jul 16 00:00 2012"},
+		{"Fri Aug 31 12:00 2012", "0 0 0 1W * *", "Mon Sep 3 00:00 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 31W * *", "Tue Jul 31 00:00 2012"},
+
+		// Nth weekday of month.
+		{"Mon Jul 9 23:35 2012", "0 0 0 * * 5#2", "Fri Jul 13 00:00 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 * * Fri#5", "Fri Aug 31 00:00 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 * * 1L", "Mon Jul 30 00:00 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 * * 1L,3#2", "Wed Jul 11 00:00 2012"},
+
+		// Time zone.
+		{"TZ=UTC 2012-07-09T23:35:00+0000", "CRON_TZ=Asia/Shanghai 0 0 8 * * *", "TZ=UTC 2012-07-10T00:00:00+0000"},
+		{"TZ=UTC 2012-07-09T23:35:00+0000", "TZ=Asia/Shanghai 0 0 8 * * *", "TZ=UTC 2012-07-10T00:00:00+0000"},
+		{"TZ=UTC 2012-07-09T23:35:00+0000", "CRON_TZ=Asia/Shanghai @daily", "TZ=UTC 2012-07-10T16:00:00+0000"},
+		{"TZ=UTC 2012-07-09T23:35:00+0000", "CRON_TZ=Asia/Kolkata 0 0 * * * *", "TZ=UTC 2012-07-10T00:30:00+0000"},
 	}
 
 	for _, c := range runs {
@@ -105,6 +129,117 @@ func TestNext(t *testing.T) {
 	}
 }
 
+func TestNext_DaylightSaving(t *testing.T) {
+	runs := []struct {
+		time, spec string
+		expected   []string
+	}{
+		// The skipped wall clock time runs at the transition time.
+		{"TZ=UTC 2024-03-09T08:00:00+0000", "CRON_TZ=America/New_York 0 30 2 * * *", []string{
+			"TZ=UTC 2024-03-10T07:00:00+0000",
+			"TZ=UTC 2024-03-11T06:30:00+0000",
+		}},
+		{"TZ=UTC 2024-03-10T05:00:00+0000", "CRON_TZ=America/New_York 0 */30 2 * * *", []string{
+			"TZ=UTC 2024-03-10T07:00:00+0000",
+			"TZ=UTC 2024-03-11T06:00:00+0000",
+		}},
+		{"TZ=UTC 2024-03-10T06:00:00+0000", "CRON_TZ=America/New_York 0 0 3 * * *", []string{
+			"TZ=UTC 2024-03-10T07:00:00+0000",
+			"TZ=UTC 2024-03-11T07:00:00+0000",
+		}},
+		// The repeated wall clock time runs only once.
+		{"TZ=UTC 2024-11-02T16:00:00+0000", "CRON_TZ=America/New_York 0 30 1 * * *", []string{
+			"TZ=UTC 2024-11-03T05:30:00+0000",
+			"TZ=UTC 2024-11-04T06:30:00+0000",
+		}},
+		// The repeated wall clock time runs twice for pattern running in every hour.
+		{"TZ=UTC 2024-11-03T05:00:00+0000", "CRON_TZ=America/New_York 0 30 * * * *", []string{
+			"TZ=UTC 2024-11-03T05:30:00+0000",
+			"TZ=UTC 2024-11-03T06:30:00+0000",
+			"TZ=UTC 2024-11-03T07:30:00+0000",
+		}},
+	}
+	gtest.C(t, func(t *gtest.T) {
+		for _, c := range runs {
+			s, err := newSchedule(c.spec)
+			t.AssertNil(err)
+			times := s.NextTimes(getTime(c.time), len(c.expected))
+			t.Assert(len(times), len(c.expected))
+			for i, expected := range c.expected {
+				t.Assert(times[i].Unix(), getTime(expected).Unix())
+				t.Assert(times[i].Location().String(), "America/New_York")
+			}
+		}
+	})
+}
+
+func TestCheckMeet_DaylightSaving(t *testing.T) {
+	runs := []struct {
+		spec     string
+		time     string
+		expected bool
+	}{
+		{"CRON_TZ=America/New_York 0 30 2 * * *", "TZ=UTC 2024-03-10T07:00:00+0000", true},
+		{"CRON_TZ=America/New_York 0 30 2 * * *", "TZ=UTC 2024-03-10T07:00:01+0000", false},
+		{"CRON_TZ=America/New_York 0 30 3 * * *", "TZ=UTC 2024-03-10T07:00:00+0000", false},
+		{"CRON_TZ=America/New_York 0 30 1 * * *", "TZ=UTC 2024-11-03T05:30:00+0000", true},
+		{"CRON_TZ=America/New_York 0 30 1 * * *", "TZ=UTC 2024-11-03T06:30:00+0000", false},
+		{"CRON_TZ=America/New_York 0 30 * * * *", "TZ=UTC 2024-11-03T06:30:00+0000", true},
+	}
+	gtest.C(t, func(t *gtest.T) {
+		for _, c := range runs {
+			s, err := newSchedule(c.spec)
+			t.AssertNil(err)
+			var (
+				currentTime  = getTime(c.time).In(s.getLocation())
+				lastMeetTime = currentTime.Add(-time.Hour)
+			)
+			t.Assert(s.checkMinIntervalAndItemMapMeet(lastMeetTime, currentTime, currentTime), c.expected)
+		}
+	})
+}
+
+func TestNextTimes(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		times, err := NextTimes("CRON_TZ=UTC 0 0 0 L * *", getTime("TZ=UTC 2024-01-15T00:00:00+0000"), 3)
+		t.AssertNil(err)
+		t.Assert(len(times), 3)
+		t.Assert(times[0].Format(time.DateTime), "2024-01-31 00:00:00")
+		t.Assert(times[1].Format(time.DateTime), "2024-02-29 00:00:00")
+		t.Assert(times[2].Format(time.DateTime), "2024-03-31 00:00:00")
+	})
+	// Interval pattern.
+	gtest.C(t, func(t *gtest.T) {
+		s, err := newSchedule("@every 1h")
+		t.AssertNil(err)
+		times := s.NextTimes(time.Unix(s.createTimestamp, 0), 2)
+		t.Assert(len(times), 2)
+		t.Assert(times[0].Unix(), s.createTimestamp+3600)
+		t.Assert(times[1].Unix(), s.createTimestamp+7200)
+	})
+	// Pattern that is never activated.
+	gtest.C(t, func(t *gtest.T) {
+		times, err := NextTimes("0 0 0 30 Feb *", time.Now(), 3)
+		t.AssertNil(err)
+		t.Assert(len(times), 0)
+	})
+	// Invalid patterns.
+	gtest.C(t, func(t *gtest.T) {
+		for _, pattern := range []string{
+			"CRON_TZ=Invalid/Zone 0 * * * * *",
+			"CRON_TZ=UTC",
+			"0 0 0 32W * *",
+			"0 0 0 L1 * *",
+			"0 0 0 * * 7#1",
+			"0 0 0 * * 1#6",
+			"0 0 0 * * 1#2#3",
+		} {
+			_, err := NextTimes(pattern, time.Now(), 1)
+			t.AssertNE(err, nil)
+		}
+	})
+}
+
 func getTime(value string) time.Time {
 	if value == "" {
 		return time.Time{}
@@ -0,0 +1,153 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gcron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func TestFileStore(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx   = context.Background()
+			path  = gfile.Temp(gtime.TimestampNanoStr(), "cron.json")
+			store = NewFileStore(path)
+			state = &EntryState{
+				LastRunTime: time.Unix(1700000000, 0),
+				RunCount:    10,
+				LastError:   "error",
+			}
+		)
+		defer gfile.Remove(gfile.Dir(path))
+
+		v, err := store.Get(ctx, "job")
+		t.AssertNil(err)
+		t.AssertNil(v)
+		t.AssertNil(store.Set(ctx, "job", state))
+		t.Assert(gfile.Exists(path+".tmp"), false)
+
+		// Reloaded from file.
+		v, err = NewFileStore(path).Get(ctx, "job")
+		t.AssertNil(err)
+		t.Assert(v.LastRunTime.Unix(), state.LastRunTime.Unix())
+		t.Assert(v.RunCount, 10)
+		t.Assert(v.LastError, "error")
+
+		// Invalid file.
+		t.AssertNil(gfile.PutContents(path, "invalid"))
+		_, err = NewFileStore(path).Get(ctx, "job")
+		t.AssertNE(err, nil)
+	})
+}
+
+func TestEntry_MisfirePolicy_Store(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		lastRunTime  = time.Now().Add(-72 * time.Hour)
+		expectedRuns = map[MisfirePolicy]int{
+			MisfirePolicySkip:    0,
+			MisfirePolicyRunOnce: 1,
+			MisfirePolicyRunAll:  3,
+		}
+	)
+	for policy, runs := range expectedRuns {
+		gtest.C(t, func(t *gtest.T) {
+			var (
+				path    = gfile.Temp(gtime.TimestampNanoStr(), "cron.json")
+				store   = NewFileStore(path)
+				cron    = New()
+				counter = gtype.NewInt()
+			)
+			defer gfile.Remove(gfile.Dir(path))
+			t.AssertNil(store.Set(ctx, "job", &EntryState{LastRunTime: lastRunTime, RunCount: 5}))
+
+			cron.SetStore(store)
+			entry, err := cron.Add(ctx, "0 0 0 * * *", func(ctx context.Context) {
+				counter.Add(1)
+			}, "job")
+			t.AssertNil(err)
+			entry.SetMisfirePolicy(policy)
+			defer entry.Close()
+
+			time.Sleep(1500 * time.Millisecond)
+			t.Assert(counter.Val(), runs)
+			t.Assert(entry.State().RunCount, 5+runs)
+
+			state, err := NewFileStore(path).Get(ctx, "job")
+			t.AssertNil(err)
+			t.Assert(state.RunCount, 5+runs)
+			if runs > 0 {
+				t.AssertGT(state.LastRunTime.Unix(), lastRunTime.Unix())
+			} else {
+				t.Assert(state.LastRunTime.Unix(), lastRunTime.Unix())
+			}
+		})
+	}
+}
+
+func TestEntry_MisfirePolicy_ClockJump(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx     = context.Background()
+			cron    = New()
+			counter = gtype.NewInt()
+		)
+		entry, err := cron.Add(ctx, "0 * * * * *", func(ctx context.Context) {
+			counter.Add(1)
+			panic("error")
+		})
+		t.AssertNil(err)
+		entry.Stop()
+		defer entry.Close()
+		entry.SetMisfirePolicy(MisfirePolicyRunAll)
+
+		var currentTime = time.Now()
+		// No jump.
+		entry.lastCheckTimestamp.Set(currentTime.Unix() - 1)
+		entry.checkAndRunMissed(ctx, currentTime)
+		t.Assert(counter.Val(), 0)
+
+		// The clock jumps 10 minutes forward.
+		entry.lastCheckTimestamp.Set(currentTime.Add(-10 * time.Minute).Unix())
+		entry.checkAndRunMissed(ctx, currentTime)
+		t.AssertIN(counter.Val(), []int{9, 10})
+		t.Assert(entry.State().RunCount, counter.Val())
+		t.Assert(entry.State().LastError, "error")
+	})
+}
+
+func TestEntry_MisfirePolicy_DueInSameCheck(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx     = context.Background()
+			cron    = New()
+			counter = gtype.NewInt()
+		)
+		entry, err := cron.Add(ctx, "0 * * * * *", func(ctx context.Context) {
+			counter.Add(1)
+		})
+		t.AssertNil(err)
+		entry.Stop()
+		defer entry.Close()
+		entry.SetMisfirePolicy(MisfirePolicyRunAll)
+
+		// The current time is due, and the last checking misses 2 executions.
+		var currentTime = time.Now().Truncate(time.Minute).Add(time.Minute)
+		entry.lastCheckTimestamp.Set(currentTime.Add(-150 * time.Second).Unix())
+		entry.checkAndRunMissed(ctx, currentTime)
+		t.Assert(counter.Val(), 2)
+		t.Assert(entry.schedule.checkMeetAndUpdateLastSeconds(ctx, currentTime), true)
+		t.Assert(entry.schedule.checkMeetAndUpdateLastSeconds(ctx, currentTime), false)
+	})
+}
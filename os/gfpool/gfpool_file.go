@@ -9,6 +9,7 @@ package gfpool
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ximplez-go/gf/errors/gerror"
@@ -56,6 +57,26 @@ func Get(path string, flag int, perm os.FileMode, ttl ...time.Duration) (file *F
 	return fp.(*File)
 }
 
+// Invalidate drops the pooled file pointers of `path` in all pools, so that the file of `path` is opened
+// again for next retrieving. It should be called after the file is renamed or removed, eg: in file rotation,
+// as the pooled file pointers still point to the renamed or removed file until the file watching event arrives.
+func Invalidate(path string) {
+	var (
+		prefix       = path + "&"
+		invalidPools = make([]*Pool, 0)
+	)
+	pools.RLockFunc(func(m map[string]interface{}) {
+		for key, pool := range m {
+			if strings.HasPrefix(key, prefix) {
+				invalidPools = append(invalidPools, pool.(*Pool))
+			}
+		}
+	})
+	for _, pool := range invalidPools {
+		pool.reset()
+	}
+}
+
 // Stat returns the FileInfo structure describing file.
 func (f *File) Stat() (os.FileInfo, error) {
 	if f.stat == nil {
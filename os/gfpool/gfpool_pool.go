@@ -70,7 +70,9 @@ func (p *Pool) File() (*File, error) {
 		f := v.(*File)
 		f.stat, err = os.Stat(f.path)
 		if f.flag&os.O_CREATE > 0 {
+			// The file is removed or renamed, it then reopens the file of the path.
 			if os.IsNotExist(err) {
+				_ = f.File.Close()
 				if f.File, err = os.OpenFile(f.path, f.flag, f.perm); err != nil {
 					return nil, err
 				} else {
@@ -102,13 +104,7 @@ func (p *Pool) File() (*File, error) {
 			var watchCallback = func(event *gfsnotify.Event) {
 				// If the file is removed or renamed, recreates the pool by increasing the pool id.
 				if event.IsRemove() || event.IsRename() {
-					// It drops the old pool.
-					p.id.Add(1)
-					// Clears the pool items staying in the pool.
-					p.pool.Clear()
-					// It uses another adding to drop the file items between the two adding.
-					// Whenever the pool id changes, the pool will be recreated.
-					p.id.Add(1)
+					p.reset()
 				}
 			}
 			_, _ = gfsnotify.Add(f.path, watchCallback, gfsnotify.WatchOption{NoRecursive: true})
@@ -117,6 +113,18 @@ func (p *Pool) File() (*File, error) {
 	}
 }
 
+// reset drops the file pointers of the pool by increasing the pool id,
+// so that the pool items are recreated for next retrieving.
+func (p *Pool) reset() {
+	// It drops the old pool.
+	p.id.Add(1)
+	// Clears the pool items staying in the pool.
+	p.pool.Clear()
+	// It uses another adding to drop the file items between the two adding.
+	// Whenever the pool id changes, the pool will be recreated.
+	p.id.Add(1)
+}
+
 // Close closes current file pointer pool.
 func (p *Pool) Close() {
 	p.pool.Close()
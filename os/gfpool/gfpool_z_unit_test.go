@@ -124,6 +124,33 @@ func TestNewPool(t *testing.T) {
 	stop(testFile)
 }
 
+// TestInvalidate test invalidating file pointers after the file is renamed
+func TestInvalidate(t *testing.T) {
+	testFile := start("TestInvalidate.txt")
+	renamedFile := testFile + ".bak"
+
+	gtest.C(t, func(t *gtest.T) {
+		f, err := gfpool.Open(testFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+		t.AssertNil(err)
+		f.Close()
+
+		t.AssertNil(gfile.Rename(testFile, renamedFile))
+		gfpool.Invalidate(testFile)
+
+		f, err = gfpool.Open(testFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+		t.AssertNil(err)
+		_, err = f.WriteString("456")
+		t.AssertNil(err)
+		f.Close()
+
+		t.Assert(gfile.GetContents(testFile), "456")
+		t.Assert(gfile.GetContents(renamedFile), "123")
+	})
+
+	stop(testFile)
+	stop(renamedFile)
+}
+
 // test before
 func start(name string) string {
 	testFile := os.TempDir() + string(os.PathSeparator) + name
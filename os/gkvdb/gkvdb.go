@@ -0,0 +1,193 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gkvdb provides an embedded log-structured key-value database.
+//
+// All records are appended to a single data file, and an in-memory index maps each key
+// to the offset of its latest record. The superseded, deleted and expired records are
+// removed by compaction, which rewrites the live records to a new data file.
+// The data file is recovered by scanning its records when opened, and the broken
+// records at the tail of the file caused by crashing in writing are truncated.
+//
+// Note that the data file can be opened by only one DB in one process at the same time.
+package gkvdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/os/gtimer"
+)
+
+// DB is the embedded key-value database using a single data file.
+type DB struct {
+	mu      sync.RWMutex
+	path    string                // Path of the data file.
+	file    *os.File              // Opened data file.
+	size    int64                 // Size of the data file, which is the offset of next record.
+	garbage int64                 // Size of the records that are superseded, deleted or expired.
+	index   map[string]*indexItem // Index of all live keys.
+	option  Option                // Options of the DB.
+	dirty   bool                  // Whether there are writes not synced to disk.
+	closed  bool                  // Whether the DB is closed.
+	timers  []*gtimer.Entry       // Background syncing and compaction timers.
+}
+
+// indexItem is the index of the latest value record of a key.
+type indexItem struct {
+	valueOffset int64 // Offset of the value in data file.
+	valueSize   int   // Size of the value.
+	recordSize  int64 // Size of the record, which is counted as garbage when the record is superseded.
+	expire      int64 // Expiration timestamp in milliseconds, which is 0 if it does not expire.
+}
+
+// Option is the options for DB.
+type Option struct {
+	SyncPolicy      SyncPolicy    // Policy of syncing writes to disk, which is SyncPolicyInterval in default.
+	SyncInterval    time.Duration // Interval of syncing for SyncPolicyInterval, which is DefaultSyncInterval in default.
+	CompactInterval time.Duration // Interval of checking for compaction, which is DefaultCompactInterval in default.
+	CompactRatio    float64       // Min ratio of garbage size to file size that triggers compaction, which is DefaultCompactRatio in default.
+	CompactMinSize  int64         // Min garbage size that triggers compaction, which is DefaultCompactMinSize in default.
+}
+
+// SyncPolicy specifies when the writes are synced to disk.
+type SyncPolicy int
+
+const (
+	SyncPolicyInterval SyncPolicy = iota // Syncs every SyncInterval, which loses the writes in the interval if system crashes.
+	SyncPolicyAlways                     // Syncs every write, which is durable but slow.
+	SyncPolicyNever                      // Never syncs and leaves it to the operating system.
+)
+
+const (
+	DefaultSyncInterval    = time.Second
+	DefaultCompactInterval = time.Minute
+	DefaultCompactRatio    = 0.5
+	DefaultCompactMinSize  = 1 << 20
+)
+
+const (
+	// Suffix of the temporary file for compaction.
+	compactFileSuffix = ".compact"
+)
+
+var (
+	// ErrClosed is returned when operating a closed DB.
+	ErrClosed = gerror.NewCode(gcode.CodeInvalidOperation, "database is closed")
+)
+
+// Open opens or creates the data file `path`, and returns the DB after recovering the index from it.
+func Open(path string, option ...Option) (*DB, error) {
+	db := &DB{
+		path:  path,
+		index: make(map[string]*indexItem),
+	}
+	if len(option) > 0 {
+		db.option = option[0]
+	}
+	if db.option.SyncInterval <= 0 {
+		db.option.SyncInterval = DefaultSyncInterval
+	}
+	if db.option.CompactInterval <= 0 {
+		db.option.CompactInterval = DefaultCompactInterval
+	}
+	if db.option.CompactRatio <= 0 {
+		db.option.CompactRatio = DefaultCompactRatio
+	}
+	if db.option.CompactMinSize <= 0 {
+		db.option.CompactMinSize = DefaultCompactMinSize
+	}
+	// It uses package os instead of gfile, as gfile depends on gcache which uses gkvdb as an adapter.
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, gerror.Wrapf(err, `create directory of data file "%s" failed`, path)
+	}
+	// The temporary file is left if the process crashes in compaction,
+	// and the data file is still complete as it is replaced by renaming.
+	if err := os.Remove(path + compactFileSuffix); err != nil && !os.IsNotExist(err) {
+		return nil, gerror.Wrapf(err, `remove compaction file "%s" failed`, path+compactFileSuffix)
+	}
+	if err := db.openFile(); err != nil {
+		return nil, err
+	}
+	if err := db.recover(); err != nil {
+		_ = db.file.Close()
+		return nil, err
+	}
+	var ctx = context.Background()
+	if db.option.SyncPolicy == SyncPolicyInterval {
+		db.timers = append(db.timers, gtimer.AddSingleton(ctx, db.option.SyncInterval, db.timelySync))
+	}
+	db.timers = append(db.timers, gtimer.AddSingleton(ctx, db.option.CompactInterval, db.timelyCompact))
+	return db, nil
+}
+
+// Path returns the path of the data file.
+func (db *DB) Path() string {
+	return db.path
+}
+
+// Sync syncs the writes to disk.
+func (db *DB) Sync() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	return db.doSync()
+}
+
+// Close syncs the writes to disk and closes the data file.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, timer := range db.timers {
+		timer.Close()
+	}
+	if db.closed {
+		return nil
+	}
+	db.closed = true
+	err := db.doSync()
+	if closeErr := db.file.Close(); err == nil && closeErr != nil {
+		err = gerror.Wrapf(closeErr, `close data file "%s" failed`, db.path)
+	}
+	return err
+}
+
+// openFile opens the data file for reading and writing.
+func (db *DB) openFile() error {
+	file, err := os.OpenFile(db.path, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return gerror.Wrapf(err, `open data file "%s" failed`, db.path)
+	}
+	db.file = file
+	return nil
+}
+
+// doSync syncs the data file if there are writes not synced.
+func (db *DB) doSync() error {
+	if !db.dirty {
+		return nil
+	}
+	if err := db.file.Sync(); err != nil {
+		return gerror.Wrapf(err, `sync data file "%s" failed`, db.path)
+	}
+	db.dirty = false
+	return nil
+}
+
+// timelySync syncs the writes to disk timely for SyncPolicyInterval.
+func (db *DB) timelySync(ctx context.Context) {
+	if err := db.Sync(); err != nil && !gerror.Is(err, ErrClosed) {
+		intlog.Errorf(ctx, `%+v`, err)
+	}
+}
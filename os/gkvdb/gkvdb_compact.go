@@ -0,0 +1,123 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gkvdb
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+)
+
+// Compact removes the expired keys, and rewrites all live records to a new data file
+// which replaces the current data file.
+//
+// Note that all reading and writing are blocked during compaction.
+func (db *DB) Compact() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	db.removeExpired(time.Now().UnixMilli())
+	return db.doCompact()
+}
+
+// timelyCompact removes the expired keys and compacts the data file
+// if the garbage reaches the thresholds of options.
+func (db *DB) timelyCompact(ctx context.Context) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return
+	}
+	db.removeExpired(time.Now().UnixMilli())
+	if db.garbage < db.option.CompactMinSize ||
+		float64(db.garbage) < float64(db.size)*db.option.CompactRatio {
+		return
+	}
+	if err := db.doCompact(); err != nil {
+		intlog.Errorf(ctx, `%+v`, err)
+	}
+}
+
+// doCompact writes all records in index to the temporary file, and replaces the data file with it.
+// The data file is kept complete if it fails or the process crashes in compaction.
+func (db *DB) doCompact() (err error) {
+	var compactPath = db.path + compactFileSuffix
+	file, err := os.OpenFile(compactPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		return gerror.Wrapf(err, `open compaction file "%s" failed`, compactPath)
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(compactPath)
+		}
+	}()
+	var (
+		offset int64
+		index  = make(map[string]*indexItem, len(db.index))
+		writer = bufio.NewWriterSize(file, 64*1024)
+		value  []byte
+		buffer []byte
+	)
+	for key, item := range db.index {
+		if value, err = db.readValue(item); err != nil {
+			return err
+		}
+		r := &record{
+			flag:   recordFlagValue,
+			expire: item.expire,
+			key:    []byte(key),
+			value:  value,
+		}
+		buffer = r.encode()
+		if _, err = writer.Write(buffer); err != nil {
+			return gerror.Wrapf(err, `write compaction file "%s" failed`, compactPath)
+		}
+		index[key] = &indexItem{
+			valueOffset: offset + recordHeaderSize + int64(len(key)),
+			valueSize:   len(value),
+			recordSize:  int64(len(buffer)),
+			expire:      item.expire,
+		}
+		offset += int64(len(buffer))
+	}
+	if err = writer.Flush(); err != nil {
+		return gerror.Wrapf(err, `write compaction file "%s" failed`, compactPath)
+	}
+	if err = file.Sync(); err != nil {
+		return gerror.Wrapf(err, `sync compaction file "%s" failed`, compactPath)
+	}
+	if err = file.Close(); err != nil {
+		return gerror.Wrapf(err, `close compaction file "%s" failed`, compactPath)
+	}
+	// The data file should be closed before renaming on some platforms.
+	if err = db.file.Close(); err != nil {
+		return gerror.Wrapf(err, `close data file "%s" failed`, db.path)
+	}
+	if err = os.Rename(compactPath, db.path); err != nil {
+		err = gerror.Wrapf(err, `rename compaction file "%s" to "%s" failed`, compactPath, db.path)
+		if openErr := db.openFile(); openErr != nil {
+			db.closed = true
+		}
+		return err
+	}
+	if err = db.openFile(); err != nil {
+		db.closed = true
+		return err
+	}
+	db.index = index
+	db.size = offset
+	db.garbage = 0
+	db.dirty = false
+	return nil
+}
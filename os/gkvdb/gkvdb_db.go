@@ -0,0 +1,324 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gkvdb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// Stats is the statistics of DB.
+type Stats struct {
+	Keys        int   // Number of keys, including the expired keys not removed yet.
+	FileSize    int64 // Size of the data file.
+	GarbageSize int64 // Size of the records that are superseded, deleted or expired.
+}
+
+// Get retrieves and returns the value of `key`.
+// It returns nil if `key` does not exist or is expired.
+func (db *DB) Get(key string) ([]byte, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return nil, ErrClosed
+	}
+	item := db.getItem(key, time.Now().UnixMilli())
+	if item == nil {
+		return nil, nil
+	}
+	return db.readValue(item)
+}
+
+// Set sets `key` with `value`, which expires after `ttl`.
+//
+// It does not expire if `ttl` == 0.
+// It deletes the `key` if `ttl` < 0.
+func (db *DB) Set(key string, value []byte, ttl time.Duration) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	if ttl < 0 {
+		return db.doDelete(key)
+	}
+	return db.doSet(key, value, db.getExpire(ttl))
+}
+
+// SetIfNotExist sets `key` with `value` which expires after `ttl` if `key` does not exist.
+// It returns true if `key` does not exist and `value` is set successfully.
+//
+// It does not expire if `ttl` == 0.
+// It deletes the `key` if `ttl` < 0.
+func (db *DB) SetIfNotExist(key string, value []byte, ttl time.Duration) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return false, ErrClosed
+	}
+	if ttl < 0 {
+		return false, db.doDelete(key)
+	}
+	if db.getItem(key, time.Now().UnixMilli()) != nil {
+		return false, nil
+	}
+	if err := db.doSet(key, value, db.getExpire(ttl)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Update updates the value of `key` without changing its expiration and returns the old value.
+// The returned value `exist` is false and it does nothing if `key` does not exist.
+func (db *DB) Update(key string, value []byte) (oldValue []byte, exist bool, err error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return nil, false, ErrClosed
+	}
+	item := db.getItem(key, time.Now().UnixMilli())
+	if item == nil {
+		return nil, false, nil
+	}
+	if oldValue, err = db.readValue(item); err != nil {
+		return nil, false, err
+	}
+	if err = db.doSet(key, value, item.expire); err != nil {
+		return nil, false, err
+	}
+	return oldValue, true, nil
+}
+
+// GetExpire retrieves and returns the remaining time to live of `key`.
+//
+// It returns 0 if `key` does not expire.
+// It returns -1 if `key` does not exist.
+func (db *DB) GetExpire(key string) (time.Duration, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return -1, ErrClosed
+	}
+	now := time.Now().UnixMilli()
+	return db.getTTL(db.getItem(key, now), now), nil
+}
+
+// UpdateExpire updates the expiration of `key` and returns the old time to live.
+//
+// It returns -1 and does nothing if `key` does not exist.
+// It removes the expiration of `key` if `ttl` == 0.
+// It deletes the `key` if `ttl` < 0.
+func (db *DB) UpdateExpire(key string, ttl time.Duration) (oldTTL time.Duration, err error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return -1, ErrClosed
+	}
+	var (
+		now  = time.Now().UnixMilli()
+		item = db.getItem(key, now)
+	)
+	if oldTTL = db.getTTL(item, now); item == nil {
+		return
+	}
+	if ttl < 0 {
+		return oldTTL, db.doDelete(key)
+	}
+	// It writes only the expiration instead of the whole value.
+	return oldTTL, db.write(&record{
+		flag:   recordFlagExpire,
+		expire: db.getExpire(ttl),
+		key:    []byte(key),
+	})
+}
+
+// Delete deletes `keys`.
+func (db *DB) Delete(keys ...string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	for _, key := range keys {
+		if err := db.doDelete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Contains checks and returns whether `key` exists.
+func (db *DB) Contains(key string) (bool, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return false, ErrClosed
+	}
+	return db.getItem(key, time.Now().UnixMilli()) != nil, nil
+}
+
+// Keys returns all keys with `prefix`, and it returns all keys if `prefix` is empty.
+func (db *DB) Keys(prefix string) ([]string, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return nil, ErrClosed
+	}
+	var (
+		now  = time.Now().UnixMilli()
+		keys = make([]string, 0)
+	)
+	for key, item := range db.index {
+		if strings.HasPrefix(key, prefix) && !item.isExpired(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Iterate calls `f` with every key with `prefix` and its value until `f` returns false.
+// It iterates all keys if `prefix` is empty.
+//
+// Note that `f` is called within reading lock, so it should not write the DB.
+func (db *DB) Iterate(prefix string, f func(key string, value []byte) bool) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return ErrClosed
+	}
+	now := time.Now().UnixMilli()
+	for key, item := range db.index {
+		if !strings.HasPrefix(key, prefix) || item.isExpired(now) {
+			continue
+		}
+		value, err := db.readValue(item)
+		if err != nil {
+			return err
+		}
+		if !f(key, value) {
+			break
+		}
+	}
+	return nil
+}
+
+// Clear deletes all keys and truncates the data file.
+func (db *DB) Clear() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	if err := db.file.Truncate(0); err != nil {
+		return gerror.Wrapf(err, `truncate data file "%s" failed`, db.path)
+	}
+	db.index = make(map[string]*indexItem)
+	db.size = 0
+	db.garbage = 0
+	db.dirty = true
+	return db.syncIfNecessary()
+}
+
+// Stats returns the statistics of DB.
+func (db *DB) Stats() Stats {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return Stats{
+		Keys:        len(db.index),
+		FileSize:    db.size,
+		GarbageSize: db.garbage,
+	}
+}
+
+// doSet writes the value record of `key`.
+func (db *DB) doSet(key string, value []byte, expire int64) error {
+	if key == "" {
+		return gerror.NewCode(gcode.CodeInvalidParameter, "empty key is not allowed")
+	}
+	return db.write(&record{
+		flag:   recordFlagValue,
+		expire: expire,
+		key:    []byte(key),
+		value:  value,
+	})
+}
+
+// doDelete writes the deleting record of `key` if it exists.
+func (db *DB) doDelete(key string) error {
+	if _, ok := db.index[key]; !ok {
+		return nil
+	}
+	return db.write(&record{
+		flag: recordFlagDelete,
+		key:  []byte(key),
+	})
+}
+
+// write appends record `r` to the data file and applies it to the index.
+func (db *DB) write(r *record) error {
+	buffer := r.encode()
+	// The broken record written partially is overwritten by next writing.
+	if _, err := db.file.WriteAt(buffer, db.size); err != nil {
+		return gerror.Wrapf(err, `write data file "%s" failed`, db.path)
+	}
+	db.apply(r, db.size, int64(len(buffer)))
+	db.size += int64(len(buffer))
+	db.dirty = true
+	return db.syncIfNecessary()
+}
+
+// syncIfNecessary syncs the data file for SyncPolicyAlways.
+func (db *DB) syncIfNecessary() error {
+	if db.option.SyncPolicy == SyncPolicyAlways {
+		return db.doSync()
+	}
+	return nil
+}
+
+// getItem returns the index item of `key` if it exists and is not expired at `now`.
+func (db *DB) getItem(key string, now int64) *indexItem {
+	if item, ok := db.index[key]; ok && !item.isExpired(now) {
+		return item
+	}
+	return nil
+}
+
+// readValue reads the value of `item` from data file.
+func (db *DB) readValue(item *indexItem) ([]byte, error) {
+	value := make([]byte, item.valueSize)
+	if _, err := db.file.ReadAt(value, item.valueOffset); err != nil {
+		return nil, gerror.Wrapf(err, `read data file "%s" failed`, db.path)
+	}
+	return value, nil
+}
+
+// getExpire returns the expiration timestamp in milliseconds after `ttl`, which is 0 if `ttl` is 0.
+func (db *DB) getExpire(ttl time.Duration) int64 {
+	if ttl == 0 {
+		return 0
+	}
+	if ms := ttl.Milliseconds(); ms > 0 {
+		return time.Now().UnixMilli() + ms
+	}
+	return time.Now().UnixMilli() + 1
+}
+
+// getTTL returns the remaining time to live of `item` at `now`.
+// It returns -1 if `item` is nil, and 0 if it does not expire.
+func (db *DB) getTTL(item *indexItem, now int64) time.Duration {
+	switch {
+	case item == nil:
+		return -1
+	case item.expire == 0:
+		return 0
+	default:
+		return time.Duration(item.expire-now) * time.Millisecond
+	}
+}
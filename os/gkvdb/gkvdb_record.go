@@ -0,0 +1,176 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gkvdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+)
+
+// Record layout in data file:
+// | crc32(4) | flag(1) | expire(8) | key size(4) | value size(4) | key | value |
+// The crc32 checksum covers all the following bytes of the record.
+const (
+	recordHeaderSize = 21
+	recordCrcSize    = 4
+)
+
+// Record flags.
+const (
+	recordFlagValue  byte = iota // Record of the key value.
+	recordFlagDelete             // Record deleting the key.
+	recordFlagExpire             // Record updating the expiration of the key without value.
+)
+
+// record is the decoded record of data file.
+type record struct {
+	flag   byte
+	expire int64
+	key    []byte
+	value  []byte
+}
+
+// encode encodes the record as bytes.
+func (r *record) encode() []byte {
+	buffer := make([]byte, recordHeaderSize+len(r.key)+len(r.value))
+	buffer[4] = r.flag
+	binary.BigEndian.PutUint64(buffer[5:], uint64(r.expire))
+	binary.BigEndian.PutUint32(buffer[13:], uint32(len(r.key)))
+	binary.BigEndian.PutUint32(buffer[17:], uint32(len(r.value)))
+	copy(buffer[recordHeaderSize:], r.key)
+	copy(buffer[recordHeaderSize+len(r.key):], r.value)
+	binary.BigEndian.PutUint32(buffer, crc32.ChecksumIEEE(buffer[recordCrcSize:]))
+	return buffer
+}
+
+// readRecord reads and decodes a record from `reader`, which has at most `remaining` bytes.
+// It returns io.ErrUnexpectedEOF if the record is incomplete or broken.
+func readRecord(reader io.Reader, remaining int64) (*record, int64, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if err == io.EOF {
+			return nil, 0, err
+		}
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	var (
+		r = &record{
+			flag:   header[4],
+			expire: int64(binary.BigEndian.Uint64(header[5:])),
+		}
+		keySize   = int64(binary.BigEndian.Uint32(header[13:]))
+		valueSize = int64(binary.BigEndian.Uint32(header[17:]))
+	)
+	if r.flag > recordFlagExpire || recordHeaderSize+keySize+valueSize > remaining {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	body := make([]byte, keySize+valueSize)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	checksum := crc32.ChecksumIEEE(header[recordCrcSize:])
+	checksum = crc32.Update(checksum, crc32.IEEETable, body)
+	if checksum != binary.BigEndian.Uint32(header) {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	r.key = body[:keySize]
+	r.value = body[keySize:]
+	return r, recordHeaderSize + keySize + valueSize, nil
+}
+
+// recover scans all records of the data file and builds the index.
+// The file is truncated at the first broken record, which is commonly caused by crashing in writing.
+func (db *DB) recover() error {
+	info, err := db.file.Stat()
+	if err != nil {
+		return gerror.Wrapf(err, `stat data file "%s" failed`, db.path)
+	}
+	var (
+		offset   int64
+		fileSize = info.Size()
+		reader   = bufio.NewReaderSize(io.NewSectionReader(db.file, 0, fileSize), 64*1024)
+	)
+	for {
+		r, size, err := readRecord(reader, fileSize-offset)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if err != io.ErrUnexpectedEOF {
+				return gerror.Wrapf(err, `read data file "%s" failed`, db.path)
+			}
+			intlog.Printf(context.TODO(), `truncate broken data file "%s" at offset %d`, db.path, offset)
+			if err = db.file.Truncate(offset); err != nil {
+				return gerror.Wrapf(err, `truncate data file "%s" failed`, db.path)
+			}
+			break
+		}
+		db.apply(r, offset, size)
+		offset += size
+	}
+	db.size = offset
+	// The expired items are removed after all records are applied,
+	// as their expiration might be updated by following records.
+	db.removeExpired(time.Now().UnixMilli())
+	return nil
+}
+
+// apply applies record `r` written at `offset` to the index.
+func (db *DB) apply(r *record, offset int64, size int64) {
+	var (
+		key  = string(r.key)
+		item = db.index[key]
+	)
+	switch r.flag {
+	case recordFlagValue:
+		if item != nil {
+			db.garbage += item.recordSize
+		}
+		db.index[key] = &indexItem{
+			valueOffset: offset + recordHeaderSize + int64(len(r.key)),
+			valueSize:   len(r.value),
+			recordSize:  size,
+			expire:      r.expire,
+		}
+
+	case recordFlagDelete:
+		if item != nil {
+			db.garbage += item.recordSize
+			delete(db.index, key)
+		}
+		db.garbage += size
+
+	case recordFlagExpire:
+		// The value record is rewritten with the expiration in compaction.
+		if item != nil {
+			item.expire = r.expire
+		}
+		db.garbage += size
+	}
+}
+
+// removeExpired removes all items expired before `now` from the index.
+func (db *DB) removeExpired(now int64) {
+	for key, item := range db.index {
+		if item.isExpired(now) {
+			db.garbage += item.recordSize
+			delete(db.index, key)
+		}
+	}
+}
+
+// isExpired checks and returns whether the item is expired at `now`.
+func (item *indexItem) isExpired(now int64) bool {
+	return item.expire > 0 && item.expire <= now
+}
@@ -0,0 +1,286 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gkvdb_test
+
+import (
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gkvdb"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func newTestPath() string {
+	return gfile.Temp("gkvdb", gtime.TimestampNanoStr(), "data.db")
+}
+
+func Test_DB_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := newTestPath()
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+
+		t.AssertNil(db.Set("k1", []byte("v1"), 0))
+		t.AssertNil(db.Set("k2", []byte("v2"), time.Hour))
+		t.AssertNE(db.Set("", []byte("v"), 0), nil)
+
+		v, err := db.Get("k1")
+		t.AssertNil(err)
+		t.Assert(v, "v1")
+		v, err = db.Get("none")
+		t.AssertNil(err)
+		t.AssertNil(v)
+
+		ok, err := db.SetIfNotExist("k1", []byte("v"), 0)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+		ok, err = db.SetIfNotExist("k3", []byte("v3"), 0)
+		t.AssertNil(err)
+		t.Assert(ok, true)
+
+		oldValue, exist, err := db.Update("k2", []byte("v22"))
+		t.AssertNil(err)
+		t.Assert(exist, true)
+		t.Assert(oldValue, "v2")
+		_, exist, err = db.Update("none", []byte("v"))
+		t.AssertNil(err)
+		t.Assert(exist, false)
+
+		ttl, err := db.GetExpire("k1")
+		t.AssertNil(err)
+		t.Assert(ttl, time.Duration(0))
+		ttl, err = db.GetExpire("k2")
+		t.AssertNil(err)
+		t.AssertGT(ttl, 59*time.Minute)
+		ttl, err = db.GetExpire("none")
+		t.AssertNil(err)
+		t.Assert(ttl, time.Duration(-1))
+
+		t.AssertNil(db.Delete("k3", "none"))
+		ok, err = db.Contains("k3")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+
+		keys, err := db.Keys("")
+		t.AssertNil(err)
+		sort.Strings(keys)
+		t.Assert(keys, []string{"k1", "k2"})
+
+		t.AssertNil(db.Close())
+		_, err = db.Get("k1")
+		t.Assert(err, gkvdb.ErrClosed)
+	})
+}
+
+func Test_DB_Expire(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := newTestPath()
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+
+		t.AssertNil(db.Set("k1", []byte("v1"), 100*time.Millisecond))
+		t.AssertNil(db.Set("k2", []byte("v2"), 100*time.Millisecond))
+		t.AssertNil(db.Set("k3", []byte("v3"), 0))
+		oldTTL, err := db.UpdateExpire("k2", time.Hour)
+		t.AssertNil(err)
+		t.AssertGT(oldTTL, 0)
+		oldTTL, err = db.UpdateExpire("k3", -1)
+		t.AssertNil(err)
+		t.Assert(oldTTL, time.Duration(0))
+		oldTTL, err = db.UpdateExpire("none", time.Hour)
+		t.AssertNil(err)
+		t.Assert(oldTTL, time.Duration(-1))
+
+		time.Sleep(200 * time.Millisecond)
+		v, err := db.Get("k1")
+		t.AssertNil(err)
+		t.AssertNil(v)
+		v, err = db.Get("k2")
+		t.AssertNil(err)
+		t.Assert(v, "v2")
+		v, err = db.Get("k3")
+		t.AssertNil(err)
+		t.AssertNil(v)
+		t.AssertNil(db.Close())
+
+		// The expiration updating is recovered.
+		db, err = gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+		keys, err := db.Keys("")
+		t.AssertNil(err)
+		t.Assert(keys, []string{"k2"})
+		ttl, err := db.GetExpire("k2")
+		t.AssertNil(err)
+		t.AssertGT(ttl, 59*time.Minute)
+	})
+}
+
+func Test_DB_Recover(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := newTestPath()
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path, gkvdb.Option{SyncPolicy: gkvdb.SyncPolicyAlways})
+		t.AssertNil(err)
+		t.AssertNil(db.Set("k1", []byte("v1"), 0))
+		t.AssertNil(db.Set("k2", []byte("v2"), 0))
+		t.AssertNil(db.Set("k1", []byte("v11"), 0))
+		t.AssertNil(db.Delete("k2"))
+		size := db.Stats().FileSize
+		t.AssertNil(db.Close())
+
+		// Broken record written partially.
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0666)
+		t.AssertNil(err)
+		_, err = file.Write([]byte{1, 2, 3, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0})
+		t.AssertNil(err)
+		t.AssertNil(file.Close())
+
+		db, err = gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+		t.Assert(gfile.Size(path), size)
+		v, err := db.Get("k1")
+		t.AssertNil(err)
+		t.Assert(v, "v11")
+		ok, err := db.Contains("k2")
+		t.AssertNil(err)
+		t.Assert(ok, false)
+		t.AssertNil(db.Set("k3", []byte("v3"), 0))
+		v, err = db.Get("k3")
+		t.AssertNil(err)
+		t.Assert(v, "v3")
+	})
+	// Broken checksum.
+	gtest.C(t, func(t *gtest.T) {
+		path := newTestPath()
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+		t.AssertNil(db.Set("k1", []byte("v1"), 0))
+		size := db.Stats().FileSize
+		t.AssertNil(db.Set("k2", []byte("v2"), 0))
+		t.AssertNil(db.Close())
+
+		content := gfile.GetBytes(path)
+		content[len(content)-1] = 'x'
+		t.AssertNil(gfile.PutBytes(path, content))
+
+		db, err = gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+		t.Assert(db.Stats().FileSize, size)
+		keys, err := db.Keys("")
+		t.AssertNil(err)
+		t.Assert(keys, []string{"k1"})
+	})
+}
+
+func Test_DB_Compact(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := newTestPath()
+		defer gfile.Remove(gfile.Dir(path))
+		// The compaction file left by crashing is removed.
+		t.AssertNil(gfile.PutContents(path+".compact", "broken"))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+		t.Assert(gfile.Exists(path+".compact"), false)
+
+		for i := 0; i < 100; i++ {
+			t.AssertNil(db.Set("k1", []byte("value"), 0))
+			t.AssertNil(db.Set("k2", []byte("value"), time.Millisecond))
+		}
+		t.AssertNil(db.Set("k3", []byte("v3"), time.Hour))
+		_, err = db.UpdateExpire("k3", 2*time.Hour)
+		t.AssertNil(err)
+		time.Sleep(10 * time.Millisecond)
+		stats := db.Stats()
+		t.AssertGT(stats.GarbageSize, 0)
+
+		t.AssertNil(db.Compact())
+		t.Assert(db.Stats().GarbageSize, 0)
+		t.Assert(db.Stats().Keys, 2)
+		t.AssertLT(db.Stats().FileSize, stats.FileSize)
+		t.Assert(gfile.Size(path), db.Stats().FileSize)
+		v, err := db.Get("k1")
+		t.AssertNil(err)
+		t.Assert(v, "value")
+		t.AssertNil(db.Set("k4", []byte("v4"), 0))
+		t.AssertNil(db.Close())
+
+		db, err = gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+		keys, err := db.Keys("")
+		t.AssertNil(err)
+		sort.Strings(keys)
+		t.Assert(keys, []string{"k1", "k3", "k4"})
+		ttl, err := db.GetExpire("k3")
+		t.AssertNil(err)
+		t.AssertGT(ttl, time.Hour)
+		t.Assert(db.Stats().GarbageSize, 0)
+	})
+	// Background compaction.
+	gtest.C(t, func(t *gtest.T) {
+		path := newTestPath()
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path, gkvdb.Option{
+			CompactInterval: 100 * time.Millisecond,
+			CompactMinSize:  1,
+		})
+		t.AssertNil(err)
+		defer db.Close()
+		for i := 0; i < 10; i++ {
+			t.AssertNil(db.Set("k", []byte("value"), 0))
+		}
+		time.Sleep(500 * time.Millisecond)
+		t.Assert(db.Stats().GarbageSize, 0)
+		t.Assert(db.Stats().Keys, 1)
+	})
+}
+
+func Test_DB_Iterate(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := newTestPath()
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+
+		t.AssertNil(db.Set("a:1", []byte("1"), 0))
+		t.AssertNil(db.Set("a:2", []byte("2"), 0))
+		t.AssertNil(db.Set("b:1", []byte("3"), 0))
+		data := make(map[string]string)
+		t.AssertNil(db.Iterate("a:", func(key string, value []byte) bool {
+			data[key] = string(value)
+			return true
+		}))
+		t.Assert(data, map[string]string{"a:1": "1", "a:2": "2"})
+
+		count := 0
+		t.AssertNil(db.Iterate("", func(key string, value []byte) bool {
+			count++
+			return false
+		}))
+		t.Assert(count, 1)
+
+		t.AssertNil(db.Clear())
+		t.Assert(db.Stats().Keys, 0)
+		t.Assert(gfile.Size(path), 0)
+		t.AssertNil(db.Set("c", []byte("c"), 0))
+		v, err := db.Get("c")
+		t.AssertNil(err)
+		t.Assert(v, "c")
+	})
+}
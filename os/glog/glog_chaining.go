@@ -21,6 +21,18 @@ func To(writer io.Writer) *Logger {
 	return defaultLogger.To(writer)
 }
 
+// With is a chaining function,
+// which adds key-value pairs `keyValues` as fields for the logging content output.
+func With(keyValues ...any) *Logger {
+	return defaultLogger.With(keyValues...)
+}
+
+// Fields is a chaining function,
+// which adds `fields` as fields for the logging content output.
+func Fields(fields map[string]any) *Logger {
+	return defaultLogger.Fields(fields)
+}
+
 // Path is a chaining function,
 // which sets the directory path to `path` for current logging content output.
 func Path(path string) *Logger {
@@ -63,6 +63,16 @@ func GetWriter() io.Writer {
 	return defaultLogger.GetWriter()
 }
 
+// AddSink adds `sink` to the defaultLogger with optional `config` for buffering and sending.
+func AddSink(sink Sink, config ...SinkConfig) *SinkWriter {
+	return defaultLogger.AddSink(sink, config...)
+}
+
+// CloseSinks flushes and closes all Sinks of the defaultLogger.
+func CloseSinks(ctx context.Context) error {
+	return defaultLogger.CloseSinks(ctx)
+}
+
 // SetDebug enables/disables the debug level for default defaultLogger.
 // The debug level is enabled in default.
 func SetDebug(debug bool) {
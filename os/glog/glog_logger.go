@@ -34,8 +34,11 @@ import (
 
 // Logger is the struct for logging management.
 type Logger struct {
-	parent *Logger // Parent logger, if it is not empty, it means the logger is used in chaining function.
-	config Config  // Logger configuration.
+	parent *Logger       // Parent logger, if it is not empty, it means the logger is used in chaining function.
+	config Config        // Logger configuration.
+	fields []Field       // Fields added by chaining function With or Fields.
+	sinks  *loggerSinks  // Remote logging sinks, which are shared with the cloned loggers.
+	dedupe *loggerDedupe // Deduplication state, which is shared with the cloned loggers.
 }
 
 const (
@@ -63,6 +66,8 @@ const (
 func New() *Logger {
 	return &Logger{
 		config: DefaultConfig(),
+		sinks:  &loggerSinks{},
+		dedupe: newLoggerDedupe(),
 	}
 }
 
@@ -79,6 +84,9 @@ func (l *Logger) Clone() *Logger {
 	return &Logger{
 		config: l.config,
 		parent: l,
+		fields: l.fields,
+		sinks:  l.sinks,
+		dedupe: l.dedupe,
 	}
 }
 
@@ -94,12 +102,13 @@ func (l *Logger) getFilePath(now time.Time) string {
 }
 
 // print prints `s` to defined writer, logging file or passed `std`.
-func (l *Logger) print(ctx context.Context, level int, stack string, values ...any) {
+// The parameter `format` is the format string of formatted logging like Infof, which is empty for others.
+func (l *Logger) print(ctx context.Context, level int, stack, format string, values ...any) {
 	// Lazy initialize for rotation feature.
 	// It uses atomic reading operation to enhance the performance checking.
 	// It here uses CAP for performance and concurrent safety.
 	// It just initializes once for each logger.
-	if l.config.RotateSize > 0 || l.config.RotateExpire > 0 {
+	if l.config.RotateSize > 0 || l.config.RotateExpire > 0 || l.config.RotateCalendar != "" {
 		if !l.config.rotatedHandlerInitialized.Val() && l.config.rotatedHandlerInitialized.Cas(false, true) {
 			l.rotateChecksTimely(ctx)
 			intlog.Printf(ctx, "logger rotation initialized: every %s", l.config.RotateCheckInterval.String())
@@ -118,11 +127,15 @@ func (l *Logger) print(ctx context.Context, level int, stack string, values ...a
 			Color:  defaultLevelColor[level],
 			Level:  level,
 			Stack:  stack,
+			Format: format,
 			Values: values,
 		}
 	)
 
 	// Logging handlers.
+	if l.config.DedupeLimit > 0 {
+		input.handlers = append(input.handlers, HandlerDedupe)
+	}
 	if len(l.config.Handlers) > 0 {
 		input.handlers = append(input.handlers, l.config.Handlers...)
 	} else if defaultHandler != nil {
@@ -191,6 +204,9 @@ func (l *Logger) print(ctx context.Context, level int, stack string, values ...a
 		if traceId := spanCtx.TraceID(); traceId.IsValid() {
 			input.TraceId = traceId.String()
 		}
+		if spanId := spanCtx.SpanID(); spanId.IsValid() {
+			input.SpanId = spanId.String()
+		}
 		// Context values.
 		if len(l.config.CtxKeys) > 0 {
 			for _, ctxKey := range l.config.CtxKeys {
@@ -207,6 +223,9 @@ func (l *Logger) print(ctx context.Context, level int, stack string, values ...a
 			}
 		}
 	}
+	// Fields.
+	input.Fields = l.getFields(ctx)
+
 	if l.config.Flags&F_ASYNC > 0 {
 		input.IsAsync = true
 		err := asyncPool.Add(ctx, func(ctx context.Context) {
@@ -244,6 +263,13 @@ func (l *Logger) doFinalPrint(ctx context.Context, input *HandlerInput) *bytes.B
 			buffer = buf
 		}
 	}
+
+	// Output to remote sinks.
+	if writers := l.sinks.Load(); len(writers) > 0 {
+		if buf := l.printToSinks(ctx, input, writers); buf != nil {
+			buffer = buf
+		}
+	}
 	return buffer
 }
 
@@ -288,8 +314,8 @@ func (l *Logger) printToFile(ctx context.Context, t time.Time, in *HandlerInput)
 	gmlock.Lock(memoryLockKey)
 	defer gmlock.Unlock(memoryLockKey)
 
-	// Rotation file size checks.
-	if l.config.RotateSize > 0 && gfile.Size(logFilePath) > l.config.RotateSize {
+	// Rotation file size and calendar checks.
+	if l.isRotationNeeded(logFilePath, t) {
 		if runtime.GOOS == "windows" {
 			file := l.createFpInPool(ctx, logFilePath)
 			if file == nil {
@@ -304,12 +330,12 @@ func (l *Logger) printToFile(ctx context.Context, t time.Time, in *HandlerInput)
 			if err := file.Close(true); err != nil {
 				intlog.Errorf(ctx, `%+v`, err)
 			}
-			l.rotateFileBySize(ctx, t)
+			l.rotateFile(ctx, logFilePath)
 
 			return buffer
 		}
 
-		l.rotateFileBySize(ctx, t)
+		l.rotateFile(ctx, logFilePath)
 	}
 	// Logging content outputting to disk file.
 	if file := l.createFpInPool(ctx, logFilePath); file == nil {
@@ -360,7 +386,16 @@ func (l *Logger) printStd(ctx context.Context, level int, values ...interface{})
 	if l == nil {
 		return
 	}
-	l.print(ctx, level, "", values...)
+	l.print(ctx, level, "", "", values...)
+}
+
+// printStdf prints content formatted with `format` without stack.
+func (l *Logger) printStdf(ctx context.Context, level int, format string, values ...interface{}) {
+	// nil logger, print nothing
+	if l == nil {
+		return
+	}
+	l.print(ctx, level, "", format, l.format(format, values...))
 }
 
 // printErr prints content `s` with stack check.
@@ -374,7 +409,20 @@ func (l *Logger) printErr(ctx context.Context, level int, values ...interface{})
 		stack = l.GetStack()
 	}
 	// In matter of sequence, do not use stderr here, but use the same stdout.
-	l.print(ctx, level, stack, values...)
+	l.print(ctx, level, stack, "", values...)
+}
+
+// printErrf prints content formatted with `format` with stack check.
+func (l *Logger) printErrf(ctx context.Context, level int, format string, values ...interface{}) {
+	// nil logger, print nothing
+	if l == nil {
+		return
+	}
+	var stack string
+	if l.config.StStatus == 1 {
+		stack = l.GetStack()
+	}
+	l.print(ctx, level, stack, format, l.format(format, values...))
 }
 
 // format formats `values` using fmt.Sprintf.
@@ -21,7 +21,7 @@ func (l *Logger) Print(ctx context.Context, v ...interface{}) {
 // Printf prints `v` with format `format` using fmt.Sprintf.
 // The parameter `v` can be multiple variables.
 func (l *Logger) Printf(ctx context.Context, format string, v ...interface{}) {
-	l.printStd(ctx, LEVEL_NONE, l.format(format, v...))
+	l.printStdf(ctx, LEVEL_NONE, format, v...)
 }
 
 // Fatal prints the logging content with [FATA] header and newline, then exit the current process.
@@ -32,7 +32,7 @@ func (l *Logger) Fatal(ctx context.Context, v ...interface{}) {
 
 // Fatalf prints the logging content with [FATA] header, custom format and newline, then exit the current process.
 func (l *Logger) Fatalf(ctx context.Context, format string, v ...interface{}) {
-	l.printErr(ctx, LEVEL_FATA, l.format(format, v...))
+	l.printErrf(ctx, LEVEL_FATA, format, v...)
 	os.Exit(1)
 }
 
@@ -44,7 +44,7 @@ func (l *Logger) Panic(ctx context.Context, v ...interface{}) {
 
 // Panicf prints the logging content with [PANI] header, custom format and newline, then panics.
 func (l *Logger) Panicf(ctx context.Context, format string, v ...interface{}) {
-	l.printErr(ctx, LEVEL_PANI, l.format(format, v...))
+	l.printErrf(ctx, LEVEL_PANI, format, v...)
 	panic(l.format(format, v...))
 }
 
@@ -58,7 +58,7 @@ func (l *Logger) Info(ctx context.Context, v ...interface{}) {
 // Infof prints the logging content with [INFO] header, custom format and newline.
 func (l *Logger) Infof(ctx context.Context, format string, v ...interface{}) {
 	if l.checkLevel(LEVEL_INFO) {
-		l.printStd(ctx, LEVEL_INFO, l.format(format, v...))
+		l.printStdf(ctx, LEVEL_INFO, format, v...)
 	}
 }
 
@@ -72,7 +72,7 @@ func (l *Logger) Debug(ctx context.Context, v ...interface{}) {
 // Debugf prints the logging content with [DEBU] header, custom format and newline.
 func (l *Logger) Debugf(ctx context.Context, format string, v ...interface{}) {
 	if l.checkLevel(LEVEL_DEBU) {
-		l.printStd(ctx, LEVEL_DEBU, l.format(format, v...))
+		l.printStdf(ctx, LEVEL_DEBU, format, v...)
 	}
 }
 
@@ -88,7 +88,7 @@ func (l *Logger) Notice(ctx context.Context, v ...interface{}) {
 // It also prints caller stack info if stack feature is enabled.
 func (l *Logger) Noticef(ctx context.Context, format string, v ...interface{}) {
 	if l.checkLevel(LEVEL_NOTI) {
-		l.printStd(ctx, LEVEL_NOTI, l.format(format, v...))
+		l.printStdf(ctx, LEVEL_NOTI, format, v...)
 	}
 }
 
@@ -104,7 +104,7 @@ func (l *Logger) Warning(ctx context.Context, v ...interface{}) {
 // It also prints caller stack info if stack feature is enabled.
 func (l *Logger) Warningf(ctx context.Context, format string, v ...interface{}) {
 	if l.checkLevel(LEVEL_WARN) {
-		l.printStd(ctx, LEVEL_WARN, l.format(format, v...))
+		l.printStdf(ctx, LEVEL_WARN, format, v...)
 	}
 }
 
@@ -120,7 +120,7 @@ func (l *Logger) Error(ctx context.Context, v ...interface{}) {
 // It also prints caller stack info if stack feature is enabled.
 func (l *Logger) Errorf(ctx context.Context, format string, v ...interface{}) {
 	if l.checkLevel(LEVEL_ERRO) {
-		l.printErr(ctx, LEVEL_ERRO, l.format(format, v...))
+		l.printErrf(ctx, LEVEL_ERRO, format, v...)
 	}
 }
 
@@ -136,7 +136,7 @@ func (l *Logger) Critical(ctx context.Context, v ...interface{}) {
 // It also prints caller stack info if stack feature is enabled.
 func (l *Logger) Criticalf(ctx context.Context, format string, v ...interface{}) {
 	if l.checkLevel(LEVEL_CRIT) {
-		l.printErr(ctx, LEVEL_CRIT, l.format(format, v...))
+		l.printErrf(ctx, LEVEL_CRIT, format, v...)
 	}
 }
 
@@ -35,18 +35,24 @@ type Config struct {
 	StStatus             int            `json:"stStatus"`             // Stack status(1: enabled - default; 0: disabled)
 	StFilter             string         `json:"stFilter"`             // Stack string filter.
 	CtxKeys              []interface{}  `json:"ctxKeys"`              // Context keys for logging, which is used for value retrieving from context.
+	BaggageKeys          []string       `json:"baggageKeys"`          // Tracing baggage keys for logging, whose values are retrieved from context and output as fields.
 	HeaderPrint          bool           `json:"header"`               // Print header or not(true in default).
 	StdoutPrint          bool           `json:"stdout"`               // Output to stdout or not(true in default).
 	LevelPrint           bool           `json:"levelPrint"`           // Print level format string or not(true in default).
 	LevelPrefixes        map[int]string `json:"levelPrefixes"`        // Logging level to its prefix string mapping.
 	RotateSize           int64          `json:"rotateSize"`           // Rotate the logging file if its size > 0 in bytes.
 	RotateExpire         time.Duration  `json:"rotateExpire"`         // Rotate the logging file if its mtime exceeds this duration.
-	RotateBackupLimit    int            `json:"rotateBackupLimit"`    // Max backup for rotated files, default is 0, means no backups.
+	RotateCalendar       string         `json:"rotateCalendar"`       // Rotate the logging file at calendar boundary in local time, which is "hourly" or "daily". It's empty in default, means disabled.
+	RotateBackupLimit    int            `json:"rotateBackupLimit"`    // Max backup for rotated files, default is 0, means no backups unless RotateBackupMaxBytes is set.
 	RotateBackupExpire   time.Duration  `json:"rotateBackupExpire"`   // Max expires for rotated files, which is 0 in default, means no expiration.
+	RotateBackupMaxBytes int64          `json:"rotateBackupMaxBytes"` // Max total size in bytes for rotated files, the oldest ones are removed if exceeded. It's 0 in default, means no limitation. It keeps backups limited by size only if RotateBackupLimit is 0.
 	RotateBackupCompress int            `json:"rotateBackupCompress"` // Compress level for rotated files using gzip algorithm. It's 0 in default, means no compression.
+	RotateBackupFormat   string         `json:"rotateBackupFormat"`   // Compress format for rotated files, which is "gzip" in default, or custom format registered by RegisterRotateCompressor.
 	RotateCheckInterval  time.Duration  `json:"rotateCheckInterval"`  // Asynchronously checks the backups and expiration at intervals. It's 1 hour in default.
 	StdoutColorDisabled  bool           `json:"stdoutColorDisabled"`  // Logging level prefix with color to writer or not (false in default).
 	WriterColorEnable    bool           `json:"writerColorEnable"`    // Logging level prefix with color to writer or not (false in default).
+	DedupeLimit          int            `json:"dedupeLimit"`          // Max occurrences of similar logging content in each DedupeInterval, others are suppressed. It's 0 in default, means no deduplication.
+	DedupeInterval       time.Duration  `json:"dedupeInterval"`       // Interval for deduplication, after which the suppressed count is summarized. It's 1 minute in default.
 	internalConfig
 }
 
@@ -88,6 +94,15 @@ func (l *Logger) GetConfig() Config {
 
 // SetConfig set configurations for the logger.
 func (l *Logger) SetConfig(config Config) error {
+	// Rotation configuration validation.
+	switch config.RotateCalendar {
+	case "", RotateCalendarHourly, RotateCalendarDaily:
+	default:
+		return gerror.NewCodef(gcode.CodeInvalidConfiguration, `invalid rotate calendar: %s`, config.RotateCalendar)
+	}
+	if config.RotateBackupFormat != "" && getRotateCompressor(config.RotateBackupFormat) == nil {
+		return gerror.NewCodef(gcode.CodeInvalidConfiguration, `invalid rotate backup format: %s`, config.RotateBackupFormat)
+	}
 	l.config = config
 	// Necessary validation.
 	if config.Path != "" {
@@ -125,10 +140,40 @@ func (l *Logger) SetConfigWithMap(m map[string]interface{}) error {
 			return gerror.NewCodef(gcode.CodeInvalidConfiguration, `invalid rotate size: %v`, rotateSizeValue)
 		}
 	}
-	if err := gconv.Struct(m, &l.config); err != nil {
+	// Change string configuration to int value for backup files size limitation.
+	backupMaxBytesKey, backupMaxBytesValue := gutil.MapPossibleItemByKey(m, "RotateBackupMaxBytes")
+	if backupMaxBytesValue != nil {
+		m[backupMaxBytesKey] = gfile.StrToSize(gconv.String(backupMaxBytesValue))
+		if m[backupMaxBytesKey] == -1 {
+			return gerror.NewCodef(gcode.CodeInvalidConfiguration, `invalid rotate backup max bytes: %v`, backupMaxBytesValue)
+		}
+	}
+	// The sinks configuration is removed from `m`, which is not the attribute of Config.
+	sinkConfigs, hasSinks, err := getSinkConfigsWithMap(m)
+	if err != nil {
+		return err
+	}
+	config := l.config
+	if err = gconv.Struct(m, &config); err != nil {
+		return err
+	}
+	// The sinks are created and replace the previous ones only if all configuration is valid.
+	var sinks []Sink
+	if hasSinks {
+		if sinks, err = newSinks(sinkConfigs); err != nil {
+			return err
+		}
+	}
+	if err = l.SetConfig(config); err != nil {
+		for _, sink := range sinks {
+			_ = sink.Close()
+		}
 		return err
 	}
-	return l.SetConfig(l.config)
+	if hasSinks {
+		return l.replaceSinks(context.TODO(), sinks, sinkConfigs)
+	}
+	return nil
 }
 
 // SetDebug enables/disables the debug level for logger.
@@ -0,0 +1,143 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/net/gtrace"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+// Field is the typed key-value pair for structured logging, which is output as separate
+// json key by HandlerJson, or logfmt pair by HandlerStructure and default handler.
+type Field struct {
+	Key   string // Key of the field.
+	Value any    // Value of the field, which keeps its type in json output.
+}
+
+// With is a chaining function, which adds key-value pairs `keyValues` as fields for the logging content
+// output, like: With("uid", 1, "name", "john"). The last key without value is added with nil value.
+//
+// Unlike other chaining functions, it always returns a new logger, so that the returned logger
+// can be stored and reused without affecting the current logger.
+func (l *Logger) With(keyValues ...any) *Logger {
+	fields := make([]Field, 0, (len(keyValues)+1)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		field := Field{Key: gconv.String(keyValues[i])}
+		if i+1 < len(keyValues) {
+			field.Value = keyValues[i+1]
+		}
+		fields = append(fields, field)
+	}
+	return l.withFields(fields)
+}
+
+// Fields is a chaining function, which adds `fields` as fields for the logging content output.
+// The fields are added in order of their keys.
+//
+// Unlike other chaining functions, it always returns a new logger, so that the returned logger
+// can be stored and reused without affecting the current logger.
+func (l *Logger) Fields(fields map[string]any) *Logger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	array := make([]Field, 0, len(keys))
+	for _, key := range keys {
+		array = append(array, Field{Key: key, Value: fields[key]})
+	}
+	return l.withFields(array)
+}
+
+// withFields returns a cloned logger with `fields` appended, in which the field of the same key is replaced.
+func (l *Logger) withFields(fields []Field) *Logger {
+	logger := l.Clone()
+	logger.fields = appendFields(make([]Field, 0, len(l.fields)+len(fields)), l.fields...)
+	logger.fields = appendFields(logger.fields, fields...)
+	return logger
+}
+
+// getFields returns the fields of the logger and the baggage entries of Config.BaggageKeys from `ctx`.
+func (l *Logger) getFields(ctx context.Context) []Field {
+	if len(l.config.BaggageKeys) == 0 || ctx == nil {
+		return l.fields
+	}
+	var fields []Field
+	for _, key := range l.config.BaggageKeys {
+		if value := gtrace.GetBaggageVar(ctx, key); !value.IsEmpty() {
+			fields = append(fields, Field{Key: key, Value: value.String()})
+		}
+	}
+	if len(fields) == 0 {
+		return l.fields
+	}
+	return appendFields(append([]Field(nil), l.fields...), fields...)
+}
+
+// appendFields appends `fields` to `array`, in which the field of the same key is replaced.
+func appendFields(array []Field, fields ...Field) []Field {
+	for _, field := range fields {
+		replaced := false
+		for i := range array {
+			if array[i].Key == field.Key {
+				array[i] = field
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			array = append(array, field)
+		}
+	}
+	return array
+}
+
+// marshalJsonWithFields marshals `output` to json, with `fields` as its extra keys.
+// The field is ignored if its key conflicts with the keys of `output`.
+func marshalJsonWithFields(output HandlerOutputJson, fields []Field) ([]byte, error) {
+	jsonBytes, err := json.Marshal(output)
+	if err != nil || len(fields) == 0 {
+		return jsonBytes, err
+	}
+	var (
+		buffer   = bytes.NewBuffer(jsonBytes[:len(jsonBytes)-1])
+		reserved = map[string]struct{}{
+			structureKeyTime: {}, structureKeyLevel: {}, structureKeyPrefix: {}, structureKeyContent: {},
+			structureKeyTraceId: {}, structureKeySpanId: {}, structureKeyCallerFunc: {},
+			structureKeyCallerPath: {}, structureKeyCtxStr: {}, structureKeyStack: {},
+		}
+	)
+	for _, field := range fields {
+		if _, ok := reserved[field.Key]; ok {
+			continue
+		}
+		keyBytes, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(field.Value)
+		if err != nil {
+			// The value that cannot be marshaled is output as string.
+			if valueBytes, err = json.Marshal(gconv.String(field.Value)); err != nil {
+				return nil, err
+			}
+		}
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+		buffer.Write(keyBytes)
+		buffer.WriteByte(':')
+		buffer.Write(valueBytes)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
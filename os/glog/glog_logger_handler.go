@@ -65,6 +65,9 @@ type HandlerInput struct {
 	// Trace id, only available if OpenTelemetry is enabled, or else it's an empty string.
 	TraceId string
 
+	// Span id, only available if OpenTelemetry is enabled, or else it's an empty string.
+	SpanId string
+
 	// Custom prefix string in logging content header part.
 	// Note that, it takes no effect if HeaderPrint is disabled.
 	Prefix string
@@ -72,9 +75,17 @@ type HandlerInput struct {
 	// Custom logging content for logging.
 	Content string
 
+	// (ReadOnly) The format string of formatted logging like Infof,
+	// which is empty for non-formatted logging.
+	Format string
+
 	// The passed un-formatted values array to logger.
 	Values []any
 
+	// Fields added by chaining function With or Fields, and the baggage entries of Config.BaggageKeys.
+	// They are output as separate keys in json, or as logfmt pairs in other handlers.
+	Fields []Field
+
 	// Stack string produced by logger, only available if Config.StStatus configured.
 	// Note that there are usually multiple lines in stack content.
 	Stack string
@@ -86,6 +97,7 @@ type HandlerInput struct {
 type internalHandlerInfo struct {
 	index    int       // Middleware handling index for internal usage.
 	handlers []Handler // Handler array calling bu index.
+	deduped  bool      // Whether it is handled by HandlerDedupe.
 }
 
 // defaultHandler is the default handler for package.
@@ -198,6 +210,13 @@ func (in *HandlerInput) getDefaultBuffer(withColor bool) *bytes.Buffer {
 		in.addStringToBuffer(buffer, in.ValuesContent())
 	}
 
+	if len(in.Fields) > 0 {
+		fieldsBuffer := &structuredBuffer{in: in, buffer: buffer}
+		for _, field := range in.Fields {
+			fieldsBuffer.addValue(field.Key, field.Value)
+		}
+	}
+
 	if in.Stack != "" {
 		in.addStringToBuffer(buffer, "\nStack:\n"+in.Stack)
 	}
@@ -0,0 +1,198 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/os/gtimer"
+)
+
+// loggerDedupe is the deduplication state of logger, which is shared by the logger and its clones.
+type loggerDedupe struct {
+	mu        sync.Mutex
+	entries   map[string]*dedupeEntry // entries is the deduplication key to its occurrences mapping.
+	scheduled bool                    // scheduled marks the timer for summarizing scheduled.
+}
+
+// dedupeEntry is the occurrences of similar logging content in current interval.
+type dedupeEntry struct {
+	logger     *Logger       // logger is the last logger printing the content, which prints the summary.
+	level      int           // level is the logging level.
+	sample     string        // sample is the format string or content for summary.
+	interval   time.Duration // interval is the deduplication interval.
+	start      time.Time     // start is the start time of current interval.
+	count      int           // count is the occurrences in current interval.
+	suppressed int           // suppressed is the suppressed occurrences in current interval.
+}
+
+// dedupeSummary is the summary of suppressed logging content to print.
+type dedupeSummary struct {
+	logger     *Logger
+	level      int
+	sample     string
+	interval   time.Duration
+	suppressed int
+}
+
+// ctxKeyForDedupeSkip is the context key marking the logging skipping deduplication.
+type ctxKeyForDedupeSkip struct{}
+
+const (
+	defaultDedupeLimit    = 10
+	defaultDedupeInterval = time.Minute
+	maxDedupeSampleSize   = 256
+)
+
+// newLoggerDedupe creates and returns a new deduplication state.
+func newLoggerDedupe() *loggerDedupe {
+	return &loggerDedupe{
+		entries: make(map[string]*dedupeEntry),
+	}
+}
+
+// HandlerDedupe is a handler for suppressing repeated logging content, which is keyed by level and
+// format string for formatted logging like Errorf, or by level and content for others.
+//
+// It allows the first Config.DedupeLimit occurrences in each Config.DedupeInterval, and suppresses
+// the others, printing a summary like "suppressed 100 similar messages in 1m0s: ..." after the interval.
+// The occurrences are counted per logger, which are shared by its cloned loggers like chaining ones.
+//
+// It is enabled automatically if Config.DedupeLimit is greater than 0, in which case there's no need
+// setting it as handler. If it is set as handler and Config.DedupeLimit is 0, it uses default limit 10.
+func HandlerDedupe(ctx context.Context, in *HandlerInput) {
+	if in.deduped || in.Logger.dedupe == nil {
+		in.Next(ctx)
+		return
+	}
+	in.deduped = true
+	if ctx != nil && ctx.Value(ctxKeyForDedupeSkip{}) != nil {
+		in.Next(ctx)
+		return
+	}
+	var (
+		limit    = in.Logger.config.DedupeLimit
+		interval = in.Logger.config.DedupeInterval
+	)
+	if limit <= 0 {
+		limit = defaultDedupeLimit
+	}
+	if interval <= 0 {
+		interval = defaultDedupeInterval
+	}
+	var sample = in.Format
+	if sample == "" {
+		sample = in.Content
+		if len(in.Values) > 0 {
+			sample += in.ValuesContent()
+		}
+	}
+	allowed, summary := in.Logger.dedupe.check(in.Logger, in.Level, sample, limit, interval)
+	if summary != nil {
+		summary.print()
+	}
+	if allowed {
+		in.Next(ctx)
+	}
+}
+
+// check counts the occurrence of `sample` in `level`, and returns whether it is allowed to print.
+// It also returns the summary of the last interval if it has suppressed occurrences.
+func (d *loggerDedupe) check(
+	logger *Logger, level int, sample string, limit int, interval time.Duration,
+) (allowed bool, summary *dedupeSummary) {
+	var (
+		now = time.Now()
+		key = strconv.Itoa(level) + ":" + sample
+	)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry := d.entries[key]
+	if entry != nil && now.Sub(entry.start) >= entry.interval {
+		summary = entry.summary()
+		entry = nil
+	}
+	if entry == nil {
+		entry = &dedupeEntry{
+			level:    level,
+			sample:   sample,
+			interval: interval,
+			start:    now,
+		}
+		d.entries[key] = entry
+	}
+	entry.logger = logger
+	entry.count++
+	if entry.count > limit {
+		entry.suppressed++
+	}
+	if !d.scheduled {
+		d.scheduled = true
+		gtimer.AddOnce(context.Background(), interval, d.summarize)
+	}
+	return entry.suppressed == 0, summary
+}
+
+// summarize prints the summaries of expired entries, and removes the expired entries.
+// It is called by timer, and reschedules itself if there are still entries.
+func (d *loggerDedupe) summarize(ctx context.Context) {
+	var (
+		now       = time.Now()
+		summaries []*dedupeSummary
+		next      time.Duration
+	)
+	d.mu.Lock()
+	for key, entry := range d.entries {
+		if remaining := entry.interval - now.Sub(entry.start); remaining > 0 {
+			if next == 0 || remaining < next {
+				next = remaining
+			}
+			continue
+		}
+		if summary := entry.summary(); summary != nil {
+			summaries = append(summaries, summary)
+		}
+		delete(d.entries, key)
+	}
+	if d.scheduled = len(d.entries) > 0; d.scheduled {
+		gtimer.AddOnce(ctx, next, d.summarize)
+	}
+	d.mu.Unlock()
+
+	for _, summary := range summaries {
+		summary.print()
+	}
+}
+
+// summary returns the summary of the entry, or nil if nothing suppressed.
+func (e *dedupeEntry) summary() *dedupeSummary {
+	if e.suppressed == 0 {
+		return nil
+	}
+	return &dedupeSummary{
+		logger:     e.logger,
+		level:      e.level,
+		sample:     e.sample,
+		interval:   e.interval,
+		suppressed: e.suppressed,
+	}
+}
+
+// print prints the summary using its logger in the same level, which skips deduplication.
+func (s *dedupeSummary) print() {
+	sample := s.sample
+	if len(sample) > maxDedupeSampleSize {
+		sample = sample[:maxDedupeSampleSize] + "..."
+	}
+	s.logger.printStdf(
+		context.WithValue(context.Background(), ctxKeyForDedupeSkip{}, true),
+		s.level, "suppressed %d similar messages in %s: %s", s.suppressed, s.interval, sample,
+	)
+}
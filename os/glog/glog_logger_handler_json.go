@@ -8,14 +8,13 @@ package glog
 
 import (
 	"context"
-
-	"github.com/ximplez-go/gf/internal/json"
 )
 
 // HandlerOutputJson is the structure outputting logging content as single json.
 type HandlerOutputJson struct {
 	Time       string `json:""`           // Formatted time string, like "2016-01-09 12:00:00".
 	TraceId    string `json:",omitempty"` // Trace id, only available if tracing is enabled.
+	SpanId     string `json:",omitempty"` // Span id, only available if tracing is enabled.
 	CtxStr     string `json:",omitempty"` // The retrieved context value string from context, only available if Config.CtxKeys configured.
 	Level      string `json:""`           // Formatted level string, like "DEBU", "ERRO", etc. Eg: ERRO
 	CallerPath string `json:",omitempty"` // The source file path and its line number that calls logging, only available if F_FILE_SHORT or F_FILE_LONG set.
@@ -26,10 +25,12 @@ type HandlerOutputJson struct {
 }
 
 // HandlerJson is a handler for output logging content as a single json string.
+// The fields of HandlerInput are output as separate json keys.
 func HandlerJson(ctx context.Context, in *HandlerInput) {
 	output := HandlerOutputJson{
 		Time:       in.TimeFormat,
 		TraceId:    in.TraceId,
+		SpanId:     in.SpanId,
 		CtxStr:     in.CtxStr,
 		Level:      in.LevelFormat,
 		CallerFunc: in.CallerFunc,
@@ -45,7 +46,7 @@ func HandlerJson(ctx context.Context, in *HandlerInput) {
 		output.Content += in.ValuesContent()
 	}
 	// Output json content.
-	jsonBytes, err := json.Marshal(output)
+	jsonBytes, err := marshalJsonWithFields(output, in.Fields)
 	if err != nil {
 		panic(err)
 	}
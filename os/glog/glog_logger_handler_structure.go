@@ -27,6 +27,7 @@ const (
 	structureKeyPrefix     = "Prefix"
 	structureKeyContent    = "Content"
 	structureKeyTraceId    = "TraceId"
+	structureKeySpanId     = "SpanId"
 	structureKeyCallerFunc = "CallerFunc"
 	structureKeyCallerPath = "CallerPath"
 	structureKeyCtxStr     = "CtxStr"
@@ -160,6 +161,9 @@ func (buf *structuredBuffer) Bytes() []byte {
 	if buf.in.TraceId != "" {
 		buf.addValue(structureKeyTraceId, buf.in.TraceId)
 	}
+	if buf.in.SpanId != "" {
+		buf.addValue(structureKeySpanId, buf.in.SpanId)
+	}
 	if buf.in.CtxStr != "" {
 		buf.addValue(structureKeyCtxStr, buf.in.CtxStr)
 	}
@@ -187,6 +191,10 @@ func (buf *structuredBuffer) Bytes() []byte {
 	if buf.in.Content != "" {
 		buf.addValue(structureKeyContent, buf.in.Content)
 	}
+	// Fields.
+	for _, field := range buf.in.Fields {
+		buf.addValue(field.Key, field.Value)
+	}
 	// Values pairs.
 	for i := 0; i < len(values); i += 2 {
 		buf.addValue(values[i], values[i+1])
@@ -9,32 +9,156 @@ package glog
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ximplez-go/gf/container/garray"
 	"github.com/ximplez-go/gf/encoding/gcompress"
+	"github.com/ximplez-go/gf/errors/gerror"
 	"github.com/ximplez-go/gf/internal/intlog"
 	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gfpool"
 	"github.com/ximplez-go/gf/os/gmlock"
 	"github.com/ximplez-go/gf/os/gtime"
 	"github.com/ximplez-go/gf/os/gtimer"
 	"github.com/ximplez-go/gf/text/gregex"
 )
 
+const (
+	RotateCalendarHourly = "hourly" // Rotate the logging file at the beginning of every hour in local time.
+	RotateCalendarDaily  = "daily"  // Rotate the logging file at local midnight of every day.
+)
+
+const (
+	RotateFormatGzip = "gzip" // Compress format for rotated files using gzip algorithm, which is the default format.
+)
+
 const (
 	memoryLockPrefixForRotating = "glog.rotateChecksTimely:"
+	rotateCompressingExtName    = "tmp"       // File extension for the backup file under compressing.
+	rotateChecksDelay           = time.Second // Delay for the backup checks after rotation.
 )
 
-// rotateFileBySize rotates the current logging file according to the
-// configured rotation size.
-func (l *Logger) rotateFileBySize(ctx context.Context, now time.Time) {
-	if l.config.RotateSize <= 0 {
-		return
+// RotateCompressor is the function compressing rotated backup file `src` to `dst` with compress `level`,
+// which is used by RegisterRotateCompressor.
+type RotateCompressor func(src, dst string, level int) error
+
+// rotateCompressorItem is the registered RotateCompressor with its file extension.
+type rotateCompressorItem struct {
+	extName    string
+	compressor RotateCompressor
+}
+
+var (
+	// rotateCompressors is the compress format to its compressor mapping.
+	rotateCompressors = map[string]*rotateCompressorItem{
+		RotateFormatGzip: {
+			extName: "gz",
+			compressor: func(src, dst string, level int) error {
+				return gcompress.GzipFile(src, dst, level)
+			},
+		},
 	}
-	if err := l.doRotateFile(ctx, l.getFilePath(now)); err != nil {
+	// rotateCompressorsMu protects rotateCompressors.
+	rotateCompressorsMu sync.RWMutex
+)
+
+// RegisterRotateCompressor registers custom `compressor` for compress `format` of rotated files,
+// the compressed backup file is named with suffix `extName`, like "zst".
+// It overwrites the previous registered compressor of the same format.
+//
+// Eg:
+// RegisterRotateCompressor("zstd", "zst", func(src, dst string, level int) error {...})
+func RegisterRotateCompressor(format, extName string, compressor RotateCompressor) {
+	rotateCompressorsMu.Lock()
+	defer rotateCompressorsMu.Unlock()
+	rotateCompressors[strings.ToLower(format)] = &rotateCompressorItem{
+		extName:    strings.TrimLeft(extName, "."),
+		compressor: compressor,
+	}
+}
+
+// getRotateCompressor returns the registered compressor of `format`, which is gzip if `format` is empty.
+// It returns nil if the `format` is not registered.
+func getRotateCompressor(format string) *rotateCompressorItem {
+	if format == "" {
+		format = RotateFormatGzip
+	}
+	rotateCompressorsMu.RLock()
+	defer rotateCompressorsMu.RUnlock()
+	return rotateCompressors[strings.ToLower(format)]
+}
+
+// getRotateCompressedExtNames returns the file extensions of all registered compressors.
+func getRotateCompressedExtNames() []string {
+	rotateCompressorsMu.RLock()
+	defer rotateCompressorsMu.RUnlock()
+	extNames := make([]string, 0, len(rotateCompressors))
+	for _, item := range rotateCompressors {
+		extNames = append(extNames, item.extName)
+	}
+	return extNames
+}
+
+// isRotateCompressedFile checks and returns whether `file` is a compressed backup file.
+func isRotateCompressedFile(file string) bool {
+	extName := gfile.ExtName(file)
+	for _, v := range getRotateCompressedExtNames() {
+		if v == extName {
+			return true
+		}
+	}
+	return false
+}
+
+// getRotateCalendarPeriod returns the beginning time of the calendar period that `t` belongs to,
+// according to the configured rotation calendar. It returns zero time if calendar rotation is disabled.
+func (l *Logger) getRotateCalendarPeriod(t time.Time) time.Time {
+	var (
+		year, month, day = t.Date()
+		hour             = 0
+	)
+	switch l.config.RotateCalendar {
+	case RotateCalendarHourly:
+		hour = t.Hour()
+	case RotateCalendarDaily:
+	default:
+		return time.Time{}
+	}
+	return time.Date(year, month, day, hour, 0, 0, 0, t.Location())
+}
+
+// isRotationNeeded checks whether the logging file `filePath` should be rotated at time `now`,
+// that is, its size exceeds the rotation size, or it was last written in previous calendar period.
+func (l *Logger) isRotationNeeded(filePath string, now time.Time) bool {
+	if l.config.RotateSize <= 0 && l.config.RotateCalendar == "" {
+		return false
+	}
+	stat, err := os.Stat(filePath)
+	if err != nil || stat.Size() == 0 {
+		return false
+	}
+	if l.config.RotateSize > 0 && stat.Size() > l.config.RotateSize {
+		return true
+	}
+	if l.config.RotateCalendar != "" {
+		return l.getRotateCalendarPeriod(stat.ModTime().In(now.Location())).Before(l.getRotateCalendarPeriod(now))
+	}
+	return false
+}
+
+// rotateFile rotates the logging file `filePath`, and schedules the backup checks,
+// so that the backups compression and cleanup are done asynchronously out of logging.
+func (l *Logger) rotateFile(ctx context.Context, filePath string) {
+	if err := l.doRotateFile(ctx, filePath); err != nil {
 		// panic(err)
 		intlog.Errorf(ctx, `%+v`, err)
+		return
+	}
+	if l.config.RotateBackupCompress > 0 || l.config.RotateBackupMaxBytes > 0 {
+		gtimer.AddOnce(ctx, rotateChecksDelay, l.rotateChecks)
 	}
 }
 
@@ -47,21 +171,23 @@ func (l *Logger) doRotateFile(ctx context.Context, filePath string) error {
 	defer gmlock.Unlock(memoryLockKey)
 
 	intlog.PrintFunc(ctx, func() string {
-		return fmt.Sprintf(`start rotating file by size: %s, file: %s`, gfile.SizeFormat(filePath), filePath)
+		return fmt.Sprintf(`start rotating file: %s, size: %s`, filePath, gfile.SizeFormat(filePath))
 	})
 	defer intlog.PrintFunc(ctx, func() string {
-		return fmt.Sprintf(`done rotating file by size: %s, size: %s`, gfile.SizeFormat(filePath), filePath)
+		return fmt.Sprintf(`done rotating file: %s`, filePath)
 	})
 
 	// No backups, it then just removes the current logging file.
-	if l.config.RotateBackupLimit == 0 {
+	// The backups are kept for size limitation if only RotateBackupMaxBytes is set.
+	if l.config.RotateBackupLimit == 0 && l.config.RotateBackupMaxBytes <= 0 {
 		if err := gfile.RemoveFile(filePath); err != nil {
 			return err
 		}
+		gfpool.Invalidate(filePath)
 		intlog.Printf(
 			ctx,
-			`%d size exceeds, no backups set, remove original logging file: %s`,
-			l.config.RotateSize, filePath,
+			`no backups set, remove original logging file: %s`,
+			filePath,
 		)
 		return nil
 	}
@@ -72,6 +198,9 @@ func (l *Logger) doRotateFile(ctx context.Context, filePath string) error {
 		fileExtName = gfile.ExtName(filePath)
 		newFilePath = ""
 	)
+	// The renaming is atomic, so that no logging content is lost or duplicated in rotation, as the
+	// file pointers of the renamed file in pool are reopened for the new file before next writing.
+	//
 	// Rename the logging file by adding extra datetime information to microseconds, like:
 	// access.log          -> access.20200326101301899002.log
 	// access.20200326.log -> access.20200326.20200326101301899002.log
@@ -100,23 +229,30 @@ func (l *Logger) doRotateFile(ctx context.Context, filePath string) error {
 			intlog.Printf(ctx, `rotation file exists, continue: %s`, newFilePath)
 		}
 	}
-	intlog.Printf(ctx, "rotating file by size from %s to %s", filePath, newFilePath)
+	intlog.Printf(ctx, "rotating file from %s to %s", filePath, newFilePath)
 	if err := gfile.Rename(filePath, newFilePath); err != nil {
 		return err
 	}
+	// The pooled file pointers are dropped, so that next writing goes to the new file of the path.
+	gfpool.Invalidate(filePath)
 	return nil
 }
 
 // rotateChecksTimely timely checks the backups expiration and the compression.
 func (l *Logger) rotateChecksTimely(ctx context.Context) {
 	defer gtimer.AddOnce(ctx, l.config.RotateCheckInterval, l.rotateChecksTimely)
+	l.rotateChecks(ctx)
+}
 
+// rotateChecks checks the logging files rotation, backups compression, count limitation,
+// size limitation and expiration.
+func (l *Logger) rotateChecks(ctx context.Context) {
 	// Checks whether file rotation not enabled.
-	if l.config.RotateSize <= 0 && l.config.RotateExpire == 0 {
+	if l.config.RotateSize <= 0 && l.config.RotateExpire == 0 && l.config.RotateCalendar == "" {
 		intlog.Printf(
 			ctx,
-			"logging rotation ignore checks: RotateSize: %d, RotateExpire: %s",
-			l.config.RotateSize, l.config.RotateExpire.String(),
+			"logging rotation ignore checks: RotateSize: %d, RotateExpire: %s, RotateCalendar: %s",
+			l.config.RotateSize, l.config.RotateExpire.String(), l.config.RotateCalendar,
 		)
 		return
 	}
@@ -129,8 +265,14 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 	defer gmlock.Unlock(memoryLockKey)
 
 	var (
-		now        = time.Now()
-		pattern    = "*.log, *.gz"
+		now      = time.Now()
+		patterns = []string{"*.log"}
+	)
+	for _, extName := range getRotateCompressedExtNames() {
+		patterns = append(patterns, "*."+extName)
+	}
+	var (
+		pattern    = strings.Join(patterns, ", ")
 		files, err = gfile.ScanDirFile(l.config.Path, pattern, true)
 	)
 	if err != nil {
@@ -143,9 +285,9 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 	fileNameRegexPattern = gregex.Quote(fileNameRegexPattern)
 	fileNameRegexPattern = strings.ReplaceAll(fileNameRegexPattern, "\\$", "(.+?)")
 	// =============================================================
-	// Rotation of expired file checks.
+	// Rotation of expired or previous calendar period file checks.
 	// =============================================================
-	if l.config.RotateExpire > 0 {
+	if l.config.RotateExpire > 0 || l.config.RotateCalendar != "" {
 		var (
 			mtime         time.Time
 			subDuration   time.Duration
@@ -153,7 +295,7 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 		)
 		for _, file := range files {
 			// ignore backup file
-			if gregex.IsMatchString(`.+\.\d{20}\.log`, gfile.Basename(file)) || gfile.ExtName(file) == "gz" {
+			if gregex.IsMatchString(`.+\.\d{20}\.log`, gfile.Basename(file)) || isRotateCompressedFile(file) {
 				continue
 			}
 			// ignore not matching file
@@ -162,7 +304,7 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 			}
 			mtime = gfile.MTime(file)
 			subDuration = now.Sub(mtime)
-			if subDuration > l.config.RotateExpire {
+			if (l.config.RotateExpire > 0 && subDuration > l.config.RotateExpire) || l.isRotationNeeded(file, now) {
 				func() {
 					memoryLockFileKey := memoryLockPrefixForPrintingToFile + file
 					if !gmlock.TryLock(memoryLockFileKey) {
@@ -172,8 +314,8 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 					expireRotated = true
 					intlog.Printf(
 						ctx,
-						`%v - %v = %v > %v, rotation expire logging file: %s`,
-						now, mtime, subDuration, l.config.RotateExpire, file,
+						`%v - %v = %v, rotation expire logging file: %s`,
+						now, mtime, subDuration, file,
 					)
 					if err = l.doRotateFile(ctx, file); err != nil {
 						intlog.Errorf(ctx, `%+v`, err)
@@ -195,9 +337,15 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 	// =============================================================
 	needCompressFileArray := garray.NewStrArray()
 	if l.config.RotateBackupCompress > 0 {
+		compressor := getRotateCompressor(l.config.RotateBackupFormat)
+		if compressor == nil {
+			intlog.Errorf(ctx, `invalid rotate backup format: %s`, l.config.RotateBackupFormat)
+			return
+		}
+		l.removeStaleCompressingFiles(ctx)
 		for _, file := range files {
 			// Eg: access.20200326101301899002.log.gz
-			if gfile.ExtName(file) == "gz" {
+			if isRotateCompressedFile(file) {
 				continue
 			}
 			// ignore not matching file
@@ -213,14 +361,8 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 		}
 		if needCompressFileArray.Len() > 0 {
 			needCompressFileArray.Iterator(func(_ int, path string) bool {
-				err := gcompress.GzipFile(path, path+".gz")
-				if err == nil {
-					intlog.Printf(ctx, `compressed done, remove original logging file: %s`, path)
-					if err = gfile.RemoveFile(path); err != nil {
-						intlog.Print(ctx, err)
-					}
-				} else {
-					intlog.Print(ctx, err)
+				if err := l.doCompressFile(ctx, compressor, path); err != nil {
+					intlog.Errorf(ctx, `%+v`, err)
 				}
 				return true
 			})
@@ -233,7 +375,7 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 	}
 
 	// =============================================================
-	// Backups count limitation and expiration checks.
+	// Backups count limitation, expiration and size limitation checks.
 	// =============================================================
 	backupFiles := garray.NewSortedArray(func(a, b interface{}) int {
 		// Sorted by rotated/backup file mtime.
@@ -248,7 +390,7 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 		}
 		return 1
 	})
-	if l.config.RotateBackupLimit > 0 || l.config.RotateBackupExpire > 0 {
+	if l.config.RotateBackupLimit > 0 || l.config.RotateBackupExpire > 0 || l.config.RotateBackupMaxBytes > 0 {
 		for _, file := range files {
 			// ignore not matching file
 			originalLoggingFilePath, _ := gregex.ReplaceString(`\.\d{20}`, "", file)
@@ -260,7 +402,11 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 			}
 		}
 		intlog.Printf(ctx, `calculated backup files array: %+v`, backupFiles)
+		// Backups count limitation checking, which is not limited if only RotateBackupMaxBytes is set.
 		diff := backupFiles.Len() - l.config.RotateBackupLimit
+		if l.config.RotateBackupLimit == 0 && l.config.RotateBackupMaxBytes > 0 {
+			diff = 0
+		}
 		for i := 0; i < diff; i++ {
 			path, _ := backupFiles.PopLeft()
 			intlog.Printf(ctx, `remove exceeded backup limit file: %s`, path)
@@ -274,24 +420,80 @@ func (l *Logger) rotateChecksTimely(ctx context.Context) {
 				mtime       time.Time
 				subDuration time.Duration
 			)
-			backupFiles.Iterator(func(_ int, v interface{}) bool {
-				path := v.(string)
+			for backupFiles.Len() > 0 {
+				path := backupFiles.At(0).(string)
 				mtime = gfile.MTime(path)
 				subDuration = now.Sub(mtime)
-				if subDuration > l.config.RotateBackupExpire {
-					intlog.Printf(
-						ctx,
-						`%v - %v = %v > %v, remove expired backup file: %s`,
-						now, mtime, subDuration, l.config.RotateBackupExpire, path,
-					)
-					if err = gfile.RemoveFile(path); err != nil {
-						intlog.Errorf(ctx, `%+v`, err)
-					}
-					return true
-				} else {
-					return false
+				if subDuration <= l.config.RotateBackupExpire {
+					break
 				}
-			})
+				intlog.Printf(
+					ctx,
+					`%v - %v = %v > %v, remove expired backup file: %s`,
+					now, mtime, subDuration, l.config.RotateBackupExpire, path,
+				)
+				if err = gfile.RemoveFile(path); err != nil {
+					intlog.Errorf(ctx, `%+v`, err)
+				}
+				backupFiles.PopLeft()
+			}
+		}
+		// Backups total size checking, the newer backups are kept in priority.
+		if l.config.RotateBackupMaxBytes > 0 {
+			var totalBytes int64
+			for i := backupFiles.Len() - 1; i >= 0; i-- {
+				path := backupFiles.At(i).(string)
+				totalBytes += gfile.Size(path)
+				if totalBytes <= l.config.RotateBackupMaxBytes {
+					continue
+				}
+				intlog.Printf(
+					ctx,
+					`%d > %d, remove exceeded backup size file: %s`,
+					totalBytes, l.config.RotateBackupMaxBytes, path,
+				)
+				if err = gfile.RemoveFile(path); err != nil {
+					intlog.Errorf(ctx, `%+v`, err)
+				}
+			}
+		}
+	}
+}
+
+// doCompressFile compresses backup file `path` using `compressor`.
+// It compresses to a temporary file and renames it to the final compressed file after done,
+// and removes the original backup file at last, so that it leaves no incomplete compressed file
+// if the process crashes in compression, and the next checks compress it again.
+func (l *Logger) doCompressFile(ctx context.Context, compressor *rotateCompressorItem, path string) error {
+	var (
+		dstFilePath = path + "." + compressor.extName
+		tmpFilePath = dstFilePath + "." + rotateCompressingExtName
+	)
+	if err := compressor.compressor(path, tmpFilePath, l.config.RotateBackupCompress); err != nil {
+		_ = gfile.RemoveFile(tmpFilePath)
+		return gerror.Wrapf(err, `compress backup file "%s" failed`, path)
+	}
+	if err := gfile.Rename(tmpFilePath, dstFilePath); err != nil {
+		return err
+	}
+	intlog.Printf(ctx, `compressed done, remove original logging file: %s`, path)
+	return gfile.RemoveFile(path)
+}
+
+// removeStaleCompressingFiles removes the temporary compressing files left by crashed compression.
+func (l *Logger) removeStaleCompressingFiles(ctx context.Context) {
+	files, err := gfile.ScanDirFile(l.config.Path, "*."+rotateCompressingExtName, true)
+	if err != nil {
+		intlog.Errorf(ctx, `%+v`, err)
+		return
+	}
+	for _, file := range files {
+		if !gregex.IsMatchString(`.+\.\d{20}\.log\.\w+\.`+rotateCompressingExtName+`$`, gfile.Basename(file)) {
+			continue
+		}
+		intlog.Printf(ctx, `remove stale compressing file: %s`, file)
+		if err = gfile.RemoveFile(file); err != nil {
+			intlog.Errorf(ctx, `%+v`, err)
 		}
 	}
 }
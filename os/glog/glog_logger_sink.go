@@ -0,0 +1,273 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/util/gconv"
+	"github.com/ximplez-go/gf/util/gutil"
+)
+
+// Sink is the interface for remote logging output, like syslog, http service, etc.
+// The logging entries are buffered and sent in batch to Sink by SinkWriter.
+type Sink interface {
+	// Send sends the batch of logging `entries` to remote.
+	// It is called in one goroutine of SinkWriter, and is retried by SinkWriter if it returns error.
+	// Note that the `entries` should not be retained after Send returns.
+	Send(ctx context.Context, entries []*SinkEntry) error
+
+	// Close closes the Sink and releases its resources.
+	Close() error
+}
+
+// SinkCreator is the function creating Sink with configuration, which is used by RegisterSink.
+type SinkCreator func(config SinkConfig) (Sink, error)
+
+// SinkEntry is the logging entry for Sink.
+// It is shared by all Sinks of the logger, which should be treated as read-only.
+type SinkEntry struct {
+	Time        time.Time // Logging time.
+	TimeFormat  string    // Formatted time string, like "2016-01-09 12:00:00".
+	Level       int       // Using level, like LEVEL_INFO, LEVEL_ERRO, etc.
+	LevelFormat string    // Formatted level string, like "DEBU", "ERRO", etc.
+	TraceId     string    // Trace id, only available if tracing is enabled.
+	SpanId      string    // Span id, only available if tracing is enabled.
+	CtxStr      string    // The retrieved context value string from context, only available if Config.CtxKeys configured.
+	Prefix      string    // Custom prefix string for logging content.
+	CallerFunc  string    // The source function name that calls logging, only available if F_CALLER_FN set.
+	CallerPath  string    // The source file path and its line number that calls logging, only available if F_FILE_SHORT or F_FILE_LONG set.
+	Content     string    // Content is the main logging content, including the values content.
+	Stack       string    // Stack string produced by logger, only available if Config.StStatus configured.
+	Fields      []Field   // Fields added by chaining function With or Fields, and the baggage entries of Config.BaggageKeys.
+	Text        string    // Text is the formatted logging content without trailing line feed, same as file or stdout output.
+}
+
+// SinkConfig is the configuration for Sink and its SinkWriter,
+// which can be configured in "sinks" item of the logger configuration map.
+type SinkConfig struct {
+	Type             string            `json:"type"`             // Sink type, which is "syslog", "http", "unix" or custom type registered by RegisterSink.
+	Address          string            `json:"address"`          // Address for syslog and unix sink, eg: udp://127.0.0.1:514, tcp://127.0.0.1:601, unixgram:///dev/log.
+	Url              string            `json:"url"`              // Url for http sink, to which the entries are posted as json array.
+	Headers          map[string]string `json:"headers"`          // Custom headers for http sink.
+	Timeout          time.Duration     `json:"timeout"`          // Timeout for connecting and sending. It's 10 seconds in default.
+	AppName          string            `json:"appName"`          // Application name for syslog sink, which is the binary name in default.
+	Facility         int               `json:"facility"`         // Facility for syslog sink, which is 1(user-level) in default if it's 0.
+	BufferSize       int               `json:"bufferSize"`       // Max buffered entries waiting to be sent. It's 10000 in default.
+	BatchSize        int               `json:"batchSize"`        // Max entries for each sending. It's 100 in default.
+	FlushInterval    time.Duration     `json:"flushInterval"`    // Interval for sending buffered entries if batch size is not reached. It's 1 second in default.
+	RetryCount       int               `json:"retryCount"`       // Retry count for failed sending, after which the entries are dropped. It's 3 in default, -1 for no retry.
+	RetryInterval    time.Duration     `json:"retryInterval"`    // Initial retry interval, which is doubled for each retry. It's 100 milliseconds in default.
+	RetryMaxInterval time.Duration     `json:"retryMaxInterval"` // Max retry interval. It's 5 seconds in default.
+	DropPolicy       string            `json:"dropPolicy"`       // Policy when buffer is full: "newest"(default), "oldest" or "block".
+}
+
+const (
+	SinkDropNewest = "newest" // Drop the new entry when buffer is full.
+	SinkDropOldest = "oldest" // Drop the oldest buffered entry when buffer is full.
+	SinkDropBlock  = "block"  // Block logging until buffer is available.
+)
+
+const (
+	SinkTypeSyslog = "syslog" // Sink type for syslog in RFC 5424 over UDP/TCP.
+	SinkTypeHttp   = "http"   // Sink type for http service receiving json batch.
+	SinkTypeUnix   = "unix"   // Sink type for local unix socket.
+)
+
+const (
+	defaultSinkTimeout          = 10 * time.Second
+	defaultSinkBufferSize       = 10000
+	defaultSinkBatchSize        = 100
+	defaultSinkFlushInterval    = time.Second
+	defaultSinkRetryCount       = 3
+	defaultSinkRetryInterval    = 100 * time.Millisecond
+	defaultSinkRetryMaxInterval = 5 * time.Second
+	configKeyForSinks           = "Sinks"
+)
+
+var (
+	// sinkCreators is the sink type to its creator mapping.
+	sinkCreators = map[string]SinkCreator{
+		SinkTypeSyslog: func(config SinkConfig) (Sink, error) { return NewSinkSyslog(config) },
+		SinkTypeHttp:   func(config SinkConfig) (Sink, error) { return NewSinkHttp(config) },
+		SinkTypeUnix:   func(config SinkConfig) (Sink, error) { return NewSinkUnix(config) },
+	}
+	// sinkCreatorsMu protects sinkCreators.
+	sinkCreatorsMu sync.RWMutex
+)
+
+// RegisterSink registers custom Sink `creator` for `sinkType`,
+// so that it can be configured by the "type" of SinkConfig.
+// It overwrites the previous registered creator of the same type.
+func RegisterSink(sinkType string, creator SinkCreator) {
+	sinkCreatorsMu.Lock()
+	defer sinkCreatorsMu.Unlock()
+	sinkCreators[strings.ToLower(sinkType)] = creator
+}
+
+// NewSink creates and returns a Sink according to the type of `config`.
+func NewSink(config SinkConfig) (Sink, error) {
+	sinkCreatorsMu.RLock()
+	creator, ok := sinkCreators[strings.ToLower(config.Type)]
+	sinkCreatorsMu.RUnlock()
+	if !ok {
+		return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `unsupported sink type "%s"`, config.Type)
+	}
+	return creator(config)
+}
+
+// loggerSinks holds the SinkWriters of the logger, which is copy-on-write.
+// The writers are replaced as a whole under lock, so that they can be read without lock in logging.
+type loggerSinks struct {
+	mu      sync.Mutex
+	writers atomic.Pointer[[]*SinkWriter]
+}
+
+// Load returns the current SinkWriters, which should not be changed.
+func (s *loggerSinks) Load() []*SinkWriter {
+	if s == nil {
+		return nil
+	}
+	if writers := s.writers.Load(); writers != nil {
+		return *writers
+	}
+	return nil
+}
+
+// Update replaces the SinkWriters with the result of `f`, which is called with the current SinkWriters
+// under lock, and should return a new slice instead of changing the given one.
+func (s *loggerSinks) Update(f func(writers []*SinkWriter) []*SinkWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writers := f(s.Load())
+	s.writers.Store(&writers)
+}
+
+// AddSink adds `sink` to the logger with optional `config` for buffering and sending,
+// and returns the SinkWriter of the `sink`. It is concurrent safe with logging.
+func (l *Logger) AddSink(sink Sink, config ...SinkConfig) *SinkWriter {
+	writer := NewSinkWriter(sink, config...)
+	l.sinks.Update(func(writers []*SinkWriter) []*SinkWriter {
+		newWriters := make([]*SinkWriter, 0, len(writers)+1)
+		newWriters = append(newWriters, writers...)
+		return append(newWriters, writer)
+	})
+	return writer
+}
+
+// AddSinkWithConfig creates Sink according to `config` and adds it to the logger.
+func (l *Logger) AddSinkWithConfig(config SinkConfig) (*SinkWriter, error) {
+	sink, err := NewSink(config)
+	if err != nil {
+		return nil, err
+	}
+	return l.AddSink(sink, config), nil
+}
+
+// GetSinks returns the SinkWriters of the logger.
+func (l *Logger) GetSinks() []*SinkWriter {
+	return l.sinks.Load()
+}
+
+// CloseSinks flushes and closes all Sinks of the logger, and removes them from the logger.
+// It should be called before process exits, or else the buffered entries might be lost.
+func (l *Logger) CloseSinks(ctx context.Context) error {
+	return l.replaceSinks(ctx, nil, nil)
+}
+
+// replaceSinks replaces the Sinks of the logger with `sinks` configured by `configs`,
+// and then flushes and closes the previous Sinks.
+func (l *Logger) replaceSinks(ctx context.Context, sinks []Sink, configs []SinkConfig) error {
+	var (
+		err        error
+		oldWriters []*SinkWriter
+		newWriters = make([]*SinkWriter, 0, len(sinks))
+	)
+	for i, sink := range sinks {
+		newWriters = append(newWriters, NewSinkWriter(sink, configs[i]))
+	}
+	l.sinks.Update(func(writers []*SinkWriter) []*SinkWriter {
+		oldWriters = writers
+		return newWriters
+	})
+	for _, writer := range oldWriters {
+		if closeErr := writer.Close(ctx); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// getSinkConfigsWithMap retrieves and removes the sinks item from configuration map `m`,
+// and returns the SinkConfigs. The returned `ok` is false if there's no sinks item.
+func getSinkConfigsWithMap(m map[string]interface{}) (configs []SinkConfig, ok bool, err error) {
+	sinksKey, sinksValue := gutil.MapPossibleItemByKey(m, configKeyForSinks)
+	if sinksKey == "" {
+		return nil, false, nil
+	}
+	delete(m, sinksKey)
+	if err = gconv.Structs(sinksValue, &configs); err != nil {
+		return nil, false, gerror.WrapCode(gcode.CodeInvalidConfiguration, err, `invalid sinks configuration`)
+	}
+	return configs, true, nil
+}
+
+// newSinks creates Sinks with `configs`. It closes the created ones if any creation fails.
+func newSinks(configs []SinkConfig) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(configs))
+	for _, config := range configs {
+		sink, err := NewSink(config)
+		if err != nil {
+			for _, created := range sinks {
+				_ = created.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// printToSinks writes the logging content to `writers`.
+func (l *Logger) printToSinks(ctx context.Context, input *HandlerInput, writers []*SinkWriter) *bytes.Buffer {
+	var (
+		buffer = input.getRealBuffer(false)
+		entry  = &SinkEntry{
+			Time:        input.Time,
+			TimeFormat:  input.TimeFormat,
+			Level:       input.Level,
+			LevelFormat: input.LevelFormat,
+			TraceId:     input.TraceId,
+			SpanId:      input.SpanId,
+			CtxStr:      input.CtxStr,
+			Prefix:      input.Prefix,
+			CallerFunc:  input.CallerFunc,
+			CallerPath:  input.CallerPath,
+			Content:     input.Content,
+			Stack:       input.Stack,
+			Fields:      input.Fields,
+			Text:        strings.TrimRight(buffer.String(), "\r\n"),
+		}
+	)
+	if len(input.Values) > 0 {
+		if entry.Content != "" {
+			entry.Content += " "
+		}
+		entry.Content += input.ValuesContent()
+	}
+	for _, writer := range writers {
+		writer.Write(ctx, entry)
+	}
+	return buffer
+}
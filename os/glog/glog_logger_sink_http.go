@@ -0,0 +1,97 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// SinkHttp is the Sink posting logging entries as json array of HandlerOutputJson to http service,
+// in which the fields of entries are output as separate json keys.
+// The sending is treated as failure if the response status code is not 2xx.
+type SinkHttp struct {
+	config SinkConfig
+	client *http.Client
+}
+
+var (
+	// Check the implements for interface Sink.
+	_ Sink = (*SinkHttp)(nil)
+)
+
+// NewSinkHttp creates and returns a SinkHttp with `config`, whose Url is required.
+func NewSinkHttp(config SinkConfig) (*SinkHttp, error) {
+	config = getSinkConfigWithDefaults(config)
+	if config.Url == "" {
+		return nil, gerror.NewCode(gcode.CodeInvalidConfiguration, `url is required for http sink`)
+	}
+	return &SinkHttp{
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}, nil
+}
+
+// Send implements interface Sink.
+func (s *SinkHttp) Send(ctx context.Context, entries []*SinkEntry) error {
+	var body bytes.Buffer
+	body.WriteByte('[')
+	for i, entry := range entries {
+		output := HandlerOutputJson{
+			Time:       entry.TimeFormat,
+			TraceId:    entry.TraceId,
+			SpanId:     entry.SpanId,
+			CtxStr:     entry.CtxStr,
+			Level:      entry.LevelFormat,
+			CallerPath: entry.CallerPath,
+			CallerFunc: entry.CallerFunc,
+			Prefix:     entry.Prefix,
+			Content:    entry.Content,
+			Stack:      entry.Stack,
+		}
+		jsonBytes, err := marshalJsonWithFields(output, entry.Fields)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(jsonBytes)
+	}
+	body.WriteByte(']')
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.Url, &body)
+	if err != nil {
+		return gerror.WrapCodef(gcode.CodeInvalidConfiguration, err, `invalid http sink url "%s"`, s.config.Url)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range s.config.Headers {
+		request.Header.Set(key, value)
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return gerror.Wrapf(err, `post logging entries to "%s" failed`, s.config.Url)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return gerror.Newf(`post logging entries to "%s" failed with status: %s`, s.config.Url, response.Status)
+	}
+	return nil
+}
+
+// Close implements interface Sink.
+func (s *SinkHttp) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
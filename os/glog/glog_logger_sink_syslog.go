@@ -0,0 +1,228 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/net/gtcp"
+	"github.com/ximplez-go/gf/net/gudp"
+	"github.com/ximplez-go/gf/os/gfile"
+)
+
+// SinkSyslog is the Sink sending logging entries in RFC 5424 format to syslog server over UDP or TCP.
+// Each entry is sent as one datagram over UDP, or framed with octet counting (RFC 6587) over TCP.
+//
+// Note that the entries might be sent more than once if the sending fails halfway and is retried.
+type SinkSyslog struct {
+	mu       sync.Mutex
+	config   SinkConfig
+	network  string           // network is "udp" or "tcp".
+	address  string           // address is the syslog server address, like "127.0.0.1:514".
+	hostname string           // hostname is the HOSTNAME field of syslog message.
+	appName  string           // appName is the APP-NAME field of syslog message.
+	procId   string           // procId is the PROCID field of syslog message.
+	tcpConn  *gtcp.Conn       // tcpConn is the lazily created TCP connection.
+	udpConn  *gudp.ClientConn // udpConn is the lazily created UDP connection.
+}
+
+const (
+	syslogVersion         = 1
+	syslogFacilityUser    = 1
+	syslogTimeFormat      = "2006-01-02T15:04:05.000000Z07:00"
+	syslogNilValue        = "-"
+	syslogMaxHostnameSize = 255
+	syslogMaxAppNameSize  = 48
+)
+
+var (
+	// Check the implements for interface Sink.
+	_ Sink = (*SinkSyslog)(nil)
+)
+
+// NewSinkSyslog creates and returns a SinkSyslog with `config`, whose Address is like
+// "udp://127.0.0.1:514" or "tcp://127.0.0.1:601". It uses UDP if no network is specified.
+func NewSinkSyslog(config SinkConfig) (*SinkSyslog, error) {
+	config = getSinkConfigWithDefaults(config)
+	network, address := parseSinkAddress(config.Address, "udp")
+	switch network {
+	case "udp", "tcp":
+	default:
+		return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `unsupported network "%s" for syslog sink`, network)
+	}
+	if address == "" {
+		return nil, gerror.NewCode(gcode.CodeInvalidConfiguration, `address is required for syslog sink`)
+	}
+	if config.Facility == 0 {
+		config.Facility = syslogFacilityUser
+	}
+	if config.Facility < 0 || config.Facility > 23 {
+		return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `invalid syslog facility %d`, config.Facility)
+	}
+	if config.AppName == "" {
+		config.AppName = gfile.SelfName()
+	}
+	hostname, _ := os.Hostname()
+	return &SinkSyslog{
+		config:   config,
+		network:  network,
+		address:  address,
+		hostname: getSyslogHeaderField(hostname, syslogMaxHostnameSize),
+		appName:  getSyslogHeaderField(config.AppName, syslogMaxAppNameSize),
+		procId:   strconv.Itoa(os.Getpid()),
+	}, nil
+}
+
+// Send implements interface Sink.
+func (s *SinkSyslog) Send(ctx context.Context, entries []*SinkEntry) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.network == "tcp" {
+		return s.sendTcp(entries)
+	}
+	return s.sendUdp(entries)
+}
+
+// Close implements interface Sink.
+func (s *SinkSyslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeConn()
+}
+
+// Format formats `entry` as RFC 5424 syslog message.
+// The message body is the prefix, content and stack of `entry`, as the time and level are in the syslog header.
+func (s *SinkSyslog) Format(entry *SinkEntry) []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(
+		&buffer, "<%d>%d %s %s %s %s %s %s ",
+		s.config.Facility*8+getSyslogSeverity(entry.Level), syslogVersion,
+		entry.Time.Format(syslogTimeFormat), s.hostname, s.appName, s.procId,
+		syslogNilValue, syslogNilValue,
+	)
+	if entry.Prefix != "" {
+		buffer.WriteString(entry.Prefix)
+		buffer.WriteByte(' ')
+	}
+	buffer.WriteString(entry.Content)
+	if entry.Stack != "" {
+		buffer.WriteByte('\n')
+		buffer.WriteString(entry.Stack)
+	}
+	return buffer.Bytes()
+}
+
+// sendTcp sends `entries` over TCP in one writing.
+func (s *SinkSyslog) sendTcp(entries []*SinkEntry) (err error) {
+	if s.tcpConn == nil {
+		if s.tcpConn, err = gtcp.NewConn(s.address, s.config.Timeout); err != nil {
+			return err
+		}
+	}
+	var buffer bytes.Buffer
+	for _, entry := range entries {
+		message := s.Format(entry)
+		buffer.WriteString(strconv.Itoa(len(message)))
+		buffer.WriteByte(' ')
+		buffer.Write(message)
+	}
+	if err = s.tcpConn.SendWithTimeout(buffer.Bytes(), s.config.Timeout); err != nil {
+		_ = s.closeConn()
+	}
+	return err
+}
+
+// sendUdp sends each entry of `entries` as one datagram.
+func (s *SinkSyslog) sendUdp(entries []*SinkEntry) (err error) {
+	if s.udpConn == nil {
+		if s.udpConn, err = gudp.NewClientConn(s.address); err != nil {
+			return err
+		}
+	}
+	if err = s.udpConn.SetDeadlineSend(time.Now().Add(s.config.Timeout)); err != nil {
+		_ = s.closeConn()
+		return err
+	}
+	for _, entry := range entries {
+		if err = s.udpConn.Send(s.Format(entry)); err != nil {
+			_ = s.closeConn()
+			return err
+		}
+	}
+	return nil
+}
+
+// closeConn closes and resets the connection, which is recreated in next sending.
+func (s *SinkSyslog) closeConn() (err error) {
+	if s.tcpConn != nil {
+		err = s.tcpConn.Close()
+		s.tcpConn = nil
+	}
+	if s.udpConn != nil {
+		err = s.udpConn.Close()
+		s.udpConn = nil
+	}
+	return err
+}
+
+// getSyslogSeverity returns the syslog severity of logging `level`.
+func getSyslogSeverity(level int) int {
+	switch level {
+	case LEVEL_FATA:
+		return 0 // Emergency.
+	case LEVEL_PANI:
+		return 1 // Alert.
+	case LEVEL_CRIT:
+		return 2 // Critical.
+	case LEVEL_ERRO:
+		return 3 // Error.
+	case LEVEL_WARN:
+		return 4 // Warning.
+	case LEVEL_NOTI:
+		return 5 // Notice.
+	case LEVEL_DEBU:
+		return 7 // Debug.
+	default:
+		return 6 // Informational.
+	}
+}
+
+// getSyslogHeaderField returns the printable header field of syslog message truncated to `maxSize`,
+// or the nil value "-" if it is empty.
+func getSyslogHeaderField(value string, maxSize int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(value) > maxSize {
+		value = value[:maxSize]
+	}
+	if value == "" {
+		return syslogNilValue
+	}
+	return value
+}
+
+// parseSinkAddress parses `address` like "tcp://127.0.0.1:601" and returns its network and address,
+// using `defaultNetwork` if it has no network.
+func parseSinkAddress(address, defaultNetwork string) (network, addr string) {
+	if array := strings.SplitN(address, "://", 2); len(array) == 2 {
+		return strings.ToLower(array[0]), array[1]
+	}
+	return defaultNetwork, address
+}
@@ -0,0 +1,105 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// SinkUnix is the Sink writing logging text to local unix socket, which is commonly used for
+// local logging agents. Each entry is written as one datagram for "unixgram" network,
+// or as one line for "unix" stream network.
+type SinkUnix struct {
+	mu      sync.Mutex
+	config  SinkConfig
+	network string   // network is "unix" or "unixgram".
+	address string   // address is the socket file path.
+	conn    net.Conn // conn is the lazily created connection.
+}
+
+var (
+	// Check the implements for interface Sink.
+	_ Sink = (*SinkUnix)(nil)
+)
+
+// NewSinkUnix creates and returns a SinkUnix with `config`, whose Address is like
+// "/var/run/log.sock", "unix:///var/run/log.sock" or "unixgram:///dev/log".
+func NewSinkUnix(config SinkConfig) (*SinkUnix, error) {
+	config = getSinkConfigWithDefaults(config)
+	network, address := parseSinkAddress(config.Address, "unix")
+	switch network {
+	case "unix", "unixgram":
+	default:
+		return nil, gerror.NewCodef(gcode.CodeInvalidConfiguration, `unsupported network "%s" for unix sink`, network)
+	}
+	if address == "" {
+		return nil, gerror.NewCode(gcode.CodeInvalidConfiguration, `address is required for unix sink`)
+	}
+	return &SinkUnix{
+		config:  config,
+		network: network,
+		address: address,
+	}, nil
+}
+
+// Send implements interface Sink.
+func (s *SinkUnix) Send(ctx context.Context, entries []*SinkEntry) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if s.conn, err = net.DialTimeout(s.network, s.address, s.config.Timeout); err != nil {
+			s.conn = nil
+			return gerror.Wrapf(err, `dial unix socket "%s" failed`, s.address)
+		}
+	}
+	if err = s.conn.SetWriteDeadline(time.Now().Add(s.config.Timeout)); err != nil {
+		_ = s.closeConn()
+		return err
+	}
+	if s.network == "unixgram" {
+		for _, entry := range entries {
+			if _, err = s.conn.Write([]byte(entry.Text)); err != nil {
+				_ = s.closeConn()
+				return gerror.Wrapf(err, `write unix socket "%s" failed`, s.address)
+			}
+		}
+		return nil
+	}
+	var buffer bytes.Buffer
+	for _, entry := range entries {
+		buffer.WriteString(entry.Text)
+		buffer.WriteByte('\n')
+	}
+	if _, err = s.conn.Write(buffer.Bytes()); err != nil {
+		_ = s.closeConn()
+		return gerror.Wrapf(err, `write unix socket "%s" failed`, s.address)
+	}
+	return nil
+}
+
+// Close implements interface Sink.
+func (s *SinkUnix) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeConn()
+}
+
+// closeConn closes and resets the connection, which is recreated in next sending.
+func (s *SinkUnix) closeConn() (err error) {
+	if s.conn != nil {
+		err = s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
@@ -0,0 +1,244 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/internal/intlog"
+)
+
+// SinkWriter buffers the logging entries in bounded buffer, and sends them to Sink in batch
+// by an asynchronous goroutine, with retry and backoff for failed sending.
+// When the buffer is full, it drops or blocks according to the configured drop policy.
+type SinkWriter struct {
+	sink      Sink
+	config    SinkConfig
+	entries   chan *SinkEntry    // entries is the bounded buffer of entries waiting to be sent.
+	flushes   chan chan struct{} // flushes receives the flushing requests.
+	closeChan chan struct{}      // closeChan is closed when the writer is closing.
+	doneChan  chan struct{}      // doneChan is closed when the sending goroutine exits.
+	closed    *gtype.Bool        // closed marks the writer closed.
+	dropped   *gtype.Int64       // dropped is the count of dropped entries.
+	closeErr  error              // closeErr is the error of closing Sink.
+}
+
+// NewSinkWriter creates and returns a SinkWriter for `sink` with optional `config`,
+// and starts its sending goroutine.
+func NewSinkWriter(sink Sink, config ...SinkConfig) *SinkWriter {
+	var c SinkConfig
+	if len(config) > 0 {
+		c = config[0]
+	}
+	c = getSinkConfigWithDefaults(c)
+	w := &SinkWriter{
+		sink:      sink,
+		config:    c,
+		entries:   make(chan *SinkEntry, c.BufferSize),
+		flushes:   make(chan chan struct{}),
+		closeChan: make(chan struct{}),
+		doneChan:  make(chan struct{}),
+		closed:    gtype.NewBool(),
+		dropped:   gtype.NewInt64(),
+	}
+	go w.run()
+	return w
+}
+
+// Sink returns the underlying Sink.
+func (w *SinkWriter) Sink() Sink {
+	return w.sink
+}
+
+// Dropped returns the count of entries dropped for full buffer or sending failure.
+func (w *SinkWriter) Dropped() int64 {
+	return w.dropped.Val()
+}
+
+// Write puts `entry` into buffer for sending.
+// If the buffer is full, it drops the entry, drops the oldest entry or blocks according to the drop policy.
+func (w *SinkWriter) Write(ctx context.Context, entry *SinkEntry) {
+	if w.closed.Val() {
+		w.dropped.Add(1)
+		return
+	}
+	switch w.config.DropPolicy {
+	case SinkDropBlock:
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		select {
+		case w.entries <- entry:
+		case <-w.closeChan:
+			w.dropped.Add(1)
+		case <-ctx.Done():
+			w.dropped.Add(1)
+		}
+
+	case SinkDropOldest:
+		for {
+			select {
+			case w.entries <- entry:
+				return
+			default:
+			}
+			select {
+			case <-w.entries:
+				w.dropped.Add(1)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case w.entries <- entry:
+		default:
+			w.dropped.Add(1)
+		}
+	}
+}
+
+// Flush sends all buffered entries and waits until they are sent or `ctx` is done.
+func (w *SinkWriter) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case w.flushes <- done:
+	case <-w.doneChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close sends all buffered entries, closes the Sink, and waits until they are done or `ctx` is done.
+// The entries written after closing are dropped.
+func (w *SinkWriter) Close(ctx context.Context) error {
+	if w.closed.Cas(false, true) {
+		close(w.closeChan)
+	}
+	select {
+	case <-w.doneChan:
+		return w.closeErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run is the sending goroutine, which sends entries in batch when batch size is reached,
+// or flush interval is reached.
+func (w *SinkWriter) run() {
+	var (
+		ticker = time.NewTicker(w.config.FlushInterval)
+		batch  = make([]*SinkEntry, 0, w.config.BatchSize)
+	)
+	defer ticker.Stop()
+	for {
+		select {
+		case entry := <-w.entries:
+			if batch = append(batch, entry); len(batch) >= w.config.BatchSize {
+				batch = w.send(batch)
+			}
+
+		case <-ticker.C:
+			batch = w.send(batch)
+
+		case done := <-w.flushes:
+			batch = w.sendBuffered(batch)
+			close(done)
+
+		case <-w.closeChan:
+			w.sendBuffered(batch)
+			w.closeErr = w.sink.Close()
+			close(w.doneChan)
+			return
+		}
+	}
+}
+
+// sendBuffered sends `batch` and all buffered entries.
+func (w *SinkWriter) sendBuffered(batch []*SinkEntry) []*SinkEntry {
+	for {
+		select {
+		case entry := <-w.entries:
+			if batch = append(batch, entry); len(batch) >= w.config.BatchSize {
+				batch = w.send(batch)
+			}
+		default:
+			return w.send(batch)
+		}
+	}
+}
+
+// send sends `batch` to Sink with retry, and returns a new empty batch.
+// The entries are dropped if it still fails after retrying.
+func (w *SinkWriter) send(batch []*SinkEntry) []*SinkEntry {
+	if len(batch) == 0 {
+		return batch
+	}
+	var (
+		ctx      = context.Background()
+		interval = w.config.RetryInterval
+		err      error
+	)
+	for i := 0; ; i++ {
+		if err = w.sink.Send(ctx, batch); err == nil {
+			break
+		}
+		if i >= w.config.RetryCount {
+			break
+		}
+		// It does not wait for retrying if the writer is closing.
+		select {
+		case <-time.After(interval):
+		case <-w.closeChan:
+		}
+		if interval *= 2; interval > w.config.RetryMaxInterval {
+			interval = w.config.RetryMaxInterval
+		}
+	}
+	if err != nil {
+		w.dropped.Add(int64(len(batch)))
+		intlog.Errorf(ctx, `sink sending failed, %d entries dropped: %+v`, len(batch), err)
+	}
+	return make([]*SinkEntry, 0, w.config.BatchSize)
+}
+
+// getSinkConfigWithDefaults returns `config` with default values for its empty attributes.
+func getSinkConfigWithDefaults(config SinkConfig) SinkConfig {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultSinkTimeout
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultSinkBufferSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultSinkBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultSinkFlushInterval
+	}
+	if config.RetryCount == 0 {
+		config.RetryCount = defaultSinkRetryCount
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultSinkRetryInterval
+	}
+	if config.RetryMaxInterval <= 0 {
+		config.RetryMaxInterval = defaultSinkRetryMaxInterval
+	}
+	config.DropPolicy = strings.ToLower(config.DropPolicy)
+	return config
+}
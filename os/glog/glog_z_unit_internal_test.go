@@ -11,6 +11,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
 	"github.com/ximplez-go/gf/test/gtest"
 	"github.com/ximplez-go/gf/text/gstr"
 )
@@ -129,3 +131,27 @@ func Test_LevelPrefix(t *testing.T) {
 		t.Assert(gstr.Contains(buffer.String(), "error"), true)
 	})
 }
+
+func Test_RemoveStaleCompressingFiles(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			l    = New()
+			path = gfile.Temp(gtime.TimestampNanoStr())
+		)
+		t.AssertNil(l.SetPath(path))
+		defer gfile.Remove(path)
+
+		var (
+			staleFile  = gfile.Join(path, "access.20200326101301899002.log.gz.tmp")
+			backupFile = gfile.Join(path, "access.20200326101301899002.log")
+			otherFile  = gfile.Join(path, "other.tmp")
+		)
+		for _, file := range []string{staleFile, backupFile, otherFile} {
+			t.AssertNil(gfile.PutContents(file, "content"))
+		}
+		l.removeStaleCompressingFiles(ctx)
+		t.Assert(gfile.Exists(staleFile), false)
+		t.Assert(gfile.Exists(backupFile), true)
+		t.Assert(gfile.Exists(otherFile), true)
+	})
+}
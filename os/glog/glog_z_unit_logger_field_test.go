@@ -0,0 +1,102 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/net/gtrace"
+	"github.com/ximplez-go/gf/os/glog"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func newTracingCtx() context.Context {
+	traceId, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanId, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceId,
+		SpanID:  spanId,
+	}))
+}
+
+func Test_Fields_Json(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w      = bytes.NewBuffer(nil)
+			logger = glog.NewWithWriter(w)
+		)
+		logger.SetStdoutPrint(false)
+		logger.SetHandlers(glog.HandlerJson)
+		t.AssertNil(logger.SetConfigWithMap(map[string]interface{}{
+			"baggageKeys": []string{"tenant", "absent"},
+		}))
+		ctx := gtrace.SetBaggageValue(newTracingCtx(), "tenant", "t1")
+		userLogger := logger.With("uid", 1000, "Time", "ignored").Fields(map[string]any{
+			"ok":   true,
+			"tags": []string{"a", "b"},
+		})
+		userLogger.Info(ctx, "login")
+
+		var m map[string]any
+		t.AssertNil(json.Unmarshal(w.Bytes(), &m))
+		t.Assert(m["Content"], "login")
+		t.Assert(m["TraceId"], "4bf92f3577b34da6a3ce929d0e0e4736")
+		t.Assert(m["SpanId"], "00f067aa0ba902b7")
+		t.Assert(m["uid"], 1000)
+		t.Assert(m["ok"], true)
+		t.Assert(m["tags"], []string{"a", "b"})
+		t.Assert(m["tenant"], "t1")
+		t.AssertNE(m["Time"], "ignored")
+		_, ok := m["absent"]
+		t.Assert(ok, false)
+		t.Assert(strings.Count(w.String(), `"Time"`), 1)
+
+		// The original logger is not affected.
+		w.Reset()
+		logger.Info(ctx, "logout")
+		t.Assert(strings.Contains(w.String(), "uid"), false)
+		t.Assert(strings.Contains(w.String(), `"tenant":"t1"`), true)
+	})
+}
+
+func Test_Fields_Structure(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w      = bytes.NewBuffer(nil)
+			logger = glog.NewWithWriter(w)
+		)
+		logger.SetStdoutPrint(false)
+		logger.SetHandlers(glog.HandlerStructure)
+		logger.With("uid", 1000, "name", "john smith").With("uid", 1001).Info(newTracingCtx(), "login")
+		content := w.String()
+		t.Assert(strings.Contains(content, "SpanId=00f067aa0ba902b7"), true)
+		t.Assert(strings.Contains(content, `Content=login uid=1001 name="john smith"`), true)
+		t.Assert(strings.Contains(content, "uid=1000"), false)
+	})
+}
+
+func Test_Fields_Default(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w      = bytes.NewBuffer(nil)
+			logger = glog.NewWithWriter(w)
+		)
+		logger.SetStdoutPrint(false)
+		logger.With("uid", 1000, "key").Info(ctx, "login")
+		t.Assert(strings.Contains(w.String(), "[INFO] login uid=1000 key="), true)
+
+		w.Reset()
+		glog.With("uid", 1).To(w).Stdout(false).Info(ctx, "1")
+		t.Assert(strings.Contains(w.String(), "1 uid=1"), true)
+	})
+}
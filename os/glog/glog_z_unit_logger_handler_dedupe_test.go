@@ -0,0 +1,147 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/os/glog"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+// safeBuffer is a concurrent safe buffer for logging writer.
+type safeBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
+
+func Test_Dedupe(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w      = &safeBuffer{}
+			logger = glog.NewWithWriter(w)
+		)
+		logger.SetStdoutPrint(false)
+		logger.SetStack(false)
+		t.AssertNil(logger.SetConfigWithMap(map[string]interface{}{
+			"dedupeLimit":    3,
+			"dedupeInterval": "200ms",
+		}))
+		for i := 0; i < 10; i++ {
+			logger.Errorf(ctx, "connection failed: %d", i)
+		}
+		// The different level or format is counted separately.
+		logger.Warningf(ctx, "connection failed: %d", 100)
+		logger.Error(ctx, "plain content")
+		logger.Line().Errorf(ctx, "connection failed: %d", 101)
+
+		content := w.String()
+		t.Assert(strings.Count(content, "connection failed"), 4)
+		t.Assert(strings.Contains(content, "connection failed: 2"), true)
+		t.Assert(strings.Contains(content, "connection failed: 3"), false)
+		t.Assert(strings.Contains(content, "[WARN] connection failed: 100"), true)
+		t.Assert(strings.Contains(content, "plain content"), true)
+		t.Assert(strings.Contains(content, "suppressed"), false)
+
+		time.Sleep(500 * time.Millisecond)
+		content = w.String()
+		t.Assert(strings.Count(content, "suppressed"), 1)
+		t.Assert(strings.Contains(content, "suppressed 8 similar messages in 200ms: connection failed: %d"), true)
+
+		// New interval.
+		logger.Errorf(ctx, "connection failed: %d", 102)
+		t.Assert(strings.Contains(w.String(), "connection failed: 102"), true)
+	})
+}
+
+func Test_Dedupe_Handler(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w      = &safeBuffer{}
+			logger = glog.NewWithWriter(w)
+		)
+		logger.SetStdoutPrint(false)
+		logger.SetHandlers(glog.HandlerDedupe, glog.HandlerJson)
+		for i := 0; i < 20; i++ {
+			logger.Info(ctx, "same content")
+		}
+		t.Assert(strings.Count(w.String(), "same content"), 10)
+		t.Assert(strings.Count(w.String(), `"Level":"INFO"`), 10)
+	})
+}
+
+func Test_Dedupe_Async(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w      = &safeBuffer{}
+			logger = glog.NewWithWriter(w)
+			wg     sync.WaitGroup
+		)
+		logger.SetStdoutPrint(false)
+		logger.SetAsync(true)
+		t.AssertNil(logger.SetConfigWithMap(map[string]interface{}{
+			"dedupeLimit":    5,
+			"dedupeInterval": "300ms",
+		}))
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					logger.Infof(ctx, "async content %d", j)
+				}
+			}()
+		}
+		wg.Wait()
+		time.Sleep(600 * time.Millisecond)
+		content := w.String()
+		t.Assert(strings.Count(content, "async content"), 6)
+		t.Assert(strings.Contains(content, "suppressed 95 similar messages"), true)
+	})
+}
+
+func Test_Dedupe_SetConfig(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w1      = &safeBuffer{}
+			w2      = &safeBuffer{}
+			logger1 = glog.NewWithWriter(w1)
+			logger2 = glog.New()
+		)
+		logger1.SetStdoutPrint(false)
+		logger1.SetStack(false)
+		t.AssertNil(logger1.SetConfigWithMap(map[string]interface{}{
+			"dedupeLimit":    2,
+			"dedupeInterval": "1m",
+		}))
+		// The occurrences are not shared by the loggers using the same configuration.
+		t.AssertNil(logger2.SetConfig(logger1.GetConfig()))
+		logger2.SetWriter(w2)
+		for i := 0; i < 3; i++ {
+			logger1.Errorf(ctx, "connection failed: %d", i)
+			logger2.Errorf(ctx, "connection failed: %d", i)
+		}
+		t.Assert(strings.Count(w1.String(), "connection failed"), 2)
+		t.Assert(strings.Count(w2.String(), "connection failed"), 2)
+	})
+}
@@ -8,6 +8,10 @@ package glog_test
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -111,3 +115,199 @@ func Test_Rotate_Expire(t *testing.T) {
 		t.Assert(len(files), 0)
 	})
 }
+
+func Test_Rotate_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		p := gfile.Temp(gtime.TimestampNanoStr())
+		err := l.SetConfigWithMap(g.Map{
+			"Path":              p,
+			"File":              "access.log",
+			"StdoutPrint":       false,
+			"RotateSize":        "1K",
+			"RotateBackupLimit": 100000,
+		})
+		t.AssertNil(err)
+		defer gfile.Remove(p)
+
+		var (
+			wg         sync.WaitGroup
+			goroutines = 20
+			lines      = 200
+		)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < lines; j++ {
+					l.Printf(ctx, "line-%d-%d", i, j)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		logFiles, err := gfile.ScanDirFile(p, "access*")
+		t.AssertNil(err)
+		t.AssertGT(len(logFiles), 1)
+		counts := make(map[string]int)
+		for _, v := range logFiles {
+			for _, line := range gstr.SplitAndTrim(gfile.GetContents(v), "\n") {
+				counts[line[strings.Index(line, "line-"):]]++
+			}
+		}
+		t.Assert(len(counts), goroutines*lines)
+		for i := 0; i < goroutines; i++ {
+			for j := 0; j < lines; j++ {
+				t.Assert(counts[fmt.Sprintf("line-%d-%d", i, j)], 1)
+			}
+		}
+	})
+}
+
+func Test_Rotate_Calendar(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		for calendar, elapsed := range map[string]time.Duration{
+			glog.RotateCalendarHourly: time.Hour,
+			glog.RotateCalendarDaily:  24 * time.Hour,
+		} {
+			l := glog.New()
+			p := gfile.Temp(gtime.TimestampNanoStr())
+			err := l.SetConfigWithMap(g.Map{
+				"Path":              p,
+				"File":              "access.log",
+				"StdoutPrint":       false,
+				"RotateCalendar":    calendar,
+				"RotateBackupLimit": 10,
+			})
+			t.AssertNil(err)
+
+			filePath := gfile.Join(p, "access.log")
+			l.Print(ctx, "1")
+			l.Print(ctx, "2")
+			files, err := gfile.ScanDirFile(p, "access*")
+			t.AssertNil(err)
+			t.Assert(len(files), 1)
+
+			// Last written in previous calendar period.
+			mtime := time.Now().Add(-elapsed)
+			t.AssertNil(os.Chtimes(filePath, mtime, mtime))
+			l.Print(ctx, "3")
+			files, err = gfile.ScanDirFile(p, "access.*.log")
+			t.AssertNil(err)
+			t.Assert(len(files), 1)
+			t.Assert(gstr.Count(gfile.GetContents(files[0]), "\n"), 2)
+			t.Assert(strings.HasSuffix(gfile.GetContents(filePath), " 3\n"), true)
+			_ = gfile.Remove(p)
+		}
+	})
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		t.AssertNE(l.SetConfigWithMap(g.Map{
+			"RotateCalendar": "weekly",
+		}), nil)
+	})
+}
+
+func Test_Rotate_BackupMaxBytes(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		p := gfile.Temp(gtime.TimestampNanoStr())
+		err := l.SetConfigWithMap(g.Map{
+			"Path":                 p,
+			"File":                 "access.log",
+			"StdoutPrint":          false,
+			"RotateSize":           10,
+			"RotateBackupLimit":    100,
+			"RotateBackupMaxBytes": 100,
+		})
+		t.AssertNil(err)
+		defer gfile.Remove(p)
+
+		for i := 0; i < 10; i++ {
+			l.Printf(ctx, "content-%d", i)
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(2 * time.Second)
+
+		files, err := gfile.ScanDirFile(p, "access.*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 2)
+		var content string
+		for _, file := range files {
+			content += gfile.GetContents(file)
+		}
+		t.Assert(len(content) <= 100, true)
+		t.Assert(strings.Contains(content, "content-7"), true)
+		t.Assert(strings.Contains(content, "content-8"), true)
+	})
+	// The backups are limited by size only without RotateBackupLimit.
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		p := gfile.Temp(gtime.TimestampNanoStr())
+		err := l.SetConfigWithMap(g.Map{
+			"Path":                 p,
+			"File":                 "access.log",
+			"StdoutPrint":          false,
+			"RotateSize":           10,
+			"RotateBackupMaxBytes": 100,
+		})
+		t.AssertNil(err)
+		defer gfile.Remove(p)
+
+		for i := 0; i < 10; i++ {
+			l.Printf(ctx, "content-%d", i)
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(2 * time.Second)
+
+		files, err := gfile.ScanDirFile(p, "access.*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 2)
+		var content string
+		for _, file := range files {
+			content += gfile.GetContents(file)
+		}
+		t.Assert(len(content) <= 100, true)
+		t.Assert(strings.Contains(content, "content-8"), true)
+	})
+}
+
+func Test_Rotate_BackupFormat(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		t.AssertNE(l.SetConfigWithMap(g.Map{
+			"RotateBackupFormat": "unknown",
+		}), nil)
+
+		glog.RegisterRotateCompressor("copy", ".copy", func(src, dst string, level int) error {
+			return gfile.CopyFile(src, dst)
+		})
+		p := gfile.Temp(gtime.TimestampNanoStr())
+		err := l.SetConfigWithMap(g.Map{
+			"Path":                 p,
+			"File":                 "access.log",
+			"StdoutPrint":          false,
+			"RotateSize":           10,
+			"RotateBackupLimit":    10,
+			"RotateBackupCompress": 1,
+			"RotateBackupFormat":   "copy",
+		})
+		t.AssertNil(err)
+		defer gfile.Remove(p)
+
+		l.Print(ctx, "1234567890abcdefg")
+		l.Print(ctx, "1234567890abcdefg")
+		time.Sleep(2 * time.Second)
+
+		files, err := gfile.ScanDirFile(p, "*.copy")
+		t.AssertNil(err)
+		t.Assert(len(files), 1)
+		t.Assert(gstr.Count(gfile.GetContents(files[0]), "1234567890abcdefg"), 1)
+		files, err = gfile.ScanDirFile(p, "*.tmp")
+		t.AssertNil(err)
+		t.Assert(len(files), 0)
+		files, err = gfile.ScanDirFile(p, "access.*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 0)
+	})
+}
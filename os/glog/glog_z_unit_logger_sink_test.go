@@ -0,0 +1,467 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/glog"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+// testSink records the sent entries, and fails the first `failures` sending.
+type testSink struct {
+	mu       sync.Mutex
+	batches  [][]*glog.SinkEntry
+	failures int
+	block    chan struct{}
+	closed   bool
+}
+
+func (s *testSink) Send(ctx context.Context, entries []*glog.SinkEntry) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sending failed")
+	}
+	s.batches = append(s.batches, append([]*glog.SinkEntry(nil), entries...))
+	return nil
+}
+
+func (s *testSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *testSink) Entries() []*glog.SinkEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []*glog.SinkEntry
+	for _, batch := range s.batches {
+		entries = append(entries, batch...)
+	}
+	return entries
+}
+
+func Test_Sink_Batch(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{}
+			logger = glog.New()
+		)
+		logger.SetStdoutPrint(false)
+		writer := logger.AddSink(sink, glog.SinkConfig{
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+		})
+		logger.Info(ctx, "1")
+		logger.Error(ctx, "2", "3")
+		logger.Warning(ctx, "4")
+		t.AssertNil(writer.Flush(ctx))
+
+		entries := sink.Entries()
+		t.Assert(len(entries), 3)
+		t.Assert(len(sink.batches), 2)
+		t.Assert(entries[0].Level, glog.LEVEL_INFO)
+		t.Assert(entries[0].Content, "1")
+		t.Assert(entries[1].LevelFormat, "ERRO")
+		t.Assert(entries[1].Content, "2 3")
+		t.Assert(strings.Contains(entries[1].Text, "[ERRO] 2 3"), true)
+		t.Assert(strings.HasSuffix(entries[1].Text, "\n"), false)
+		t.Assert(writer.Dropped(), 0)
+
+		t.AssertNil(logger.CloseSinks(ctx))
+		t.Assert(sink.closed, true)
+		t.Assert(len(logger.GetSinks()), 0)
+		t.Assert(writer.Close(ctx), nil)
+		writer.Write(ctx, &glog.SinkEntry{})
+		t.Assert(writer.Dropped(), 1)
+	})
+	// Flushing by interval.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{}
+			logger = glog.New()
+		)
+		logger.SetStdoutPrint(false)
+		logger.AddSink(sink, glog.SinkConfig{
+			FlushInterval: 50 * time.Millisecond,
+		})
+		defer logger.CloseSinks(ctx)
+		logger.Info(ctx, "1")
+		time.Sleep(200 * time.Millisecond)
+		t.Assert(len(sink.Entries()), 1)
+	})
+}
+
+func Test_Sink_DropPolicy(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{block: make(chan struct{})}
+			writer = glog.NewSinkWriter(sink, glog.SinkConfig{
+				BufferSize: 2,
+				BatchSize:  1,
+			})
+		)
+		// The first entry is taken by the blocked sending.
+		writer.Write(ctx, &glog.SinkEntry{Content: "0"})
+		time.Sleep(50 * time.Millisecond)
+		for i := 1; i <= 4; i++ {
+			writer.Write(ctx, &glog.SinkEntry{Content: gtime.Now().String()})
+		}
+		t.Assert(writer.Dropped(), 2)
+		close(sink.block)
+		t.AssertNil(writer.Close(ctx))
+		t.Assert(len(sink.Entries()), 3)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{block: make(chan struct{})}
+			writer = glog.NewSinkWriter(sink, glog.SinkConfig{
+				BufferSize: 2,
+				BatchSize:  1,
+				DropPolicy: glog.SinkDropOldest,
+			})
+		)
+		writer.Write(ctx, &glog.SinkEntry{Content: "0"})
+		time.Sleep(50 * time.Millisecond)
+		for _, content := range []string{"1", "2", "3", "4"} {
+			writer.Write(ctx, &glog.SinkEntry{Content: content})
+		}
+		t.Assert(writer.Dropped(), 2)
+		close(sink.block)
+		t.AssertNil(writer.Close(ctx))
+		entries := sink.Entries()
+		t.Assert(len(entries), 3)
+		t.Assert(entries[1].Content, "3")
+		t.Assert(entries[2].Content, "4")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{block: make(chan struct{})}
+			writer = glog.NewSinkWriter(sink, glog.SinkConfig{
+				BufferSize: 1,
+				BatchSize:  1,
+				DropPolicy: glog.SinkDropBlock,
+			})
+		)
+		writer.Write(ctx, &glog.SinkEntry{Content: "0"})
+		time.Sleep(50 * time.Millisecond)
+		writer.Write(ctx, &glog.SinkEntry{Content: "1"})
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		writer.Write(timeoutCtx, &glog.SinkEntry{Content: "2"})
+		t.Assert(writer.Dropped(), 1)
+		close(sink.block)
+		t.AssertNil(writer.Close(ctx))
+		t.Assert(len(sink.Entries()), 2)
+	})
+}
+
+func Test_Sink_Retry(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{failures: 2}
+			writer = glog.NewSinkWriter(sink, glog.SinkConfig{
+				RetryInterval: time.Millisecond,
+			})
+		)
+		writer.Write(ctx, &glog.SinkEntry{Content: "1"})
+		t.AssertNil(writer.Flush(ctx))
+		t.Assert(len(sink.Entries()), 1)
+		t.Assert(writer.Dropped(), 0)
+		t.AssertNil(writer.Close(ctx))
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{failures: 1}
+			writer = glog.NewSinkWriter(sink, glog.SinkConfig{
+				RetryCount: -1,
+			})
+		)
+		writer.Write(ctx, &glog.SinkEntry{Content: "1"})
+		writer.Write(ctx, &glog.SinkEntry{Content: "2"})
+		t.AssertNil(writer.Flush(ctx))
+		t.Assert(len(sink.Entries()), 0)
+		t.Assert(writer.Dropped(), 2)
+		t.AssertNil(writer.Close(ctx))
+	})
+}
+
+func Test_Sink_ConcurrentAdd(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sinks  = make([]*testSink, 10)
+			logger = glog.New()
+			wg     sync.WaitGroup
+		)
+		logger.SetStdoutPrint(false)
+		for i := range sinks {
+			sinks[i] = &testSink{}
+			wg.Add(2)
+			go func(sink *testSink) {
+				defer wg.Done()
+				logger.AddSink(sink)
+			}(sinks[i])
+			go func() {
+				defer wg.Done()
+				logger.Info(ctx, "1")
+			}()
+		}
+		wg.Wait()
+		t.Assert(len(logger.GetSinks()), len(sinks))
+		logger.Info(ctx, "2")
+		t.AssertNil(logger.CloseSinks(ctx))
+		for _, sink := range sinks {
+			entries := sink.Entries()
+			t.AssertGT(len(entries), 0)
+			t.Assert(entries[len(entries)-1].Content, "2")
+		}
+	})
+}
+
+func Test_Sink_SetConfigWithMap_Invalid(t *testing.T) {
+	var created = make(chan *testSink, 10)
+	glog.RegisterSink("test-invalid", func(config glog.SinkConfig) (glog.Sink, error) {
+		sink := &testSink{}
+		created <- sink
+		return sink, nil
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			sink   = &testSink{}
+			logger = glog.New()
+		)
+		logger.SetStdoutPrint(false)
+		logger.AddSink(sink)
+
+		// The sinks are not created for invalid configuration.
+		err := logger.SetConfigWithMap(map[string]interface{}{
+			"level": "unknown",
+			"sinks": []interface{}{map[string]interface{}{"type": "test-invalid"}},
+		})
+		t.AssertNE(err, nil)
+		t.Assert(len(created), 0)
+
+		// The created sinks are closed if configuration fails applying.
+		err = logger.SetConfigWithMap(map[string]interface{}{
+			"rotateCalendar": "weekly",
+			"sinks":          []interface{}{map[string]interface{}{"type": "test-invalid"}},
+		})
+		t.AssertNE(err, nil)
+		t.Assert(len(created), 1)
+		t.Assert((<-created).closed, true)
+
+		// The previous sinks are kept.
+		t.Assert(len(logger.GetSinks()), 1)
+		t.Assert(sink.closed, false)
+		logger.Info(ctx, "1")
+		t.AssertNil(logger.CloseSinks(ctx))
+		t.Assert(len(sink.Entries()), 1)
+	})
+}
+
+func Test_Sink_Syslog(t *testing.T) {
+	// UDP.
+	gtest.C(t, func(t *gtest.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		t.AssertNil(err)
+		defer conn.Close()
+
+		logger := glog.New()
+		logger.SetStdoutPrint(false)
+		_, err = logger.AddSinkWithConfig(glog.SinkConfig{
+			Type:    glog.SinkTypeSyslog,
+			Address: "udp://" + conn.LocalAddr().String(),
+			AppName: "my app",
+		})
+		t.AssertNil(err)
+		logger.Error(ctx, "syslog message")
+		t.AssertNil(logger.CloseSinks(ctx))
+
+		buffer := make([]byte, 1024)
+		t.AssertNil(conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buffer)
+		t.AssertNil(err)
+		message := string(buffer[:n])
+		t.Assert(strings.HasPrefix(message, "<11>1 "), true)
+		t.Assert(strings.Contains(message, " my_app "), true)
+		// The time and level are only in the syslog header.
+		t.Assert(strings.Contains(message, " - - syslog message"), true)
+		t.Assert(strings.Contains(message, "[ERRO]"), false)
+	})
+	// TCP with octet counting.
+	gtest.C(t, func(t *gtest.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		t.AssertNil(err)
+		defer listener.Close()
+		received := make(chan string, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			data, _ := io.ReadAll(conn)
+			received <- string(data)
+		}()
+
+		logger := glog.New()
+		logger.SetStdoutPrint(false)
+		_, err = logger.AddSinkWithConfig(glog.SinkConfig{
+			Type:     glog.SinkTypeSyslog,
+			Address:  "tcp://" + listener.Addr().String(),
+			Facility: 16,
+		})
+		t.AssertNil(err)
+		logger.Info(ctx, "1")
+		logger.Debug(ctx, "2")
+		t.AssertNil(logger.CloseSinks(ctx))
+
+		var data string
+		select {
+		case data = <-received:
+		case <-time.After(time.Second):
+		}
+		reader := bufio.NewReader(strings.NewReader(data))
+		for _, expect := range []string{"<134>1 ", "<135>1 "} {
+			length, err := reader.ReadString(' ')
+			t.AssertNil(err)
+			size, err := strconv.Atoi(strings.TrimSpace(length))
+			t.AssertNil(err)
+			buffer := make([]byte, size)
+			_, err = io.ReadFull(reader, buffer)
+			t.AssertNil(err)
+			t.Assert(strings.HasPrefix(string(buffer), expect), true)
+		}
+	})
+	gtest.C(t, func(t *gtest.T) {
+		_, err := glog.NewSink(glog.SinkConfig{Type: glog.SinkTypeSyslog, Address: "quic://127.0.0.1:514"})
+		t.AssertNE(err, nil)
+		_, err = glog.NewSink(glog.SinkConfig{Type: "unknown"})
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Sink_Http(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			mu      sync.Mutex
+			outputs []glog.HandlerOutputJson
+			token   string
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var batch []glog.HandlerOutputJson
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &batch); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			outputs = append(outputs, batch...)
+			token = r.Header.Get("Authorization")
+			mu.Unlock()
+		}))
+		defer server.Close()
+
+		logger := glog.New()
+		logger.SetStdoutPrint(false)
+		err := logger.SetConfigWithMap(map[string]interface{}{
+			"level": "all",
+			"sinks": []interface{}{
+				map[string]interface{}{
+					"type":          "http",
+					"url":           server.URL,
+					"headers":       map[string]interface{}{"Authorization": "Bearer token"},
+					"batchSize":     10,
+					"flushInterval": "1h",
+				},
+			},
+		})
+		t.AssertNil(err)
+		t.Assert(len(logger.GetSinks()), 1)
+		logger.Info(ctx, "1")
+		logger.Warning(ctx, "2")
+		t.AssertNil(logger.GetSinks()[0].Flush(ctx))
+
+		mu.Lock()
+		t.Assert(len(outputs), 2)
+		t.Assert(outputs[0].Level, "INFO")
+		t.Assert(outputs[0].Content, "1")
+		t.Assert(outputs[1].Level, "WARN")
+		t.Assert(token, "Bearer token")
+		mu.Unlock()
+		t.AssertNil(logger.CloseSinks(ctx))
+	})
+	gtest.C(t, func(t *gtest.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		sink, err := glog.NewSinkHttp(glog.SinkConfig{Url: server.URL})
+		t.AssertNil(err)
+		t.AssertNE(sink.Send(ctx, []*glog.SinkEntry{{Content: "1"}}), nil)
+	})
+}
+
+func Test_Sink_Unix(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp(gtime.TimestampNanoStr() + ".sock")
+		listener, err := net.Listen("unix", path)
+		t.AssertNil(err)
+		defer listener.Close()
+		received := make(chan string, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			data, _ := io.ReadAll(conn)
+			received <- string(data)
+		}()
+
+		logger := glog.New()
+		logger.SetStdoutPrint(false)
+		logger.SetHeaderPrint(false)
+		_, err = logger.AddSinkWithConfig(glog.SinkConfig{
+			Type:    glog.SinkTypeUnix,
+			Address: "unix://" + path,
+		})
+		t.AssertNil(err)
+		logger.Info(ctx, "1")
+		logger.Info(ctx, "2")
+		t.AssertNil(logger.CloseSinks(ctx))
+
+		select {
+		case data := <-received:
+			t.Assert(data, "1\n2\n")
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+	})
+}
@@ -10,9 +10,10 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 
 	"github.com/ximplez-go/gf/internal/json"
-	"github.com/ximplez-go/gf/os/gfile"
 )
 
 // Attributes is a slice of Attribute.
@@ -46,7 +47,20 @@ var (
 
 func init() {
 	hostname, _ = os.Hostname()
-	processPath = gfile.SelfPath()
+	processPath = getProcessPath()
+}
+
+// getProcessPath returns absolute file path of current running process, like gfile.SelfPath.
+// It does not use package gfile here, as gfile depends on gcache, which uses gmetric.
+func getProcessPath() string {
+	path, _ := exec.LookPath(os.Args[0])
+	if path != "" {
+		path, _ = filepath.Abs(path)
+	}
+	if path == "" {
+		path, _ = filepath.Abs(os.Args[0])
+	}
+	return path
 }
 
 // CommonAttributes returns the common used attributes for an instrument.
@@ -7,9 +7,9 @@
 package gmetric
 
 import (
-	"github.com/ximplez-go/gf/encoding/gjson"
 	"github.com/ximplez-go/gf/errors/gcode"
 	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/json"
 	"github.com/ximplez-go/gf/text/gregex"
 )
 
@@ -23,10 +23,11 @@ func (meter *localMeter) newMetric(
 	metricType MetricType, metricName string, metricOption MetricOption,
 ) (Metric, error) {
 	if metricName == "" {
+		optionBytes, _ := json.Marshal(metricOption)
 		return nil, gerror.NewCodef(
 			gcode.CodeInvalidParameter,
 			`error creating %s metric while given name is empty, option: %s`,
-			metricType, gjson.MustEncodeString(metricOption),
+			metricType, optionBytes,
 		)
 	}
 	if !gregex.IsMatchString(MetricNamePattern, metricName) {
@@ -0,0 +1,250 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmetric
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+)
+
+// PrometheusProvider is a Provider that keeps all metric values in memory and renders them
+// in the Prometheus text exposition format on demand.
+//
+// The global attributes, the Meter attributes and the constant Metric attributes are rendered
+// as labels of every series of the metric, along with the dynamic attributes given in Option.
+type PrometheusProvider interface {
+	Provider
+
+	// Handler serves the Prometheus text exposition of all metrics, which can be registered
+	// to any http server as the scraping endpoint.
+	http.Handler
+
+	// Export collects all metrics, calling the callbacks of observable metrics,
+	// and writes them in the Prometheus text exposition format to `writer`.
+	Export(ctx context.Context, writer io.Writer) error
+}
+
+const (
+	// PrometheusContentType is the content type for the Prometheus text exposition format.
+	PrometheusContentType = `text/plain; version=0.0.4; charset=utf-8`
+)
+
+// localPrometheusProvider implements interface PrometheusProvider.
+type localPrometheusProvider struct {
+	mu        sync.RWMutex                   // mu protects families and callbacks.
+	collectMu sync.Mutex                     // collectMu serializes the metrics collecting.
+	families  map[string]*prometheusFamily   // families maps the exposition name to its metric family.
+	callbacks []CallbackItem                 // callbacks holds the callbacks registered for observable metrics.
+	closed    *gtype.Bool                    // closed marks the provider is shut down.
+	metrics   map[*prometheusMetric]struct{} // metrics holds all metrics created by current provider.
+}
+
+// prometheusFamily is a group of metrics sharing the same exposition name.
+type prometheusFamily struct {
+	name       string
+	help       string
+	metricType MetricType
+	metrics    []*prometheusMetric
+}
+
+var (
+	// Check the implements for interface PrometheusProvider.
+	_ PrometheusProvider = (*localPrometheusProvider)(nil)
+)
+
+// NewPrometheusProvider creates and returns a Provider that exports metrics
+// in the Prometheus text exposition format.
+func NewPrometheusProvider() PrometheusProvider {
+	return &localPrometheusProvider{
+		families: make(map[string]*prometheusFamily),
+		closed:   gtype.NewBool(),
+		metrics:  make(map[*prometheusMetric]struct{}),
+	}
+}
+
+// SetAsGlobal sets current provider as global meter provider for current process.
+// It also initializes all metrics that were created before the provider is set.
+func (p *localPrometheusProvider) SetAsGlobal() {
+	SetGlobalProvider(p)
+	for _, metric := range GetAllMetrics() {
+		initializer, ok := metric.(MetricInitializer)
+		if !ok {
+			continue
+		}
+		if err := initializer.Init(p); err != nil {
+			intlog.Errorf(context.Background(), `%+v`, err)
+		}
+	}
+}
+
+// MeterPerformer creates and returns the MeterPerformer that can produce kinds of metric Performer.
+func (p *localPrometheusProvider) MeterPerformer(option MeterOption) MeterPerformer {
+	return &prometheusMeterPerformer{
+		MeterOption: option,
+		provider:    p,
+	}
+}
+
+// ForceFlush does nothing as the metrics are pulled by the scraper,
+// but it honors the deadline or cancellation of ctx.
+func (p *localPrometheusProvider) ForceFlush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Shutdown shuts down the Provider, after which the Export returns error.
+func (p *localPrometheusProvider) Shutdown(ctx context.Context) error {
+	p.closed.Set(true)
+	return ctx.Err()
+}
+
+// ServeHTTP implements the interface http.Handler.
+func (p *localPrometheusProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buffer = bytes.NewBuffer(nil)
+	if err := p.Export(r.Context(), buffer); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", PrometheusContentType)
+	_, _ = w.Write(buffer.Bytes())
+}
+
+// Export collects all metrics and writes them in the Prometheus text exposition format to `writer`.
+func (p *localPrometheusProvider) Export(ctx context.Context, writer io.Writer) error {
+	if p.closed.Val() {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `prometheus provider is already shut down`)
+	}
+	p.collectMu.Lock()
+	defer p.collectMu.Unlock()
+	p.collectObservables(ctx)
+
+	p.mu.RLock()
+	names := make([]string, 0, len(p.families))
+	for name := range p.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buffer = bytes.NewBuffer(nil)
+	for _, name := range names {
+		p.families[name].render(buffer)
+	}
+	p.mu.RUnlock()
+
+	_, err := writer.Write(buffer.Bytes())
+	if err != nil {
+		err = gerror.Wrap(err, `write prometheus exposition failed`)
+	}
+	return err
+}
+
+// addMetric registers `metric` to its family by the exposition name.
+// It returns the existing metric if the metric of the same name and instrument is already registered.
+func (p *localPrometheusProvider) addMetric(metric *prometheusMetric) (*prometheusMetric, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	family, ok := p.families[metric.exposeName]
+	if !ok {
+		family = &prometheusFamily{
+			name:       metric.exposeName,
+			help:       metric.metricOption.Help,
+			metricType: metric.metricType,
+		}
+		p.families[metric.exposeName] = family
+	}
+	if prometheusTypeName(family.metricType) != prometheusTypeName(metric.metricType) {
+		return nil, gerror.NewCodef(
+			gcode.CodeInvalidParameter,
+			`metric "%s" is already registered with type "%s", cannot register it again with type "%s"`,
+			metric.name, family.metricType, metric.metricType,
+		)
+	}
+	for _, existing := range family.metrics {
+		if existing.metricType == metric.metricType &&
+			existing.meterOption.Instrument == metric.meterOption.Instrument &&
+			existing.meterOption.InstrumentVersion == metric.meterOption.InstrumentVersion {
+			return existing, nil
+		}
+	}
+	family.metrics = append(family.metrics, metric)
+	p.metrics[metric] = struct{}{}
+	return metric, nil
+}
+
+// addCallback registers callback for observable metrics.
+func (p *localPrometheusProvider) addCallback(item CallbackItem) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callbacks = append(p.callbacks, item)
+}
+
+// collectObservables calls all callbacks of observable metrics to refresh their values.
+func (p *localPrometheusProvider) collectObservables(ctx context.Context) {
+	// The callbacks registered by Meter are stored globally, which are taken over by current provider.
+	for _, item := range GetRegisteredCallbacks() {
+		p.addCallback(item)
+	}
+	p.mu.RLock()
+	var (
+		observables = make([]*prometheusMetric, 0)
+		callbacks   = make([]CallbackItem, len(p.callbacks))
+	)
+	for metric := range p.metrics {
+		if metric.isObservable() {
+			observables = append(observables, metric)
+		}
+	}
+	copy(callbacks, p.callbacks)
+	p.mu.RUnlock()
+
+	for _, metric := range observables {
+		metric.reset()
+	}
+	for _, metric := range observables {
+		if metric.metricOption.Callback == nil {
+			continue
+		}
+		if err := metric.metricOption.Callback(ctx, &prometheusMetricObserver{metric: metric}); err != nil {
+			intlog.Errorf(ctx, `callback of metric "%s" failed: %+v`, metric.name, err)
+		}
+	}
+	for _, item := range callbacks {
+		observer := &prometheusObserver{
+			provider: p,
+			metrics:  item.Metrics,
+		}
+		if err := item.Callback(ctx, observer); err != nil {
+			intlog.Errorf(ctx, `metric callback failed: %+v`, err)
+		}
+	}
+}
+
+// render writes the family in the Prometheus text exposition format to `buffer`.
+func (f *prometheusFamily) render(buffer *bytes.Buffer) {
+	if f.help != "" {
+		buffer.WriteString("# HELP ")
+		buffer.WriteString(f.name)
+		buffer.WriteByte(' ')
+		buffer.WriteString(prometheusEscapeHelp(f.help))
+		buffer.WriteByte('\n')
+	}
+	buffer.WriteString("# TYPE ")
+	buffer.WriteString(f.name)
+	buffer.WriteByte(' ')
+	buffer.WriteString(prometheusTypeName(f.metricType))
+	buffer.WriteByte('\n')
+	for _, metric := range f.metrics {
+		metric.render(buffer)
+	}
+}
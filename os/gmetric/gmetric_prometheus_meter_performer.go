@@ -0,0 +1,95 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmetric
+
+// prometheusMeterPerformer implements interface MeterPerformer for PrometheusProvider.
+type prometheusMeterPerformer struct {
+	MeterOption
+	provider *localPrometheusProvider
+}
+
+// CounterPerformer creates and returns a CounterPerformer that performs
+// the operations for Counter metric.
+func (m *prometheusMeterPerformer) CounterPerformer(name string, option MetricOption) (CounterPerformer, error) {
+	metric, err := m.newMetric(MetricTypeCounter, name, option)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusCounterPerformer{prometheusMetric: metric}, nil
+}
+
+// UpDownCounterPerformer creates and returns a UpDownCounterPerformer that performs
+// the operations for UpDownCounter metric.
+func (m *prometheusMeterPerformer) UpDownCounterPerformer(name string, option MetricOption) (UpDownCounterPerformer, error) {
+	metric, err := m.newMetric(MetricTypeUpDownCounter, name, option)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusUpDownCounterPerformer{prometheusMetric: metric}, nil
+}
+
+// HistogramPerformer creates and returns a HistogramPerformer that performs
+// the operations for Histogram metric.
+func (m *prometheusMeterPerformer) HistogramPerformer(name string, option MetricOption) (HistogramPerformer, error) {
+	metric, err := m.newMetric(MetricTypeHistogram, name, option)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusHistogramPerformer{prometheusMetric: metric}, nil
+}
+
+// ObservableCounterPerformer creates and returns an ObservableCounterPerformer that performs
+// the operations for ObservableCounter metric.
+func (m *prometheusMeterPerformer) ObservableCounterPerformer(name string, option MetricOption) (ObservableCounterPerformer, error) {
+	metric, err := m.newMetric(MetricTypeObservableCounter, name, option)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusObservablePerformer{prometheusMetric: metric}, nil
+}
+
+// ObservableUpDownCounterPerformer creates and returns an ObservableUpDownCounterPerformer that performs
+// the operations for ObservableUpDownCounter metric.
+func (m *prometheusMeterPerformer) ObservableUpDownCounterPerformer(name string, option MetricOption) (ObservableUpDownCounterPerformer, error) {
+	metric, err := m.newMetric(MetricTypeObservableUpDownCounter, name, option)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusObservablePerformer{prometheusMetric: metric}, nil
+}
+
+// ObservableGaugePerformer creates and returns an ObservableGaugePerformer that performs
+// the operations for ObservableGauge metric.
+func (m *prometheusMeterPerformer) ObservableGaugePerformer(name string, option MetricOption) (ObservableGaugePerformer, error) {
+	metric, err := m.newMetric(MetricTypeObservableGauge, name, option)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusObservablePerformer{prometheusMetric: metric}, nil
+}
+
+// RegisterCallback registers callback on certain metrics.
+// The callback is called each time the metrics are exported.
+func (m *prometheusMeterPerformer) RegisterCallback(callback Callback, observableMetrics ...ObservableMetric) error {
+	if len(observableMetrics) == 0 {
+		return nil
+	}
+	m.provider.addCallback(CallbackItem{
+		Callback:    callback,
+		Metrics:     observableMetrics,
+		MeterOption: m.MeterOption,
+		Provider:    m.provider,
+	})
+	return nil
+}
+
+// newMetric creates and registers a prometheusMetric to the provider.
+func (m *prometheusMeterPerformer) newMetric(
+	metricType MetricType, metricName string, metricOption MetricOption,
+) (*prometheusMetric, error) {
+	return m.provider.addMetric(newPrometheusMetric(metricType, metricName, m.MeterOption, metricOption))
+}
@@ -0,0 +1,61 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmetric
+
+import (
+	"context"
+
+	"github.com/ximplez-go/gf/internal/intlog"
+)
+
+// prometheusObserver implements interface Observer for callbacks registered by RegisterCallback.
+type prometheusObserver struct {
+	provider *localPrometheusProvider
+	metrics  []ObservableMetric // Metrics that the callback is registered on.
+}
+
+// prometheusMetricObserver implements interface MetricObserver for the Callback of MetricOption.
+type prometheusMetricObserver struct {
+	metric *prometheusMetric
+}
+
+// Observe observes the value for certain initialized Metric.
+// It adds the value to total result if the observed Metrics is type of Counter.
+// It sets the value as the result if the observed Metrics is type of Gauge.
+func (o *prometheusObserver) Observe(m ObservableMetric, value float64, option ...Option) {
+	var registered bool
+	for _, metric := range o.metrics {
+		if metric == m {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		intlog.Printf(context.Background(), `observed metric is not registered in current callback, ignored`)
+		return
+	}
+	// The observed metric might be the Metric object or the performer itself.
+	var performer any = m
+	if exporter, ok := m.(PerformerExporter); ok {
+		performer = exporter.Performer()
+	}
+	observable, ok := performer.(*prometheusObservablePerformer)
+	if !ok {
+		return
+	}
+	o.provider.mu.RLock()
+	_, ok = o.provider.metrics[observable.prometheusMetric]
+	o.provider.mu.RUnlock()
+	if ok {
+		observable.observe(value, option)
+	}
+}
+
+// Observe observes the value for the bound Metric.
+func (o *prometheusMetricObserver) Observe(value float64, option ...Option) {
+	o.metric.observe(value, option)
+}
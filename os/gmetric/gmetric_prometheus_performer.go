@@ -0,0 +1,359 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmetric
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+var (
+	// defaultPrometheusBuckets is the default buckets for Histogram if no buckets configured,
+	// which is the same as the default buckets of Prometheus client.
+	defaultPrometheusBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// prometheusMetric stores the values of all series for one metric in memory.
+type prometheusMetric struct {
+	mu           sync.Mutex
+	name         string                       // Original metric name.
+	exposeName   string                       // Metric name in exposition format.
+	metricType   MetricType                   // Metric type.
+	meterOption  MeterOption                  // Option of the Meter creating this metric.
+	metricOption MetricOption                 // Option of this metric.
+	buckets      []float64                    // Sorted buckets for Histogram.
+	series       map[string]*prometheusSeries // Series values keyed by their dynamic attributes.
+}
+
+// prometheusSeries is a single series of a metric, identified by its dynamic attributes.
+type prometheusSeries struct {
+	attributes Attributes // Dynamic attributes of this series.
+	value      float64    // Value for Counter and Gauge.
+	counts     []uint64   // Non-cumulative bucket counts for Histogram.
+	sum        float64    // Sum of observations for Histogram.
+	count      uint64     // Count of observations for Histogram.
+}
+
+// prometheusCounterPerformer implements interface CounterPerformer.
+type prometheusCounterPerformer struct {
+	*prometheusMetric
+}
+
+// prometheusUpDownCounterPerformer implements interface UpDownCounterPerformer.
+type prometheusUpDownCounterPerformer struct {
+	*prometheusMetric
+}
+
+// prometheusHistogramPerformer implements interface HistogramPerformer.
+type prometheusHistogramPerformer struct {
+	*prometheusMetric
+}
+
+// prometheusObservablePerformer implements interface ObservableMetric,
+// of which the values are refreshed by callbacks when exporting.
+type prometheusObservablePerformer struct {
+	*prometheusMetric
+}
+
+// newPrometheusMetric creates and returns a prometheusMetric.
+func newPrometheusMetric(
+	metricType MetricType, metricName string, meterOption MeterOption, metricOption MetricOption,
+) *prometheusMetric {
+	metric := &prometheusMetric{
+		name:         metricName,
+		exposeName:   prometheusSanitizeName(metricName, true),
+		metricType:   metricType,
+		meterOption:  meterOption,
+		metricOption: metricOption,
+		series:       make(map[string]*prometheusSeries),
+	}
+	if metricType == MetricTypeHistogram {
+		buckets := metricOption.Buckets
+		if len(buckets) == 0 {
+			buckets = defaultPrometheusBuckets
+		}
+		metric.buckets = make([]float64, len(buckets))
+		copy(metric.buckets, buckets)
+		sort.Float64s(metric.buckets)
+	}
+	return metric
+}
+
+// Inc increments the counter by 1.
+func (p *prometheusCounterPerformer) Inc(ctx context.Context, option ...Option) {
+	p.add(1, false, option)
+}
+
+// Add adds the given value to the counter. It panics if the value is < 0.
+func (p *prometheusCounterPerformer) Add(ctx context.Context, increment float64, option ...Option) {
+	if increment < 0 {
+		panic(gerror.NewCodef(
+			gcode.CodeInvalidParameter,
+			`counter "%s" cannot be decreased, but given increment value is %v`,
+			p.name, increment,
+		))
+	}
+	p.add(increment, false, option)
+}
+
+// Inc increments the counter by 1.
+func (p *prometheusUpDownCounterPerformer) Inc(ctx context.Context, option ...Option) {
+	p.add(1, false, option)
+}
+
+// Dec decrements the counter by 1.
+func (p *prometheusUpDownCounterPerformer) Dec(ctx context.Context, option ...Option) {
+	p.add(-1, false, option)
+}
+
+// Add adds the given value to the counter.
+func (p *prometheusUpDownCounterPerformer) Add(ctx context.Context, increment float64, option ...Option) {
+	p.add(increment, false, option)
+}
+
+// Record adds a single value to the histogram.
+func (p *prometheusHistogramPerformer) Record(increment float64, option ...Option) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	series := p.getSeries(option)
+	for i, bound := range p.buckets {
+		if increment <= bound {
+			series.counts[i]++
+			break
+		}
+	}
+	series.sum += increment
+	series.count++
+}
+
+func (p *prometheusObservablePerformer) observable() {}
+
+// observe records an observed value for observable metric.
+// It adds the value if the metric is ObservableCounter, or else it sets the value.
+func (m *prometheusMetric) observe(value float64, option []Option) {
+	m.add(value, m.metricType != MetricTypeObservableCounter, option)
+}
+
+// isObservable checks and returns whether the metric is observable metric.
+func (m *prometheusMetric) isObservable() bool {
+	switch m.metricType {
+	case MetricTypeObservableCounter, MetricTypeObservableUpDownCounter, MetricTypeObservableGauge:
+		return true
+	default:
+		return false
+	}
+}
+
+// reset clears all series of the metric,
+// which is used for observable metric before each collecting.
+func (m *prometheusMetric) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.series = make(map[string]*prometheusSeries)
+}
+
+// add adds or sets the value of the series specified by `option`.
+func (m *prometheusMetric) add(value float64, set bool, option []Option) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	series := m.getSeries(option)
+	if set {
+		series.value = value
+	} else {
+		series.value += value
+	}
+}
+
+// getSeries retrieves or creates the series by the dynamic attributes in `option`.
+// Note that it should be called with lock held.
+func (m *prometheusMetric) getSeries(option []Option) *prometheusSeries {
+	var attributes Attributes
+	for _, opt := range option {
+		attributes = append(attributes, opt.Attributes...)
+	}
+	key := prometheusSeriesKey(attributes)
+	series, ok := m.series[key]
+	if !ok {
+		series = &prometheusSeries{
+			attributes: attributes,
+		}
+		if m.metricType == MetricTypeHistogram {
+			series.counts = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = series
+	}
+	return series
+}
+
+// constantAttributes returns the attributes for all series of the metric,
+// which are global attributes, meter attributes and metric attributes in sequence.
+func (m *prometheusMetric) constantAttributes() Attributes {
+	var attributes = GetGlobalAttributes(GetGlobalAttributesOption{
+		Instrument:        m.meterOption.Instrument,
+		InstrumentVersion: m.meterOption.InstrumentVersion,
+	})
+	attributes = append(attributes, m.meterOption.Attributes...)
+	attributes = append(attributes, m.metricOption.Attributes...)
+	return attributes
+}
+
+// render writes all series of the metric in the Prometheus text exposition format to `buffer`.
+func (m *prometheusMetric) render(buffer *bytes.Buffer) {
+	constants := m.constantAttributes()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var (
+			series = m.series[key]
+			labels = prometheusLabels(constants, series.attributes)
+		)
+		if m.metricType != MetricTypeHistogram {
+			prometheusWriteSample(buffer, m.exposeName, labels, "", series.value)
+			continue
+		}
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += series.counts[i]
+			prometheusWriteSample(
+				buffer, m.exposeName+"_bucket", labels,
+				`le="`+prometheusFormatFloat(bound)+`"`, float64(cumulative),
+			)
+		}
+		prometheusWriteSample(buffer, m.exposeName+"_bucket", labels, `le="+Inf"`, float64(series.count))
+		prometheusWriteSample(buffer, m.exposeName+"_sum", labels, "", series.sum)
+		prometheusWriteSample(buffer, m.exposeName+"_count", labels, "", float64(series.count))
+	}
+}
+
+// prometheusSeriesKey generates and returns a unique key for given attributes.
+func prometheusSeriesKey(attributes Attributes) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+	var items = make([]string, 0, len(attributes))
+	for _, attr := range attributes {
+		items = append(items, attr.Key()+"\xff"+gconv.String(attr.Value()))
+	}
+	sort.Strings(items)
+	return strings.Join(items, "\xfe")
+}
+
+// prometheusLabels merges the constant and dynamic attributes into rendered labels,
+// in which the latter attribute overwrites the former one of the same key.
+func prometheusLabels(constants, dynamics Attributes) string {
+	var labelMap = make(map[string]string)
+	for _, attrs := range []Attributes{constants, dynamics} {
+		for _, attr := range attrs {
+			labelMap[prometheusSanitizeName(attr.Key(), false)] = gconv.String(attr.Value())
+		}
+	}
+	if len(labelMap) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labelMap))
+	for name := range labelMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var builder strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(name)
+		builder.WriteString(`="`)
+		builder.WriteString(prometheusEscapeLabelValue(labelMap[name]))
+		builder.WriteByte('"')
+	}
+	return builder.String()
+}
+
+// prometheusWriteSample writes one sample line to `buffer`.
+func prometheusWriteSample(buffer *bytes.Buffer, name, labels, extraLabel string, value float64) {
+	buffer.WriteString(name)
+	if labels != "" || extraLabel != "" {
+		buffer.WriteByte('{')
+		buffer.WriteString(labels)
+		if labels != "" && extraLabel != "" {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteString(extraLabel)
+		buffer.WriteByte('}')
+	}
+	buffer.WriteByte(' ')
+	buffer.WriteString(prometheusFormatFloat(value))
+	buffer.WriteByte('\n')
+}
+
+// prometheusFormatFloat formats float value in the exposition format.
+func prometheusFormatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return gconv.String(value)
+	}
+}
+
+// prometheusTypeName returns the type name in exposition format of given MetricType.
+func prometheusTypeName(metricType MetricType) string {
+	switch metricType {
+	case MetricTypeCounter, MetricTypeObservableCounter:
+		return "counter"
+	case MetricTypeHistogram:
+		return "histogram"
+	default:
+		return "gauge"
+	}
+}
+
+// prometheusSanitizeName replaces the characters that are not allowed in metric or label name with '_'.
+// The colon character is allowed only in metric name.
+func prometheusSanitizeName(name string, isMetricName bool) string {
+	var builder strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				builder.WriteByte('_')
+			}
+		case r == ':' && isMetricName:
+		default:
+			r = '_'
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// prometheusEscapeHelp escapes the help text in exposition format.
+func prometheusEscapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// prometheusEscapeLabelValue escapes the label value in exposition format.
+func prometheusEscapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
@@ -0,0 +1,202 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmetric_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ximplez-go/gf/os/gmetric"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func Test_PrometheusProvider_Counter(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.Background()
+			provider = gmetric.NewPrometheusProvider()
+			meter    = provider.MeterPerformer(gmetric.MeterOption{
+				Instrument: "test",
+				Attributes: gmetric.Attributes{gmetric.NewAttribute("meter", "m")},
+			})
+		)
+		counter, err := meter.CounterPerformer("goframe.test.counter", gmetric.MetricOption{
+			Help:       "counter help",
+			Attributes: gmetric.Attributes{gmetric.NewAttribute("const", 1)},
+		})
+		t.AssertNil(err)
+		counter.Inc(ctx)
+		counter.Add(ctx, 2)
+		counter.Add(ctx, 5, gmetric.Option{
+			Attributes: gmetric.Attributes{gmetric.NewAttribute("path", `/a"b`)},
+		})
+
+		updown, err := meter.UpDownCounterPerformer("goframe.test.updown", gmetric.MetricOption{})
+		t.AssertNil(err)
+		updown.Inc(ctx)
+		updown.Inc(ctx)
+		updown.Dec(ctx)
+
+		var buffer = bytes.NewBuffer(nil)
+		t.AssertNil(provider.Export(ctx, buffer))
+		t.Assert(buffer.String(), `# HELP goframe_test_counter counter help
+# TYPE goframe_test_counter counter
+goframe_test_counter{const="1",meter="m"} 3
+goframe_test_counter{const="1",meter="m",path="/a\"b"} 5
+# TYPE goframe_test_updown gauge
+goframe_test_updown{meter="m"} 1
+`)
+	})
+	// Negative increment for counter.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			provider   = gmetric.NewPrometheusProvider()
+			counter, _ = provider.MeterPerformer(gmetric.MeterOption{}).CounterPerformer(
+				"goframe.test.counter", gmetric.MetricOption{},
+			)
+		)
+		defer func() {
+			t.AssertNE(recover(), nil)
+		}()
+		counter.Add(context.Background(), -1)
+	})
+	// Conflict type.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			provider = gmetric.NewPrometheusProvider()
+			meter    = provider.MeterPerformer(gmetric.MeterOption{})
+		)
+		_, err := meter.CounterPerformer("goframe.test.metric", gmetric.MetricOption{})
+		t.AssertNil(err)
+		_, err = meter.HistogramPerformer("goframe.test.metric", gmetric.MetricOption{})
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_PrometheusProvider_Histogram(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.Background()
+			provider = gmetric.NewPrometheusProvider()
+			meter    = provider.MeterPerformer(gmetric.MeterOption{})
+		)
+		histogram, err := meter.HistogramPerformer("goframe.test.histogram", gmetric.MetricOption{
+			Buckets: []float64{10, 1},
+		})
+		t.AssertNil(err)
+		histogram.Record(0.5)
+		histogram.Record(5)
+		histogram.Record(5)
+		histogram.Record(100)
+
+		var buffer = bytes.NewBuffer(nil)
+		t.AssertNil(provider.Export(ctx, buffer))
+		t.Assert(buffer.String(), `# TYPE goframe_test_histogram histogram
+goframe_test_histogram_bucket{le="1"} 1
+goframe_test_histogram_bucket{le="10"} 3
+goframe_test_histogram_bucket{le="+Inf"} 4
+goframe_test_histogram_sum 110.5
+goframe_test_histogram_count 4
+`)
+	})
+}
+
+func Test_PrometheusProvider_Observable(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.Background()
+			provider = gmetric.NewPrometheusProvider()
+			meter    = provider.MeterPerformer(gmetric.MeterOption{})
+			value    = 1.0
+		)
+		_, err := meter.ObservableGaugePerformer("goframe.test.gauge", gmetric.MetricOption{
+			Callback: func(ctx context.Context, obs gmetric.MetricObserver) error {
+				obs.Observe(value)
+				return nil
+			},
+		})
+		t.AssertNil(err)
+		counter, err := meter.ObservableCounterPerformer("goframe.test.observable.counter", gmetric.MetricOption{})
+		t.AssertNil(err)
+		err = meter.RegisterCallback(func(ctx context.Context, obs gmetric.Observer) error {
+			obs.Observe(counter, 2)
+			obs.Observe(counter, 3)
+			return nil
+		}, counter)
+		t.AssertNil(err)
+
+		var buffer = bytes.NewBuffer(nil)
+		t.AssertNil(provider.Export(ctx, buffer))
+		t.Assert(buffer.String(), `# TYPE goframe_test_gauge gauge
+goframe_test_gauge 1
+# TYPE goframe_test_observable_counter counter
+goframe_test_observable_counter 5
+`)
+
+		value = 2
+		buffer.Reset()
+		t.AssertNil(provider.Export(ctx, buffer))
+		t.Assert(buffer.String(), `# TYPE goframe_test_gauge gauge
+goframe_test_gauge 2
+# TYPE goframe_test_observable_counter counter
+goframe_test_observable_counter 5
+`)
+	})
+}
+
+func Test_PrometheusProvider_Global(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx         = context.Background()
+			provider    = gmetric.NewPrometheusProvider()
+			meterOption = gmetric.MeterOption{
+				Instrument:        "goframe.test.prometheus.global",
+				InstrumentVersion: "v1.0",
+			}
+			meter = gmetric.GetGlobalProvider().Meter(meterOption)
+		)
+		// Metric created before the provider is set as global.
+		counter := meter.MustCounter("goframe.test.prometheus.global.counter", gmetric.MetricOption{})
+		counter.Inc(ctx)
+		provider.SetAsGlobal()
+		defer gmetric.SetGlobalProvider(nil)
+		counter.Inc(ctx)
+
+		gauge := meter.MustObservableGauge("goframe.test.prometheus.global.gauge", gmetric.MetricOption{})
+		meter.MustRegisterCallback(func(ctx context.Context, obs gmetric.Observer) error {
+			obs.Observe(gauge, 10)
+			return nil
+		}, gauge)
+
+		gmetric.SetGlobalAttributes(gmetric.Attributes{
+			gmetric.NewAttribute("service", "test"),
+		}, gmetric.SetGlobalAttributesOption{
+			Instrument:        meterOption.Instrument,
+			InstrumentVersion: meterOption.InstrumentVersion,
+		})
+
+		server := httptest.NewServer(provider)
+		defer server.Close()
+		response, err := server.Client().Get(server.URL)
+		t.AssertNil(err)
+		defer response.Body.Close()
+		t.Assert(response.Header.Get("Content-Type"), gmetric.PrometheusContentType)
+		content, err := io.ReadAll(response.Body)
+		t.AssertNil(err)
+		t.Assert(string(content), `# TYPE goframe_test_prometheus_global_counter counter
+goframe_test_prometheus_global_counter{service="test"} 1
+# TYPE goframe_test_prometheus_global_gauge gauge
+goframe_test_prometheus_global_gauge{service="test"} 10
+`)
+
+		t.AssertNil(provider.Shutdown(ctx))
+		t.AssertNE(provider.Export(ctx, bytes.NewBuffer(nil)), nil)
+	})
+}
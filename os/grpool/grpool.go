@@ -5,14 +5,22 @@
 // You can obtain one at https://github.com/gogf/gf.
 
 // Package grpool implements a goroutine reusable pool.
+//
+// The pool queues the jobs without limit in default, and it can also bound the queue with
+// a rejection policy for the jobs added when the queue is full. The jobs can be submitted
+// with Future for their errors and panics, and the pool can be shut down gracefully after
+// all queued and running jobs are done.
 package grpool
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/ximplez-go/gf/container/glist"
 	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
 	"github.com/ximplez-go/gf/os/gtimer"
 	"github.com/ximplez-go/gf/util/grand"
 )
@@ -20,51 +28,108 @@ import (
 // Func is the pool function which contains context parameter.
 type Func func(ctx context.Context)
 
+// ErrorFunc is the pool function which returns error, which is used for job submitted with Future.
+type ErrorFunc func(ctx context.Context) error
+
 // RecoverFunc is the pool runtime panic recover function which contains context parameter.
 type RecoverFunc func(ctx context.Context, exception error)
 
 // Pool manages the goroutines using pool.
 type Pool struct {
-	limit  int         // Max goroutine count limit.
-	count  *gtype.Int  // Current running goroutine count.
-	list   *glist.List // List for asynchronous job adding purpose.
-	closed *gtype.Bool // Is pool closed or not.
+	limit     int           // Max goroutine count limit.
+	count     *gtype.Int    // Current running goroutine count.
+	list      *glist.List   // List for asynchronous job adding purpose.
+	closed    *gtype.Bool   // Is pool closed or not.
+	draining  *gtype.Bool   // Is pool shutting down, which rejects new jobs but still runs the queued jobs.
+	addMu     sync.RWMutex  // Mutex making the closing atomic against the pushing and popping of jobs.
+	option    Option        // Options of the pool.
+	slots     chan struct{} // Slots of the bounded queue, which is nil if the queue is not bounded.
+	closeChan chan struct{} // Closed when the pool is closed, which wakes up the waiting callers.
+	closeOnce sync.Once     // Used for closing closeChan only once.
+	pendingMu sync.Mutex    // Mutex for pending and idleChan.
+	pending   int           // Count of the queued and running jobs.
+	idleChan  chan struct{} // Closed when all pending jobs are done, which is nil if there are no pending jobs.
 }
 
+// Option is the options for Pool creation.
+type Option struct {
+	Name         string        // (optional) Name of the pool, which is used as the attribute of metrics.
+	Limit        int           // (optional) Max goroutine count, which is not limited in default.
+	QueueSize    int           // (optional) Max queued job count, which is not limited in default.
+	RejectPolicy RejectPolicy  // (optional) Policy for the job added when the queue is full, which is RejectPolicyError in default.
+	Timeout      time.Duration // (optional) Timeout for each job, which is not limited in default.
+}
+
+// RejectPolicy specifies how to handle the job added when the queue is full.
+type RejectPolicy int
+
+const (
+	RejectPolicyError      RejectPolicy = iota // Rejects the job and returns ErrRejected.
+	RejectPolicyBlock                          // Blocks the caller until the queue has room, the context is done or the pool is closed.
+	RejectPolicyDrop                           // Drops the job silently.
+	RejectPolicyCallerRuns                     // Runs the job in the caller goroutine.
+)
+
 // localPoolItem is the job item storing in job list.
 type localPoolItem struct {
-	Ctx  context.Context // Context.
-	Func Func            // Job function.
+	Ctx     context.Context // Context.
+	Func    Func            // Job function.
+	Timeout time.Duration   // Timeout of the job, which is not limited if it is 0.
+	Discard func(err error) // Called with the error if the queued job is discarded without running, which can be nil.
 }
 
 const (
 	minSupervisorTimerDuration = 500 * time.Millisecond
 	maxSupervisorTimerDuration = 1500 * time.Millisecond
+	defaultPoolName            = "default"
+)
+
+var (
+	// ErrRejected is returned when the job is rejected as the queue is full.
+	ErrRejected = gerror.NewCode(gcode.CodeServerBusy, "job is rejected as the queue of goroutine pool is full")
+
+	// ErrClosed is returned when adding job to the pool which is closed or shutting down.
+	ErrClosed = gerror.NewCode(gcode.CodeInvalidOperation, "goroutine pool is already closed")
 )
 
 // Default goroutine pool.
 var (
-	defaultPool = New()
+	defaultPool = NewWithOption(Option{Name: defaultPoolName})
 )
 
 // New creates and returns a new goroutine pool object.
 // The parameter `limit` is used to limit the max goroutine count,
 // which is not limited in default.
 func New(limit ...int) *Pool {
+	var option Option
+	if len(limit) > 0 {
+		option.Limit = limit[0]
+	}
+	return NewWithOption(option)
+}
+
+// NewWithOption creates and returns a new goroutine pool object with `option`.
+func NewWithOption(option Option) *Pool {
 	var (
 		pool = &Pool{
-			limit:  -1,
-			count:  gtype.NewInt(),
-			list:   glist.New(true),
-			closed: gtype.NewBool(),
+			limit:     -1,
+			count:     gtype.NewInt(),
+			list:      glist.New(true),
+			closed:    gtype.NewBool(),
+			draining:  gtype.NewBool(),
+			option:    option,
+			closeChan: make(chan struct{}),
 		}
 		timerDuration = grand.D(
 			minSupervisorTimerDuration,
 			maxSupervisorTimerDuration,
 		)
 	)
-	if len(limit) > 0 && limit[0] > 0 {
-		pool.limit = limit[0]
+	if option.Limit > 0 {
+		pool.limit = option.Limit
+	}
+	if option.QueueSize > 0 {
+		pool.slots = make(chan struct{}, option.QueueSize)
 	}
 	gtimer.Add(context.Background(), timerDuration, pool.supervisor)
 	return pool
@@ -94,3 +159,9 @@ func Size() int {
 func Jobs() int {
 	return defaultPool.Jobs()
 }
+
+// Submit pushes a new job to the default goroutine pool, and returns the Future of the job.
+// Also see Pool.Submit.
+func Submit(ctx context.Context, f ErrorFunc) (*Future, error) {
+	return defaultPool.Submit(ctx, f)
+}
@@ -0,0 +1,110 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package grpool
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+)
+
+// Future is the result of a job submitted to the pool, which is completed after the job is done.
+type Future struct {
+	done chan struct{} // Closed when the job is done.
+	err  error         // Error of the job, which is set before done is closed.
+}
+
+// newFuture creates and returns an uncompleted Future.
+func newFuture() *Future {
+	return &Future{
+		done: make(chan struct{}),
+	}
+}
+
+// Done returns a channel that is closed when the job is done.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the job is done and returns its error.
+// It returns the error of `ctx` if `ctx` is done before the job.
+func (f *Future) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Err returns the error of the job.
+// It returns nil if the job is not done yet or it succeeds.
+func (f *Future) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// complete completes the Future with the error of the job.
+func (f *Future) complete(err error) {
+	f.err = err
+	close(f.done)
+}
+
+// Submit pushes a new job to the pool, and returns the Future of the job.
+// The error returned by the job, or the panic of the job converted to error, is propagated through the Future.
+// The job will be executed asynchronously.
+//
+// If the queue is full, the job is handled by the RejectPolicy of the pool,
+// and the Future of the job dropped by RejectPolicyDrop is completed with ErrRejected.
+// The Future of the queued job is completed with ErrClosed if the pool is closed before the job runs.
+func (p *Pool) Submit(ctx context.Context, f ErrorFunc) (*Future, error) {
+	return p.SubmitWithTimeout(ctx, p.option.Timeout, f)
+}
+
+// SubmitWithTimeout pushes a new job to the pool, of which the context is canceled after `timeout`
+// since it starts running, and returns the Future of the job. It is not limited if `timeout` <= 0.
+// The Future is completed with error of code gcode.CodeOperationFailed wrapping context.DeadlineExceeded
+// if the job exceeds the timeout.
+func (p *Pool) SubmitWithTimeout(ctx context.Context, timeout time.Duration, f ErrorFunc) (*Future, error) {
+	future := newFuture()
+	err := p.doAdd(&localPoolItem{
+		Ctx:     ctx,
+		Timeout: timeout,
+		Discard: future.complete,
+		Func: func(jobCtx context.Context) {
+			var err error
+			defer func() {
+				if exception := recover(); exception != nil {
+					if v, ok := exception.(error); ok && gerror.HasStack(v) {
+						err = v
+					} else {
+						err = gerror.NewCodef(gcode.CodeInternalPanic, "%+v", exception)
+					}
+				} else if err == nil && timeout > 0 && ctx.Err() == nil && errors.Is(jobCtx.Err(), context.DeadlineExceeded) {
+					err = gerror.WrapCodef(gcode.CodeOperationFailed, jobCtx.Err(), `job timeout after %s`, timeout)
+				}
+				future.complete(err)
+			}()
+			err = f(jobCtx)
+		},
+	})
+	if err != nil {
+		if err == ErrRejected && p.option.RejectPolicy == RejectPolicyDrop {
+			future.complete(err)
+			return future, nil
+		}
+		return nil, err
+	}
+	return future, nil
+}
@@ -0,0 +1,106 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package grpool
+
+import (
+	"context"
+
+	"github.com/ximplez-go/gf"
+	"github.com/ximplez-go/gf/os/gmetric"
+)
+
+// localMetricManager manages the metrics of workers and jobs of pools.
+type localMetricManager struct {
+	PoolWorkers      gmetric.UpDownCounter
+	PoolQueuedJobs   gmetric.UpDownCounter
+	PoolRejectedJobs gmetric.Counter
+}
+
+const (
+	instrument            = "github.com/ximplez-go/gf/os/grpool.Pool"
+	metricAttrKeyPoolName = "pool.name"
+)
+
+// metricManager tracks the workers, queued jobs and rejected jobs of every pool by its name.
+var metricManager = newMetricManager()
+
+// newMetricManager creates the worker and job metrics of grpool.
+func newMetricManager() *localMetricManager {
+	meter := gmetric.GetGlobalProvider().Meter(gmetric.MeterOption{
+		Instrument:        instrument,
+		InstrumentVersion: gf.VERSION,
+	})
+	return &localMetricManager{
+		PoolWorkers: meter.MustUpDownCounter(
+			"grpool.pool.workers",
+			gmetric.MetricOption{
+				Help: "Number of worker goroutines of the pool.",
+			},
+		),
+		PoolQueuedJobs: meter.MustUpDownCounter(
+			"grpool.pool.queued_jobs",
+			gmetric.MetricOption{
+				Help: "Number of jobs waiting in the queue of the pool.",
+			},
+		),
+		PoolRejectedJobs: meter.MustCounter(
+			"grpool.pool.rejected_jobs",
+			gmetric.MetricOption{
+				Help: "Total number of jobs rejected as the queue of the pool is full.",
+			},
+		),
+	}
+}
+
+// newMetricOption creates and returns the metric operation option for pool named `name`.
+func (m *localMetricManager) newMetricOption(name string) gmetric.Option {
+	return gmetric.Option{
+		Attributes: gmetric.Attributes{
+			gmetric.NewAttribute(metricAttrKeyPoolName, name),
+		},
+	}
+}
+
+// handleWorkerStart records the metrics for a worker starting.
+func (m *localMetricManager) handleWorkerStart(name string) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	m.PoolWorkers.Inc(context.Background(), m.newMetricOption(name))
+}
+
+// handleWorkerExit records the metrics for a worker exiting.
+func (m *localMetricManager) handleWorkerExit(name string) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	m.PoolWorkers.Dec(context.Background(), m.newMetricOption(name))
+}
+
+// handleJobQueued records the metrics for a job pushed to the queue.
+func (m *localMetricManager) handleJobQueued(ctx context.Context, name string) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	m.PoolQueuedJobs.Inc(ctx, m.newMetricOption(name))
+}
+
+// handleJobDequeued records the metrics for a job popped from the queue.
+func (m *localMetricManager) handleJobDequeued(ctx context.Context, name string) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	m.PoolQueuedJobs.Dec(ctx, m.newMetricOption(name))
+}
+
+// handleJobRejected records the metrics for a job rejected as the queue is full.
+func (m *localMetricManager) handleJobRejected(ctx context.Context, name string) {
+	if !gmetric.IsEnabled() {
+		return
+	}
+	m.PoolRejectedJobs.Inc(ctx, m.newMetricOption(name))
+}
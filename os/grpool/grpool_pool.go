@@ -8,6 +8,7 @@ package grpool
 
 import (
 	"context"
+	"time"
 
 	"github.com/ximplez-go/gf/errors/gcode"
 	"github.com/ximplez-go/gf/errors/gerror"
@@ -15,20 +16,26 @@ import (
 
 // Add pushes a new job to the pool.
 // The job will be executed asynchronously.
+//
+// If the queue is full, the job is handled by the RejectPolicy of the pool,
+// and the job dropped by RejectPolicyDrop is ignored without error.
 func (p *Pool) Add(ctx context.Context, f Func) error {
-	for p.closed.Val() {
-		return gerror.NewCode(
-			gcode.CodeInvalidOperation,
-			"goroutine defaultPool is already closed",
-		)
-	}
-	p.list.PushFront(&localPoolItem{
-		Ctx:  ctx,
-		Func: f,
+	return p.AddWithTimeout(ctx, p.option.Timeout, f)
+}
+
+// AddWithTimeout pushes a new job to the pool, of which the context is canceled after `timeout`
+// since it starts running. It is not limited if `timeout` <= 0.
+// The job will be executed asynchronously.
+func (p *Pool) AddWithTimeout(ctx context.Context, timeout time.Duration, f Func) error {
+	err := p.doAdd(&localPoolItem{
+		Ctx:     ctx,
+		Func:    f,
+		Timeout: timeout,
 	})
-	// Check and fork new worker.
-	p.checkAndForkNewGoroutineWorker()
-	return nil
+	if err == ErrRejected && p.option.RejectPolicy == RejectPolicyDrop {
+		return nil
+	}
+	return err
 }
 
 // AddWithRecover pushes a new job to the pool with specified recover function.
@@ -77,8 +84,161 @@ func (p *Pool) IsClosed() bool {
 }
 
 // Close closes the goroutine pool, which makes all goroutines exit.
+// The queued jobs are not executed after the pool is closed, and the Futures of them are completed with ErrClosed.
 func (p *Pool) Close() {
+	p.addMu.Lock()
 	p.closed.Set(true)
+	p.addMu.Unlock()
+	p.closeOnce.Do(func() {
+		close(p.closeChan)
+		// No job is pushed or popped after the pool is closed, so each queued job is discarded only once.
+		for _, listItem := range p.list.BackAll() {
+			poolItem := listItem.(*localPoolItem)
+			metricManager.handleJobDequeued(poolItem.Ctx, p.option.Name)
+			p.addPending(-1)
+			if poolItem.Discard != nil {
+				poolItem.Discard(ErrClosed)
+			}
+		}
+	})
+}
+
+// Wait blocks until all queued and running jobs are done, or the pool is closed.
+func (p *Pool) Wait() {
+	if idleChan := p.getIdleChan(); idleChan != nil {
+		select {
+		case <-idleChan:
+		case <-p.closeChan:
+		}
+	}
+}
+
+// Shutdown stops accepting new jobs, waits until all queued and running jobs are done,
+// and then closes the pool. It closes the pool and returns error without waiting
+// if `ctx` is done before that, and the queued jobs are not executed after closing.
+func (p *Pool) Shutdown(ctx context.Context) (err error) {
+	p.addMu.Lock()
+	p.draining.Set(true)
+	p.addMu.Unlock()
+	// It makes sure the queued jobs have workers.
+	p.checkAndForkNewGoroutineWorker()
+	if idleChan := p.getIdleChan(); idleChan != nil {
+		select {
+		case <-idleChan:
+		case <-p.closeChan:
+		case <-ctx.Done():
+			err = gerror.WrapCodef(
+				gcode.CodeOperationFailed, ctx.Err(),
+				`goroutine pool shutdown with %d pending jobs`, p.getPending(),
+			)
+		}
+	}
+	p.Close()
+	return err
+}
+
+// doAdd pushes `item` to the queue, or handles it by the RejectPolicy if the queue is full.
+func (p *Pool) doAdd(item *localPoolItem) error {
+	if p.closed.Val() || p.draining.Val() {
+		return ErrClosed
+	}
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		default:
+			if err := p.reject(item); err != nil {
+				return err
+			}
+			if p.option.RejectPolicy == RejectPolicyCallerRuns {
+				return nil
+			}
+		}
+	}
+	// The checking and pushing are atomic against closing, so that the pushed job is either run or discarded.
+	p.addMu.RLock()
+	if p.closed.Val() || p.draining.Val() {
+		p.addMu.RUnlock()
+		if p.slots != nil {
+			<-p.slots
+		}
+		return ErrClosed
+	}
+	p.addPending(1)
+	p.list.PushFront(item)
+	p.addMu.RUnlock()
+	metricManager.handleJobQueued(item.Ctx, p.option.Name)
+	// Check and fork new worker.
+	p.checkAndForkNewGoroutineWorker()
+	return nil
+}
+
+// reject handles `item` added when the queue is full by the RejectPolicy.
+// It returns nil if `item` gets a slot of the queue for RejectPolicyBlock,
+// or it is run in caller goroutine for RejectPolicyCallerRuns.
+func (p *Pool) reject(item *localPoolItem) error {
+	switch p.option.RejectPolicy {
+	case RejectPolicyBlock:
+		select {
+		case p.slots <- struct{}{}:
+			return nil
+		case <-item.Ctx.Done():
+			metricManager.handleJobRejected(item.Ctx, p.option.Name)
+			return gerror.WrapCode(gcode.CodeOperationFailed, item.Ctx.Err(), `waiting for queue of goroutine pool failed`)
+		case <-p.closeChan:
+			return ErrClosed
+		}
+
+	case RejectPolicyCallerRuns:
+		metricManager.handleJobRejected(item.Ctx, p.option.Name)
+		p.addPending(1)
+		p.runItem(item)
+		return nil
+
+	default:
+		metricManager.handleJobRejected(item.Ctx, p.option.Name)
+		return ErrRejected
+	}
+}
+
+// runItem runs the job of `item` with its timeout.
+func (p *Pool) runItem(item *localPoolItem) {
+	defer p.addPending(-1)
+	ctx := item.Ctx
+	if item.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, item.Timeout)
+		defer cancel()
+	}
+	item.Func(ctx)
+}
+
+// addPending adds `delta` to the count of pending jobs, and notifies the waiters if there are no pending jobs.
+func (p *Pool) addPending(delta int) {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	p.pending += delta
+	switch {
+	case p.pending > 0 && p.idleChan == nil:
+		p.idleChan = make(chan struct{})
+	case p.pending == 0 && p.idleChan != nil:
+		close(p.idleChan)
+		p.idleChan = nil
+	}
+}
+
+// getPending returns the count of pending jobs.
+func (p *Pool) getPending() int {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	return p.pending
+}
+
+// getIdleChan returns the channel closed when all pending jobs are done,
+// which is nil if there are no pending jobs.
+func (p *Pool) getIdleChan() chan struct{} {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	return p.idleChan
 }
 
 // checkAndForkNewGoroutineWorker checks and creates a new goroutine worker.
@@ -103,19 +263,34 @@ func (p *Pool) checkAndForkNewGoroutineWorker() {
 }
 
 func (p *Pool) asynchronousWorker() {
-	defer p.count.Add(-1)
+	metricManager.handleWorkerStart(p.option.Name)
+	defer func() {
+		p.count.Add(-1)
+		metricManager.handleWorkerExit(p.option.Name)
+	}()
 
 	var (
 		listItem interface{}
 		poolItem *localPoolItem
 	)
 	// Harding working, one by one, job never empty, worker never die.
-	for !p.closed.Val() {
+	for {
+		// The popping is atomic against closing, so that no job is run after its Future is discarded.
+		p.addMu.RLock()
+		if p.closed.Val() {
+			p.addMu.RUnlock()
+			return
+		}
 		listItem = p.list.PopBack()
+		p.addMu.RUnlock()
 		if listItem == nil {
 			return
 		}
+		if p.slots != nil {
+			<-p.slots
+		}
 		poolItem = listItem.(*localPoolItem)
-		poolItem.Func(poolItem.Ctx)
+		metricManager.handleJobDequeued(poolItem.Ctx, p.option.Name)
+		p.runItem(poolItem)
 	}
 }
@@ -0,0 +1,340 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package grpool_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/garray"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gmetric"
+	"github.com/ximplez-go/gf/os/grpool"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+	"github.com/ximplez-go/gf/text/gregex"
+)
+
+// provider is the global metric provider, which is set before any worker starts,
+// as the global provider is read by workers without synchronization.
+var provider = gmetric.NewPrometheusProvider()
+
+func TestMain(m *testing.M) {
+	provider.SetAsGlobal()
+	os.Exit(m.Run())
+}
+
+// newFullPool creates a pool with one worker and a queue of one job, and fills both of them
+// with jobs blocking until the returned channel is closed.
+func newFullPool(t *gtest.T, option grpool.Option) (*grpool.Pool, chan struct{}) {
+	option.Limit = 1
+	option.QueueSize = 1
+	var (
+		pool        = grpool.NewWithOption(option)
+		releaseChan = make(chan struct{})
+		startChan   = make(chan struct{})
+	)
+	t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+		close(startChan)
+		<-releaseChan
+	}))
+	<-startChan
+	t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+		<-releaseChan
+	}))
+	t.Assert(pool.Jobs(), 1)
+	return pool, releaseChan
+}
+
+func Test_RejectPolicy_Error(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		pool, releaseChan := newFullPool(t, grpool.Option{})
+		defer pool.Close()
+		err := pool.Add(ctx, func(ctx context.Context) {})
+		t.Assert(gerror.Is(err, grpool.ErrRejected), true)
+		t.Assert(gerror.Code(err), gcode.CodeServerBusy)
+		future, err := pool.Submit(ctx, func(ctx context.Context) error { return nil })
+		t.Assert(future, nil)
+		t.Assert(gerror.Is(err, grpool.ErrRejected), true)
+		close(releaseChan)
+		pool.Wait()
+		t.AssertNil(pool.Add(ctx, func(ctx context.Context) {}))
+	})
+}
+
+func Test_RejectPolicy_Drop(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array             = garray.New(true)
+			pool, releaseChan = newFullPool(t, grpool.Option{RejectPolicy: grpool.RejectPolicyDrop})
+		)
+		defer pool.Close()
+		t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+			array.Append(1)
+		}))
+		future, err := pool.Submit(ctx, func(ctx context.Context) error {
+			array.Append(1)
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(gerror.Is(future.Wait(ctx), grpool.ErrRejected), true)
+		close(releaseChan)
+		pool.Wait()
+		t.Assert(array.Len(), 0)
+	})
+}
+
+func Test_RejectPolicy_CallerRuns(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array             = garray.New(true)
+			pool, releaseChan = newFullPool(t, grpool.Option{RejectPolicy: grpool.RejectPolicyCallerRuns})
+		)
+		defer pool.Close()
+		t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+			array.Append(1)
+		}))
+		// It runs in the caller goroutine before Add returns.
+		t.Assert(array.Len(), 1)
+		close(releaseChan)
+		pool.Wait()
+	})
+}
+
+func Test_RejectPolicy_Block(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array             = garray.New(true)
+			pool, releaseChan = newFullPool(t, grpool.Option{RejectPolicy: grpool.RejectPolicyBlock})
+		)
+		defer pool.Close()
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		err := pool.Add(timeoutCtx, func(ctx context.Context) {})
+		t.Assert(errors.Is(err, context.DeadlineExceeded), true)
+
+		time.AfterFunc(100*time.Millisecond, func() {
+			close(releaseChan)
+		})
+		start := time.Now()
+		t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+			array.Append(1)
+		}))
+		t.AssertGE(time.Since(start), 50*time.Millisecond)
+		pool.Wait()
+		t.Assert(array.Len(), 1)
+	})
+}
+
+func Test_Pool_Shutdown(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array = garray.New(true)
+			pool  = grpool.New(2)
+		)
+		for i := 0; i < 10; i++ {
+			t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+				time.Sleep(20 * time.Millisecond)
+				array.Append(1)
+			}))
+		}
+		t.AssertNil(pool.Shutdown(ctx))
+		t.Assert(array.Len(), 10)
+		t.Assert(pool.IsClosed(), true)
+		t.Assert(gerror.Is(pool.Add(ctx, func(ctx context.Context) {}), grpool.ErrClosed), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array = garray.New(true)
+			pool  = grpool.New(1)
+		)
+		for i := 0; i < 10; i++ {
+			t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+				time.Sleep(50 * time.Millisecond)
+				array.Append(1)
+			}))
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, 80*time.Millisecond)
+		defer cancel()
+		err := pool.Shutdown(timeoutCtx)
+		t.Assert(errors.Is(err, context.DeadlineExceeded), true)
+		t.Assert(pool.IsClosed(), true)
+		time.Sleep(100 * time.Millisecond)
+		t.AssertLT(array.Len(), 10)
+	})
+}
+
+func Test_Pool_Close_Future(t *testing.T) {
+	// The Futures of the queued jobs are completed after the pool is closed.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			pool        = grpool.New(1)
+			releaseChan = make(chan struct{})
+			startChan   = make(chan struct{})
+			futures     = make([]*grpool.Future, 0)
+		)
+		defer close(releaseChan)
+		t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+			close(startChan)
+			<-releaseChan
+		}))
+		<-startChan
+		for i := 0; i < 5; i++ {
+			future, err := pool.Submit(ctx, func(ctx context.Context) error {
+				return nil
+			})
+			t.AssertNil(err)
+			futures = append(futures, future)
+		}
+		pool.Close()
+		for _, future := range futures {
+			timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+			t.Assert(gerror.Is(future.Wait(timeoutCtx), grpool.ErrClosed), true)
+			cancel()
+		}
+	})
+	// Each Future returned is completed when jobs are submitted during shutdown.
+	gtest.C(t, func(t *gtest.T) {
+		for n := 0; n < 20; n++ {
+			var (
+				pool      = grpool.New(2)
+				doneChan  = make(chan struct{})
+				futureArr = garray.New(true)
+			)
+			go func() {
+				defer close(doneChan)
+				for {
+					future, err := pool.Submit(ctx, func(ctx context.Context) error {
+						return nil
+					})
+					if err != nil {
+						return
+					}
+					futureArr.Append(future)
+				}
+			}()
+			time.Sleep(time.Millisecond)
+			timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+			_ = pool.Shutdown(timeoutCtx)
+			cancel()
+			<-doneChan
+			for _, v := range futureArr.Slice() {
+				select {
+				case <-v.(*grpool.Future).Done():
+				case <-time.After(time.Second):
+					t.Fatal("future is not completed")
+				}
+			}
+		}
+	})
+}
+
+func Test_Pool_Future(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		pool := grpool.New(2)
+		defer pool.Close()
+
+		future, err := pool.Submit(ctx, func(ctx context.Context) error {
+			return nil
+		})
+		t.AssertNil(err)
+		t.AssertNil(future.Wait(ctx))
+
+		future, err = pool.Submit(ctx, func(ctx context.Context) error {
+			return errors.New("failed")
+		})
+		t.AssertNil(err)
+		<-future.Done()
+		t.Assert(future.Err(), "failed")
+
+		future, err = pool.Submit(ctx, func(ctx context.Context) error {
+			panic("exception")
+		})
+		t.AssertNil(err)
+		err = future.Wait(ctx)
+		t.Assert(gerror.Code(err), gcode.CodeInternalPanic)
+		t.Assert(err, "exception")
+
+		future, err = pool.SubmitWithTimeout(ctx, 50*time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		})
+		t.AssertNil(err)
+		err = future.Wait(ctx)
+		t.Assert(errors.Is(err, context.DeadlineExceeded), true)
+
+		// The waiting is canceled before the job is done.
+		future, err = pool.Submit(ctx, func(ctx context.Context) error {
+			time.Sleep(200 * time.Millisecond)
+			return nil
+		})
+		t.AssertNil(err)
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		t.Assert(errors.Is(future.Wait(timeoutCtx), context.DeadlineExceeded), true)
+		t.AssertNil(future.Err())
+		t.AssertNil(future.Wait(ctx))
+	})
+	// The timeout in option applies to all jobs.
+	gtest.C(t, func(t *gtest.T) {
+		pool := grpool.NewWithOption(grpool.Option{Timeout: 50 * time.Millisecond})
+		defer pool.Close()
+		doneChan := make(chan error, 1)
+		t.AssertNil(pool.Add(ctx, func(ctx context.Context) {
+			<-ctx.Done()
+			doneChan <- ctx.Err()
+		}))
+		select {
+		case err := <-doneChan:
+			t.Assert(errors.Is(err, context.DeadlineExceeded), true)
+		case <-time.After(time.Second):
+			t.Error("job is not timeout")
+		}
+	})
+}
+
+func Test_Pool_Metrics(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		name := "metrics" + gtime.TimestampNanoStr()
+		pool, releaseChan := newFullPool(t, grpool.Option{Name: name})
+		defer pool.Close()
+		t.AssertNE(pool.Add(ctx, func(ctx context.Context) {}), nil)
+
+		buffer := bytes.NewBuffer(nil)
+		t.AssertNil(provider.Export(ctx, buffer))
+		content := buffer.String()
+		t.Assert(gregex.IsMatchString(fmt.Sprintf(`grpool_pool_workers\S*\{[^}]*pool_name="%s"[^}]*\} 1`, name), content), true)
+		t.Assert(gregex.IsMatchString(fmt.Sprintf(`grpool_pool_queued_jobs\S*\{[^}]*pool_name="%s"[^}]*\} 1`, name), content), true)
+		t.Assert(gregex.IsMatchString(fmt.Sprintf(`grpool_pool_rejected_jobs\S*\{[^}]*pool_name="%s"[^}]*\} 1`, name), content), true)
+
+		close(releaseChan)
+		pool.Wait()
+		buffer.Reset()
+		t.AssertNil(provider.Export(ctx, buffer))
+		t.Assert(gregex.IsMatchString(fmt.Sprintf(`grpool_pool_queued_jobs\S*\{[^}]*pool_name="%s"[^}]*\} 0`, name), buffer.String()), true)
+	})
+}
+
+func Test_Pool_Metrics_Close(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		name := "metrics-close" + gtime.TimestampNanoStr()
+		pool, releaseChan := newFullPool(t, grpool.Option{Name: name})
+		defer close(releaseChan)
+
+		// The discarded jobs are no longer counted as queued.
+		pool.Close()
+		buffer := bytes.NewBuffer(nil)
+		t.AssertNil(provider.Export(ctx, buffer))
+		t.Assert(gregex.IsMatchString(fmt.Sprintf(`grpool_pool_queued_jobs\S*\{[^}]*pool_name="%s"[^}]*\} 0`, name), buffer.String()), true)
+	})
+}
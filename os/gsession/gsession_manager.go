@@ -8,15 +8,34 @@ package gsession
 
 import (
 	"context"
+	"hash/fnv"
+	"sync"
 	"time"
+
+	"github.com/ximplez-go/gf/container/gmap"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/util/gconv"
 )
 
 // Manager for sessions.
+//
+// The modifications of a session are merged to its latest stored data when it closes,
+// within the lock of the session id in current Manager, so concurrent requests of the same
+// session handled by the Manager do not drop the writes of each other. Note that the merging
+// is not atomic among multiple Managers or processes sharing the same storage, where the
+// concurrent writes of the same session might be lost.
 type Manager struct {
-	ttl     time.Duration // TTL for sessions.
-	storage Storage       // Storage interface for session storage.
+	ttl         time.Duration                // Idle TTL for sessions, which is renewed by every access.
+	absoluteTTL time.Duration                // Absolute TTL for sessions since they are created, which is not renewed.
+	storage     Storage                      // Storage interface for session storage.
+	locks       [sessionLockCount]sync.Mutex // Striped locks for saving sessions and user indexes.
 }
 
+const (
+	// sessionLockCount is the count of striped locks for session ids.
+	sessionLockCount = 64
+)
+
 // New creates and returns a new session manager.
 func New(ttl time.Duration, storage ...Storage) *Manager {
 	m := &Manager{
@@ -56,12 +75,41 @@ func (m *Manager) GetStorage() Storage {
 	return m.storage
 }
 
-// SetTTL the TTL for the session manager.
+// SetTTL the idle TTL for the session manager, which is renewed by every access of the session.
 func (m *Manager) SetTTL(ttl time.Duration) {
 	m.ttl = ttl
 }
 
-// GetTTL returns the TTL of the session manager.
+// GetTTL returns the idle TTL of the session manager.
 func (m *Manager) GetTTL() time.Duration {
 	return m.ttl
 }
+
+// SetAbsoluteTTL sets the absolute TTL for sessions, which is the max lifetime since session is created
+// no matter whether it is accessed. It is disabled if `ttl` <= 0, which is the default.
+func (m *Manager) SetAbsoluteTTL(ttl time.Duration) {
+	m.absoluteTTL = ttl
+}
+
+// GetAbsoluteTTL returns the absolute TTL of the session manager.
+func (m *Manager) GetAbsoluteTTL() time.Duration {
+	return m.absoluteTTL
+}
+
+// lockSession locks the session `id` and returns the function unlocking it.
+func (m *Manager) lockSession(id string) (unlock func()) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	mu := &m.locks[h.Sum32()%sessionLockCount]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// isExpired checks and returns whether session `data` exceeds the absolute TTL.
+func (m *Manager) isExpired(data *gmap.StrAnyMap) bool {
+	if m.absoluteTTL <= 0 {
+		return false
+	}
+	createTime := gconv.Int64(data.Get(sessionKeyCreateTime))
+	return createTime > 0 && createTime+m.absoluteTTL.Milliseconds() <= gtime.TimestampMilli()
+}
@@ -0,0 +1,152 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gmap"
+	"github.com/ximplez-go/gf/crypto/gmd5"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+const (
+	// userIndexIdPrefix is the prefix of the storage id of user index,
+	// which maps session ids of the user to their binding timestamps.
+	userIndexIdPrefix = "gsession_user_"
+)
+
+// UserSessionIds retrieves and returns the ids of all alive sessions bound to user `userId`.
+// The sessions which are expired or bound to other users are removed from the user index.
+func (m *Manager) UserSessionIds(ctx context.Context, userId string) ([]string, error) {
+	indexId := m.userIndexId(userId)
+	unlock := m.lockSession(indexId)
+	defer unlock()
+	index, err := m.getUserIndex(ctx, indexId)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ids   = make([]string, 0)
+		stale = make([]string, 0)
+	)
+	for _, sessionId := range index.Keys() {
+		data, err := m.storage.GetSession(ctx, sessionId, m.ttl)
+		if err != nil && !gerror.Is(err, ErrorDisabled) {
+			return nil, err
+		}
+		if data == nil || m.isExpired(data) || gconv.String(data.Get(sessionKeyUserId)) != userId {
+			stale = append(stale, sessionId)
+			continue
+		}
+		ids = append(ids, sessionId)
+	}
+	if len(stale) > 0 {
+		index.Removes(stale)
+		if index.IsEmpty() {
+			err = m.storage.RemoveAll(ctx, indexId)
+		} else {
+			err = m.storage.SetSession(ctx, indexId, index, m.getUserIndexTTL())
+		}
+		if err != nil && !gerror.Is(err, ErrorDisabled) {
+			return nil, err
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// RemoveUserSessions removes all sessions bound to user `userId`, which logs the user out everywhere.
+// The sessions being accessed concurrently are not saved back when they close.
+func (m *Manager) RemoveUserSessions(ctx context.Context, userId string) error {
+	ids, err := m.UserSessionIds(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = m.removeSession(ctx, id); err != nil {
+			return err
+		}
+	}
+	return m.removeSession(ctx, m.userIndexId(userId))
+}
+
+// addUserSession adds session `sessionId` to the index of user `userId`.
+func (m *Manager) addUserSession(ctx context.Context, userId, sessionId string) error {
+	indexId := m.userIndexId(userId)
+	unlock := m.lockSession(indexId)
+	defer unlock()
+	index, err := m.getUserIndex(ctx, indexId)
+	if err != nil {
+		return err
+	}
+	index.Set(sessionId, gtime.TimestampMilli())
+	err = m.storage.SetSession(ctx, indexId, index, m.getUserIndexTTL())
+	if err != nil && !gerror.Is(err, ErrorDisabled) {
+		return err
+	}
+	return nil
+}
+
+// updateUserIndexTTL renews the index of user `userId` along with the idle TTL of its sessions.
+// It does nothing if absolute TTL is enabled, as the index outlives all its sessions in this case.
+func (m *Manager) updateUserIndexTTL(ctx context.Context, userId string) error {
+	if userId == "" || m.absoluteTTL > 0 {
+		return nil
+	}
+	err := m.storage.UpdateTTL(ctx, m.userIndexId(userId), m.ttl)
+	if err != nil && !gerror.Is(err, ErrorDisabled) {
+		return err
+	}
+	return nil
+}
+
+// getUserIndex retrieves the user index from storage, which is never nil.
+func (m *Manager) getUserIndex(ctx context.Context, indexId string) (*gmap.StrAnyMap, error) {
+	index, err := m.storage.GetSession(ctx, indexId, m.getUserIndexTTL())
+	if err != nil && !gerror.Is(err, ErrorDisabled) {
+		return nil, err
+	}
+	if index == nil {
+		index = gmap.NewStrAnyMap(true)
+	}
+	return index, nil
+}
+
+// removeSession removes session `id` from storage within its lock.
+func (m *Manager) removeSession(ctx context.Context, id string) error {
+	unlock := m.lockSession(id)
+	defer unlock()
+	err := m.storage.RemoveAll(ctx, id)
+	if err != nil && !gerror.Is(err, ErrorDisabled) {
+		return err
+	}
+	return nil
+}
+
+// userIndexId returns the storage id of the index of user `userId`.
+func (m *Manager) userIndexId(userId string) string {
+	return userIndexIdPrefix + gmd5.MustEncryptString(userId)
+}
+
+// isUserIndexId checks and returns whether `id` is the reserved storage id of user index.
+func isUserIndexId(id string) bool {
+	return strings.HasPrefix(id, userIndexIdPrefix)
+}
+
+// getUserIndexTTL returns the TTL of user index, which is the longer one of the idle TTL and absolute TTL.
+func (m *Manager) getUserIndexTTL() time.Duration {
+	if m.absoluteTTL > m.ttl {
+		return m.absoluteTTL
+	}
+	return m.ttl
+}
@@ -26,8 +26,16 @@ type Session struct {
 	data    *gmap.StrAnyMap // Current Session data, which is retrieved from Storage.
 	dirty   bool            // Used to mark session is modified.
 	start   bool            // Used to mark session is started.
+	loaded  bool            // Used to mark session data is loaded from storage.
 	manager *Manager        // Parent session Manager.
 
+	// changes, removes and cleared track the modifications since session starts,
+	// which are merged to the latest stored data when session closes, so concurrent
+	// requests of the same session in the same Manager do not drop the writes of each other.
+	changes map[string]interface{}
+	removes map[string]struct{}
+	cleared bool
+
 	// idFunc is a callback function used for creating custom session id.
 	// This is called if session id is empty ever when session starts.
 	idFunc func(ttl time.Duration) (id string)
@@ -40,6 +48,12 @@ func (s *Session) init() error {
 		return nil
 	}
 	var err error
+	// The session id from client is discarded if it is reserved for user index,
+	// which prevents client from reading or writing the user index as session.
+	if isUserIndexId(s.id) {
+		intlog.Printf(s.ctx, `session id "%s" is reserved, a new one is created`, s.id)
+		s.id = ""
+	}
 	// Session retrieving.
 	if s.id != "" {
 		// Retrieve stored session data from storage.
@@ -50,6 +64,9 @@ func (s *Session) init() error {
 				return err
 			}
 		}
+		if err = s.checkLoadedData(); err != nil {
+			return err
+		}
 	}
 	// Session id creation.
 	if s.id == "" {
@@ -79,6 +96,8 @@ func (s *Session) init() error {
 
 // Close closes current session and updates its ttl in the session manager.
 // If this session is dirty, it also exports it to storage.
+// For TokenStorage, it renews the session id with the token carrying the session data,
+// which should be retrieved by Id after closing.
 //
 // NOTE that this function must be called ever after a session request done.
 func (s *Session) Close() error {
@@ -87,17 +106,29 @@ func (s *Session) Close() error {
 	}
 	if s.start && s.id != "" {
 		size := s.data.Size()
+		// The token carries both the data and expiry, so it is renewed for dirty or alive session.
+		if tokenStorage, ok := s.manager.storage.(TokenStorage); ok {
+			if s.dirty || size > 0 {
+				s.setCreateTimeIfNotExist()
+				id, err := tokenStorage.NewToken(s.ctx, s.id, s.data, s.getTTL())
+				if err != nil {
+					return err
+				}
+				s.id = id
+			}
+			return nil
+		}
 		if s.dirty {
-			err := s.manager.storage.SetSession(s.ctx, s.id, s.data, s.manager.ttl)
-			if err != nil && !gerror.Is(err, ErrorDisabled) {
+			if err := s.save(); err != nil {
 				return err
 			}
 		} else if size > 0 {
-			err := s.manager.storage.UpdateTTL(s.ctx, s.id, s.manager.ttl)
+			err := s.manager.storage.UpdateTTL(s.ctx, s.id, s.getTTL())
 			if err != nil && !gerror.Is(err, ErrorDisabled) {
 				return err
 			}
 		}
+		return s.manager.updateUserIndexTTL(s.ctx, s.getUserId())
 	}
 	return nil
 }
@@ -112,6 +143,7 @@ func (s *Session) Set(key string, value interface{}) (err error) {
 			return err
 		}
 		s.data.Set(key, value)
+		s.trackSet(key, value)
 	}
 	s.dirty = true
 	return nil
@@ -127,6 +159,9 @@ func (s *Session) SetMap(data map[string]interface{}) (err error) {
 			return err
 		}
 		s.data.Sets(data)
+		for key, value := range data {
+			s.trackSet(key, value)
+		}
 	}
 	s.dirty = true
 	return nil
@@ -146,6 +181,7 @@ func (s *Session) Remove(keys ...string) (err error) {
 				return err
 			}
 			s.data.Remove(key)
+			s.trackRemove(key)
 		}
 	}
 	s.dirty = true
@@ -169,6 +205,9 @@ func (s *Session) RemoveAll() (err error) {
 	if s.data != nil {
 		s.data.Clear()
 	}
+	s.changes = nil
+	s.removes = nil
+	s.cleared = true
 	s.dirty = true
 	return nil
 }
@@ -215,10 +254,15 @@ func (s *Session) Data() (sessionData map[string]interface{}, err error) {
 	if err != nil && !gerror.Is(err, ErrorDisabled) {
 		intlog.Errorf(s.ctx, `%+v`, err)
 	}
-	if sessionData != nil {
-		return sessionData, nil
+	if sessionData == nil {
+		sessionData = s.data.Map()
+	}
+	for key := range sessionData {
+		if isReservedKey(key) {
+			delete(sessionData, key)
+		}
 	}
-	return s.data.Map(), nil
+	return sessionData, nil
 }
 
 // Size returns the size of the session.
@@ -236,7 +280,13 @@ func (s *Session) Size() (size int, err error) {
 	if size > 0 {
 		return size, nil
 	}
-	return s.data.Size(), nil
+	s.data.Iterator(func(key string, _ interface{}) bool {
+		if !isReservedKey(key) {
+			size++
+		}
+		return true
+	})
+	return size, nil
 }
 
 // Contains checks whether key exist in the session.
@@ -362,11 +412,24 @@ func (s *Session) MustRemove(keys ...string) {
 // The parameter `deleteOld` specifies whether to delete the old session data:
 // - If true: the old session data will be deleted immediately
 // - If false: the old session data will be kept and expire according to its TTL
+// It takes no effect for TokenStorage, as the old token is not stored on server side.
 func (s *Session) RegenerateId(deleteOld bool) (newId string, err error) {
 	if err = s.init(); err != nil {
 		return "", err
 	}
 
+	// The token storage creates token with new identity, and the old token cannot be deleted
+	// as it is not stored on server side.
+	if tokenStorage, ok := s.manager.storage.(TokenStorage); ok {
+		s.setCreateTimeIfNotExist()
+		if newId, err = tokenStorage.NewToken(s.ctx, "", s.data, s.getTTL()); err != nil {
+			return "", err
+		}
+		s.id = newId
+		s.dirty = true
+		return newId, nil
+	}
+
 	// Generate new session id
 	if s.idFunc != nil {
 		newId = s.idFunc(s.manager.ttl)
@@ -382,7 +445,8 @@ func (s *Session) RegenerateId(deleteOld bool) (newId string, err error) {
 
 	// If using storage, need to copy data to new id
 	if s.manager.storage != nil {
-		if err = s.manager.storage.SetSession(s.ctx, newId, s.data, s.manager.ttl); err != nil {
+		s.setCreateTimeIfNotExist()
+		if err = s.manager.storage.SetSession(s.ctx, newId, s.data, s.getTTL()); err != nil {
 			if !gerror.Is(err, ErrorDisabled) {
 				return "", err
 			}
@@ -399,6 +463,7 @@ func (s *Session) RegenerateId(deleteOld bool) (newId string, err error) {
 
 	// Update session id
 	s.id = newId
+	s.loaded = true
 	s.dirty = true
 	return newId, nil
 }
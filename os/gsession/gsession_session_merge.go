@@ -0,0 +1,153 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gmap"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+const (
+	// reservedKeyPrefix is the prefix of the keys reserved for session metadata,
+	// which are hidden from Session.Data and Session.Size.
+	reservedKeyPrefix = "__gsession_"
+
+	// sessionKeyCreateTime is the reserved key of session creation timestamp in milliseconds.
+	sessionKeyCreateTime = reservedKeyPrefix + "create_time"
+
+	// sessionKeyUserId is the reserved key of the user identifier bound to session.
+	sessionKeyUserId = reservedKeyPrefix + "user_id"
+)
+
+// checkLoadedData checks the session data loaded from storage.
+// It discards the session if it exceeds the absolute TTL.
+func (s *Session) checkLoadedData() error {
+	if s.data == nil || s.data.IsEmpty() {
+		s.data = nil
+		return nil
+	}
+	if s.manager.isExpired(s.data) {
+		intlog.Printf(s.ctx, `session "%s" exceeds the absolute TTL %s`, s.id, s.manager.GetAbsoluteTTL())
+		if err := s.manager.storage.RemoveAll(s.ctx, s.id); err != nil && !gerror.Is(err, ErrorDisabled) {
+			return err
+		}
+		s.data = nil
+		s.id = ""
+		return nil
+	}
+	s.loaded = true
+	return nil
+}
+
+// save merges the modifications of current session to the latest stored data, and saves it to storage.
+// The merging and saving are done within the lock of session id in current Manager, so the concurrent
+// requests of the same session in current Manager keep the writes of each other.
+// The session is not saved if it was removed by others, like logging out everywhere, since it was loaded.
+func (s *Session) save() error {
+	unlock := s.manager.lockSession(s.id)
+	defer unlock()
+	if s.changes != nil || s.removes != nil || s.cleared {
+		latest, err := s.manager.storage.GetSession(s.ctx, s.id, s.manager.GetTTL())
+		if err != nil && !gerror.Is(err, ErrorDisabled) {
+			return err
+		}
+		if s.loaded && !s.cleared && (latest == nil || latest.IsEmpty()) {
+			intlog.Printf(s.ctx, `session "%s" is removed by others, it is not saved`, s.id)
+			s.resetTracking()
+			return nil
+		}
+		// The memory storage returns the same map that is modified in place.
+		if latest != nil && latest != s.data {
+			merged := gmap.NewStrAnyMap(true)
+			if !s.cleared {
+				merged.Sets(latest.Map())
+			}
+			for key := range s.removes {
+				merged.Remove(key)
+			}
+			merged.Sets(s.changes)
+			s.data = merged
+		}
+	}
+	s.setCreateTimeIfNotExist()
+	err := s.manager.storage.SetSession(s.ctx, s.id, s.data, s.getTTL())
+	if err != nil && !gerror.Is(err, ErrorDisabled) {
+		return err
+	}
+	s.resetTracking()
+	s.loaded = true
+	return nil
+}
+
+// trackSet tracks the setting of `key`.
+func (s *Session) trackSet(key string, value interface{}) {
+	if s.changes == nil {
+		s.changes = make(map[string]interface{})
+	}
+	s.changes[key] = value
+	delete(s.removes, key)
+}
+
+// trackRemove tracks the removing of `key`.
+func (s *Session) trackRemove(key string) {
+	if s.removes == nil {
+		s.removes = make(map[string]struct{})
+	}
+	s.removes[key] = struct{}{}
+	delete(s.changes, key)
+}
+
+// resetTracking resets the tracked modifications after they are saved.
+func (s *Session) resetTracking() {
+	s.changes = nil
+	s.removes = nil
+	s.cleared = false
+}
+
+// setCreateTimeIfNotExist records the creation time of session for absolute TTL.
+// It is not recorded for empty session, which is removed by some storages.
+func (s *Session) setCreateTimeIfNotExist() {
+	if s.data.IsEmpty() {
+		return
+	}
+	s.data.SetIfNotExist(sessionKeyCreateTime, gtime.TimestampMilli())
+}
+
+// getTTL returns the TTL for storing session, which is the idle TTL of manager,
+// but not longer than the remaining time of the absolute TTL.
+func (s *Session) getTTL() time.Duration {
+	var (
+		ttl         = s.manager.GetTTL()
+		absoluteTTL = s.manager.GetAbsoluteTTL()
+	)
+	if absoluteTTL <= 0 || s.data == nil {
+		return ttl
+	}
+	createTime := gconv.Int64(s.data.Get(sessionKeyCreateTime))
+	if createTime <= 0 {
+		return ttl
+	}
+	remaining := time.Duration(createTime+absoluteTTL.Milliseconds()-gtime.TimestampMilli()) * time.Millisecond
+	if remaining < ttl {
+		if remaining < time.Millisecond {
+			remaining = time.Millisecond
+		}
+		return remaining
+	}
+	return ttl
+}
+
+// isReservedKey checks and returns whether `key` is reserved for session metadata.
+func isReservedKey(key string) bool {
+	return strings.HasPrefix(key, reservedKeyPrefix)
+}
@@ -0,0 +1,56 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/util/gconv"
+)
+
+// SetUser binds current session to user `userId`, which is commonly called after the user logs in.
+// It regenerates the session id to prevent session fixation attacks, and adds the session to the index
+// of the user, so all sessions of the user can be retrieved and removed by Manager.
+// It unbinds the user if `userId` is empty.
+//
+// Note that it is not supported by TokenStorage, as it has no server side storage for the index.
+func (s *Session) SetUser(userId string) (err error) {
+	if err = s.init(); err != nil {
+		return err
+	}
+	if _, ok := s.manager.storage.(TokenStorage); ok {
+		return gerror.NewCode(gcode.CodeNotSupported, `user index is not supported by token storage`)
+	}
+	if userId == "" {
+		return s.Remove(sessionKeyUserId)
+	}
+	if err = s.Set(sessionKeyUserId, userId); err != nil {
+		return err
+	}
+	if _, err = s.RegenerateId(true); err != nil {
+		return err
+	}
+	return s.manager.addUserSession(s.ctx, userId, s.id)
+}
+
+// GetUser returns the user identifier bound to current session.
+// It returns empty string if no user is bound.
+func (s *Session) GetUser() (userId string, err error) {
+	v, err := s.Get(sessionKeyUserId)
+	if err != nil || v == nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+// getUserId returns the user identifier bound to current session from loaded data.
+func (s *Session) getUserId() string {
+	if s.data == nil {
+		return ""
+	}
+	return gconv.String(s.data.Get(sessionKeyUserId))
+}
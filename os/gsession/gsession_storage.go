@@ -62,3 +62,14 @@ type Storage interface {
 	// This function is called ever after session, which is not dirty, is closed.
 	UpdateTTL(ctx context.Context, sessionId string, ttl time.Duration) error
 }
+
+// TokenStorage is the optional interface for stateless storages, which encode the session data
+// into a token used as the session id, instead of storing the data on server side.
+// The session id is replaced with the renewed token when the session is closed,
+// so the final session id should be retrieved after the session is closed.
+type TokenStorage interface {
+	// NewToken encodes `sessionData` into a token that expires in `ttl`.
+	// The parameter `sessionId` is the current session id, and the token keeps its identity if it is
+	// a valid token, or else a new identity is generated, which makes the token different from all old ones.
+	NewToken(ctx context.Context, sessionId string, sessionData *gmap.StrAnyMap, ttl time.Duration) (token string, err error)
+}
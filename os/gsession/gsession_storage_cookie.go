@@ -0,0 +1,228 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gmap"
+	"github.com/ximplez-go/gf/crypto/gaead"
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/os/gtime"
+)
+
+// StorageCookie implements the Session Storage interface without server side storage,
+// which encodes the session data into a signed and optionally encrypted token as the session id.
+// The token is commonly stored in the client cookie, which lets the sessions be shared by all nodes.
+//
+// The tokens are signed and encrypted with the keys of gaead.Keyring, in which the primary key is used
+// for new tokens, and the token carries its key id for verifying. The keys are rotated by the Keyring.
+//
+// Note that the token cannot be revoked before it expires, as it is not stored on server side.
+type StorageCookie struct {
+	StorageBase
+	keyring       *gaead.Keyring // Keys signing and encrypting the tokens.
+	cryptoEnabled bool           // Whether encrypts the token with the Keyring.
+	maxSize       int            // Max size of the encoded token.
+}
+
+// storageCookiePayload is the content of the token.
+type storageCookiePayload struct {
+	Id     string                 `json:"i"` // Identity of the session, which changes only when regenerating.
+	Expire int64                  `json:"e"` // Expiration timestamp in milliseconds.
+	Data   map[string]interface{} `json:"d"` // Session data.
+}
+
+const (
+	DefaultStorageCookieCryptoEnabled = false
+	DefaultStorageCookieMaxSize       = 4000 // Cookie size is limited to 4096 bytes including its name and attributes in most browsers.
+)
+
+const (
+	storageCookieTokenVersion = 1
+	storageCookieFlagCrypto   = 1 << 0
+	storageCookieHeaderSize   = 3 // Version, flags and length of key id.
+	storageCookieSignKeySalt  = "gsession.cookie.sign"
+)
+
+var _ TokenStorage = (*StorageCookie)(nil)
+
+// NewStorageCookie creates and returns a cookie storage object for session.
+// The parameter `keyring` holds the keys for signing and encrypting the tokens. The primary key is used
+// for new tokens, and all keys are used for verifying, which supports key rotation by adding the new key
+// as primary and removing the old key after all its tokens expire.
+func NewStorageCookie(keyring *gaead.Keyring) *StorageCookie {
+	s := &StorageCookie{
+		cryptoEnabled: DefaultStorageCookieCryptoEnabled,
+		maxSize:       DefaultStorageCookieMaxSize,
+	}
+	s.SetKeyring(keyring)
+	return s
+}
+
+// SetKeyring sets the keys for signing and encrypting the tokens.
+// It panics if `keyring` is nil.
+func (s *StorageCookie) SetKeyring(keyring *gaead.Keyring) {
+	if keyring == nil {
+		panic(gerror.NewCode(gcode.CodeInvalidParameter, `keyring is required for cookie session storage`))
+	}
+	s.keyring = keyring
+}
+
+// SetCryptoEnabled enables/disables the encryption of the tokens.
+// The tokens are always signed, and the encryption hides the session data from the client.
+func (s *StorageCookie) SetCryptoEnabled(enabled bool) {
+	s.cryptoEnabled = enabled
+}
+
+// SetMaxSize sets the max size of the encoded token, which is DefaultStorageCookieMaxSize in default.
+// It does not limit the size if `size` is not positive.
+func (s *StorageCookie) SetMaxSize(size int) {
+	s.maxSize = size
+}
+
+// RemoveAll deletes all key-value pairs of the session.
+// The session data is cleared in memory, and the token is renewed when the session is closed.
+func (s *StorageCookie) RemoveAll(ctx context.Context, sessionId string) error {
+	return nil
+}
+
+// GetSession returns the session data as *gmap.StrAnyMap decoded from the token `sessionId`.
+// It returns nil if the token is invalid or expired.
+//
+// This function is called ever when session starts.
+func (s *StorageCookie) GetSession(ctx context.Context, sessionId string, ttl time.Duration) (*gmap.StrAnyMap, error) {
+	payload, err := s.decode(sessionId)
+	if err != nil {
+		intlog.Printf(ctx, `StorageCookie.GetSession: %+v`, err)
+		return nil, nil
+	}
+	if payload.Expire < gtime.TimestampMilli() {
+		return nil, nil
+	}
+	if payload.Data == nil {
+		payload.Data = make(map[string]interface{})
+	}
+	return gmap.NewStrAnyMapFrom(payload.Data, true), nil
+}
+
+// NewToken encodes `sessionData` into a token that expires in `ttl`.
+// It keeps the identity of `sessionId` if it is a valid token, or else a new identity is generated.
+// It returns error if the size of token exceeds the max size.
+func (s *StorageCookie) NewToken(
+	ctx context.Context, sessionId string, sessionData *gmap.StrAnyMap, ttl time.Duration,
+) (token string, err error) {
+	payload := storageCookiePayload{
+		Expire: gtime.TimestampMilli() + ttl.Milliseconds(),
+	}
+	if oldPayload, err := s.decode(sessionId); err == nil {
+		payload.Id = oldPayload.Id
+	} else {
+		payload.Id = NewSessionId()
+	}
+	if sessionData != nil {
+		payload.Data = sessionData.Map()
+	}
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return "", gerror.Wrap(err, `marshal session data failed`)
+	}
+	if token, err = s.encode(content); err != nil {
+		return "", err
+	}
+	if s.maxSize > 0 && len(token) > s.maxSize {
+		return "", gerror.NewCodef(
+			gcode.CodeInvalidOperation,
+			`session token size %d exceeds the max size %d`,
+			len(token), s.maxSize,
+		)
+	}
+	return token, nil
+}
+
+// encode encrypts `content` if crypto is enabled, and signs it as the token with the primary key.
+// The token is composed of version, flags, key id, content and signature in base64 url encoding.
+func (s *StorageCookie) encode(content []byte) (string, error) {
+	key := s.keyring.Primary()
+	if key == nil {
+		return "", gerror.NewCode(gcode.CodeInvalidOperation, `no primary key for session token`)
+	}
+	header := append([]byte{storageCookieTokenVersion, 0, byte(len(key.Id))}, key.Id...)
+	if s.cryptoEnabled {
+		header[1] |= storageCookieFlagCrypto
+		cipherText, err := s.keyring.Encrypt(content, header)
+		if err != nil {
+			return "", err
+		}
+		content = cipherText
+	}
+	buffer := append(header, content...)
+	mac := hmac.New(sha256.New, s.deriveSignKey(key))
+	mac.Write(buffer)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(buffer)), nil
+}
+
+// decode verifies the token with the key of its key id, and returns its payload.
+func (s *StorageCookie) decode(token string) (*storageCookiePayload, error) {
+	buffer, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buffer) < storageCookieHeaderSize+sha256.Size {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `invalid session token`)
+	}
+	if buffer[0] != storageCookieTokenVersion {
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `unsupported session token version %d`, buffer[0])
+	}
+	var (
+		headerSize = storageCookieHeaderSize + int(buffer[2])
+		signed     = buffer[:len(buffer)-sha256.Size]
+		signature  = buffer[len(buffer)-sha256.Size:]
+	)
+	if len(signed) < headerSize {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `invalid session token`)
+	}
+	var (
+		header  = signed[:headerSize]
+		content = signed[headerSize:]
+		keyId   = string(header[storageCookieHeaderSize:])
+		key     = s.keyring.Get(keyId)
+	)
+	if key == nil {
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, `unknown key id "%s" of session token`, keyId)
+	}
+	mac := hmac.New(sha256.New, s.deriveSignKey(key))
+	mac.Write(signed)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `session token signature mismatch`)
+	}
+	if header[1]&storageCookieFlagCrypto > 0 {
+		if content, err = s.keyring.Decrypt(content, header); err != nil {
+			return nil, gerror.WrapCode(gcode.CodeInvalidParameter, err, `decrypt session token failed`)
+		}
+	}
+	var payload *storageCookiePayload
+	if err = json.UnmarshalUseNumber(content, &payload); err != nil {
+		return nil, gerror.WrapCode(gcode.CodeInvalidParameter, err, `invalid session token payload`)
+	}
+	if payload == nil {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `invalid session token payload`)
+	}
+	return payload, nil
+}
+
+// deriveSignKey derives the 32 bytes key for HMAC-SHA256 signature from `key`,
+// which separates the keys for signing and encryption.
+func (s *StorageCookie) deriveSignKey(key *gaead.Key) []byte {
+	mac := hmac.New(sha256.New, key.Secret)
+	mac.Write([]byte(storageCookieSignKeySalt))
+	return mac.Sum(nil)
+}
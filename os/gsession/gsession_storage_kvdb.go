@@ -0,0 +1,99 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"context"
+	"time"
+
+	"github.com/ximplez-go/gf/container/gmap"
+	"github.com/ximplez-go/gf/internal/intlog"
+	"github.com/ximplez-go/gf/internal/json"
+	"github.com/ximplez-go/gf/os/gkvdb"
+)
+
+// StorageKvdb implements the Session Storage interface with embedded key-value database gkvdb,
+// which stores all sessions in a single data file and expires them by the database.
+type StorageKvdb struct {
+	StorageBase
+	db     *gkvdb.DB // Database storing sessions.
+	prefix string    // Key prefix for session ids.
+}
+
+const (
+	// DefaultStorageKvdbPrefix is the default key prefix for session ids in database.
+	DefaultStorageKvdbPrefix = "gsession:"
+)
+
+// NewStorageKvdb creates and returns a gkvdb storage object for session.
+// The optional parameter `prefix` specifies the key prefix for session ids,
+// which is DefaultStorageKvdbPrefix in default.
+func NewStorageKvdb(db *gkvdb.DB, prefix ...string) *StorageKvdb {
+	s := &StorageKvdb{
+		db:     db,
+		prefix: DefaultStorageKvdbPrefix,
+	}
+	if len(prefix) > 0 && prefix[0] != "" {
+		s.prefix = prefix[0]
+	}
+	return s
+}
+
+// RemoveAll deletes session from storage.
+func (s *StorageKvdb) RemoveAll(ctx context.Context, sessionId string) error {
+	return s.db.Delete(s.sessionKey(sessionId))
+}
+
+// GetSession returns the session data as *gmap.StrAnyMap for given session id from storage.
+//
+// The parameter `ttl` specifies the TTL for this session, and it returns nil if the TTL is exceeded.
+//
+// This function is called ever when session starts.
+func (s *StorageKvdb) GetSession(ctx context.Context, sessionId string, ttl time.Duration) (*gmap.StrAnyMap, error) {
+	intlog.Printf(ctx, "StorageKvdb.GetSession: %s, %v", sessionId, ttl)
+	content, err := s.db.Get(s.sessionKey(sessionId))
+	if err != nil || content == nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err = json.UnmarshalUseNumber(content, &m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	return gmap.NewStrAnyMapFrom(m, true), nil
+}
+
+// SetSession updates the data map for specified session id.
+// This function is called ever after session, which is changed dirty, is closed.
+// This copy all session data map from memory to storage, and the session is deleted if it is empty.
+func (s *StorageKvdb) SetSession(ctx context.Context, sessionId string, sessionData *gmap.StrAnyMap, ttl time.Duration) error {
+	intlog.Printf(ctx, "StorageKvdb.SetSession: %s, %v, %v", sessionId, sessionData, ttl)
+	if sessionData == nil || sessionData.IsEmpty() {
+		return s.db.Delete(s.sessionKey(sessionId))
+	}
+	content, err := json.Marshal(sessionData)
+	if err != nil {
+		return err
+	}
+	return s.db.Set(s.sessionKey(sessionId), content, ttl)
+}
+
+// UpdateTTL updates the TTL for specified session id.
+// This function is called ever after session, which is not dirty, is closed.
+// It writes only the expiration to the database instead of the whole session data.
+func (s *StorageKvdb) UpdateTTL(ctx context.Context, sessionId string, ttl time.Duration) error {
+	intlog.Printf(ctx, "StorageKvdb.UpdateTTL: %s, %v", sessionId, ttl)
+	_, err := s.db.UpdateExpire(s.sessionKey(sessionId), ttl)
+	return err
+}
+
+// sessionKey returns the database key for given session id.
+func (s *StorageKvdb) sessionKey(sessionId string) string {
+	return s.prefix + sessionId
+}
@@ -0,0 +1,254 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/errors/gcode"
+	"github.com/ximplez-go/gf/errors/gerror"
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+// testStorages returns the storages for testing and the function cleaning them.
+func testStorages(t *gtest.T) (storages map[string]Storage, clean func()) {
+	path := gfile.Temp("gsession", gtime.TimestampNanoStr())
+	t.AssertNil(gfile.Mkdir(path))
+	storages = map[string]Storage{
+		"memory": NewStorageMemory(),
+		"file":   NewStorageFile(path, time.Hour),
+	}
+	return storages, func() {
+		_ = gfile.Remove(path)
+	}
+}
+
+func Test_Session_ConcurrentWrites(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		storages, clean := testStorages(t)
+		defer clean()
+		for _, storage := range storages {
+			manager := New(time.Hour, storage)
+			session := manager.New(ctx)
+			t.AssertNil(session.SetMap(map[string]interface{}{"a": 1, "b": 2}))
+			t.AssertNil(session.Close())
+			sessionId := session.MustId()
+
+			// Both sessions load the data before either is saved.
+			session1 := manager.New(ctx, sessionId)
+			session2 := manager.New(ctx, sessionId)
+			t.Assert(session1.MustGet("a").Int(), 1)
+			t.Assert(session2.MustGet("a").Int(), 1)
+			t.AssertNil(session1.Set("k1", "v1"))
+			t.AssertNil(session2.Set("k2", "v2"))
+			t.AssertNil(session2.Remove("b"))
+			t.AssertNil(session1.Close())
+			t.AssertNil(session2.Close())
+
+			session = manager.New(ctx, sessionId)
+			t.Assert(session.MustData(), map[string]interface{}{"a": 1, "k1": "v1", "k2": "v2"})
+
+			// Concurrent requests of the same session.
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					s := manager.New(ctx, sessionId)
+					t.AssertNil(s.Set(fmt.Sprintf("key%d", i), i))
+					t.AssertNil(s.Close())
+				}(i)
+			}
+			wg.Wait()
+			session = manager.New(ctx, sessionId)
+			t.Assert(session.MustSize(), 13)
+			for i := 0; i < 10; i++ {
+				t.Assert(session.MustGet(fmt.Sprintf("key%d", i)).Int(), i)
+			}
+		}
+	})
+	// The cleared data is not merged back.
+	gtest.C(t, func(t *gtest.T) {
+		storages, clean := testStorages(t)
+		defer clean()
+		for _, storage := range storages {
+			manager := New(time.Hour, storage)
+			session := manager.New(ctx)
+			t.AssertNil(session.Set("a", 1))
+			t.AssertNil(session.Close())
+			sessionId := session.MustId()
+
+			session = manager.New(ctx, sessionId)
+			t.AssertNil(session.RemoveAll())
+			t.AssertNil(session.Set("b", 2))
+			t.AssertNil(session.Close())
+
+			session = manager.New(ctx, sessionId)
+			t.Assert(session.MustData(), map[string]interface{}{"b": 2})
+		}
+	})
+}
+
+func Test_Session_User(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		storages, clean := testStorages(t)
+		defer clean()
+		for name, storage := range storages {
+			var (
+				manager = New(time.Hour, storage)
+				userId  = "john-" + name
+			)
+			session1 := manager.New(ctx)
+			t.AssertNil(session1.Set("k", "v"))
+			oldId := session1.MustId()
+			t.AssertNil(session1.SetUser(userId))
+			t.AssertNE(session1.MustId(), oldId)
+			t.AssertNil(session1.Close())
+			t.Assert(manager.New(ctx, oldId).MustSize(), 0)
+
+			session2 := manager.New(ctx)
+			t.AssertNil(session2.SetUser(userId))
+			t.AssertNil(session2.Close())
+
+			session := manager.New(ctx, session1.MustId())
+			userIdValue, err := session.GetUser()
+			t.AssertNil(err)
+			t.Assert(userIdValue, userId)
+			t.Assert(session.MustData(), map[string]interface{}{"k": "v"})
+			t.Assert(session.MustSize(), 1)
+
+			ids, err := manager.UserSessionIds(ctx, userId)
+			t.AssertNil(err)
+			t.Assert(len(ids), 2)
+			t.AssertIN(session1.MustId(), ids)
+			t.AssertIN(session2.MustId(), ids)
+
+			// The session is not indexed after it is bound to another user.
+			session = manager.New(ctx, session2.MustId())
+			t.AssertNil(session.SetUser("other-" + name))
+			t.AssertNil(session.Close())
+			ids, err = manager.UserSessionIds(ctx, userId)
+			t.AssertNil(err)
+			t.Assert(ids, []string{session1.MustId()})
+
+			// The session being accessed is not saved back after logging out everywhere.
+			session = manager.New(ctx, session1.MustId())
+			t.Assert(session.MustGet("k"), "v")
+			t.AssertNil(manager.RemoveUserSessions(ctx, userId))
+			t.AssertNil(session.Set("k", "v2"))
+			t.AssertNil(session.Close())
+			t.Assert(manager.New(ctx, session1.MustId()).MustSize(), 0)
+			ids, err = manager.UserSessionIds(ctx, userId)
+			t.AssertNil(err)
+			t.Assert(len(ids), 0)
+		}
+	})
+	// Token storage does not support user index.
+	gtest.C(t, func(t *gtest.T) {
+		manager := New(time.Hour, newTestStorageCookie(t, "key"))
+		session := manager.New(ctx)
+		err := session.SetUser("john")
+		t.Assert(gerror.Code(err), gcode.CodeNotSupported)
+	})
+}
+
+func Test_Session_ReservedId(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		storages, clean := testStorages(t)
+		defer clean()
+		for _, storage := range storages {
+			var (
+				manager = New(time.Hour, storage)
+				session = manager.New(ctx)
+			)
+			t.AssertNil(session.SetUser("john"))
+			t.AssertNil(session.Close())
+			indexId := manager.userIndexId("john")
+
+			// The user index cannot be read or written as session.
+			session = manager.New(ctx, indexId)
+			t.AssertNE(session.MustId(), indexId)
+			t.Assert(session.MustSize(), 0)
+			t.AssertNil(session.Set("k", "v"))
+			t.AssertNil(session.Close())
+
+			session = manager.New(ctx)
+			t.AssertNil(session.SetId(indexId))
+			t.AssertNE(session.MustId(), indexId)
+
+			ids, err := manager.UserSessionIds(ctx, "john")
+			t.AssertNil(err)
+			t.Assert(len(ids), 1)
+		}
+	})
+}
+
+func Test_Session_ConcurrentWrites_Managers(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp("gsession", gtime.TimestampNanoStr())
+		t.AssertNil(gfile.Mkdir(path))
+		defer gfile.Remove(path)
+		var (
+			storage  = NewStorageFile(path, time.Hour)
+			manager1 = New(time.Hour, storage)
+			manager2 = New(time.Hour, storage)
+		)
+
+		session := manager1.New(ctx)
+		t.AssertNil(session.Set("a", 1))
+		t.AssertNil(session.Close())
+		sessionId := session.MustId()
+
+		// The modifications are merged if the sessions of different managers are not saved concurrently.
+		session1 := manager1.New(ctx, sessionId)
+		session2 := manager2.New(ctx, sessionId)
+		t.Assert(session1.MustGet("a").Int(), 1)
+		t.Assert(session2.MustGet("a").Int(), 1)
+		t.AssertNil(session1.Set("k1", "v1"))
+		t.AssertNil(session2.Set("k2", "v2"))
+		t.AssertNil(session1.Close())
+		t.AssertNil(session2.Close())
+
+		session = manager1.New(ctx, sessionId)
+		t.Assert(session.MustData(), map[string]interface{}{"a": 1, "k1": "v1", "k2": "v2"})
+	})
+}
+
+func Test_Manager_AbsoluteTTL(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		storages, clean := testStorages(t)
+		defer clean()
+		for _, storage := range storages {
+			manager := New(time.Hour, storage)
+			manager.SetAbsoluteTTL(500 * time.Millisecond)
+			t.Assert(manager.GetAbsoluteTTL(), 500*time.Millisecond)
+
+			session := manager.New(ctx)
+			t.AssertNil(session.Set("k", "v"))
+			t.AssertNil(session.Close())
+			t.AssertLE(session.getTTL(), 500*time.Millisecond)
+			sessionId := session.MustId()
+
+			// Accessing the session does not renew the absolute TTL.
+			time.Sleep(300 * time.Millisecond)
+			session = manager.New(ctx, sessionId)
+			t.Assert(session.MustGet("k"), "v")
+			t.AssertNil(session.Set("k2", "v2"))
+			t.AssertNil(session.Close())
+
+			time.Sleep(300 * time.Millisecond)
+			session = manager.New(ctx, sessionId)
+			t.Assert(session.MustGet("k"), nil)
+			t.Assert(session.MustSize(), 0)
+		}
+	})
+}
@@ -0,0 +1,234 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/crypto/gaead"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+// newTestStorageCookie creates a StorageCookie with keys of `ids`, whose secrets are derived from their ids.
+func newTestStorageCookie(t *gtest.T, ids ...string) *StorageCookie {
+	return NewStorageCookie(newTestKeyring(t, ids...))
+}
+
+func newTestKeyring(t *gtest.T, ids ...string) *gaead.Keyring {
+	keys := make([]*gaead.Key, 0, len(ids))
+	for _, id := range ids {
+		secret := sha256.Sum256([]byte(id))
+		keys = append(keys, &gaead.Key{
+			Id:        id,
+			Algorithm: gaead.AlgorithmAesGcm,
+			Secret:    secret[:],
+		})
+	}
+	keyring, err := gaead.NewKeyring(keys[0], keys[1:]...)
+	t.AssertNil(err)
+	return keyring
+}
+
+func Test_StorageCookie(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			storage = newTestStorageCookie(t, "key")
+			manager = New(time.Hour, storage)
+			session = manager.New(ctx)
+		)
+		t.AssertNil(session.Set("k1", "v1"))
+		t.AssertNil(session.Set("k2", 2))
+		t.AssertNil(session.Close())
+		token := session.MustId()
+
+		// The session data is signed but not hidden from client without crypto.
+		content, err := base64.RawURLEncoding.DecodeString(token)
+		t.AssertNil(err)
+		t.Assert(strings.Contains(string(content), `"v1"`), true)
+
+		// Restored from token.
+		session = manager.New(ctx, token)
+		t.Assert(session.MustGet("k1"), "v1")
+		t.Assert(session.MustGet("k2").Int(), 2)
+		t.Assert(session.MustSize(), 2)
+		t.AssertNil(session.Remove("k1"))
+		t.AssertNil(session.Close())
+		token2 := session.MustId()
+		t.AssertNE(token2, token)
+
+		session = manager.New(ctx, token2)
+		t.Assert(session.MustContains("k1"), false)
+		t.Assert(session.MustGet("k2").Int(), 2)
+
+		// Tampered token.
+		session = manager.New(ctx, token2[:len(token2)-2]+"AA")
+		t.Assert(session.MustSize(), 0)
+
+		// Token signed by other key.
+		session = New(time.Hour, newTestStorageCookie(t, "other")).New(ctx, token2)
+		t.Assert(session.MustSize(), 0)
+
+		// Token signed by other key with the same key id.
+		keyring, err := gaead.NewKeyring(&gaead.Key{
+			Id:        "key",
+			Algorithm: gaead.AlgorithmAesGcm,
+			Secret:    []byte("12345678901234567890123456789012"),
+		})
+		t.AssertNil(err)
+		session = New(time.Hour, NewStorageCookie(keyring)).New(ctx, token2)
+		t.Assert(session.MustSize(), 0)
+	})
+}
+
+func Test_StorageCookie_Crypto(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		storage := newTestStorageCookie(t, "key")
+		storage.SetCryptoEnabled(true)
+		var (
+			manager = New(time.Hour, storage)
+			session = manager.New(ctx)
+		)
+		t.AssertNil(session.Set("name", "john"))
+		t.AssertNil(session.Close())
+		token := session.MustId()
+
+		// The session data is hidden from client.
+		content, err := base64.RawURLEncoding.DecodeString(token)
+		t.AssertNil(err)
+		t.Assert(strings.Contains(string(content), "john"), false)
+
+		// The encrypted token is decrypted according to its flags.
+		payload, err := newTestStorageCookie(t, "key").decode(token)
+		t.AssertNil(err)
+		t.Assert(payload.Data["name"], "john")
+
+		session = manager.New(ctx, token)
+		t.Assert(session.MustGet("name"), "john")
+	})
+}
+
+func Test_StorageCookie_KeyRotation(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			oldStorage = newTestStorageCookie(t, "old")
+			newStorage = newTestStorageCookie(t, "new", "old")
+			session    = New(time.Hour, oldStorage).New(ctx)
+		)
+		oldStorage.SetCryptoEnabled(true)
+		t.AssertNil(session.Set("k", "v"))
+		t.AssertNil(session.Close())
+		oldToken := session.MustId()
+
+		// Verified by the old key, and renewed with the new key.
+		session = New(time.Hour, newStorage).New(ctx, oldToken)
+		t.Assert(session.MustGet("k"), "v")
+		t.AssertNil(session.Close())
+		newToken := session.MustId()
+		t.AssertNE(newToken, oldToken)
+
+		session = New(time.Hour, newTestStorageCookie(t, "new")).New(ctx, newToken)
+		t.Assert(session.MustGet("k"), "v")
+		session = New(time.Hour, newTestStorageCookie(t, "new")).New(ctx, oldToken)
+		t.Assert(session.MustSize(), 0)
+	})
+	// Rotates the keys of the Keyring in use.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			keyring = newTestKeyring(t, "old")
+			storage = NewStorageCookie(keyring)
+			manager = New(time.Hour, storage)
+			session = manager.New(ctx)
+		)
+		storage.SetCryptoEnabled(true)
+		t.AssertNil(session.Set("k", "v"))
+		t.AssertNil(session.Close())
+		oldToken := session.MustId()
+
+		t.AssertNil(keyring.Add(newTestKeyring(t, "new").Primary()))
+		t.AssertNil(keyring.SetPrimary("new"))
+		session = manager.New(ctx, oldToken)
+		t.Assert(session.MustGet("k"), "v")
+		t.AssertNil(session.Close())
+		newToken := session.MustId()
+
+		t.AssertNil(keyring.Remove("old"))
+		t.Assert(manager.New(ctx, oldToken).MustSize(), 0)
+		t.Assert(manager.New(ctx, newToken).MustGet("k"), "v")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		defer func() {
+			t.AssertNE(recover(), nil)
+		}()
+		NewStorageCookie(nil)
+	})
+}
+
+func Test_StorageCookie_Expire(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			manager = New(100*time.Millisecond, newTestStorageCookie(t, "key"))
+			session = manager.New(ctx)
+		)
+		t.AssertNil(session.Set("k", "v"))
+		t.AssertNil(session.Close())
+		token := session.MustId()
+		t.Assert(manager.New(ctx, token).MustGet("k"), "v")
+
+		time.Sleep(200 * time.Millisecond)
+		t.Assert(manager.New(ctx, token).MustSize(), 0)
+	})
+}
+
+func Test_StorageCookie_MaxSize(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		storage := newTestStorageCookie(t, "key")
+		storage.SetMaxSize(100)
+		session := New(time.Hour, storage).New(ctx)
+		t.AssertNil(session.Set("k", strings.Repeat("v", 100)))
+		t.AssertNE(session.Close(), nil)
+
+		storage.SetMaxSize(0)
+		t.AssertNil(session.Close())
+	})
+}
+
+func Test_StorageCookie_RegenerateId(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			storage = newTestStorageCookie(t, "key")
+			manager = New(time.Hour, storage)
+			session = manager.New(ctx)
+		)
+		t.AssertNil(session.Set("k", "v"))
+		t.AssertNil(session.Close())
+		oldToken := session.MustId()
+		oldPayload, err := storage.decode(oldToken)
+		t.AssertNil(err)
+
+		session = manager.New(ctx, oldToken)
+		newToken := session.MustRegenerateId(true)
+		t.AssertNE(newToken, oldToken)
+		t.AssertNil(session.Close())
+		newToken = session.MustId()
+		newPayload, err := storage.decode(newToken)
+		t.AssertNil(err)
+		t.AssertNE(newPayload.Id, oldPayload.Id)
+		t.Assert(newPayload.Data["k"], "v")
+
+		// Identity is kept for renewing.
+		session = manager.New(ctx, newToken)
+		t.AssertNil(session.Set("k2", "v2"))
+		t.AssertNil(session.Close())
+		payload, err := storage.decode(session.MustId())
+		t.AssertNil(err)
+		t.Assert(payload.Id, newPayload.Id)
+	})
+}
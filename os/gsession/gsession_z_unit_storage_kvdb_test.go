@@ -0,0 +1,89 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gsession
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/os/gfile"
+	"github.com/ximplez-go/gf/os/gkvdb"
+	"github.com/ximplez-go/gf/os/gtime"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+func Test_StorageKvdb(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp("gsession", gtime.TimestampNanoStr(), "session.db")
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+
+		manager := New(time.Hour, NewStorageKvdb(db))
+		session := manager.New(ctx)
+		t.AssertNil(session.Set("k1", "v1"))
+		t.AssertNil(session.Set("k2", 2))
+		t.AssertNil(session.Close())
+		sessionId := session.MustId()
+
+		// Sessions are durable.
+		t.AssertNil(db.Close())
+		db, err = gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+		manager = New(time.Hour, NewStorageKvdb(db))
+
+		session = manager.New(ctx, sessionId)
+		t.Assert(session.MustGet("k1"), "v1")
+		t.Assert(session.MustGet("k2").Int(), 2)
+		t.AssertNil(session.Close())
+		ttl, err := db.GetExpire(DefaultStorageKvdbPrefix + sessionId)
+		t.AssertNil(err)
+		t.AssertGT(ttl, 59*time.Minute)
+
+		newId := session.MustRegenerateId(true)
+		t.AssertNil(session.Close())
+		session = manager.New(ctx, newId)
+		t.Assert(session.MustGet("k1"), "v1")
+		session = manager.New(ctx, sessionId)
+		t.Assert(session.MustSize(), 0)
+
+		session = manager.New(ctx, newId)
+		t.AssertNil(session.RemoveAll())
+		t.AssertNil(session.Close())
+		ok, err := db.Contains(DefaultStorageKvdbPrefix + newId)
+		t.AssertNil(err)
+		t.Assert(ok, false)
+	})
+}
+
+func Test_StorageKvdb_Expire(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp("gsession", gtime.TimestampNanoStr(), "session.db")
+		defer gfile.Remove(gfile.Dir(path))
+		db, err := gkvdb.Open(path)
+		t.AssertNil(err)
+		defer db.Close()
+
+		manager := New(200*time.Millisecond, NewStorageKvdb(db, "s:"))
+		session := manager.New(ctx)
+		t.AssertNil(session.Set("k", "v"))
+		t.AssertNil(session.Close())
+		sessionId := session.MustId()
+
+		time.Sleep(100 * time.Millisecond)
+		// Accessing renews the TTL.
+		session = manager.New(ctx, sessionId)
+		t.Assert(session.MustGet("k"), "v")
+		t.AssertNil(session.Close())
+		time.Sleep(150 * time.Millisecond)
+		t.Assert(manager.New(ctx, sessionId).MustGet("k"), "v")
+
+		time.Sleep(250 * time.Millisecond)
+		t.Assert(manager.New(ctx, sessionId).MustSize(), 0)
+	})
+}
@@ -15,6 +15,10 @@
 //  4. gtimer's benchmark OP is measured in nanoseconds, and gcron's benchmark OP is measured
 //     in microseconds.
 //
+// The timer manages its jobs with a priority queue in default, and it can also use a hierarchical
+// timing wheel by TimerModeWheel, which adds and closes jobs in O(1) for lots of short-lived jobs,
+// like the idle timeouts of connections.
+//
 // ALSO VERY NOTE the common delay of the timer: https://github.com/golang/go/issues/14410
 package gtimer
 
@@ -33,7 +37,8 @@ import (
 // Timer is the timer manager, which uses ticks to calculate the timing interval.
 type Timer struct {
 	mu      sync.RWMutex
-	queue   *priorityQueue // queue is a priority queue based on heap structure.
+	queue   *priorityQueue // queue is a priority queue based on heap structure, for TimerModeHeap.
+	wheel   *timingWheel   // wheel is a hierarchical timing wheel, for TimerModeWheel.
 	status  *gtype.Int     // status is the current timer status.
 	ticks   *gtype.Int64   // ticks is the proceeded interval number by the timer.
 	options TimerOptions   // timer options is used for timer configuration.
@@ -43,8 +48,22 @@ type Timer struct {
 type TimerOptions struct {
 	Interval time.Duration // (optional) Interval is the underlying rolling interval tick of the timer.
 	Quick    bool          // Quick is used for quick timer, which means the timer will not wait for the first interval to be elapsed.
+	Mode     TimerMode     // (optional) Mode specifies the underlying structure managing the jobs, which is TimerModeHeap in default.
 }
 
+// TimerMode specifies the underlying structure managing the jobs of Timer.
+type TimerMode int
+
+const (
+	// TimerModeHeap manages jobs with a priority queue based on heap, which adds a job in O(log n),
+	// and a closed job is removed when it is due.
+	TimerModeHeap TimerMode = iota
+
+	// TimerModeWheel manages jobs with a hierarchical timing wheel, which adds and closes a job in O(1),
+	// and checks only the jobs in the slot of current tick in each tick.
+	TimerModeWheel
+)
+
 // internalPanic is the custom panic for internal usage.
 type internalPanic string
 
@@ -25,6 +25,11 @@ type Entry struct {
 	isSingleton *gtype.Bool     // Singleton mode.
 	nextTicks   *gtype.Int64    // Next run ticks of the job.
 	infinite    *gtype.Bool     // No times limit.
+
+	// The slot and siblings of the job in timing wheel, which are guarded by the wheel for TimerModeWheel.
+	wheelSlot *wheelSlot
+	wheelPrev *Entry
+	wheelNext *Entry
 }
 
 // JobFunc is the timing called job function in timer.
@@ -116,11 +121,17 @@ func (entry *Entry) Stop() {
 // Close closes the job, and then it will be removed from the timer.
 func (entry *Entry) Close() {
 	entry.status.Set(StatusClosed)
+	if entry.timer.wheel != nil {
+		entry.timer.wheel.Remove(entry)
+	}
 }
 
 // Reset resets the job, which resets its ticks for next running.
 func (entry *Entry) Reset() {
 	entry.nextTicks.Set(entry.timer.ticks.Val() + entry.ticks)
+	if entry.timer.wheel != nil {
+		entry.timer.wheel.Reset(entry)
+	}
 }
 
 // IsSingleton checks and returns whether the job in singleton mode.
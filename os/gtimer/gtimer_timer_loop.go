@@ -22,7 +22,10 @@ func (t *Timer) loop() {
 			switch t.status.Val() {
 			case StatusRunning:
 				// Timer proceeding.
-				if currentTimerTicks = t.ticks.Add(1); currentTimerTicks >= t.queue.NextPriority() {
+				currentTimerTicks = t.ticks.Add(1)
+				if t.wheel != nil {
+					t.wheel.Advance(currentTimerTicks)
+				} else if currentTimerTicks >= t.queue.NextPriority() {
 					t.proceed(currentTimerTicks)
 				}
 
@@ -0,0 +1,180 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtimer
+
+import (
+	"sync"
+)
+
+// Layout of the hierarchical timing wheel:
+// The first level has 256 slots of one tick, and each following level has 64 slots,
+// of which a slot covers all the slots of its lower level. The 5 levels cover 2^32 ticks,
+// and the entry beyond that is placed at the farthest slot and re-placed when it is cascaded.
+const (
+	wheelLevel0Bits  = 8
+	wheelLevel0Size  = 1 << wheelLevel0Bits
+	wheelLevel0Mask  = wheelLevel0Size - 1
+	wheelLevelNBits  = 6
+	wheelLevelNSize  = 1 << wheelLevelNBits
+	wheelLevelNMask  = wheelLevelNSize - 1
+	wheelLevelNCount = 4
+	wheelMaxTicks    = 1<<(wheelLevel0Bits+wheelLevelNCount*wheelLevelNBits) - 1
+)
+
+// timingWheel is a hierarchical timing wheel, which adds and removes entries in O(1),
+// and checks only the entries in the slot of current tick in each tick.
+type timingWheel struct {
+	mu      sync.Mutex
+	current int64                                        // current is the last proceeded ticks of the wheel.
+	level0  [wheelLevel0Size]wheelSlot                   // level0 is the slots of one tick.
+	levelN  [wheelLevelNCount][wheelLevelNSize]wheelSlot // levelN is the slots of the upper levels.
+}
+
+// wheelSlot is the slot of timing wheel holding a doubly linked list of entries.
+type wheelSlot struct {
+	head *Entry
+}
+
+// newTimingWheel creates and returns a timing wheel starting from `ticks`.
+func newTimingWheel(ticks int64) *timingWheel {
+	return &timingWheel{
+		current: ticks,
+	}
+}
+
+// Add adds `entry` to the wheel by its next running ticks.
+// The entry whose next running ticks is passed runs in the next tick.
+func (w *timingWheel) Add(entry *Entry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.doAdd(entry, w.current+1)
+}
+
+// Remove removes `entry` from the wheel.
+func (w *timingWheel) Remove(entry *Entry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.doRemove(entry)
+}
+
+// Reset re-places `entry` in the wheel after its next running ticks is changed.
+// It does nothing if `entry` is not in the wheel, which is closed or being proceeded.
+func (w *timingWheel) Reset(entry *Entry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if entry.wheelSlot == nil {
+		return
+	}
+	w.doRemove(entry)
+	w.doAdd(entry, w.current+1)
+}
+
+// Advance proceeds the wheel tick by tick to `ticks`,
+// and checks and runs the entries in the slot of each tick.
+func (w *timingWheel) Advance(ticks int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.current < ticks {
+		w.current++
+		w.proceed(w.current)
+	}
+}
+
+// proceed cascades the upper level slots reaching the boundary of `ticks`,
+// and then checks and runs the entries in the first level slot of `ticks`.
+func (w *timingWheel) proceed(ticks int64) {
+	if ticks&wheelLevel0Mask == 0 {
+		for level := 0; level < wheelLevelNCount; level++ {
+			index := (ticks >> (wheelLevel0Bits + level*wheelLevelNBits)) & wheelLevelNMask
+			for entry := w.detach(&w.levelN[level][index]); entry != nil; {
+				next := entry.wheelNext
+				entry.wheelNext = nil
+				w.doAdd(entry, ticks)
+				entry = next
+			}
+			// The upper level is cascaded only if this level also reaches its boundary.
+			if index != 0 {
+				break
+			}
+		}
+	}
+	for entry := w.detach(&w.level0[ticks&wheelLevel0Mask]); entry != nil; {
+		next := entry.wheelNext
+		entry.wheelNext = nil
+		if entry.Status() != StatusClosed {
+			// The entry placed at the farthest slot or reset concurrently might not reach its ticks.
+			if ticks >= entry.nextTicks.Val() {
+				entry.doCheckAndRunByTicks(ticks)
+			}
+			if entry.Status() != StatusClosed {
+				w.doAdd(entry, ticks+1)
+			}
+		}
+		entry = next
+	}
+}
+
+// doAdd places `entry` at the slot of its next running ticks, which is not earlier than `minTicks`.
+func (w *timingWheel) doAdd(entry *Entry, minTicks int64) {
+	var (
+		ticks = entry.nextTicks.Val()
+		slot  *wheelSlot
+	)
+	if ticks < minTicks {
+		ticks = minTicks
+	}
+	if ticks-w.current > wheelMaxTicks {
+		ticks = w.current + wheelMaxTicks
+	}
+	if delta := ticks - w.current; delta < wheelLevel0Size {
+		slot = &w.level0[ticks&wheelLevel0Mask]
+	} else {
+		for level := 0; level < wheelLevelNCount; level++ {
+			shift := wheelLevel0Bits + level*wheelLevelNBits
+			if delta < 1<<(shift+wheelLevelNBits) {
+				slot = &w.levelN[level][(ticks>>shift)&wheelLevelNMask]
+				break
+			}
+		}
+	}
+	entry.wheelSlot = slot
+	entry.wheelPrev = nil
+	entry.wheelNext = slot.head
+	if slot.head != nil {
+		slot.head.wheelPrev = entry
+	}
+	slot.head = entry
+}
+
+// doRemove unlinks `entry` from its slot if it is in the wheel.
+func (w *timingWheel) doRemove(entry *Entry) {
+	if entry.wheelSlot == nil {
+		return
+	}
+	if entry.wheelPrev != nil {
+		entry.wheelPrev.wheelNext = entry.wheelNext
+	} else {
+		entry.wheelSlot.head = entry.wheelNext
+	}
+	if entry.wheelNext != nil {
+		entry.wheelNext.wheelPrev = entry.wheelPrev
+	}
+	entry.wheelSlot = nil
+	entry.wheelPrev = nil
+	entry.wheelNext = nil
+}
+
+// detach removes and returns all entries of `slot` as a list linked by wheelNext.
+func (w *timingWheel) detach(slot *wheelSlot) *Entry {
+	head := slot.head
+	slot.head = nil
+	for entry := head; entry != nil; entry = entry.wheelNext {
+		entry.wheelSlot = nil
+		entry.wheelPrev = nil
+	}
+	return head
+}
@@ -37,3 +37,79 @@ func Benchmark_StartStop(b *testing.B) {
 		timer.Stop()
 	}
 }
+
+// newBenchTimer creates a timer of `mode` holding `size` stopped jobs, of which the intervals are in 1 - 10000 ticks.
+// The jobs are stopped to measure the scheduling without running them, and the timer is stopped
+// and proceeded manually, which exits soon after it is closed.
+func newBenchTimer(mode TimerMode, size int) *Timer {
+	t := New(TimerOptions{
+		Interval: time.Millisecond,
+		Mode:     mode,
+	})
+	t.Stop()
+	for i := 0; i < size; i++ {
+		t.AddEntry(ctx, time.Duration(i%10000+1)*time.Millisecond, func(ctx context.Context) {}, false, -1, StatusStopped)
+	}
+	return t
+}
+
+// proceedBenchTimer proceeds the ticks of timer by one tick.
+func proceedBenchTimer(t *Timer) {
+	ticks := t.ticks.Add(1)
+	if t.wheel != nil {
+		t.wheel.Advance(ticks)
+	} else if ticks >= t.queue.NextPriority() {
+		t.proceed(ticks)
+	}
+}
+
+func benchmarkAdd1M(b *testing.B, mode TimerMode) {
+	t := newBenchTimer(mode, 1000000)
+	defer t.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.AddOnce(ctx, time.Duration(i%10000+1)*time.Millisecond, func(ctx context.Context) {})
+	}
+}
+
+func benchmarkAddClose1M(b *testing.B, mode TimerMode) {
+	t := newBenchTimer(mode, 1000000)
+	defer t.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.AddOnce(ctx, time.Duration(i%10000+1)*time.Millisecond, func(ctx context.Context) {}).Close()
+	}
+}
+
+func benchmarkProceed1M(b *testing.B, mode TimerMode) {
+	t := newBenchTimer(mode, 1000000)
+	defer t.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		proceedBenchTimer(t)
+	}
+}
+
+func Benchmark_Heap_Add_1M(b *testing.B) {
+	benchmarkAdd1M(b, TimerModeHeap)
+}
+
+func Benchmark_Wheel_Add_1M(b *testing.B) {
+	benchmarkAdd1M(b, TimerModeWheel)
+}
+
+func Benchmark_Heap_AddClose_1M(b *testing.B) {
+	benchmarkAddClose1M(b, TimerModeHeap)
+}
+
+func Benchmark_Wheel_AddClose_1M(b *testing.B) {
+	benchmarkAddClose1M(b, TimerModeWheel)
+}
+
+func Benchmark_Heap_Proceed_1M(b *testing.B) {
+	benchmarkProceed1M(b, TimerModeHeap)
+}
+
+func Benchmark_Wheel_Proceed_1M(b *testing.B) {
+	benchmarkProceed1M(b, TimerModeWheel)
+}
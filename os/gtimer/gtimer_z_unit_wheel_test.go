@@ -0,0 +1,151 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gtimer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ximplez-go/gf/container/garray"
+	"github.com/ximplez-go/gf/container/gtype"
+	"github.com/ximplez-go/gf/test/gtest"
+)
+
+// advanceWheel proceeds the ticks of wheel mode `timer` manually to `ticks`.
+func advanceWheel(timer *Timer, ticks int64) {
+	for timer.ticks.Val() < ticks {
+		timer.wheel.Advance(timer.ticks.Add(1))
+	}
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestTimer_Wheel_Cascade(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		timer := New(TimerOptions{
+			Interval: time.Hour,
+			Mode:     TimerModeWheel,
+		})
+		defer timer.Close()
+		var (
+			allTicks = []int64{1, 5, 255, 256, 257, 1000, 16383, 16384, 16385, 70000, 1 << 20}
+			arrays   = make(map[int64]*garray.Array)
+		)
+		for _, ticks := range allTicks {
+			array := garray.New(true)
+			arrays[ticks] = array
+			timer.AddOnce(ctx, time.Duration(ticks)*time.Hour, func(ctx context.Context) {
+				array.Append(1)
+			})
+		}
+		for _, ticks := range allTicks {
+			advanceWheel(timer, ticks-1)
+			t.Assert(arrays[ticks].Len(), 0)
+			advanceWheel(timer, ticks)
+			t.Assert(arrays[ticks].Len(), 1)
+		}
+	})
+}
+
+func TestTimer_Wheel_Interval(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		timer := New(TimerOptions{
+			Interval: time.Hour,
+			Mode:     TimerModeWheel,
+		})
+		defer timer.Close()
+		array := garray.New(true)
+		timer.Add(ctx, 300*time.Hour, func(ctx context.Context) {
+			array.Append(1)
+		})
+		advanceWheel(timer, 299)
+		t.Assert(array.Len(), 0)
+		advanceWheel(timer, 300)
+		t.Assert(array.Len(), 1)
+		advanceWheel(timer, 900)
+		t.Assert(array.Len(), 3)
+	})
+}
+
+func TestTimer_Wheel_Close_Reset(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		timer := New(TimerOptions{
+			Interval: time.Hour,
+			Mode:     TimerModeWheel,
+		})
+		defer timer.Close()
+		array1 := garray.New(true)
+		entry1 := timer.Add(ctx, 10*time.Hour, func(ctx context.Context) {
+			array1.Append(1)
+		})
+		array2 := garray.New(true)
+		entry2 := timer.Add(ctx, 10*time.Hour, func(ctx context.Context) {
+			array2.Append(1)
+		})
+		entry1.Close()
+		t.Assert(entry1.wheelSlot == nil, true)
+
+		advanceWheel(timer, 5)
+		entry2.Reset()
+		advanceWheel(timer, 10)
+		t.Assert(array1.Len(), 0)
+		t.Assert(array2.Len(), 0)
+		advanceWheel(timer, 15)
+		t.Assert(array1.Len(), 0)
+		t.Assert(array2.Len(), 1)
+	})
+}
+
+func TestTimer_Wheel_MaxTicks(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			wheel = newTimingWheel(0)
+			entry = &Entry{
+				status:    gtype.NewInt(StatusReady),
+				nextTicks: gtype.NewInt64(wheelMaxTicks * 2),
+			}
+		)
+		// It is placed at the farthest slot.
+		wheel.Add(entry)
+		t.Assert(entry.wheelSlot == &wheel.levelN[wheelLevelNCount-1][wheelLevelNMask], true)
+		wheel.Remove(entry)
+		t.Assert(entry.wheelSlot == nil, true)
+		t.Assert(wheel.levelN[wheelLevelNCount-1][wheelLevelNMask].head == nil, true)
+	})
+}
+
+func TestTimer_Wheel_Mode(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		timer := New(TimerOptions{
+			Interval: 10 * time.Millisecond,
+			Mode:     TimerModeWheel,
+		})
+		defer timer.Close()
+		var (
+			array1 = garray.New(true)
+			array2 = garray.New(true)
+			array3 = garray.New(true)
+		)
+		timer.AddTimes(ctx, 50*time.Millisecond, 2, func(ctx context.Context) {
+			array1.Append(1)
+		})
+		entry := timer.Add(ctx, 50*time.Millisecond, func(ctx context.Context) {
+			array2.Append(1)
+		})
+		entry.Stop()
+		timer.AddOnce(ctx, 3*time.Second, func(ctx context.Context) {
+			array3.Append(1)
+		}).Close()
+		time.Sleep(300 * time.Millisecond)
+		t.Assert(array1.Len(), 2)
+		t.Assert(array2.Len(), 0)
+		entry.Start()
+		time.Sleep(150 * time.Millisecond)
+		t.AssertGT(array2.Len(), 0)
+		t.Assert(array3.Len(), 0)
+	})
+}